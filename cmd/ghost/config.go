@@ -33,6 +33,7 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/sysmon"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
 )
 
 type Config struct {
@@ -41,6 +42,7 @@ type Config struct {
 	Transport transportConfig.Transport `json:"transport"`
 	Ghost     ghostConfig.Ghost         `json:"ghost"`
 	Feeds     feedsConfig.Feeds         `json:"feeds"`
+	Rotations feedsConfig.Rotations     `json:"feedRotations" yaml:"feedRotations"`
 	Logger    loggerConfig.Logger       `json:"logger"`
 }
 
@@ -49,6 +51,33 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	if err != nil {
 		return nil, fmt.Errorf(`config error: %w`, err)
 	}
+	return prepareServices(ctx, opts)
+}
+
+// ValidateConfig parses the config file and builds all services without
+// starting them. It returns all errors found.
+func ValidateConfig(ctx context.Context, opts *options) []error {
+	return config.ValidateFile(&opts.Config, opts.ConfigFilePath, func() error {
+		if err := opts.Config.Validate(); err != nil {
+			return err
+		}
+		_, err := prepareServices(ctx, opts)
+		return err
+	})
+}
+
+// Validate validates the config without building services.
+func (c *Config) Validate() error {
+	return config.Join(
+		config.WithPath(c.Gofer.Validate(), "gofer"),
+		config.WithPath(c.Transport.Validate(), "transport"),
+		config.WithPath(c.Ghost.Validate(maputil.Keys(c.Gofer.PriceModels)), "ghost"),
+		config.WithPath(c.Feeds.Validate(), "feeds"),
+		config.WithPath(c.Rotations.Validate(c.Feeds), "feedRotations"),
+	)
+}
+
+func prepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor, error) {
 	log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
 		AppName:    "ghost",
 		BaseLogger: opts.Logger(),
@@ -76,10 +105,15 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	if err != nil {
 		return nil, fmt.Errorf(`feeds config error: %w`, err)
 	}
+	als, err := opts.Config.Rotations.Aliases()
+	if err != nil {
+		return nil, fmt.Errorf(`feedRotations config error: %w`, err)
+	}
 	tra, err := opts.Config.Transport.Configure(transportConfig.Dependencies{
-		Signer: sig,
-		Feeds:  fed,
-		Logger: log,
+		Signer:        sig,
+		Feeds:         fed,
+		FeederAliases: als,
+		Logger:        log,
 	},
 		map[string]transport.Message{
 			messages.PriceV0MessageName: (*messages.Price)(nil),
@@ -79,6 +79,40 @@ func PrepareClientServices(
 	return sup, gof, mar, hook, nil
 }
 
+// ValidateConfig parses the config file and builds all services without
+// starting them. It returns all errors found.
+func ValidateConfig(ctx context.Context, opts *options) []error {
+	return config.ValidateFile(&opts.Config, opts.ConfigFilePath, func() error {
+		if err := opts.Config.Validate(); err != nil {
+			return err
+		}
+		log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
+			AppName:    "gofer",
+			BaseLogger: opts.Logger(),
+		})
+		if err != nil {
+			return fmt.Errorf(`logger config error: %w`, err)
+		}
+		cli, err := opts.Config.Ethereum.ConfigureEthereumClient(nil, log)
+		if err != nil {
+			return fmt.Errorf(`ethereum config error: %w`, err)
+		}
+		gof, err := opts.Config.Gofer.ConfigureAsyncGofer(cli, log)
+		if err != nil {
+			return fmt.Errorf(`gofer config error: %w`, err)
+		}
+		if _, err := opts.Config.Gofer.ConfigureRPCAgent(cli, gof, log); err != nil {
+			return fmt.Errorf(`gofer config error: %w`, err)
+		}
+		return nil
+	})
+}
+
+// Validate validates the config without building services.
+func (c *Config) Validate() error {
+	return config.WithPath(c.Gofer.Validate(), "gofer")
+}
+
 func PrepareAgentServices(ctx context.Context, opts *options) (*supervisor.Supervisor, error) {
 	err := config.ParseFile(&opts.Config, opts.ConfigFilePath)
 	if err != nil {
@@ -16,10 +16,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	suite "github.com/chronicleprotocol/oracle-suite"
+	configCobra "github.com/chronicleprotocol/oracle-suite/pkg/config/cobra"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal"
 )
 
@@ -37,6 +39,7 @@ func main() {
 		NewPairsCmd(&opts),
 		NewPricesCmd(&opts),
 		NewAgentCmd(&opts),
+		configCobra.NewConfigCmd("gofer", Config{}, func() []error { return ValidateConfig(context.Background(), &opts) }),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -26,6 +26,8 @@ import (
 	loggerConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/logger"
 	transportConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/arbitrum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/optimism"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportevm"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportstarknet"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/store"
@@ -47,6 +49,31 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	if err != nil {
 		return nil, fmt.Errorf(`config error: %w`, err)
 	}
+	return prepareServices(ctx, opts)
+}
+
+// ValidateConfig parses the config file and builds all services without
+// starting them. It returns all errors found.
+func ValidateConfig(ctx context.Context, opts *options) []error {
+	return config.ValidateFile(&opts.Config, opts.ConfigFilePath, func() error {
+		if err := opts.Config.Validate(); err != nil {
+			return err
+		}
+		_, err := prepareServices(ctx, opts)
+		return err
+	})
+}
+
+// Validate validates the config without building services.
+func (c *Config) Validate() error {
+	return config.Join(
+		config.WithPath(c.Lair.Validate(), "lair"),
+		config.WithPath(c.Transport.Validate(), "transport"),
+		config.WithPath(c.Feeds.Validate(), "feeds"),
+	)
+}
+
+func prepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor, error) {
 	log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
 		AppName:    "lair",
 		BaseLogger: opts.Logger(),
@@ -72,11 +99,26 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	if err != nil {
 		return nil, fmt.Errorf(`lair config error: %w`, err)
 	}
+	dom, err := opts.Config.Lair.ConfigureDomain()
+	if err != nil {
+		return nil, fmt.Errorf(`lair config error: %w`, err)
+	}
 	evs, err := store.New(store.Config{
-		EventTypes: []string{teleportevm.TeleportEventType, teleportstarknet.TeleportEventType},
-		Storage:    sto,
-		Transport:  tra,
-		Logger:     log,
+		EventTypes: append(
+			[]string{
+				teleportevm.TeleportEventType,
+				teleportstarknet.TeleportEventType,
+				optimism.SentMessageEventType,
+				arbitrum.L2ToL1TxEventType,
+			},
+			opts.Config.Lair.EventTypes...,
+		),
+		Storage:   sto,
+		Transport: tra,
+		Signer:    geth.NewSigner(nil),
+		Feeds:     fed,
+		Domain:    dom,
+		Logger:    log,
 	})
 	if err != nil {
 		return nil, fmt.Errorf(`lair config error: %w`, err)
@@ -84,6 +126,7 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	api, err := opts.Config.Lair.Configure(eventAPIConfig.Dependencies{
 		EventStore: evs,
 		Transport:  tra,
+		Feeds:      fed,
 		Logger:     log,
 	})
 	if err != nil {
@@ -91,6 +134,9 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	}
 	sup := supervisor.New(log)
 	sup.Watch(tra, evs, api, sysmon.New(time.Minute, log))
+	if s, ok := sto.(supervisor.Service); ok {
+		sup.Watch(s)
+	}
 	if l, ok := log.(supervisor.Service); ok {
 		sup.Watch(l)
 	}
@@ -16,7 +16,10 @@
 package main
 
 import (
+	"context"
 	"os"
+
+	configCobra "github.com/chronicleprotocol/oracle-suite/pkg/config/cobra"
 )
 
 func main() {
@@ -25,6 +28,7 @@ func main() {
 
 	rootCmd.AddCommand(
 		NewRunCmd(&opts),
+		configCobra.NewConfigCmd("lair", Config{}, func() []error { return ValidateConfig(context.Background(), &opts) }),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
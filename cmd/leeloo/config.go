@@ -45,6 +45,31 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	if err != nil {
 		return nil, fmt.Errorf(`config error: %w`, err)
 	}
+	return prepareServices(ctx, opts)
+}
+
+// ValidateConfig parses the config file and builds all services without
+// starting them. It returns all errors found.
+func ValidateConfig(ctx context.Context, opts *options) []error {
+	return config.ValidateFile(&opts.Config, opts.ConfigFilePath, func() error {
+		if err := opts.Config.Validate(); err != nil {
+			return err
+		}
+		_, err := prepareServices(ctx, opts)
+		return err
+	})
+}
+
+// Validate validates the config without building services.
+func (c *Config) Validate() error {
+	return config.Join(
+		config.WithPath(c.Leeloo.Validate(), "leeloo"),
+		config.WithPath(c.Transport.Validate(), "transport"),
+		config.WithPath(c.Feeds.Validate(), "feeds"),
+	)
+}
+
+func prepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor, error) {
 	log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
 		AppName:    "leeloo",
 		BaseLogger: opts.Logger(),
@@ -25,7 +25,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/config"
-	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
 	loggerConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/logger"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/logrus/flag"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
@@ -37,16 +36,7 @@ type options struct {
 	ConfigFilePath string
 	Config         Config
 	Version        string
-}
-
-type Config struct {
-	Ethereum  ethereumConfig.Ethereum `json:"ethereum"`
-	Logger    loggerConfig.Logger     `json:"logger"`
-	Contracts []struct {
-		Address string `json:"address"`
-		Symbol  string `json:"symbol"`
-		Wat     string `json:"wat"`
-	} `json:"contracts"`
+	GoferNoRPC     bool
 }
 
 func NewRootCommand(opts *options) *cobra.Command {
@@ -66,6 +56,12 @@ func NewRootCommand(opts *options) *cobra.Command {
 		"./monitor.json",
 		"monitor config file",
 	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.GoferNoRPC,
+		"gofer.norpc",
+		false,
+		"disable the use of Graph RPC agent",
+	)
 
 	return rootCmd
 }
@@ -0,0 +1,46 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRunCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "run",
+		Args:    cobra.ExactArgs(0),
+		Version: opts.Version,
+		Short:   "Continuously monitor Median contracts",
+		Long: `Periodically reads the age, price, bar and feeds of Median contracts, compares
+prices with Gofer reference prices and sends alerts to logs and webhooks.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
+			sup, err := PrepareServices(ctx, opts)
+			if err != nil {
+				return err
+			}
+			if err = sup.Start(ctx); err != nil {
+				return err
+			}
+			return <-sup.Wait()
+		},
+	}
+}
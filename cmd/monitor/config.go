@@ -0,0 +1,163 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
+	goferConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/gofer"
+	indexerConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/indexer"
+	loggerConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/logger"
+	monitorConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/monitor"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcclient"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/supervisor"
+	"github.com/chronicleprotocol/oracle-suite/pkg/sysmon"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
+)
+
+type Config struct {
+	Ethereum  ethereumConfig.Ethereum `json:"ethereum"`
+	Gofer     goferConfig.Gofer       `json:"gofer"`
+	Monitor   monitorConfig.Monitor   `json:"monitor"`
+	Logger    loggerConfig.Logger     `json:"logger"`
+	Contracts monitorConfig.Contracts `json:"contracts"`
+	// Indexer is the optional configuration of the poke indexer. If not set,
+	// pokes are not indexed.
+	Indexer *indexerConfig.Indexer `json:"indexer"`
+}
+
+func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor, error) {
+	err := config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	if err != nil {
+		return nil, fmt.Errorf(`config error: %w`, err)
+	}
+	return prepareServices(ctx, opts)
+}
+
+// ValidateConfig parses the config file and builds all services without
+// starting them. It returns all errors found.
+func ValidateConfig(ctx context.Context, opts *options) []error {
+	return config.ValidateFile(&opts.Config, opts.ConfigFilePath, func() error {
+		if err := opts.Config.Validate(); err != nil {
+			return err
+		}
+		_, err := prepareServices(ctx, opts)
+		return err
+	})
+}
+
+// Validate validates the config without building services.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Contracts.HasPairs() {
+		errs = append(errs, config.WithPath(c.Gofer.Validate(), "gofer"))
+	}
+	errs = append(errs,
+		config.WithPath(c.Monitor.Validate(), "monitor"),
+		config.WithPath(c.Contracts.Validate(maputil.Keys(c.Gofer.PriceModels)), "contracts"),
+	)
+	if c.Indexer != nil {
+		errs = append(errs, config.WithPath(c.Indexer.Validate(), "indexer"))
+	}
+	return config.Join(errs...)
+}
+
+func prepareServices(_ context.Context, opts *options) (*supervisor.Supervisor, error) {
+	log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
+		AppName:    "monitor",
+		BaseLogger: opts.Logger(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(`logger config error: %w`, err)
+	}
+	cli, err := opts.Config.Ethereum.ConfigureEthereumClient(nil, log)
+	if err != nil {
+		return nil, fmt.Errorf(`ethereum config error: %w`, err)
+	}
+	var gof provider.Provider
+	if opts.Config.Contracts.HasPairs() {
+		gof, err = opts.Config.Gofer.ConfigureGofer(cli, log, opts.GoferNoRPC)
+		if err != nil {
+			return nil, fmt.Errorf(`gofer config error: %w`, err)
+		}
+	}
+	mon, err := opts.Config.Monitor.ConfigureMonitor(monitorConfig.Dependencies{
+		Contracts:      opts.Config.Contracts,
+		EthereumClient: cli,
+		Gofer:          gof,
+		Logger:         log,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(`monitor config error: %w`, err)
+	}
+	sup := supervisor.New(log)
+	sup.Watch(mon, sysmon.New(time.Minute, log))
+	if opts.Config.Indexer != nil {
+		services, err := prepareIndexer(opts.Config, log)
+		if err != nil {
+			return nil, err
+		}
+		sup.Watch(services...)
+	}
+	if g, ok := gof.(supervisor.Service); ok {
+		sup.Watch(g)
+	}
+	if l, ok := log.(supervisor.Service); ok {
+		sup.Watch(l)
+	}
+	return sup, nil
+}
+
+// prepareIndexer returns the poke indexer for the monitored contracts and,
+// if enabled, its HTTP API.
+func prepareIndexer(cfg Config, logger log.Logger) ([]supervisor.Service, error) {
+	rpc, err := cfg.Ethereum.ConfigureRPCClient(logger)
+	if err != nil {
+		return nil, fmt.Errorf(`ethereum config error: %w`, err)
+	}
+	sig, err := cfg.Ethereum.ConfigureSigner()
+	if err != nil {
+		return nil, fmt.Errorf(`ethereum config error: %w`, err)
+	}
+	var contracts []types.Address
+	for _, c := range cfg.Contracts {
+		contracts = append(contracts, types.HexToAddress(c.Address))
+	}
+	ix, err := cfg.Indexer.ConfigureIndexer(indexerConfig.Dependencies{
+		Client:    rpcclient.New(rpc),
+		Signer:    sig,
+		Contracts: contracts,
+		Logger:    logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(`indexer config error: %w`, err)
+	}
+	api, err := cfg.Indexer.ConfigureAPI(ix, logger)
+	if err != nil {
+		return nil, fmt.Errorf(`indexer config error: %w`, err)
+	}
+	if api == nil {
+		return []supervisor.Service{ix}, nil
+	}
+	return []supervisor.Service{ix, api}, nil
+}
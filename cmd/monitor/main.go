@@ -16,9 +16,11 @@
 package main
 
 import (
+	"context"
 	"os"
 
 	suite "github.com/chronicleprotocol/oracle-suite"
+	configCobra "github.com/chronicleprotocol/oracle-suite/pkg/config/cobra"
 )
 
 func main() {
@@ -26,6 +28,8 @@ func main() {
 	rootCmd := NewRootCommand(&opts)
 	rootCmd.AddCommand(
 		NewMedianCmd(&opts),
+		NewRunCmd(&opts),
+		configCobra.NewConfigCmd("monitor", Config{}, func() []error { return ValidateConfig(context.Background(), &opts) }),
 	)
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -28,6 +28,16 @@ type options struct {
 	GracefulTimeoutSec int
 	TotalTimeoutSec    int
 	MaxBlocksBehind    int
+	EvictErrorRate     float64
+	EvictLatencyMs     int
+	EvictBlocksBehind  int
+	ProbeIntervalSec   int
+	CacheEntries       int
+	CacheSizeMB        int
+	CacheConfirmations int
+	SubMinResponses    int
+	MaxBatchSize       int
+	MethodPolicies     []string
 	EthRPCURLs         []string
 	flag.LoggerFlag
 }
@@ -75,11 +85,71 @@ func NewRootCommand(opts *options) *cobra.Command {
 		10,
 		"determines how far one node can be behind the last known block",
 	)
+	rootCmd.PersistentFlags().Float64Var(
+		&opts.EvictErrorRate,
+		"evict-error-rate",
+		0,
+		"evict nodes with an error rate above this value (0-1), 0 disables",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.EvictLatencyMs,
+		"evict-latency",
+		0,
+		"evict nodes with a 90th percentile latency above this value in milliseconds, 0 disables",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.EvictBlocksBehind,
+		"evict-blocks-behind",
+		0,
+		"evict nodes that are more blocks behind the highest known block, 0 disables",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.ProbeIntervalSec,
+		"probe-interval",
+		30,
+		"set interval in seconds between probes of evicted nodes",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.CacheEntries,
+		"cache-entries",
+		0,
+		"maximum number of cached responses for immutable queries, 0 disables the cache",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.CacheSizeMB,
+		"cache-size",
+		64,
+		"maximum size of cached responses in megabytes",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.CacheConfirmations,
+		"cache-confirmations",
+		10,
+		"number of confirmations after which a block is considered immutable",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.SubMinResponses,
+		"subscription-min-responses",
+		0,
+		"minimum number of nodes that must send the same notification, 0 uses the same value as for other requests",
+	)
+	rootCmd.PersistentFlags().IntVar(
+		&opts.MaxBatchSize,
+		"max-batch-size",
+		100,
+		"maximum number of elements in a batch request, 0 disables the limit",
+	)
+	rootCmd.PersistentFlags().StringSliceVar(
+		&opts.MethodPolicies,
+		"method-policy",
+		[]string{},
+		"list of method policies in the method=consensus|first|pinned:<url>|deny form, method may be a namespace wildcard like debug_*",
+	)
 	rootCmd.PersistentFlags().StringSliceVar(
 		&opts.EthRPCURLs,
 		"eth-rpc",
 		[]string{},
-		"list of ethereum RPC nodes",
+		"list of ethereum RPC nodes, nodes with ws:// or wss:// URLs are also used for subscriptions",
 	)
 	err := rootCmd.MarkPersistentFlagRequired("eth-rpc")
 	if err != nil {
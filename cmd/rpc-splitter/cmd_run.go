@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -40,13 +41,7 @@ func NewRunCmd(opts *options) *cobra.Command {
 		RunE: func(_ *cobra.Command, _ []string) error {
 			ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
 			log := opts.Logger()
-			var server, err = rpcsplitter.NewServer(
-				rpcsplitter.WithEndpoints(opts.EthRPCURLs),
-				rpcsplitter.WithTotalTimeout(time.Duration(opts.TotalTimeoutSec)*time.Second),
-				rpcsplitter.WithGracefulTimeout(time.Duration(opts.GracefulTimeoutSec)*time.Second),
-				rpcsplitter.WithRequirements(minimumRequiredResponses(len(opts.EthRPCURLs)), opts.MaxBlocksBehind),
-				rpcsplitter.WithLogger(opts.Logger()),
-			)
+			server, err := newServer(opts)
 			if err != nil {
 				return err
 			}
@@ -89,6 +84,61 @@ func NewRunCmd(opts *options) *cobra.Command {
 	}
 }
 
+// ValidateConfig validates the command line options by creating the server
+// without starting it.
+func ValidateConfig(opts *options) []error {
+	if _, err := newServer(opts); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+func newServer(opts *options) (http.Handler, error) {
+	var wsOrigins []string
+	if opts.EnableCORS {
+		wsOrigins = []string{"*"}
+	}
+	policies, err := methodPolicies(opts.MethodPolicies)
+	if err != nil {
+		return nil, err
+	}
+	serverOpts := []rpcsplitter.Option{
+		rpcsplitter.WithEndpoints(opts.EthRPCURLs),
+		rpcsplitter.WithTotalTimeout(time.Duration(opts.TotalTimeoutSec) * time.Second),
+		rpcsplitter.WithGracefulTimeout(time.Duration(opts.GracefulTimeoutSec) * time.Second),
+		rpcsplitter.WithRequirements(minimumRequiredResponses(len(opts.EthRPCURLs)), opts.MaxBlocksBehind),
+		rpcsplitter.WithEviction(
+			opts.EvictErrorRate,
+			time.Duration(opts.EvictLatencyMs)*time.Millisecond,
+			opts.EvictBlocksBehind,
+		),
+		rpcsplitter.WithProbeInterval(time.Duration(opts.ProbeIntervalSec) * time.Second),
+		rpcsplitter.WithCache(opts.CacheEntries, opts.CacheSizeMB*1024*1024, opts.CacheConfirmations),
+		rpcsplitter.WithSubscriptionRequirements(opts.SubMinResponses),
+		rpcsplitter.WithMaxBatchSize(opts.MaxBatchSize),
+		rpcsplitter.WithWebsocketOrigins(wsOrigins),
+		rpcsplitter.WithLogger(opts.Logger()),
+	}
+	return rpcsplitter.NewServer(append(serverOpts, policies...)...)
+}
+
+// methodPolicies parses method policies given in the method=policy form.
+func methodPolicies(policies []string) ([]rpcsplitter.Option, error) {
+	var opts []rpcsplitter.Option
+	for _, p := range policies {
+		method, policy, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid method policy: %s", p)
+		}
+		mp, err := rpcsplitter.ParsePolicy(policy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, rpcsplitter.WithMethodPolicy(method, mp))
+	}
+	return opts, nil
+}
+
 func minimumRequiredResponses(endpoints int) int {
 	if endpoints < 2 {
 		return endpoints
@@ -17,6 +17,8 @@ package main
 
 import (
 	"os"
+
+	configCobra "github.com/chronicleprotocol/oracle-suite/pkg/config/cobra"
 )
 
 func main() {
@@ -24,6 +26,7 @@ func main() {
 	rootCmd := NewRootCommand(&opts)
 	rootCmd.AddCommand(
 		NewRunCmd(&opts),
+		configCobra.NewConfigCmd("rpc-splitter", nil, func() []error { return ValidateConfig(&opts) }),
 	)
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
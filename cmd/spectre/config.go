@@ -26,6 +26,7 @@ import (
 	loggerConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/logger"
 	spectreConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/spectre"
 	transportConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcclient"
 	"github.com/chronicleprotocol/oracle-suite/pkg/supervisor"
 	"github.com/chronicleprotocol/oracle-suite/pkg/sysmon"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
@@ -37,6 +38,7 @@ type Config struct {
 	Ethereum  ethereumConfig.Ethereum   `json:"ethereum"`
 	Spectre   spectreConfig.Spectre     `json:"spectre"`
 	Feeds     feedsConfig.Feeds         `json:"feeds"`
+	Rotations feedsConfig.Rotations     `json:"feedRotations" yaml:"feedRotations"`
 	Logger    loggerConfig.Logger       `json:"logger"`
 }
 
@@ -45,6 +47,32 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	if err != nil {
 		return nil, fmt.Errorf(`config error: %w`, err)
 	}
+	return prepareServices(ctx, opts)
+}
+
+// ValidateConfig parses the config file and builds all services without
+// starting them. It returns all errors found.
+func ValidateConfig(ctx context.Context, opts *options) []error {
+	return config.ValidateFile(&opts.Config, opts.ConfigFilePath, func() error {
+		if err := opts.Config.Validate(); err != nil {
+			return err
+		}
+		_, err := prepareServices(ctx, opts)
+		return err
+	})
+}
+
+// Validate validates the config without building services.
+func (c *Config) Validate() error {
+	return config.Join(
+		config.WithPath(c.Transport.Validate(), "transport"),
+		config.WithPath(c.Spectre.Validate(), "spectre"),
+		config.WithPath(c.Feeds.Validate(), "feeds"),
+		config.WithPath(c.Rotations.Validate(c.Feeds), "feedRotations"),
+	)
+}
+
+func prepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor, error) {
 	log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
 		AppName:    "spectre",
 		BaseLogger: opts.Logger(),
@@ -60,14 +88,23 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 	if err != nil {
 		return nil, fmt.Errorf(`ethereum config error: %w`, err)
 	}
+	rpc, err := opts.Config.Ethereum.ConfigureRPCClient(log)
+	if err != nil {
+		return nil, fmt.Errorf(`ethereum config error: %w`, err)
+	}
 	fed, err := opts.Config.Feeds.Addresses()
 	if err != nil {
 		return nil, fmt.Errorf(`feeds config error: %w`, err)
 	}
+	als, err := opts.Config.Rotations.Aliases()
+	if err != nil {
+		return nil, fmt.Errorf(`feedRotations config error: %w`, err)
+	}
 	tra, err := opts.Config.Transport.Configure(transportConfig.Dependencies{
-		Signer: sig,
-		Feeds:  fed,
-		Logger: log,
+		Signer:        sig,
+		Feeds:         fed,
+		FeederAliases: als,
+		Logger:        log,
 	},
 		map[string]transport.Message{
 			messages.PriceV0MessageName: (*messages.Price)(nil),
@@ -90,6 +127,8 @@ func PrepareServices(ctx context.Context, opts *options) (*supervisor.Supervisor
 		Signer:         sig,
 		PriceStore:     pst,
 		EthereumClient: cli,
+		RPCClient:      rpcclient.New(rpc),
+		FeederAliases:  als,
 		Logger:         log,
 	})
 	if err != nil {
@@ -16,8 +16,11 @@
 package main
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
+	configCobra "github.com/chronicleprotocol/oracle-suite/pkg/config/cobra"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/logrus/flag"
 )
 
@@ -52,6 +55,7 @@ func NewRootCommand(opts *options) *cobra.Command {
 		NewAgentCmd(opts),
 		NewPullCmd(opts),
 		NewPushCmd(opts),
+		configCobra.NewConfigCmd("spire", Config{}, func() []error { return ValidateConfig(context.Background(), opts) }),
 	)
 
 	return rootCmd
@@ -38,6 +38,7 @@ type Config struct {
 	Ethereum  ethereumConfig.Ethereum   `json:"ethereum"`
 	Spire     spireConfig.Spire         `json:"spire"`
 	Feeds     feedsConfig.Feeds         `json:"feeds"`
+	Rotations feedsConfig.Rotations     `json:"feedRotations" yaml:"feedRotations"`
 	Logger    loggerConfig.Logger       `json:"logger"`
 }
 
@@ -46,6 +47,31 @@ func PrepareAgentServices(ctx context.Context, opts *options) (*supervisor.Super
 	if err != nil {
 		return nil, fmt.Errorf(`config error: %w`, err)
 	}
+	return prepareAgentServices(ctx, opts)
+}
+
+// ValidateConfig parses the config file and builds all services without
+// starting them. It returns all errors found.
+func ValidateConfig(ctx context.Context, opts *options) []error {
+	return config.ValidateFile(&opts.Config, opts.ConfigFilePath, func() error {
+		if err := opts.Config.Validate(); err != nil {
+			return err
+		}
+		_, err := prepareAgentServices(ctx, opts)
+		return err
+	})
+}
+
+// Validate validates the config without building services.
+func (c *Config) Validate() error {
+	return config.Join(
+		config.WithPath(c.Transport.Validate(), "transport"),
+		config.WithPath(c.Feeds.Validate(), "feeds"),
+		config.WithPath(c.Rotations.Validate(c.Feeds), "feedRotations"),
+	)
+}
+
+func prepareAgentServices(ctx context.Context, opts *options) (*supervisor.Supervisor, error) {
 	log, err := opts.Config.Logger.Configure(loggerConfig.Dependencies{
 		AppName:    "spire",
 		BaseLogger: opts.Logger(),
@@ -61,10 +87,15 @@ func PrepareAgentServices(ctx context.Context, opts *options) (*supervisor.Super
 	if err != nil {
 		return nil, fmt.Errorf(`feeds config error: %w`, err)
 	}
+	als, err := opts.Config.Rotations.Aliases()
+	if err != nil {
+		return nil, fmt.Errorf(`feedRotations config error: %w`, err)
+	}
 	tra, err := opts.Config.Transport.Configure(transportConfig.Dependencies{
-		Signer: sig,
-		Feeds:  fed,
-		Logger: log,
+		Signer:        sig,
+		Feeds:         fed,
+		FeederAliases: als,
+		Logger:        log,
 	},
 		map[string]transport.Message{
 			messages.PriceV0MessageName: (*messages.Price)(nil),
@@ -21,7 +21,10 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
+	"sort"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cobra"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
@@ -48,6 +51,7 @@ func NewMedianCmd(opts *options) *cobra.Command {
 		NewMedianLiftCmd(opts),
 		NewMedianDropCmd(opts),
 		NewMedianSetBarCmd(opts),
+		NewMedianRotateCmd(opts),
 	)
 
 	return cmd
@@ -312,3 +316,106 @@ func NewMedianSetBarCmd(opts *options) *cobra.Command {
 		},
 	}
 }
+
+// rotationDraft is an unsigned transaction drafted by the rotate command.
+type rotationDraft struct {
+	Method string        `json:"method"`
+	Args   []string      `json:"args"`
+	To     string        `json:"to"`
+	Data   hexutil.Bytes `json:"data"`
+}
+
+func NewMedianRotateCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate old_address new_address [median_addresses...]",
+		Args:  cobra.MinimumNArgs(2),
+		Short: "drafts lift and drop transactions required to replace a feeder key",
+		Long: `Drafts lift and drop transactions required to replace a feeder key.
+If no median addresses are given, medianizers from the Spectre config are used.
+Transactions are not sent, they are printed as JSON in the order in which
+they should be sent. Drop transactions should be sent after the rotation
+window, unless both keys use the same bloom slot.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			srv, err := PrepareServices(opts)
+			if err != nil {
+				return err
+			}
+			oldAddr := ethereum.HexToAddress(args[0])
+			newAddr := ethereum.HexToAddress(args[1])
+			medians := args[2:]
+			if len(medians) == 0 {
+				for _, m := range opts.Config.Medianizers() {
+					medians = append(medians, m.Contract)
+				}
+				sort.Strings(medians)
+			}
+
+			var lifts, drops []rotationDraft
+			for _, addr := range medians {
+				median := oracleGeth.NewMedian(srv.Client, ethereum.HexToAddress(addr))
+				feeds, err := median.Feeds(context.Background())
+				if err != nil {
+					return err
+				}
+				rot, err := oracle.PlanFeedRotation(feeds, oldAddr, newAddr)
+				if err != nil {
+					return fmt.Errorf("median %s: %w", addr, err)
+				}
+				if rot.DropFirst {
+					fmt.Fprintf(os.Stderr, "median %s: both keys use the same slot, the old key must be dropped first\n", addr)
+				}
+				lift, err := draftRotationTx(median, "lift", oldAddr, newAddr)
+				if err != nil {
+					return err
+				}
+				drop, err := draftRotationTx(median, "drop", oldAddr, newAddr)
+				if err != nil {
+					return err
+				}
+				if rot.DropFirst {
+					lifts = append(lifts, drop, lift)
+					continue
+				}
+				if rot.Lift {
+					lifts = append(lifts, lift)
+				}
+				if rot.Drop {
+					drops = append(drops, drop)
+				}
+			}
+
+			b, err := json.MarshalIndent(append(lifts, drops...), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+
+			return nil
+		},
+	}
+}
+
+func draftRotationTx(median *oracleGeth.Median, method string, oldAddr, newAddr ethereum.Address) (rotationDraft, error) {
+	var (
+		addr ethereum.Address
+		data []byte
+		err  error
+	)
+	switch method {
+	case "lift":
+		addr = newAddr
+		data, err = oracleGeth.LiftCalldata([]ethereum.Address{addr})
+	case "drop":
+		addr = oldAddr
+		data, err = oracleGeth.DropCalldata([]ethereum.Address{addr})
+	}
+	if err != nil {
+		return rotationDraft{}, err
+	}
+	return rotationDraft{
+		Method: method,
+		Args:   []string{addr.String()},
+		To:     median.Address().String(),
+		Data:   data,
+	}, nil
+}
@@ -0,0 +1,230 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	oracleGeth "github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
+)
+
+func NewOSMCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "osm",
+		Args:  cobra.ExactArgs(1),
+		Short: "commands related to the Oracle Security Module contract",
+		Long:  ``,
+	}
+
+	cmd.AddCommand(
+		NewOSMSrcCmd(opts),
+		NewOSMPeekCmd(opts),
+		NewOSMPeepCmd(opts),
+		NewOSMHopCmd(opts),
+		NewOSMZzzCmd(opts),
+		NewOSMPassCmd(opts),
+		NewOSMPokeCmd(opts),
+	)
+
+	return cmd
+}
+
+func NewOSMSrcCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "src osm_address",
+		Args:  cobra.ExactArgs(1),
+		Short: "returns the src value (source contract address)",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			srv, err := PrepareServices(opts)
+			if err != nil {
+				return err
+			}
+			osm := oracleGeth.NewOSM(srv.Client, ethereum.HexToAddress(args[0]))
+
+			src, err := osm.Src(context.Background())
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(src.String())
+
+			return nil
+		},
+	}
+}
+
+func NewOSMPeekCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "peek osm_address",
+		Args:  cobra.ExactArgs(1),
+		Short: "returns the current price and its validity",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			srv, err := PrepareServices(opts)
+			if err != nil {
+				return err
+			}
+			osm := oracleGeth.NewOSM(srv.Client, ethereum.HexToAddress(args[0]))
+
+			val, has, err := osm.Peek(context.Background())
+			if err != nil {
+				return err
+			}
+
+			printOSMFeed(val, has)
+
+			return nil
+		},
+	}
+}
+
+func NewOSMPeepCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "peep osm_address",
+		Args:  cobra.ExactArgs(1),
+		Short: "returns the next price and its validity",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			srv, err := PrepareServices(opts)
+			if err != nil {
+				return err
+			}
+			osm := oracleGeth.NewOSM(srv.Client, ethereum.HexToAddress(args[0]))
+
+			val, has, err := osm.Peep(context.Background())
+			if err != nil {
+				return err
+			}
+
+			printOSMFeed(val, has)
+
+			return nil
+		},
+	}
+}
+
+func NewOSMHopCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "hop osm_address",
+		Args:  cobra.ExactArgs(1),
+		Short: "returns the hop value (minimum time between pokes)",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			srv, err := PrepareServices(opts)
+			if err != nil {
+				return err
+			}
+			osm := oracleGeth.NewOSM(srv.Client, ethereum.HexToAddress(args[0]))
+
+			hop, err := osm.Hop(context.Background())
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(hop.String())
+
+			return nil
+		},
+	}
+}
+
+func NewOSMZzzCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "zzz osm_address",
+		Args:  cobra.ExactArgs(1),
+		Short: "returns the zzz value (last poke time rounded down to the hop)",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			srv, err := PrepareServices(opts)
+			if err != nil {
+				return err
+			}
+			osm := oracleGeth.NewOSM(srv.Client, ethereum.HexToAddress(args[0]))
+
+			zzz, err := osm.Zzz(context.Background())
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(zzz.String())
+
+			return nil
+		},
+	}
+}
+
+func NewOSMPassCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pass osm_address",
+		Args:  cobra.ExactArgs(1),
+		Short: "returns true if the OSM can be poked",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			srv, err := PrepareServices(opts)
+			if err != nil {
+				return err
+			}
+			osm := oracleGeth.NewOSM(srv.Client, ethereum.HexToAddress(args[0]))
+
+			pass, err := osm.Pass(context.Background())
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(pass)
+
+			return nil
+		},
+	}
+}
+
+func NewOSMPokeCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "poke osm_address",
+		Args:  cobra.ExactArgs(1),
+		Short: "directly invokes poke method",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			srv, err := PrepareServices(opts)
+			if err != nil {
+				return err
+			}
+			osm := oracleGeth.NewOSM(srv.Client, ethereum.HexToAddress(args[0]))
+
+			tx, err := osm.Poke(context.Background(), true)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Transaction: %s\n", tx.String())
+
+			return nil
+		},
+	}
+}
+
+// printOSMFeed prints the price value and its validity flag, each in
+// a separate line.
+func printOSMFeed(val *big.Int, has bool) {
+	fmt.Println(val.String())
+	fmt.Println(has)
+}
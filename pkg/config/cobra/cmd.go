@@ -0,0 +1,85 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cobra
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema"
+)
+
+// ValidateFunc validates the config and returns all errors found.
+type ValidateFunc func() []error
+
+// NewConfigCmd returns the "config" command, shared by all applications.
+// The "validate" subcommand uses the given function to validate the config.
+// The "schema" subcommand prints the JSON Schema generated from the config
+// value, it is omitted if the config is nil.
+func NewConfigCmd(app string, config interface{}, validate ValidateFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Args:  cobra.ExactArgs(0),
+		Short: "Config related commands",
+		Long:  `Config related commands.`,
+	}
+	cmd.AddCommand(NewValidateCmd(validate))
+	if config != nil {
+		cmd.AddCommand(NewSchemaCmd(app, config))
+	}
+	return cmd
+}
+
+// NewSchemaCmd returns the "schema" command that prints the JSON Schema of
+// the config.
+func NewSchemaCmd(app string, config interface{}) *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Args:  cobra.ExactArgs(0),
+		Short: "Print the JSON Schema of the config file",
+		Long:  `Print the JSON Schema of the config file.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(schema.Generate(config, fmt.Sprintf("%s config", app)))
+		},
+	}
+}
+
+// NewValidateCmd returns the "validate" command that builds all services
+// from the config without starting them and prints all errors found.
+func NewValidateCmd(validate ValidateFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:     "validate",
+		Args:    cobra.ExactArgs(0),
+		Aliases: []string{"dry-run"},
+		Short:   "Validate the config file",
+		Long:    `Parse the config file and build all services without starting them. Prints all errors found.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			errs := validate()
+			for _, err := range errs {
+				fmt.Fprintln(cmd.ErrOrStderr(), err)
+			}
+			if len(errs) > 0 {
+				return fmt.Errorf("config is invalid, %d error(s) found", len(errs))
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "config is valid")
+			return nil
+		},
+	}
+}
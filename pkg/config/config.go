@@ -85,10 +85,10 @@ func Parse(out interface{}, config []byte) error {
 }
 
 // yamlReplaceEnvVars replaces recursively all environment variables in the
-// given YAML node.
+// given YAML node. All missing variables are reported as LineErrors.
 func yamlReplaceEnvVars(n *yaml.Node) error {
-	return yamlVisitScalarNodes(n, func(n *yaml.Node) error {
-		var err error
+	var errs Errors
+	err := yamlVisitScalarNodes(n, func(n *yaml.Node) error {
 		parsed := interpolate.Parse(n.Value)
 		if parsed.HasVars() {
 			n.Value = parsed.Interpolate(func(v interpolate.Variable) string {
@@ -97,7 +97,10 @@ func yamlReplaceEnvVars(n *yaml.Node) error {
 					if v.HasDefault {
 						return v.Default
 					}
-					err = fmt.Errorf("environment variable %s not set", v.Name)
+					errs = append(errs, LineError{
+						Line: n.Line,
+						Err:  fmt.Errorf("environment variable %s not set", v.Name),
+					})
 					return ""
 				}
 				return env
@@ -107,8 +110,15 @@ func yamlReplaceEnvVars(n *yaml.Node) error {
 			n.Style = 0
 			n.Tag = ""
 		}
-		return err
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 func yamlVisitScalarNodes(n *yaml.Node, fn func(n *yaml.Node) error) error {
@@ -0,0 +1,70 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package eip712
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/eip712"
+)
+
+var ErrInvalidEthereumAddress = errors.New("invalid ethereum address")
+
+// Domain is the EIP-712 domain used to sign and verify events. At least one
+// field must be set.
+type Domain struct {
+	Name              string `yaml:"name"`
+	Version           string `yaml:"version"`
+	ChainID           uint64 `yaml:"chainId"`
+	VerifyingContract string `yaml:"verifyingContract"`
+}
+
+func (c *Domain) Configure() (eip712.Domain, error) {
+	d := eip712.Domain{
+		Name:    c.Name,
+		Version: c.Version,
+		ChainID: c.ChainID,
+	}
+	if c.VerifyingContract != "" {
+		if !ethereum.IsHexAddress(c.VerifyingContract) {
+			return eip712.Domain{}, fmt.Errorf("eip712 config: %w: %s", ErrInvalidEthereumAddress, c.VerifyingContract)
+		}
+		d.VerifyingContract = ethereum.HexToAddress(c.VerifyingContract)
+	}
+	if err := d.Validate(); err != nil {
+		return eip712.Domain{}, fmt.Errorf("eip712 config: %w", err)
+	}
+	return d, nil
+}
+
+// Validate validates the config without building services. Returned errors
+// are config.PathErrors relative to the domain config.
+func (c *Domain) Validate() error {
+	var errs []error
+	if c.VerifyingContract != "" && !ethereum.IsHexAddress(c.VerifyingContract) {
+		errs = append(errs, config.WithPath(
+			fmt.Errorf("%w: %s", ErrInvalidEthereumAddress, c.VerifyingContract),
+			"verifyingContract",
+		))
+	}
+	if c.Name == "" && c.Version == "" && c.ChainID == 0 && c.VerifyingContract == "" {
+		errs = append(errs, eip712.ErrEmptyDomain)
+	}
+	return config.Join(errs...)
+}
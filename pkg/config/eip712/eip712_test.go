@@ -0,0 +1,72 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package eip712
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/eip712"
+)
+
+func TestDomain_Configure(t *testing.T) {
+	c := Domain{
+		Name:              "Lair",
+		Version:           "1",
+		ChainID:           1,
+		VerifyingContract: "0x2d800d93b065ce011af83f316cef9f0d005b0aa4",
+	}
+	d, err := c.Configure()
+	require.NoError(t, err)
+	assert.Equal(t, eip712.Domain{
+		Name:              "Lair",
+		Version:           "1",
+		ChainID:           1,
+		VerifyingContract: ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4"),
+	}, d)
+}
+
+func TestDomain_Validate(t *testing.T) {
+	tests := []struct {
+		domain Domain
+		err    error
+	}{
+		{domain: Domain{Name: "Lair"}},
+		{domain: Domain{ChainID: 1}},
+		{domain: Domain{VerifyingContract: "0x2d800d93b065ce011af83f316cef9f0d005b0aa4"}},
+		{domain: Domain{}, err: eip712.ErrEmptyDomain},
+		{domain: Domain{Name: "Lair", VerifyingContract: "abc"}, err: ErrInvalidEthereumAddress},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			if tt.err == nil {
+				assert.NoError(t, tt.domain.Validate())
+			} else {
+				assert.Error(t, tt.domain.Validate())
+			}
+			_, err := tt.domain.Configure()
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -16,16 +16,21 @@
 package ethereum
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcsplitter"
@@ -74,16 +79,55 @@ var ethClientFactory = func(
 }
 
 type Ethereum struct {
-	From            string      `yaml:"from"`
-	Keystore        string      `yaml:"keystore"`
-	Password        string      `yaml:"password"`
-	RPC             interface{} `yaml:"rpc"`
-	Timeout         int         `yaml:"timeout"`
-	GracefulTimeout int         `yaml:"gracefulTimeout"`
-	MaxBlocksBehind int         `yaml:"maxBlocksBehind"`
+	From            string       `yaml:"from"`
+	Keystore        string       `yaml:"keystore"`
+	Password        secret.Value `yaml:"password"`
+	RPC             interface{}  `yaml:"rpc"`
+	Timeout         int          `yaml:"timeout"`
+	GracefulTimeout int          `yaml:"gracefulTimeout"`
+	MaxBlocksBehind int          `yaml:"maxBlocksBehind"`
+	RemoteSigner    RemoteSigner `yaml:"remoteSigner"`
+}
+
+// RemoteSigner configures an external signing service. If the type is set,
+// the keystore and password fields are ignored and data is signed by the
+// remote service using the account from the "from" field.
+type RemoteSigner struct {
+	// Type is the API of the remote signer, "clef" or "web3signer".
+	Type                  string `yaml:"type"`
+	URL                   string `yaml:"url"`
+	Timeout               int    `yaml:"timeout"`
+	TLSServerName         string `yaml:"tlsServerName"`
+	TLSCertFile           string `yaml:"tlsCertFile"`
+	TLSKeyFile            string `yaml:"tlsKeyFile"`
+	TLSRootCAFile         string `yaml:"tlsRootCAFile"`
+	TLSInsecureSkipVerify bool   `yaml:"tlsInsecureSkipVerify"`
+}
+
+// JSONSchema implements the schema.Provider interface.
+func (RemoteSigner) JSONSchema() *schema.Schema {
+	s := schema.Struct(RemoteSigner{})
+	s.Properties["type"].Enum = []interface{}{"", string(geth.ClefAPI), string(geth.Web3SignerAPI)}
+	return s
+}
+
+// JSONSchema implements the schema.Provider interface. The RPC field may be
+// a single endpoint or a list of endpoints.
+func (Ethereum) JSONSchema() *schema.Schema {
+	s := schema.Struct(Ethereum{})
+	s.Properties["rpc"] = &schema.Schema{
+		OneOf: []*schema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &schema.Schema{Type: "string"}},
+		},
+	}
+	return s
 }
 
 func (c *Ethereum) ConfigureSigner() (ethereum.Signer, error) {
+	if c.RemoteSigner.Type != "" {
+		return c.configureRemoteSigner()
+	}
 	account, err := c.configureAccount()
 	if err != nil {
 		return nil, err
@@ -156,17 +200,71 @@ func (c *Ethereum) configureAccount() (*geth.Account, error) {
 	return account, nil
 }
 
-func (c *Ethereum) readAccountPassphrase(path string) (string, error) {
-	if path == "" {
+// readAccountPassphrase returns the account passphrase. The password field may
+// be a secret reference, otherwise it is a path to the password file.
+func (c *Ethereum) readAccountPassphrase(password secret.Value) (string, error) {
+	if password == "" {
 		return "", nil
 	}
-	passphrase, err := ioutil.ReadFile(path)
+	if password.IsReference() {
+		return password.Resolve()
+	}
+	passphrase, err := ioutil.ReadFile(string(password))
 	if err != nil {
 		return "", fmt.Errorf("failed to read Ethereum password file: %w", err)
 	}
 	return strings.TrimSuffix(string(passphrase), "\n"), nil
 }
 
+func (c *Ethereum) configureRemoteSigner() (ethereum.Signer, error) {
+	if c.From == "" {
+		return nil, errors.New("ethereum config: the from field is required when using a remote signer")
+	}
+	if c.RemoteSigner.Timeout < 0 {
+		return nil, errors.New("ethereum config: remoteSigner.timeout cannot be less than 0")
+	}
+	tlsConfig, err := c.RemoteSigner.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("ethereum config: %w", err)
+	}
+	signer, err := geth.NewRemoteSigner(geth.RemoteSignerConfig{
+		URL:        c.RemoteSigner.URL,
+		API:        geth.RemoteSignerAPI(c.RemoteSigner.Type),
+		Address:    ethereum.HexToAddress(c.From),
+		HTTPClient: &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+		Timeout:    time.Second * time.Duration(c.RemoteSigner.Timeout),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ethereum config: %w", err)
+	}
+	return signer, nil
+}
+
+func (c *RemoteSigner) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLSServerName != "" {
+		tlsConfig.ServerName = c.TLSServerName
+	}
+	if c.TLSCertFile != "" && c.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if c.TLSRootCAFile != "" {
+		caCert, err := os.ReadFile(c.TLSRootCAFile)
+		if err != nil {
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		tlsConfig.RootCAs = caCertPool
+	}
+	tlsConfig.InsecureSkipVerify = c.TLSInsecureSkipVerify //nolint:gosec
+	return tlsConfig, nil
+}
+
 func minimumRequiredResponses(endpoints int) int {
 	if endpoints < 2 {
 		return endpoints
@@ -16,14 +16,21 @@
 package ethereum
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 )
@@ -70,6 +77,104 @@ func TestEthereum_ConfigureSigner_WithPassword(t *testing.T) {
 	)
 }
 
+func TestEthereum_ConfigureSigner_WithSecretPassword(t *testing.T) {
+	config := Ethereum{
+		From:     "2d800d93b065ce011af83f316cef9f0d005b0aa4",
+		Keystore: "./testdata/keystore",
+		Password: "exec://cat ./testdata/2.pass",
+		RPC:      "",
+	}
+
+	signer, err := config.ConfigureSigner()
+	require.NoError(t, err)
+	assert.Equal(t, "0x2D800d93B065CE011Af83f316ceF9F0d005B0AA4", signer.Address().String())
+}
+
+// mockWeb3Signer returns a precomputed signature of the "test" message
+// for the 0x2d800d93b065ce011af83f316cef9f0d005b0aa4 address.
+type mockWeb3Signer struct{}
+
+func (mockWeb3Signer) Sign(_ ethereum.Address, _ hexutil.Bytes) (hexutil.Bytes, error) {
+	return hexutil.Decode("0x9c22c5f33a59a7e0d309e74ce2f448663d18d9d90b67de692a26134ba2f5cbb64826cbd408f8ce8f067205f36c614bb145c2cc1acc3902bd2d40cb1a0626a9361b")
+}
+
+func newMockRemoteSignerServer(t *testing.T) *httptest.Server {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("eth", mockWeb3Signer{}))
+	cert, err := tls.LoadX509KeyPair("./testdata/tls/server.crt", "./testdata/tls/server.key")
+	require.NoError(t, err)
+	caCert, err := os.ReadFile("./testdata/tls/ca.crt")
+	require.NoError(t, err)
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(caCert)
+	httpSrv := httptest.NewUnstartedServer(srv)
+	httpSrv.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    caCertPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	httpSrv.StartTLS()
+	return httpSrv
+}
+
+func TestEthereum_ConfigureSigner_RemoteSigner(t *testing.T) {
+	srv := newMockRemoteSignerServer(t)
+	defer srv.Close()
+
+	config := Ethereum{
+		From: "2d800d93b065ce011af83f316cef9f0d005b0aa4",
+		RemoteSigner: RemoteSigner{
+			Type:          "web3signer",
+			URL:           srv.URL,
+			TLSCertFile:   "./testdata/tls/client.crt",
+			TLSKeyFile:    "./testdata/tls/client.key",
+			TLSRootCAFile: "./testdata/tls/ca.crt",
+		},
+	}
+
+	signer, err := config.ConfigureSigner()
+	require.NoError(t, err)
+
+	signature, err := signer.Signature([]byte("test"))
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		"9c22c5f33a59a7e0d309e74ce2f448663d18d9d90b67de692a26134ba2f5cbb64826cbd408f8ce8f067205f36c614bb145c2cc1acc3902bd2d40cb1a0626a9361b",
+		hex.EncodeToString(signature.Bytes()),
+	)
+}
+
+func TestEthereum_ConfigureSigner_RemoteSignerWithoutClientCert(t *testing.T) {
+	srv := newMockRemoteSignerServer(t)
+	defer srv.Close()
+
+	config := Ethereum{
+		From: "2d800d93b065ce011af83f316cef9f0d005b0aa4",
+		RemoteSigner: RemoteSigner{
+			Type:          "web3signer",
+			URL:           srv.URL,
+			TLSRootCAFile: "./testdata/tls/ca.crt",
+		},
+	}
+
+	signer, err := config.ConfigureSigner()
+	require.NoError(t, err)
+
+	_, err = signer.Signature([]byte("test"))
+	assert.Error(t, err)
+}
+
+func TestEthereum_ConfigureSigner_RemoteSignerWithoutFrom(t *testing.T) {
+	config := Ethereum{
+		RemoteSigner: RemoteSigner{Type: "clef", URL: "http://localhost:8550"},
+	}
+
+	_, err := config.ConfigureSigner()
+	assert.Error(t, err)
+}
+
 func TestEthereum_ConfigureEthereumClient(t *testing.T) {
 	prevEthClientFactory := ethClientFactory
 	defer func() { ethClientFactory = prevEthClientFactory }()
@@ -116,3 +221,7 @@ func TestEthereum_ConfigureEthereumClientWithMultipleEndpoints(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, client)
 }
+
+func TestEthereum_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Ethereum{}, "ethereum", "testdata/schema.json")
+}
@@ -17,13 +17,20 @@ package eventapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	eip712Config "github.com/chronicleprotocol/oracle-suite/pkg/config/eip712"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/store"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/api"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/eip712"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/store/file"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/store/redis"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
@@ -39,38 +46,64 @@ var eventAPIFactory = func(cfg api.Config) (*api.EventAPI, error) {
 type EventAPI struct {
 	ListenAddr string  `yaml:"listenAddr"`
 	Storage    storage `yaml:"storage"`
+	// EventTypes is a list of additional event types to store, e.g. types
+	// of events published by the EVM log listeners.
+	EventTypes []string `yaml:"eventTypes"`
+	// Quorum is the number of feeds required to consider an event as attested
+	// in the v2 API. If zero, the majority of feeds is required.
+	Quorum int `yaml:"quorum"`
+	// Webhooks is a list of HTTP endpoints notified about new events.
+	Webhooks []webhook `yaml:"webhooks"`
+	// EIP712 is the EIP-712 domain used to verify event signatures. If not
+	// set, EIP-712 signatures are rejected.
+	EIP712 *eip712Config.Domain `yaml:"eip712"`
+}
+
+type webhook struct {
+	URL        string       `yaml:"url"`
+	Secret     secret.Value `yaml:"secret"`
+	EventTypes []string     `yaml:"eventTypes"`
+	Attested   bool         `yaml:"attested"`
 }
 
 type storage struct {
 	Type   string        `yaml:"type"`
 	Memory storageMemory `yaml:"memory"`
 	Redis  storageRedis  `yaml:"redis"`
+	File   storageFile   `yaml:"file"`
 }
 
 type storageMemory struct {
 	TTL int `yaml:"ttl"`
 }
 
+type storageFile struct {
+	TTL         int    `yaml:"ttl"`
+	Path        string `yaml:"path"`
+	MemoryLimit int64  `yaml:"memoryLimit"`
+}
+
 type storageRedis struct {
-	TTL                   int      `yaml:"ttl"`
-	Address               string   `yaml:"address"`
-	Username              string   `yaml:"username"`
-	Password              string   `yaml:"password"`
-	DB                    int      `yaml:"db"`
-	MemoryLimit           int64    `yaml:"memoryLimit"`
-	TLS                   bool     `yaml:"tls"`
-	TLSServerName         string   `yaml:"tlsServerName"`
-	TLSCertFile           string   `yaml:"tlsCertFile"`
-	TLSKeyFile            string   `yaml:"tlsKeyFile"`
-	TLSRootCAFile         string   `yaml:"tlsRootCAFile"`
-	TLSInsecureSkipVerify bool     `yaml:"tlsInsecureSkipVerify"`
-	Cluster               bool     `yaml:"cluster"`
-	ClusterAddresses      []string `yaml:"clusterAddresses"`
+	TTL                   int          `yaml:"ttl"`
+	Address               string       `yaml:"address"`
+	Username              string       `yaml:"username"`
+	Password              secret.Value `yaml:"password"`
+	DB                    int          `yaml:"db"`
+	MemoryLimit           int64        `yaml:"memoryLimit"`
+	TLS                   bool         `yaml:"tls"`
+	TLSServerName         string       `yaml:"tlsServerName"`
+	TLSCertFile           string       `yaml:"tlsCertFile"`
+	TLSKeyFile            string       `yaml:"tlsKeyFile"`
+	TLSRootCAFile         string       `yaml:"tlsRootCAFile"`
+	TLSInsecureSkipVerify bool         `yaml:"tlsInsecureSkipVerify"`
+	Cluster               bool         `yaml:"cluster"`
+	ClusterAddresses      []string     `yaml:"clusterAddresses"`
 }
 
 type Dependencies struct {
 	EventStore *store.EventStore
 	Transport  transport.Transport
+	Feeds      []ethereum.Address
 	Logger     log.Logger
 }
 
@@ -82,13 +115,65 @@ type DatastoreDependencies struct {
 }
 
 func (c *EventAPI) Configure(d Dependencies) (*api.EventAPI, error) {
+	var webhooks []api.Webhook
+	for _, w := range c.Webhooks {
+		key, err := w.Secret.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("eventapi config: %w", err)
+		}
+		webhooks = append(webhooks, api.Webhook{
+			URL:        w.URL,
+			Secret:     []byte(key),
+			EventTypes: w.EventTypes,
+			Attested:   w.Attested,
+		})
+	}
 	return eventAPIFactory(api.Config{
 		EventStore: d.EventStore,
 		Address:    c.ListenAddr,
+		Signers:    d.Feeds,
+		Quorum:     c.Quorum,
+		Webhooks:   webhooks,
 		Logger:     d.Logger,
 	})
 }
 
+// Validate validates the config without building services.
+func (c *EventAPI) Validate() error {
+	var errs []error
+	if c.Storage.Type == "file" && c.Storage.File.Path == "" {
+		errs = append(errs, config.WithPath(errors.New("path must be set"), "storage", "file", "path"))
+	}
+	if c.Quorum < 0 {
+		errs = append(errs, config.WithPath(errors.New("quorum must not be negative"), "quorum"))
+	}
+	for i, w := range c.Webhooks {
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, config.WithPath(errors.New("url must be a valid HTTP or HTTPS address"), "webhooks", i, "url"))
+		}
+		if w.Secret == "" {
+			errs = append(errs, config.WithPath(errors.New("secret must be set"), "webhooks", i, "secret"))
+		}
+	}
+	if c.EIP712 != nil {
+		errs = append(errs, config.WithPath(c.EIP712.Validate(), "eip712"))
+	}
+	return config.Join(errs...)
+}
+
+// ConfigureDomain returns the EIP-712 domain used to verify event
+// signatures, or nil if it is not configured.
+func (c *EventAPI) ConfigureDomain() (*eip712.Domain, error) {
+	if c.EIP712 == nil {
+		return nil, nil
+	}
+	d, err := c.EIP712.Configure()
+	if err != nil {
+		return nil, fmt.Errorf("eventapi config: %w", err)
+	}
+	return &d, nil
+}
+
 func (c *EventAPI) ConfigureStorage() (store.Storage, error) {
 	switch c.Storage.Type {
 	case "memory", "":
@@ -102,11 +187,15 @@ func (c *EventAPI) ConfigureStorage() (store.Storage, error) {
 		if c.Storage.Redis.TTL > 0 {
 			ttl = c.Storage.Redis.TTL
 		}
+		password, err := c.Storage.Redis.Password.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("eventapi config: %w", err)
+		}
 		r, err := redis.New(redis.Config{
 			TTL:                   time.Duration(ttl) * time.Second,
 			Address:               c.Storage.Redis.Address,
 			Username:              c.Storage.Redis.Username,
-			Password:              c.Storage.Redis.Password,
+			Password:              password,
 			DB:                    c.Storage.Redis.DB,
 			MemoryLimit:           c.Storage.Redis.MemoryLimit,
 			TLS:                   c.Storage.Redis.TLS,
@@ -125,7 +214,21 @@ func (c *EventAPI) ConfigureStorage() (store.Storage, error) {
 			return nil, fmt.Errorf(`eventapi config: unable to connect to the Redis server: %w`, err)
 		}
 		return r, nil
+	case "file":
+		ttl := week
+		if c.Storage.File.TTL > 0 {
+			ttl = c.Storage.File.TTL
+		}
+		f, err := file.New(file.Config{
+			Path:        c.Storage.File.Path,
+			TTL:         time.Duration(ttl) * time.Second,
+			MemoryLimit: c.Storage.File.MemoryLimit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("eventapi config: unable to initialize file storage: %w", err)
+		}
+		return f, nil
 	default:
-		return nil, fmt.Errorf(`eventapi config: storage type must be "memory", "redis" or empty to use default one`)
+		return nil, fmt.Errorf(`eventapi config: storage type must be "memory", "redis", "file" or empty to use default one`)
 	}
 }
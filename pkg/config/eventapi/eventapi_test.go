@@ -17,14 +17,20 @@ package eventapi
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	eip712Config "github.com/chronicleprotocol/oracle-suite/pkg/config/eip712"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/api"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/store"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/store/file"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/store/redis"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
@@ -38,13 +44,30 @@ func TestEventAPI_Configure(t *testing.T) {
 	log := null.New()
 	evs := &store.EventStore{}
 
+	fed := []ethereum.Address{ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")}
+
 	config := EventAPI{
 		ListenAddr: "127.0.0.1:0",
+		Quorum:     1,
+		Webhooks: []webhook{{
+			URL:        "https://example.com/hook",
+			Secret:     "secret",
+			EventTypes: []string{"teleport_evm"},
+			Attested:   true,
+		}},
 	}
 
 	eventAPIFactory = func(cfg api.Config) (*api.EventAPI, error) {
 		assert.Equal(t, evs, cfg.EventStore)
 		assert.Equal(t, config.ListenAddr, cfg.Address)
+		assert.Equal(t, fed, cfg.Signers)
+		assert.Equal(t, config.Quorum, cfg.Quorum)
+		assert.Equal(t, []api.Webhook{{
+			URL:        "https://example.com/hook",
+			Secret:     []byte("secret"),
+			EventTypes: []string{"teleport_evm"},
+			Attested:   true,
+		}}, cfg.Webhooks)
 		assert.Equal(t, log, cfg.Logger)
 		return &api.EventAPI{}, nil
 	}
@@ -52,12 +75,24 @@ func TestEventAPI_Configure(t *testing.T) {
 	a, err := config.Configure(Dependencies{
 		EventStore: evs,
 		Transport:  tra,
+		Feeds:      fed,
 		Logger:     log,
 	})
 	require.NoError(t, err)
 	assert.NotNil(t, a)
 }
 
+func TestEventAPI_Validate(t *testing.T) {
+	assert.NoError(t, (&EventAPI{Quorum: 2}).Validate())
+	assert.Error(t, (&EventAPI{Quorum: -1}).Validate())
+	assert.NoError(t, (&EventAPI{Webhooks: []webhook{{URL: "http://localhost:8080", Secret: "secret"}}}).Validate())
+	assert.Error(t, (&EventAPI{Webhooks: []webhook{{URL: "localhost:8080", Secret: "secret"}}}).Validate())
+	assert.Error(t, (&EventAPI{Webhooks: []webhook{{URL: "http://localhost:8080"}}}).Validate())
+	assert.Error(t, (&EventAPI{Storage: storage{Type: "file"}}).Validate())
+	assert.NoError(t, (&EventAPI{EIP712: &eip712Config.Domain{Name: "Lair"}}).Validate())
+	assert.Error(t, (&EventAPI{EIP712: &eip712Config.Domain{}}).Validate())
+}
+
 func TestEventAPI_ConfigureStorage_memory(t *testing.T) {
 	config := EventAPI{
 		Storage: storage{
@@ -69,6 +104,21 @@ func TestEventAPI_ConfigureStorage_memory(t *testing.T) {
 	require.IsType(t, &store.MemoryStorage{}, sto)
 }
 
+func TestEventAPI_ConfigureStorage_file(t *testing.T) {
+	config := EventAPI{
+		Storage: storage{
+			Type: "file",
+			File: storageFile{
+				Path: filepath.Join(t.TempDir(), "events"),
+			},
+		},
+	}
+	sto, err := config.ConfigureStorage()
+	require.NoError(t, err)
+	require.IsType(t, &file.Storage{}, sto)
+	require.NoError(t, sto.(*file.Storage).Close())
+}
+
 func TestEventAPI_ConfigureStorage_redis(t *testing.T) {
 	addr := os.Getenv("TEST_REDIS_ADDR")
 	pass := os.Getenv("TEST_REDIS_PASS")
@@ -83,7 +133,7 @@ func TestEventAPI_ConfigureStorage_redis(t *testing.T) {
 			Redis: storageRedis{
 				TTL:      60,
 				Address:  addr,
-				Password: pass,
+				Password: secret.Value(pass),
 				DB:       db,
 			},
 		},
@@ -109,3 +159,7 @@ func TestEventAPI_ConfigureStorage_defaultType(t *testing.T) {
 	require.NoError(t, err)
 	require.IsType(t, &store.MemoryStorage{}, sto)
 }
+
+func TestEventAPI_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, EventAPI{}, "eventapi", "testdata/schema.json")
+}
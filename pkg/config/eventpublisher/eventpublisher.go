@@ -17,6 +17,7 @@ package eventpublisher
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -25,9 +26,16 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcclient"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	eip712Config "github.com/chronicleprotocol/oracle-suite/pkg/config/eip712"
 	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/arbitrum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/checkpoint"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/evmlog"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/optimism"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/replayer"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportevm"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportstarknet"
@@ -42,12 +50,40 @@ var eventPublisherFactory = func(cfg publisher.Config) (*publisher.EventPublishe
 }
 
 type EventPublisher struct {
-	Listeners listeners `yaml:"listeners"`
+	Listeners  listeners       `yaml:"listeners"`
+	Checkpoint checkpointStore `yaml:"checkpoint"`
+	// EIP712 is the EIP-712 domain used to sign events. If set, events are
+	// signed as EIP-712 typed data instead of using the raw event hash.
+	EIP712 *eip712Config.Domain `yaml:"eip712"`
+}
+
+type checkpointStore struct {
+	// Type is the checkpoint store type, "file" or empty to disable
+	// checkpoints.
+	Type string         `yaml:"type"`
+	File checkpointFile `yaml:"file"`
+}
+
+type checkpointFile struct {
+	Path string `yaml:"path"`
+}
+
+// JSONSchema implements the schema.Provider interface.
+func (checkpointStore) JSONSchema() *schema.Schema {
+	s := schema.Struct(checkpointStore{})
+	s.Properties["type"].Enum = []interface{}{"", "file"}
+	return s
 }
 
 type listeners struct {
 	TeleportEVM      []teleportEVMListener      `yaml:"teleportEVM"`
 	TeleportStarknet []teleportStarknetListener `yaml:"teleportStarknet"`
+	EVMLog           []evmLogListener           `yaml:"evmLog"`
+	// Optimism and Arbitrum listeners accept the same options as TeleportEVM
+	// listeners. If addresses are empty, the messenger predeploy and the
+	// ArbSys precompile addresses are used.
+	Optimism []teleportEVMListener `yaml:"optimism"`
+	Arbitrum []teleportEVMListener `yaml:"arbitrum"`
 }
 
 type teleportEVMListener struct {
@@ -58,14 +94,69 @@ type teleportEVMListener struct {
 	BlockLimit         int                     `yaml:"blockLimit"`
 	ReplayAfter        []int64                 `yaml:"replayAfter"`
 	Addresses          []types.Address         `yaml:"addresses"`
+	// Chain identifies the chain in checkpoint keys. If empty, the chain ID
+	// reported by the node is used.
+	Chain string `yaml:"chain"`
+}
+
+type evmLogListener struct {
+	teleportEVMListener `yaml:",inline"`
+	// EventType is the type of created event messages.
+	EventType string `yaml:"eventType"`
+	// ABI is a JSON ABI containing the event, as a string, an object or an
+	// array.
+	ABI            interface{} `yaml:"abi"`
+	EventName      string      `yaml:"eventName"`
+	IndexFields    []string    `yaml:"indexFields"`
+	HashFields     []string    `yaml:"hashFields"`
+	TimestampField string      `yaml:"timestampField"`
+}
+
+// JSONSchema implements the schema.Provider interface. The ABI field may be
+// a JSON string, an object or an array.
+func (evmLogListener) JSONSchema() *schema.Schema {
+	s := schema.Struct(evmLogListener{})
+	s.Properties["abi"] = &schema.Schema{
+		OneOf: []*schema.Schema{
+			{Type: "string"},
+			{Type: "object"},
+			{Type: "array"},
+		},
+	}
+	return s
+}
+
+// abiJSON returns the ABI as a JSON string.
+func (l evmLogListener) abiJSON() (string, error) {
+	switch v := l.ABI.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	return "", errors.New("value of the abi key must be a string, an object or an array")
 }
 
 type teleportStarknetListener struct {
-	Sequencer      string                 `yaml:"sequencer"`
+	// Sequencer is the feeder gateway address. Either the sequencer or
+	// the RPC address must be set.
+	Sequencer string `yaml:"sequencer"`
+	// RPC is the JSON-RPC endpoint of a Starknet node.
+	RPC            string                 `yaml:"rpc"`
 	Interval       int64                  `yaml:"interval"`
 	PrefetchPeriod int64                  `yaml:"prefetchPeriod"`
 	ReplayAfter    []int64                `yaml:"replayAfter"`
 	Addresses      []*starknetClient.Felt `yaml:"addresses"`
+	// EventKeys is an optional list of event keys used to filter events
+	// fetched using the JSON-RPC API.
+	EventKeys []*starknetClient.Felt `yaml:"eventKeys"`
+	// Chain identifies the chain in checkpoint keys. If empty, the host
+	// of the RPC or the sequencer address is used.
+	Chain string `yaml:"chain"`
 }
 
 type Dependencies struct {
@@ -84,20 +175,34 @@ func (c *EventPublisher) Configure(d Dependencies) (*publisher.EventPublisher, e
 	if d.Logger == nil {
 		return nil, fmt.Errorf("eventpublisher config: logger cannot be nil")
 	}
+	cps, err := c.configureCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("eventpublisher config: %w", err)
+	}
 	var eps []publisher.EventProvider
-	if err := c.configureTeleportEVM(&eps, d.Logger); err != nil {
+	clients := ethClients{}
+	if err := c.configureTeleportEVM(&eps, clients, cps, d.Logger); err != nil {
 		return nil, fmt.Errorf("eventpublisher config: teleport EVM: %w", err)
 	}
-	if err := c.configureTeleportStarknet(&eps, d.Logger); err != nil {
+	if err := c.configureTeleportStarknet(&eps, cps, d.Logger); err != nil {
 		return nil, fmt.Errorf("eventpublisher config: teleport Starknet: %w", err)
 	}
-	signer := []publisher.EventSigner{teleportevm.NewSigner(d.Signer, []string{
-		teleportevm.TeleportEventType,
-		teleportstarknet.TeleportEventType,
-	})}
+	if err := c.configureEVMLog(&eps, clients, cps, d.Logger); err != nil {
+		return nil, fmt.Errorf("eventpublisher config: EVM log: %w", err)
+	}
+	if err := configureBridge(&eps, clients, c.Listeners.Optimism, optimism.NewConverter(), optimism.L2CrossDomainMessenger, cps, d.Logger); err != nil {
+		return nil, fmt.Errorf("eventpublisher config: Optimism: %w", err)
+	}
+	if err := configureBridge(&eps, clients, c.Listeners.Arbitrum, arbitrum.NewConverter(), arbitrum.ArbSys, cps, d.Logger); err != nil {
+		return nil, fmt.Errorf("eventpublisher config: Arbitrum: %w", err)
+	}
+	signer, err := c.configureSigner(d.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("eventpublisher config: %w", err)
+	}
 	cfg := publisher.Config{
 		Providers: eps,
-		Signers:   signer,
+		Signers:   []publisher.EventSigner{signer},
 		Transport: d.Transport,
 		Logger:    d.Logger,
 	}
@@ -108,44 +213,166 @@ func (c *EventPublisher) Configure(d Dependencies) (*publisher.EventPublisher, e
 	return ep, nil
 }
 
-func (c *EventPublisher) configureTeleportEVM(lis *[]publisher.EventProvider, logger log.Logger) error {
-	clients := ethClients{}
+// EventTypes returns types of events published by configured listeners.
+func (c *EventPublisher) EventTypes() []string {
+	types := []string{
+		teleportevm.TeleportEventType,
+		teleportstarknet.TeleportEventType,
+		optimism.SentMessageEventType,
+		arbitrum.L2ToL1TxEventType,
+	}
+	for _, cfg := range c.Listeners.EVMLog {
+		types = append(types, cfg.EventType)
+	}
+	return types
+}
+
+// Validate validates the config without building services.
+func (c *EventPublisher) Validate() error {
+	var errs []error
+	switch c.Checkpoint.Type {
+	case "":
+	case "file":
+		if c.Checkpoint.File.Path == "" {
+			errs = append(errs, config.WithPath(errors.New("path must be set"), "checkpoint", "file", "path"))
+		}
+	default:
+		errs = append(errs, config.WithPath(
+			fmt.Errorf(`unknown type %q, must be "file" or empty`, c.Checkpoint.Type),
+			"checkpoint", "type",
+		))
+	}
+	seen := map[string]bool{
+		teleportevm.TeleportEventType:      true,
+		teleportstarknet.TeleportEventType: true,
+		optimism.SentMessageEventType:      true,
+		arbitrum.L2ToL1TxEventType:         true,
+	}
+	for i, cfg := range c.Listeners.EVMLog {
+		switch {
+		case cfg.EventType == "":
+			errs = append(errs, config.WithPath(errors.New("event type must be set"), "listeners", "evmLog", i, "eventType"))
+		case seen[cfg.EventType]:
+			errs = append(errs, config.WithPath(
+				fmt.Errorf("event type %q is already used", cfg.EventType),
+				"listeners", "evmLog", i, "eventType",
+			))
+		}
+		seen[cfg.EventType] = true
+		if _, err := cfg.abiJSON(); err != nil {
+			errs = append(errs, config.WithPath(err, "listeners", "evmLog", i, "abi"))
+		}
+	}
+	for i, cfg := range c.Listeners.TeleportStarknet {
+		switch {
+		case cfg.Sequencer == "" && cfg.RPC == "":
+			errs = append(errs, config.WithPath(errors.New("either sequencer or rpc must be set"), "listeners", "teleportStarknet", i))
+		case cfg.Sequencer != "" && cfg.RPC != "":
+			errs = append(errs, config.WithPath(errors.New("only one of sequencer or rpc may be set"), "listeners", "teleportStarknet", i))
+		}
+	}
+	if c.EIP712 != nil {
+		errs = append(errs, config.WithPath(c.EIP712.Validate(), "eip712"))
+	}
+	return config.Join(errs...)
+}
+
+// configureSigner returns the event signer. If the EIP-712 domain is
+// configured, events are signed as EIP-712 typed data.
+func (c *EventPublisher) configureSigner(signer ethereum.Signer) (*teleportevm.Signer, error) {
+	if c.EIP712 == nil {
+		return teleportevm.NewSigner(signer, c.EventTypes()), nil
+	}
+	domain, err := c.EIP712.Configure()
+	if err != nil {
+		return nil, err
+	}
+	return teleportevm.NewEIP712Signer(signer, c.EventTypes(), domain)
+}
+
+// configureCheckpoint returns the checkpoint store, or nil if checkpoints
+// are disabled.
+func (c *EventPublisher) configureCheckpoint() (checkpoint.Store, error) {
+	switch c.Checkpoint.Type {
+	case "":
+		return nil, nil
+	case "file":
+		s, err := checkpoint.NewFileStore(c.Checkpoint.File.Path)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf(`checkpoint type must be "file" or empty to disable checkpoints`)
+	}
+}
+
+func (c *EventPublisher) configureTeleportEVM(
+	lis *[]publisher.EventProvider,
+	clients ethClients,
+	cps checkpoint.Store,
+	logger log.Logger,
+) error {
+
 	for _, cfg := range c.Listeners.TeleportEVM {
-		client, err := clients.configure(cfg.Ethereum, logger)
+		ep, err := cfg.configure(clients, nil, cps, logger)
 		if err != nil {
 			return err
 		}
-		interval := cfg.Interval
-		if interval < 1 {
-			interval = 1
-		}
-		if cfg.BlockLimit == 0 {
-			cfg.BlockLimit = 1000
-		}
-		replayAfter := make([]time.Duration, len(cfg.ReplayAfter))
-		for i, r := range cfg.ReplayAfter {
-			replayAfter[i] = time.Duration(r) * time.Second
+		*lis = append(*lis, ep)
+	}
+	return nil
+}
+
+func (c *EventPublisher) configureEVMLog(
+	lis *[]publisher.EventProvider,
+	clients ethClients,
+	cps checkpoint.Store,
+	logger log.Logger,
+) error {
+
+	for _, cfg := range c.Listeners.EVMLog {
+		abiJSON, err := cfg.abiJSON()
+		if err != nil {
+			return err
 		}
-		var ep publisher.EventProvider
-		ep, err = teleportevm.New(teleportevm.Config{
-			Client:             client,
-			Addresses:          cfg.Addresses,
-			Interval:           time.Second * time.Duration(interval),
-			PrefetchPeriod:     time.Duration(cfg.PrefetchPeriod) * time.Second,
-			BlockLimit:         uint64(cfg.BlockLimit),
-			BlockConfirmations: uint64(cfg.BlockConfirmations),
-			Logger:             logger,
+		conv, err := evmlog.NewConverter(evmlog.Config{
+			EventType:      cfg.EventType,
+			ABI:            abiJSON,
+			EventName:      cfg.EventName,
+			IndexFields:    cfg.IndexFields,
+			HashFields:     cfg.HashFields,
+			TimestampField: cfg.TimestampField,
 		})
 		if err != nil {
 			return err
 		}
-		if len(cfg.ReplayAfter) > 0 {
-			ep, err = replayer.New(replayer.Config{
-				EventProvider: ep,
-				Interval:      time.Minute,
-				ReplayAfter:   replayAfter,
-			})
+		ep, err := cfg.configure(clients, conv, cps, logger)
+		if err != nil {
+			return err
+		}
+		*lis = append(*lis, ep)
+	}
+	return nil
+}
+
+// configureBridge configures listeners of native L2 message bridges. If
+// a listener has no addresses, the default address is used.
+func configureBridge(
+	lis *[]publisher.EventProvider,
+	clients ethClients,
+	listeners []teleportEVMListener,
+	conv teleportevm.LogConverter,
+	defaultAddress types.Address,
+	cps checkpoint.Store,
+	logger log.Logger,
+) error {
+
+	for _, cfg := range listeners {
+		if len(cfg.Addresses) == 0 {
+			cfg.Addresses = []types.Address{defaultAddress}
 		}
+		ep, err := cfg.configure(clients, conv, cps, logger)
 		if err != nil {
 			return err
 		}
@@ -154,15 +381,77 @@ func (c *EventPublisher) configureTeleportEVM(lis *[]publisher.EventProvider, lo
 	return nil
 }
 
-func (c *EventPublisher) configureTeleportStarknet(lis *[]publisher.EventProvider, logger log.Logger) error {
-	var err error
+// configure returns an event provider for the listener. If the converter
+// is nil, TeleportGUID events are used.
+func (cfg teleportEVMListener) configure(
+	clients ethClients,
+	conv teleportevm.LogConverter,
+	cps checkpoint.Store,
+	logger log.Logger,
+) (publisher.EventProvider, error) {
+
+	client, err := clients.configure(cfg.Ethereum, logger)
+	if err != nil {
+		return nil, err
+	}
+	interval := cfg.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	if cfg.BlockLimit == 0 {
+		cfg.BlockLimit = 1000
+	}
+	replayAfter := make([]time.Duration, len(cfg.ReplayAfter))
+	for i, r := range cfg.ReplayAfter {
+		replayAfter[i] = time.Duration(r) * time.Second
+	}
+	var ep publisher.EventProvider
+	ep, err = teleportevm.New(teleportevm.Config{
+		Client:             client,
+		Addresses:          cfg.Addresses,
+		Converter:          conv,
+		Interval:           time.Second * time.Duration(interval),
+		PrefetchPeriod:     time.Duration(cfg.PrefetchPeriod) * time.Second,
+		Checkpoint:         cps,
+		Chain:              cfg.Chain,
+		BlockLimit:         uint64(cfg.BlockLimit),
+		BlockConfirmations: uint64(cfg.BlockConfirmations),
+		Logger:             logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.ReplayAfter) > 0 {
+		ep, err = replayer.New(replayer.Config{
+			EventProvider: ep,
+			Interval:      time.Minute,
+			ReplayAfter:   replayAfter,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+func (c *EventPublisher) configureTeleportStarknet(
+	lis *[]publisher.EventProvider,
+	cps checkpoint.Store,
+	logger log.Logger,
+) error {
+
 	for _, cfg := range c.Listeners.TeleportStarknet {
 		interval := cfg.Interval
 		if interval < 1 {
 			interval = 1
 		}
-		if _, err := url.Parse(cfg.Sequencer); err != nil {
-			return fmt.Errorf("sequencer url is invalid: %w", err)
+		seq, err := cfg.sequencer()
+		if err != nil {
+			return err
+		}
+		chain, err := cfg.chain()
+		if err != nil {
+			return err
 		}
 		replayAfter := make([]time.Duration, len(cfg.ReplayAfter))
 		for i, r := range cfg.ReplayAfter {
@@ -170,10 +459,12 @@ func (c *EventPublisher) configureTeleportStarknet(lis *[]publisher.EventProvide
 		}
 		var ep publisher.EventProvider
 		ep, err = teleportstarknet.New(teleportstarknet.Config{
-			Sequencer:      starknetClient.NewSequencer(cfg.Sequencer, http.Client{}),
+			Sequencer:      seq,
 			Addresses:      cfg.Addresses,
 			Interval:       time.Second * time.Duration(interval),
 			PrefetchPeriod: time.Duration(cfg.PrefetchPeriod) * time.Second,
+			Checkpoint:     cps,
+			Chain:          chain,
 			Logger:         logger,
 		})
 		if err != nil {
@@ -194,6 +485,41 @@ func (c *EventPublisher) configureTeleportStarknet(lis *[]publisher.EventProvide
 	return nil
 }
 
+// sequencer returns the Starknet sequencer. If the RPC address is set, the
+// JSON-RPC API is used, otherwise the feeder gateway is used.
+func (cfg teleportStarknetListener) sequencer() (teleportstarknet.Sequencer, error) {
+	if cfg.RPC != "" {
+		if _, err := url.Parse(cfg.RPC); err != nil {
+			return nil, fmt.Errorf("rpc url is invalid: %w", err)
+		}
+		rpc, err := starknetClient.NewRPC(starknetClient.RPCConfig{Endpoint: cfg.RPC})
+		if err != nil {
+			return nil, err
+		}
+		return starknetClient.NewRPCSequencer(rpc, cfg.Addresses, cfg.EventKeys), nil
+	}
+	if _, err := url.Parse(cfg.Sequencer); err != nil {
+		return nil, fmt.Errorf("sequencer url is invalid: %w", err)
+	}
+	return starknetClient.NewSequencer(cfg.Sequencer, http.Client{}), nil
+}
+
+// chain returns the name of the chain used in checkpoint keys.
+func (cfg teleportStarknetListener) chain() (string, error) {
+	if cfg.Chain != "" {
+		return cfg.Chain, nil
+	}
+	endpoint := cfg.Sequencer
+	if cfg.RPC != "" {
+		endpoint = cfg.RPC
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("unable to determine chain: %w", err)
+	}
+	return u.Host, nil
+}
+
 type ethClients map[string]*rpcclient.Client
 
 // configure returns an Ethereum client for given configuration.
@@ -17,16 +17,23 @@ package eventpublisher
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	eip712Config "github.com/chronicleprotocol/oracle-suite/pkg/config/eip712"
 	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/checkpoint"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/starknet"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
 )
 
@@ -66,6 +73,223 @@ func TestEventPublisher_Configure_Teleport(t *testing.T) {
 	require.NotNil(t, ep)
 }
 
+func TestEventPublisher_Configure_Bridges(t *testing.T) {
+	prevEventPublisherFactory := eventPublisherFactory
+	defer func() { eventPublisherFactory = prevEventPublisherFactory }()
+
+	tra := local.New([]byte("test"), 0, nil)
+	_ = tra.Start(context.Background())
+
+	var c struct {
+		EventPublisher EventPublisher `yaml:"eventPublisher"`
+	}
+	require.NoError(t, config.Parse(&c, []byte(`
+eventPublisher:
+  listeners:
+    optimism:
+      - ethereum:
+          rpc: https://example.com/optimism
+        interval: 1
+        replayAfter: [60]
+    arbitrum:
+      - ethereum:
+          rpc: https://example.com/arbitrum
+        interval: 1
+        addresses: ["0x0000000000000000000000000000000000000064"]
+`)))
+	require.NoError(t, c.EventPublisher.Validate())
+
+	eventPublisherFactory = func(cfg publisher.Config) (*publisher.EventPublisher, error) {
+		assert.Len(t, cfg.Providers, 2)
+		return &publisher.EventPublisher{}, nil
+	}
+
+	ep, err := c.EventPublisher.Configure(Dependencies{
+		Signer:    geth.NewSigner(nil),
+		Transport: tra,
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, ep)
+}
+
+const testEventABI = `{
+  "anonymous": false,
+  "inputs": [
+    {"indexed": true, "name": "id", "type": "bytes32"},
+    {"indexed": false, "name": "amount", "type": "uint256"}
+  ],
+  "name": "MessageSent",
+  "type": "event"
+}`
+
+func TestEventPublisher_Configure_EVMLog(t *testing.T) {
+	prevEventPublisherFactory := eventPublisherFactory
+	defer func() { eventPublisherFactory = prevEventPublisherFactory }()
+
+	sig := geth.NewSigner(nil)
+	tra := local.New([]byte("test"), 0, nil)
+	_ = tra.Start(context.Background())
+
+	var c struct {
+		EventPublisher EventPublisher `yaml:"eventPublisher"`
+	}
+	require.NoError(t, config.Parse(&c, []byte(`
+eventPublisher:
+  listeners:
+    evmLog:
+      - eventType: message
+        ethereum:
+          rpc: https://example.com/
+        interval: 1
+        addresses: ["0x07a35a1d4b751a818d93aa38e615c0df23064881"]
+        abi: `+testEventABI+`
+        eventName: MessageSent
+        indexFields: [id]
+        hashFields: [id, amount]
+`)))
+	require.NoError(t, c.EventPublisher.Validate())
+	assert.Equal(
+		t,
+		[]string{"teleport_evm", "teleport_starknet", "optimism_sent_message", "arbitrum_l2_to_l1_tx", "message"},
+		c.EventPublisher.EventTypes(),
+	)
+
+	eventPublisherFactory = func(cfg publisher.Config) (*publisher.EventPublisher, error) {
+		assert.Len(t, cfg.Providers, 1)
+		assert.Len(t, cfg.Signers, 1)
+		return &publisher.EventPublisher{}, nil
+	}
+
+	ep, err := c.EventPublisher.Configure(Dependencies{
+		Signer:    sig,
+		Transport: tra,
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, ep)
+
+	// Invalid hash field:
+	c.EventPublisher.Listeners.EVMLog[0].HashFields = []string{"unknown"}
+	_, err = c.EventPublisher.Configure(Dependencies{
+		Signer:    sig,
+		Transport: tra,
+		Logger:    null.New(),
+	})
+	assert.Error(t, err)
+}
+
+func Test_teleportStarknetListener_sequencer(t *testing.T) {
+	seq, err := teleportStarknetListener{Sequencer: "https://example.com"}.sequencer()
+	require.NoError(t, err)
+	assert.IsType(t, &starknet.Sequencer{}, seq)
+
+	seq, err = teleportStarknetListener{RPC: "https://example.com"}.sequencer()
+	require.NoError(t, err)
+	assert.IsType(t, &starknet.RPCSequencer{}, seq)
+}
+
+func TestEventPublisher_configureSigner(t *testing.T) {
+	c := EventPublisher{}
+	sig, err := c.configureSigner(geth.NewSigner(nil))
+	require.NoError(t, err)
+	assert.NotNil(t, sig)
+
+	c.EIP712 = &eip712Config.Domain{Name: "Teleport", ChainID: 1}
+	sig, err = c.configureSigner(geth.NewSigner(nil))
+	require.NoError(t, err)
+	assert.NotNil(t, sig)
+
+	c.EIP712 = &eip712Config.Domain{VerifyingContract: "abc"}
+	_, err = c.configureSigner(geth.NewSigner(nil))
+	assert.Error(t, err)
+}
+
+func TestEventPublisher_configureCheckpoint(t *testing.T) {
+	c := EventPublisher{}
+	cps, err := c.configureCheckpoint()
+	require.NoError(t, err)
+	assert.Nil(t, cps)
+
+	c.Checkpoint.Type = "file"
+	c.Checkpoint.File.Path = filepath.Join(t.TempDir(), "checkpoints.json")
+	cps, err = c.configureCheckpoint()
+	require.NoError(t, err)
+	assert.IsType(t, &checkpoint.FileStore{}, cps)
+
+	c.Checkpoint.Type = "unknown"
+	_, err = c.configureCheckpoint()
+	assert.Error(t, err)
+}
+
+func TestEventPublisher_Validate(t *testing.T) {
+	tests := []struct {
+		config  EventPublisher
+		wantErr bool
+	}{
+		{config: EventPublisher{}, wantErr: false},
+		{config: EventPublisher{Checkpoint: checkpointStore{Type: "file", File: checkpointFile{Path: "cp.json"}}}, wantErr: false},
+		{config: EventPublisher{Checkpoint: checkpointStore{Type: "file"}}, wantErr: true},
+		{config: EventPublisher{Checkpoint: checkpointStore{Type: "unknown"}}, wantErr: true},
+		{
+			config:  EventPublisher{Listeners: listeners{EVMLog: []evmLogListener{{EventType: "a", ABI: testEventABI}}}},
+			wantErr: false,
+		},
+		{
+			// Missing event type:
+			config:  EventPublisher{Listeners: listeners{EVMLog: []evmLogListener{{ABI: testEventABI}}}},
+			wantErr: true,
+		},
+		{
+			// Event type already used:
+			config:  EventPublisher{Listeners: listeners{EVMLog: []evmLogListener{{EventType: "teleport_evm", ABI: testEventABI}}}},
+			wantErr: true,
+		},
+		{
+			// Invalid ABI type:
+			config:  EventPublisher{Listeners: listeners{EVMLog: []evmLogListener{{EventType: "a", ABI: 1}}}},
+			wantErr: true,
+		},
+		{
+			config:  EventPublisher{Listeners: listeners{TeleportStarknet: []teleportStarknetListener{{Sequencer: "https://example.com"}}}},
+			wantErr: false,
+		},
+		{
+			config:  EventPublisher{Listeners: listeners{TeleportStarknet: []teleportStarknetListener{{RPC: "https://example.com"}}}},
+			wantErr: false,
+		},
+		{
+			// Missing sequencer and RPC:
+			config:  EventPublisher{Listeners: listeners{TeleportStarknet: []teleportStarknetListener{{}}}},
+			wantErr: true,
+		},
+		{
+			// Both sequencer and RPC:
+			config: EventPublisher{Listeners: listeners{TeleportStarknet: []teleportStarknetListener{{
+				Sequencer: "https://example.com",
+				RPC:       "https://example.com",
+			}}}},
+			wantErr: true,
+		},
+		{config: EventPublisher{EIP712: &eip712Config.Domain{Name: "Teleport", ChainID: 1}}, wantErr: false},
+		{
+			// Empty EIP-712 domain:
+			config:  EventPublisher{EIP712: &eip712Config.Domain{}},
+			wantErr: true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			c := tt.config
+			if tt.wantErr {
+				assert.Error(t, c.Validate())
+			} else {
+				assert.NoError(t, c.Validate())
+			}
+		})
+	}
+}
+
 func Test_ethClients_configure(t *testing.T) {
 	c := &ethClients{}
 
@@ -79,3 +303,7 @@ func Test_ethClients_configure(t *testing.T) {
 	assert.Same(t, c1, c2)
 	assert.NotSame(t, c1, c3)
 }
+
+func TestEventPublisher_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, EventPublisher{}, "eventpublisher", "testdata/schema.json")
+}
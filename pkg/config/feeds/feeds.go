@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 )
 
@@ -36,3 +37,15 @@ func (f *Feeds) Addresses() ([]ethereum.Address, error) {
 	}
 	return addrs, nil
 }
+
+// Validate verifies that all feed addresses are valid Ethereum addresses.
+// Returned errors are config.PathErrors relative to the feeds config.
+func (f *Feeds) Validate() error {
+	var errs []error
+	for i, addr := range *f {
+		if !ethereum.IsHexAddress(addr) {
+			errs = append(errs, config.WithPath(fmt.Errorf("%w: %s", ErrInvalidEthereumAddress, addr), i))
+		}
+	}
+	return config.Join(errs...)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 )
 
@@ -39,3 +40,7 @@ func TestFeeds_Addresses_Invalid(t *testing.T) {
 
 	require.ErrorIs(t, err, ErrInvalidEthereumAddress)
 }
+
+func TestFeeds_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Feeds{}, "feeds", "testdata/schema.json")
+}
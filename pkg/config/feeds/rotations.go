@@ -0,0 +1,91 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package feeds
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// Rotations is a list of feeder key rotations. While a rotation is
+// configured, prices signed by the old and the new key are treated as prices
+// from the same logical feeder, so they are not counted twice towards
+// a quorum.
+type Rotations []Rotation
+
+// Rotation describes a single feeder key rotation.
+type Rotation struct {
+	Old string `yaml:"old"`
+	New string `yaml:"new"`
+}
+
+// Aliases returns a map of new feeder addresses to the old ones. The old
+// address is used as the logical feeder address.
+func (r *Rotations) Aliases() (map[ethereum.Address]ethereum.Address, error) {
+	aliases := map[ethereum.Address]ethereum.Address{}
+	for _, rot := range *r {
+		if !ethereum.IsHexAddress(rot.Old) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEthereumAddress, rot.Old)
+		}
+		if !ethereum.IsHexAddress(rot.New) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEthereumAddress, rot.New)
+		}
+		aliases[ethereum.HexToAddress(rot.New)] = ethereum.HexToAddress(rot.Old)
+	}
+	return aliases, nil
+}
+
+// Validate verifies that all rotations use valid addresses, that both
+// addresses are on the feeds list and that every address is used in at most
+// one rotation. Returned errors are config.PathErrors relative to the
+// rotations config.
+func (r *Rotations) Validate(feeds Feeds) error {
+	var errs []error
+	known := map[ethereum.Address]bool{}
+	for _, f := range feeds {
+		if ethereum.IsHexAddress(f) {
+			known[ethereum.HexToAddress(f)] = true
+		}
+	}
+	used := map[ethereum.Address]bool{}
+	for i, rot := range *r {
+		if rot.Old == rot.New {
+			errs = append(errs, config.WithPath(errors.New("old and new addresses must be different"), i))
+			continue
+		}
+		for _, f := range []struct {
+			name string
+			addr string
+		}{{"old", rot.Old}, {"new", rot.New}} {
+			if !ethereum.IsHexAddress(f.addr) {
+				errs = append(errs, config.WithPath(fmt.Errorf("%w: %s", ErrInvalidEthereumAddress, f.addr), i, f.name))
+				continue
+			}
+			addr := ethereum.HexToAddress(f.addr)
+			if !known[addr] {
+				errs = append(errs, config.WithPath(fmt.Errorf("address %s is not on the feeds list", f.addr), i, f.name))
+			}
+			if used[addr] {
+				errs = append(errs, config.WithPath(fmt.Errorf("address %s is used in more than one rotation", f.addr), i, f.name))
+			}
+			used[addr] = true
+		}
+	}
+	return config.Join(errs...)
+}
@@ -0,0 +1,82 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package feeds
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+func TestRotations_Aliases(t *testing.T) {
+	rotations := Rotations{{
+		Old: "0x07a35a1d4b751a818d93aa38e615c0df23064881",
+		New: "0x2d800d93b065ce011af83f316cef9f0d005b0aa4",
+	}}
+	aliases, err := rotations.Aliases()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[ethereum.Address]ethereum.Address{
+		ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4"): ethereum.HexToAddress("0x07a35a1d4b751a818d93aa38e615c0df23064881"),
+	}, aliases)
+}
+
+func TestRotations_Validate(t *testing.T) {
+	feeds := Feeds{
+		"0x07a35a1d4b751a818d93aa38e615c0df23064881",
+		"0x2d800d93b065ce011af83f316cef9f0d005b0aa4",
+		"0x3333333333333333333333333333333333333333",
+	}
+	tests := []struct {
+		rotations Rotations
+		wantErr   bool
+	}{
+		{
+			rotations: Rotations{{Old: feeds[0], New: feeds[1]}},
+			wantErr:   false,
+		},
+		{
+			rotations: Rotations{{Old: feeds[0], New: "abc"}},
+			wantErr:   true,
+		},
+		{
+			rotations: Rotations{{Old: feeds[0], New: "0x4444444444444444444444444444444444444444"}},
+			wantErr:   true,
+		},
+		{
+			rotations: Rotations{{Old: feeds[0], New: feeds[0]}},
+			wantErr:   true,
+		},
+		{
+			rotations: Rotations{{Old: feeds[0], New: feeds[1]}, {Old: feeds[1], New: feeds[2]}},
+			wantErr:   true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			err := tt.rotations.Validate(feeds)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -16,8 +16,12 @@
 package ghost
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ghost"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
@@ -31,8 +35,22 @@ var ghostFactory = func(cfg ghost.Config) (*ghost.Ghost, error) {
 }
 
 type Ghost struct {
-	Interval int      `yaml:"interval"`
-	Pairs    []string `yaml:"pairs"`
+	Interval int       `yaml:"interval"`
+	Pairs    []string  `yaml:"pairs"`
+	Rotation *Rotation `yaml:"rotation"`
+}
+
+// Rotation configures a new feeder key used during a key rotation window.
+// While the window lasts, prices are signed with both the current and
+// the new key.
+type Rotation struct {
+	// Ethereum configures the signer for the new key. Only the from,
+	// keystore, password and remoteSigner fields are used.
+	Ethereum ethereumConfig.Ethereum `yaml:"ethereum"`
+	// Until is the end of the rotation window in the RFC3339 format. After
+	// this time, prices are signed only with the new key. If empty, both
+	// keys are used until the configuration is changed.
+	Until string `yaml:"until"`
 }
 
 type Dependencies struct {
@@ -42,6 +60,32 @@ type Dependencies struct {
 	Logger    log.Logger
 }
 
+// Validate verifies that all pairs are supported by the price provider.
+// The knownPairs argument is a list of pairs for which price models are
+// defined, if it is empty, only the pair names are verified. Returned errors
+// are config.PathErrors relative to the ghost config.
+func (c *Ghost) Validate(knownPairs []string) error {
+	var errs []error
+	for i, name := range c.Pairs {
+		if _, err := provider.NewPair(name); err != nil {
+			errs = append(errs, config.WithPath(err, "pairs", i))
+			continue
+		}
+		if len(knownPairs) > 0 && !contains(knownPairs, name) {
+			errs = append(errs, config.WithPath(fmt.Errorf("unknown pair %s", name), "pairs", i))
+		}
+	}
+	if c.Rotation != nil {
+		if c.Rotation.Ethereum.From == "" {
+			errs = append(errs, config.WithPath(errors.New("the new key address must be specified"), "rotation", "ethereum", "from"))
+		}
+		if _, err := c.Rotation.until(); err != nil {
+			errs = append(errs, config.WithPath(err, "rotation", "until"))
+		}
+	}
+	return config.Join(errs...)
+}
+
 func (c *Ghost) Configure(d Dependencies) (*ghost.Ghost, error) {
 	cfg := ghost.Config{
 		PriceProvider: d.Gofer,
@@ -51,5 +95,40 @@ func (c *Ghost) Configure(d Dependencies) (*ghost.Ghost, error) {
 		Interval:      time.Second * time.Duration(c.Interval),
 		Pairs:         c.Pairs,
 	}
+	if c.Rotation != nil {
+		sig, err := c.Rotation.Ethereum.ConfigureSigner()
+		if err != nil {
+			return nil, fmt.Errorf("rotation signer error: %w", err)
+		}
+		if sig.Address() == ethereum.EmptyAddress {
+			return nil, errors.New("rotation signer error: the new key address must be specified")
+		}
+		until, err := c.Rotation.until()
+		if err != nil {
+			return nil, err
+		}
+		cfg.RotationSigner = sig
+		cfg.RotationUntil = until
+	}
 	return ghostFactory(cfg)
 }
+
+func (r *Rotation) until() (time.Time, error) {
+	if r.Until == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, r.Until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid rotation end time: %w", err)
+	}
+	return t, nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -22,6 +22,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ghost"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -64,3 +67,52 @@ func TestGhost_Configure(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, g)
 }
+
+func TestGhost_ConfigureWithRotation(t *testing.T) {
+	prevGhostFactory := ghostFactory
+	defer func() { ghostFactory = prevGhostFactory }()
+
+	config := Ghost{
+		Interval: 10,
+		Pairs:    []string{"AAABBB"},
+		Rotation: &Rotation{
+			Ethereum: ethereumConfig.Ethereum{
+				From:     "0x07a35a1d4b751a818d93aa38e615c0df23064881",
+				Keystore: "../ethereum/testdata/keystore",
+			},
+			Until: "2022-01-02T15:04:05Z",
+		},
+	}
+
+	ghostFactory = func(cfg ghost.Config) (*ghost.Ghost, error) {
+		require.NotNil(t, cfg.RotationSigner)
+		assert.Equal(t, ethereum.HexToAddress("0x07a35a1d4b751a818d93aa38e615c0df23064881"), cfg.RotationSigner.Address())
+		assert.Equal(t, time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC), cfg.RotationUntil)
+
+		return &ghost.Ghost{}, nil
+	}
+
+	g, err := config.Configure(Dependencies{
+		Gofer:     &goferMocks.Provider{},
+		Signer:    &ethereumMocks.Signer{},
+		Transport: local.New([]byte("test"), 0, nil),
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, g)
+}
+
+func TestGhost_Validate_Rotation(t *testing.T) {
+	config := Ghost{
+		Pairs:    []string{"AAA/BBB"},
+		Rotation: &Rotation{Until: "tomorrow"},
+	}
+	err := config.Validate(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rotation.ethereum.from")
+	assert.Contains(t, err.Error(), "rotation.until")
+}
+
+func TestGhost_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Ghost{}, "ghost", "testdata/schema.json")
+}
@@ -17,6 +17,7 @@ package gofer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
@@ -25,6 +26,8 @@ import (
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
 
@@ -69,6 +72,10 @@ type Gofer struct {
 	RPCListenAddr string                `yaml:"rpcListenAddr"`
 	Origins       map[string]Origin     `yaml:"origins"`
 	PriceModels   map[string]PriceModel `yaml:"priceModels"`
+
+	// MulticallAddress is the address of the Multicall3 contract used by
+	// on-chain origins. If empty, the canonical Multicall3 address is used.
+	MulticallAddress string `yaml:"multicallAddress"`
 }
 
 type RPC struct {
@@ -88,6 +95,36 @@ type PriceModel struct {
 	TTL     int        `yaml:"ttl"`
 }
 
+// JSONSchema implements the schema.Provider interface. The schema of the
+// params field depends on the origin type.
+func (Origin) JSONSchema() *schema.Schema {
+	s := schema.Struct(Origin{})
+	s.Required = []string{"type"}
+	var types []interface{}
+	for _, typ := range sortedOriginTypes() {
+		types = append(types, typ)
+		s.AllOf = append(s.AllOf, &schema.Schema{
+			If: &schema.Schema{
+				Properties: map[string]*schema.Schema{"type": {Const: typ}},
+			},
+			Then: &schema.Schema{
+				Properties: map[string]*schema.Schema{"params": schema.Reflect(originParams[typ])},
+			},
+		})
+	}
+	s.Properties["type"].Enum = types
+	return s
+}
+
+// JSONSchema implements the schema.Provider interface.
+func (PriceModel) JSONSchema() *schema.Schema {
+	s := schema.Struct(PriceModel{})
+	s.Required = []string{"method"}
+	s.Properties["method"].Enum = []interface{}{"median"}
+	s.Properties["params"] = schema.Reflect(MedianPriceModel{})
+	return s
+}
+
 type MedianPriceModel struct {
 	MinSourceSuccess int                    `yaml:"minimumSuccessfulSources"`
 	PostPriceHook    map[string]interface{} `yaml:"postPriceHook"`
@@ -180,6 +217,77 @@ func (c *Gofer) ConfigureGofer(cli ethereum.Client, logger log.Logger, noRPC boo
 	return c.configureRPCClient(listenAddr)
 }
 
+// Validate checks the price models and origins without building the price
+// graphs. Contrary to the Configure* methods, it does not stop on the first
+// error. Returned errors are config.PathErrors relative to the gofer config.
+func (c *Gofer) Validate() error {
+	var errs []error
+	if c.MulticallAddress != "" && !ethereum.IsHexAddress(c.MulticallAddress) {
+		errs = append(errs, config.WithPath(
+			fmt.Errorf("invalid multicall address: %s", c.MulticallAddress),
+			"multicallAddress",
+		))
+	}
+	known := origins.DefaultOriginSet(nil).Handlers()
+	for name, origin := range c.Origins {
+		known[name] = nil
+		if _, err := NewHandler(origin.Type, nil, nil, origin.URL, origin.Params); err != nil {
+			errs = append(errs, config.WithPath(
+				fmt.Errorf("failed to initiate %s origin: %w", origin.Type, err),
+				"origins", name,
+			))
+		}
+	}
+	for _, name := range sortedKeys(c.PriceModels) {
+		model := c.PriceModels[name]
+		if _, err := provider.NewPair(name); err != nil {
+			errs = append(errs, config.WithPath(err, "priceModels", name))
+		}
+		if model.Method != "median" {
+			errs = append(errs, config.WithPath(
+				fmt.Errorf("unknown method %s for pair %s", model.Method, name),
+				"priceModels", name, "method",
+			))
+		}
+		for i, sources := range model.Sources {
+			for j, source := range sources {
+				path := []interface{}{"priceModels", name, "sources", i, j}
+				if _, err := provider.NewPair(source.Pair); err != nil {
+					errs = append(errs, config.WithPath(err, append(path, "pair")...))
+					continue
+				}
+				if source.Origin == "." {
+					if _, ok := c.PriceModels[source.Pair]; !ok {
+						errs = append(errs, config.WithPath(
+							fmt.Errorf("unable to find price model for the %s pair", source.Pair),
+							append(path, "pair")...,
+						))
+					}
+					continue
+				}
+				if _, ok := known[source.Origin]; !ok {
+					errs = append(errs, config.WithPath(
+						fmt.Errorf("unknown origin %s", source.Origin),
+						append(path, "origin")...,
+					))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return config.Join(errs...)
+	}
+	// Cycles can be detected only on fully built graphs.
+	if _, err := c.buildGraphs(); err != nil {
+		var cycleErr ErrCyclicReference
+		if errors.As(err, &cycleErr) {
+			return config.WithPath(err, "priceModels", cycleErr.Pair.String())
+		}
+		return err
+	}
+	return nil
+}
+
 // configureRPCClient returns a new rpc.RPC instance.
 func (c *Gofer) configureRPCClient(listenAddr string) (*rpc.Provider, error) {
 	return rpc.NewProvider("tcp", listenAddr)
@@ -189,8 +297,16 @@ func (c *Gofer) buildOrigins(cli ethereum.Client) (*origins.Set, error) {
 	const defaultWorkerCount = 10
 	wp := query.NewHTTPWorkerPool(defaultWorkerCount)
 	originSet := origins.DefaultOriginSet(wp)
+	mcAddress := origins.Multicall3Address
+	if c.MulticallAddress != "" {
+		if !ethereum.IsHexAddress(c.MulticallAddress) {
+			return nil, fmt.Errorf("invalid multicall address: %s", c.MulticallAddress)
+		}
+		mcAddress = ethereum.HexToAddress(c.MulticallAddress)
+	}
+	mc := origins.NewMulticall(cli, mcAddress)
 	for name, origin := range c.Origins {
-		handler, err := NewHandler(origin.Type, wp, cli, origin.URL, origin.Params)
+		handler, err := NewHandler(origin.Type, wp, mc, origin.URL, origin.Params)
 		if err != nil || handler == nil {
 			return nil, fmt.Errorf(
 				"failed to initiate %s origin with name %s due to error: %w", origin.Type, name, err,
@@ -355,6 +471,24 @@ func (c *Gofer) detectCycle(graphs map[provider.Pair]nodes.Aggregator) error {
 	return nil
 }
 
+func sortedKeys(m map[string]PriceModel) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+func sortedOriginTypes() []string {
+	var ts []string
+	for t := range originParams {
+		ts = append(ts, t)
+	}
+	sort.Strings(ts)
+	return ts
+}
+
 func sortGraphs(graphs map[provider.Pair]nodes.Aggregator) []provider.Pair {
 	var ps []provider.Pair
 	for p := range graphs {
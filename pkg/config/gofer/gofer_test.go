@@ -21,6 +21,7 @@ import (
 
 	"gopkg.in/yaml.v3"
 
+	configPkg "github.com/chronicleprotocol/oracle-suite/pkg/config"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
@@ -228,6 +229,76 @@ func TestConfig_buildGraphs_ReferenceToSelf(t *testing.T) {
 	assert.Error(t, err2)
 }
 
+func TestConfig_Validate_ValidConfig(t *testing.T) {
+	config := Gofer{
+		Origins: map[string]Origin{
+			"custom": {Type: "binance"},
+		},
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "median",
+				Sources: [][]Source{
+					{{Origin: "custom", Pair: "A/B"}},
+					{{Origin: "kraken", Pair: "A/B"}},
+				},
+			},
+			"A/C": {
+				Method: "median",
+				Sources: [][]Source{
+					{{Origin: ".", Pair: "A/B"}, {Origin: "binance", Pair: "B/C"}},
+				},
+			},
+		},
+	}
+
+	assert.NoError(t, config.Validate())
+}
+
+func TestConfig_Validate_InvalidConfig(t *testing.T) {
+	config := Gofer{
+		MulticallAddress: "0x1234",
+		Origins: map[string]Origin{
+			"custom": {Type: "unknown"},
+		},
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "mean",
+				Sources: [][]Source{
+					{{Origin: "unknown", Pair: "A/B"}},
+					{{Origin: ".", Pair: "X/Y"}},
+				},
+			},
+		},
+	}
+
+	var errs configPkg.Errors
+	require.ErrorAs(t, config.Validate(), &errs)
+	assert.Len(t, errs, 5)
+	for _, err := range errs {
+		var pathErr configPkg.PathError
+		assert.ErrorAs(t, err, &pathErr)
+	}
+}
+
+func TestConfig_Validate_CyclicConfig(t *testing.T) {
+	config := Gofer{
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: ".", Pair: "B/C"}}},
+			},
+			"B/C": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: ".", Pair: "A/B"}}},
+			},
+		},
+	}
+
+	var pathErr configPkg.PathError
+	require.ErrorAs(t, config.Validate(), &pathErr)
+	assert.IsType(t, ErrCyclicReference{}, pathErr.Err)
+}
+
 func TestConfig_buildGraphs_DefaultTTL(t *testing.T) {
 	config := Gofer{
 		Origins: nil,
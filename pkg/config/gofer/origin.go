@@ -22,7 +22,7 @@ import (
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/query"
 
-	pkgEthereum "github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
 )
 
@@ -30,10 +30,68 @@ import (
 // which prices will be averaged.
 var averageFromBlocks = []int64{0, 10, 20}
 
+// symbolAliasesParams are params supported by all origins.
+type symbolAliasesParams struct {
+	SymbolAliases origins.SymbolAliases `yaml:"symbolAliases"`
+}
+
+// apiKeyParams are params used by origins that require an API key.
+type apiKeyParams struct {
+	symbolAliasesParams `yaml:",inline"`
+	APIKey              secret.Value `yaml:"apiKey"`
+}
+
+// contractsParams are params used by on-chain origins.
+type contractsParams struct {
+	symbolAliasesParams `yaml:",inline"`
+	Contracts           origins.ContractAddresses `yaml:"contracts"`
+}
+
+// originParams maps origin types supported by NewHandler to the structure of
+// their params. It is used to generate the config schema.
+var originParams = map[string]interface{}{
+	"balancer":          contractsParams{},
+	"balancerV2":        contractsParams{},
+	"binance":           symbolAliasesParams{},
+	"bitfinex":          symbolAliasesParams{},
+	"bithumb":           symbolAliasesParams{},
+	"bitstamp":          symbolAliasesParams{},
+	"bitthumb":          symbolAliasesParams{},
+	"bittrex":           symbolAliasesParams{},
+	"coinbase":          symbolAliasesParams{},
+	"coinbasepro":       symbolAliasesParams{},
+	"coinmarketcap":     apiKeyParams{},
+	"cryptocompare":     symbolAliasesParams{},
+	"curve":             contractsParams{},
+	"curvefinance":      contractsParams{},
+	"ddex":              symbolAliasesParams{},
+	"folgory":           symbolAliasesParams{},
+	"fx":                apiKeyParams{},
+	"gateio":            symbolAliasesParams{},
+	"gemini":            symbolAliasesParams{},
+	"gsu":               symbolAliasesParams{},
+	"gsu1":              symbolAliasesParams{},
+	"gsu2":              symbolAliasesParams{},
+	"hitbtc":            symbolAliasesParams{},
+	"huobi":             symbolAliasesParams{},
+	"kraken":            symbolAliasesParams{},
+	"kucoin":            symbolAliasesParams{},
+	"loopring":          symbolAliasesParams{},
+	"okex":              symbolAliasesParams{},
+	"okx":               symbolAliasesParams{},
+	"openexchangerates": apiKeyParams{},
+	"poloniex":          symbolAliasesParams{},
+	"rocketpool":        contractsParams{},
+	"sushiswap":         contractsParams{},
+	"uniswap":           contractsParams{},
+	"uniswapV2":         contractsParams{},
+	"uniswapV3":         contractsParams{},
+	"upbit":             symbolAliasesParams{},
+	"wsteth":            contractsParams{},
+}
+
 func parseParamsSymbolAliases(params yaml.Node) (origins.SymbolAliases, error) {
-	var res struct {
-		SymbolAliases origins.SymbolAliases `yaml:"symbolAliases"`
-	}
+	var res symbolAliasesParams
 	err := params.Decode(&res)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal origin symbol aliases from params: %w", err)
@@ -42,20 +100,16 @@ func parseParamsSymbolAliases(params yaml.Node) (origins.SymbolAliases, error) {
 }
 
 func parseParamsAPIKey(params yaml.Node) (string, error) {
-	var res struct {
-		APIKey string `yaml:"apiKey"`
-	}
+	var res apiKeyParams
 	err := params.Decode(&res)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal origin symbol aliases from params: %w", err)
 	}
-	return res.APIKey, nil
+	return res.APIKey.Resolve()
 }
 
 func parseParamsContracts(params yaml.Node) (origins.ContractAddresses, error) {
-	var res struct {
-		Contracts origins.ContractAddresses `yaml:"contracts"`
-	}
+	var res contractsParams
 	err := params.Decode(&res)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal origin symbol aliases from params: %w", err)
@@ -67,7 +121,7 @@ func parseParamsContracts(params yaml.Node) (origins.ContractAddresses, error) {
 func NewHandler(
 	origin string,
 	wp query.WorkerPool,
-	cli pkgEthereum.Client,
+	mc *origins.Multicall,
 	baseURL string,
 	params yaml.Node,
 ) (origins.Handler, error) {
@@ -172,7 +226,7 @@ func NewHandler(
 		if err != nil {
 			return nil, err
 		}
-		h, err := origins.NewCurveFinance(cli, contracts, averageFromBlocks)
+		h, err := origins.NewCurveFinance(mc, contracts, averageFromBlocks)
 		if err != nil {
 			return nil, err
 		}
@@ -182,7 +236,7 @@ func NewHandler(
 		if err != nil {
 			return nil, err
 		}
-		h, err := origins.NewBalancerV2(cli, contracts, averageFromBlocks)
+		h, err := origins.NewBalancerV2(mc, contracts, averageFromBlocks)
 		if err != nil {
 			return nil, err
 		}
@@ -192,7 +246,7 @@ func NewHandler(
 		if err != nil {
 			return nil, err
 		}
-		h, err := origins.NewWrappedStakedETH(cli, contracts, averageFromBlocks)
+		h, err := origins.NewWrappedStakedETH(mc, contracts, averageFromBlocks)
 		if err != nil {
 			return nil, err
 		}
@@ -202,7 +256,7 @@ func NewHandler(
 		if err != nil {
 			return nil, err
 		}
-		h, err := origins.NewRocketPool(cli, contracts, averageFromBlocks)
+		h, err := origins.NewRocketPool(mc, contracts, averageFromBlocks)
 		if err != nil {
 			return nil, err
 		}
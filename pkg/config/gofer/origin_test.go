@@ -16,9 +16,16 @@
 package gofer
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
 )
 
 func TestParsingOriginParamsAliases(t *testing.T) {
@@ -44,3 +51,47 @@ func TestParsingOriginParamsAliases(t *testing.T) {
 	assert.NotNil(t, aliases)
 	assert.Equal(t, "WETH", aliases["ETH"])
 }
+
+func TestOriginParams_Types(t *testing.T) {
+	// All origin types listed in the schema must be supported by NewHandler.
+	for typ := range originParams {
+		h, err := NewHandler(typ, nil, nil, "", yamlNode(t, `{}`))
+		assert.NoError(t, err, typ)
+		assert.NotNil(t, h, typ)
+	}
+
+	// All origin types supported by NewHandler must be listed in the schema.
+	types := handlerTypes(t)
+	require.NotEmpty(t, types)
+	for _, typ := range types {
+		assert.Contains(t, originParams, typ)
+	}
+}
+
+func TestGofer_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Gofer{}, "gofer", "testdata/schema.json")
+}
+
+// handlerTypes returns the origin types from the case clauses of the switch
+// statement in the NewHandler function.
+func handlerTypes(t *testing.T) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), "origin.go", nil, 0)
+	require.NoError(t, err)
+	var types []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		if fn, ok := n.(*ast.FuncDecl); ok {
+			return fn.Name.Name == "NewHandler"
+		}
+		if cc, ok := n.(*ast.CaseClause); ok {
+			for _, e := range cc.List {
+				if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					typ, err := strconv.Unquote(lit.Value)
+					require.NoError(t, err)
+					types = append(types, typ)
+				}
+			}
+		}
+		return true
+	})
+	return types
+}
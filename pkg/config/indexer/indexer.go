@@ -0,0 +1,130 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/indexer"
+)
+
+// defaultInterval is the default interval between checks for new pokes in
+// seconds.
+const defaultInterval = 60
+
+// defaultBlockLimit is the default number of blocks from which logs are
+// fetched at once.
+const defaultBlockLimit = 1000
+
+//nolint
+var indexerFactory = func(cfg indexer.Config) (*indexer.Indexer, error) {
+	return indexer.New(cfg)
+}
+
+//nolint
+var apiFactory = func(cfg indexer.APIConfig) (*indexer.API, error) {
+	return indexer.NewAPI(cfg)
+}
+
+type Indexer struct {
+	// Interval is the interval between checks for new pokes in seconds.
+	Interval int64 `yaml:"interval"`
+	// PrefetchBlocks is the number of blocks before the latest block indexed
+	// during the start.
+	PrefetchBlocks uint64 `yaml:"prefetchBlocks"`
+	// BlockLimit is the maximum number of blocks from which logs are fetched
+	// at once.
+	BlockLimit uint64 `yaml:"blockLimit"`
+	// BlockConfirmations is the number of blocks that must be mined on top
+	// of a block before its pokes are indexed.
+	BlockConfirmations uint64 `yaml:"blockConfirmations"`
+	// StorageLimit is the maximum number of the most recent pokes kept in
+	// memory. Zero means no limit.
+	StorageLimit int `yaml:"storageLimit"`
+	// ListenAddr is the address of the HTTP API serving indexed pokes. If
+	// empty, the API is disabled.
+	ListenAddr string `yaml:"listenAddr"`
+}
+
+type Dependencies struct {
+	Client    ethereumv2.Client
+	Signer    ethereum.Signer
+	Contracts []types.Address
+	Logger    log.Logger
+}
+
+func (c *Indexer) ConfigureIndexer(d Dependencies) (*indexer.Indexer, error) {
+	interval := c.Interval
+	if interval == 0 {
+		interval = defaultInterval
+	}
+	blockLimit := c.BlockLimit
+	if blockLimit == 0 {
+		blockLimit = defaultBlockLimit
+	}
+	ix, err := indexerFactory(indexer.Config{
+		Client:             d.Client,
+		Signer:             d.Signer,
+		Storage:            indexer.NewMemoryStorage(c.StorageLimit),
+		Contracts:          d.Contracts,
+		Interval:           time.Second * time.Duration(interval),
+		PrefetchBlocks:     c.PrefetchBlocks,
+		BlockLimit:         blockLimit,
+		BlockConfirmations: c.BlockConfirmations,
+		Logger:             d.Logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indexer config: %w", err)
+	}
+	return ix, nil
+}
+
+// ConfigureAPI returns the HTTP API for the given indexer. It returns nil if
+// the API is disabled.
+func (c *Indexer) ConfigureAPI(ix *indexer.Indexer, logger log.Logger) (*indexer.API, error) {
+	if c.ListenAddr == "" {
+		return nil, nil
+	}
+	api, err := apiFactory(indexer.APIConfig{
+		Indexer: ix,
+		Address: c.ListenAddr,
+		Logger:  logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indexer config: %w", err)
+	}
+	return api, nil
+}
+
+// Validate validates the config without building services. Returned errors
+// are config.PathErrors relative to the indexer config.
+func (c *Indexer) Validate() error {
+	var errs []error
+	if c.Interval < 0 {
+		errs = append(errs, config.WithPath(errors.New("interval must not be negative"), "interval"))
+	}
+	if c.StorageLimit < 0 {
+		errs = append(errs, config.WithPath(errors.New("storageLimit must not be negative"), "storageLimit"))
+	}
+	return config.Join(errs...)
+}
@@ -0,0 +1,125 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
+	ethereumGeth "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcclient"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/indexer"
+)
+
+func TestIndexer_Configure(t *testing.T) {
+	prevIndexerFactory := indexerFactory
+	prevAPIFactory := apiFactory
+	defer func() {
+		indexerFactory = prevIndexerFactory
+		apiFactory = prevAPIFactory
+	}()
+
+	cli := &rpcclient.Client{}
+	sig := ethereumGeth.NewSigner(nil)
+	logger := null.New()
+	contracts := []types.Address{types.HexToAddress("0x1111111111111111111111111111111111111111")}
+	ix := &indexer.Indexer{}
+	config := Indexer{
+		PrefetchBlocks:     100,
+		BlockConfirmations: 2,
+		StorageLimit:       10,
+		ListenAddr:         "127.0.0.1:0",
+	}
+
+	indexerFactory = func(cfg indexer.Config) (*indexer.Indexer, error) {
+		assert.Equal(t, cli, cfg.Client)
+		assert.Equal(t, sig, cfg.Signer)
+		assert.NotNil(t, cfg.Storage)
+		assert.Equal(t, contracts, cfg.Contracts)
+		assert.Equal(t, time.Minute, cfg.Interval)
+		assert.Equal(t, uint64(100), cfg.PrefetchBlocks)
+		assert.Equal(t, uint64(1000), cfg.BlockLimit)
+		assert.Equal(t, uint64(2), cfg.BlockConfirmations)
+		assert.Equal(t, logger, cfg.Logger)
+		return ix, nil
+	}
+	apiFactory = func(cfg indexer.APIConfig) (*indexer.API, error) {
+		assert.Equal(t, ix, cfg.Indexer)
+		assert.Equal(t, "127.0.0.1:0", cfg.Address)
+		assert.Equal(t, logger, cfg.Logger)
+		return &indexer.API{}, nil
+	}
+
+	i, err := config.ConfigureIndexer(Dependencies{
+		Client:    cli,
+		Signer:    sig,
+		Contracts: contracts,
+		Logger:    logger,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ix, i)
+
+	a, err := config.ConfigureAPI(i, logger)
+	require.NoError(t, err)
+	assert.NotNil(t, a)
+
+	// The API is disabled if the listen address is empty:
+	config.ListenAddr = ""
+	a, err = config.ConfigureAPI(i, logger)
+	require.NoError(t, err)
+	assert.Nil(t, a)
+}
+
+func TestIndexer_Validate(t *testing.T) {
+	tests := []struct {
+		indexer Indexer
+		errs    []string
+	}{
+		{
+			indexer: Indexer{Interval: 10, StorageLimit: 10},
+		},
+		{
+			indexer: Indexer{Interval: -1, StorageLimit: -1},
+			errs: []string{
+				"interval: interval must not be negative",
+				"storageLimit: storageLimit must not be negative",
+			},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var errs []string
+			if err := tt.indexer.Validate(); err != nil {
+				for _, e := range err.(config.Errors) {
+					errs = append(errs, e.Error())
+				}
+			}
+			assert.Equal(t, tt.errs, errs)
+		})
+	}
+}
+
+func TestIndexer_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Indexer{}, "indexer", "testdata/schema.json")
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	suite "github.com/chronicleprotocol/oracle-suite"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/chain"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/grafana"
@@ -44,7 +45,7 @@ type grafanaLogger struct {
 	Enable   bool            `yaml:"enable"`
 	Interval int             `yaml:"interval"`
 	Endpoint string          `yaml:"endpoint"`
-	APIKey   string          `yaml:"apiKey"`
+	APIKey   secret.Value    `yaml:"apiKey"`
 	Metrics  []grafanaMetric `yaml:"metrics"`
 }
 
@@ -133,6 +134,11 @@ func (c *Logger) configureGrafanaLogger(d Dependencies) (log.Logger, error) {
 		ms = append(ms, gm)
 	}
 
+	apiKey, err := c.Grafana.APIKey.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
 	interval := c.Grafana.Interval
 	if interval < 1 {
 		interval = 1
@@ -142,7 +148,7 @@ func (c *Logger) configureGrafanaLogger(d Dependencies) (log.Logger, error) {
 		Metrics:          ms,
 		Interval:         uint(interval),
 		GraphiteEndpoint: c.Grafana.Endpoint,
-		GraphiteAPIKey:   c.Grafana.APIKey,
+		GraphiteAPIKey:   apiKey,
 		HTTPClient:       http.DefaultClient,
 		Logger:           d.BaseLogger,
 	})
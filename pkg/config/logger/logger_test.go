@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/grafana"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -128,3 +129,7 @@ func TestLogger_Configure(t *testing.T) {
 	assert.Equal(t, "*chain.logger", reflect.TypeOf(l).String())
 	assert.NoError(t, err)
 }
+
+func TestLogger_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Logger{}, "logger", "testdata/schema.json")
+}
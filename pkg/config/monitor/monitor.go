@@ -0,0 +1,197 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/monitor"
+	oracleGeth "github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+// defaultInterval is the default interval between checks in seconds.
+const defaultInterval = 60
+
+//nolint
+var monitorFactory = func(cfg monitor.Config) (*monitor.Monitor, error) {
+	return monitor.New(cfg)
+}
+
+type Monitor struct {
+	// Interval is the interval between checks in seconds.
+	Interval int64 `yaml:"interval"`
+	// MaxAge is the maximum time in seconds since the last contract update
+	// before the staleAge alert is sent. Zero disables the rule.
+	MaxAge int64 `yaml:"maxAge"`
+	// MaxDeviation is the maximum difference in percent between the contract
+	// price and the Gofer price before the deviation alert is sent. Zero
+	// disables the rule.
+	MaxDeviation float64 `yaml:"maxDeviation"`
+	// BarChanged enables the barChanged alert.
+	BarChanged bool `yaml:"barChanged"`
+	// FeedsChanged enables the feedsChanged alert.
+	FeedsChanged bool `yaml:"feedsChanged"`
+	// ReadFailed enables the readFailed alert.
+	ReadFailed bool `yaml:"readFailed"`
+	// Webhooks is a list of HTTP endpoints notified about alerts.
+	Webhooks []webhook `yaml:"webhooks"`
+}
+
+type webhook struct {
+	URL    string       `yaml:"url"`
+	Secret secret.Value `yaml:"secret"`
+}
+
+// Contract is a monitored Median contract.
+type Contract struct {
+	Address string `yaml:"address"`
+	Symbol  string `yaml:"symbol"`
+	Wat     string `yaml:"wat"`
+	// Pair is the Gofer asset pair used as the reference price, e.g.
+	// "ETH/USD". If empty, the deviation rule is not evaluated.
+	Pair string `yaml:"pair"`
+}
+
+type Contracts []Contract
+
+type Dependencies struct {
+	Contracts      Contracts
+	EthereumClient ethereum.Client
+	// Gofer provides reference prices. It may be nil if no contract has
+	// a reference pair.
+	Gofer  provider.Provider
+	Logger log.Logger
+}
+
+func (c *Monitor) ConfigureMonitor(d Dependencies) (*monitor.Monitor, error) {
+	interval := c.Interval
+	if interval == 0 {
+		interval = defaultInterval
+	}
+	cfg := monitor.Config{
+		Reference: d.Gofer,
+		Interval:  time.Second * time.Duration(interval),
+		Rules: monitor.Rules{
+			MaxAge:       time.Second * time.Duration(c.MaxAge),
+			MaxDeviation: c.MaxDeviation,
+			BarChanged:   c.BarChanged,
+			FeedsChanged: c.FeedsChanged,
+			ReadFailed:   c.ReadFailed,
+		},
+		Logger: d.Logger,
+	}
+	for _, contract := range d.Contracts {
+		var pair provider.Pair
+		if contract.Pair != "" {
+			var err error
+			if pair, err = provider.NewPair(contract.Pair); err != nil {
+				return nil, fmt.Errorf("monitor config: %w", err)
+			}
+		}
+		cfg.Contracts = append(cfg.Contracts, &monitor.Contract{
+			Name:   contract.Name(),
+			Pair:   pair,
+			Median: oracleGeth.NewMedian(d.EthereumClient, ethereum.HexToAddress(contract.Address)),
+		})
+	}
+	for _, w := range c.Webhooks {
+		key, err := w.Secret.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("monitor config: %w", err)
+		}
+		cfg.Webhooks = append(cfg.Webhooks, monitor.Webhook{
+			URL:    w.URL,
+			Secret: []byte(key),
+		})
+	}
+	return monitorFactory(cfg)
+}
+
+// Validate validates the config without building services. Returned errors
+// are config.PathErrors relative to the monitor config.
+func (c *Monitor) Validate() error {
+	var errs []error
+	if c.Interval < 0 {
+		errs = append(errs, config.WithPath(errors.New("interval must not be negative"), "interval"))
+	}
+	if c.MaxAge < 0 {
+		errs = append(errs, config.WithPath(errors.New("maxAge must not be negative"), "maxAge"))
+	}
+	if c.MaxDeviation < 0 {
+		errs = append(errs, config.WithPath(errors.New("maxDeviation must not be negative"), "maxDeviation"))
+	}
+	for i, w := range c.Webhooks {
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, config.WithPath(errors.New("url must be a valid HTTP or HTTPS address"), "webhooks", i, "url"))
+		}
+	}
+	return config.Join(errs...)
+}
+
+// Name returns the name of the contract used in logs and alerts.
+func (c Contract) Name() string {
+	if c.Wat != "" {
+		return c.Wat
+	}
+	return c.Symbol
+}
+
+// HasPairs returns true if any contract has a reference pair.
+func (c Contracts) HasPairs() bool {
+	for _, contract := range c {
+		if contract.Pair != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate verifies that all contracts have valid addresses and reference
+// pairs. If knownPairs is not empty, reference pairs must be on that list.
+// Returned errors are config.PathErrors relative to the contract list.
+func (c Contracts) Validate(knownPairs []string) error {
+	var errs []error
+	for i, contract := range c {
+		if !ethereum.IsHexAddress(contract.Address) {
+			errs = append(errs, config.WithPath(fmt.Errorf("invalid address: %s", contract.Address), i, "address"))
+		}
+		if contract.Pair != "" {
+			if _, err := provider.NewPair(contract.Pair); err != nil {
+				errs = append(errs, config.WithPath(err, i, "pair"))
+			} else if len(knownPairs) > 0 && !contains(knownPairs, contract.Pair) {
+				errs = append(errs, config.WithPath(fmt.Errorf("unknown pair %s", contract.Pair), i, "pair"))
+			}
+		}
+	}
+	return config.Join(errs...)
+}
+
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package monitor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/monitor"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+func TestMonitor_Configure(t *testing.T) {
+	prevMonitorFactory := monitorFactory
+	defer func() { monitorFactory = prevMonitorFactory }()
+
+	ethClient := &ethereumMocks.Client{}
+	logger := null.New()
+	config := Monitor{
+		MaxAge:       3600,
+		MaxDeviation: 1.5,
+		BarChanged:   true,
+		FeedsChanged: true,
+		ReadFailed:   true,
+		Webhooks:     []webhook{{URL: "https://example.com/hook", Secret: secret.Value("secret")}},
+	}
+	contracts := Contracts{
+		{Address: "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f", Wat: "ETHUSD", Pair: "ETH/USD"},
+		{Address: "0x1111111111111111111111111111111111111111", Symbol: "BTCUSD"},
+	}
+
+	monitorFactory = func(cfg monitor.Config) (*monitor.Monitor, error) {
+		assert.Equal(t, time.Minute, cfg.Interval)
+		assert.Equal(t, time.Hour, cfg.Rules.MaxAge)
+		assert.Equal(t, 1.5, cfg.Rules.MaxDeviation)
+		assert.True(t, cfg.Rules.BarChanged)
+		assert.True(t, cfg.Rules.FeedsChanged)
+		assert.True(t, cfg.Rules.ReadFailed)
+		assert.Equal(t, logger, cfg.Logger)
+		require.Len(t, cfg.Contracts, 2)
+		assert.Equal(t, "ETHUSD", cfg.Contracts[0].Name)
+		assert.Equal(t, provider.Pair{Base: "ETH", Quote: "USD"}, cfg.Contracts[0].Pair)
+		assert.Equal(t, ethereum.HexToAddress(contracts[0].Address), cfg.Contracts[0].Median.Address())
+		assert.Equal(t, "BTCUSD", cfg.Contracts[1].Name)
+		assert.True(t, cfg.Contracts[1].Pair.Empty())
+		assert.Equal(t, []monitor.Webhook{{URL: "https://example.com/hook", Secret: []byte("secret")}}, cfg.Webhooks)
+		return &monitor.Monitor{}, nil
+	}
+
+	m, err := config.ConfigureMonitor(Dependencies{
+		Contracts:      contracts,
+		EthereumClient: ethClient,
+		Logger:         logger,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, m)
+}
+
+func TestMonitor_Validate(t *testing.T) {
+	tests := []struct {
+		monitor   Monitor
+		contracts Contracts
+		errs      []string
+	}{
+		{
+			monitor:   Monitor{Interval: 10, MaxAge: 10, MaxDeviation: 1},
+			contracts: Contracts{{Address: "0x1111111111111111111111111111111111111111", Pair: "ETH/USD"}},
+		},
+		{
+			monitor: Monitor{Interval: -1, MaxAge: -1, MaxDeviation: -1, Webhooks: []webhook{{URL: "ftp://example.com"}}},
+			errs: []string{
+				"interval: interval must not be negative",
+				"maxAge: maxAge must not be negative",
+				"maxDeviation: maxDeviation must not be negative",
+				"webhooks[0].url: url must be a valid HTTP or HTTPS address",
+			},
+		},
+		{
+			contracts: Contracts{{Address: "0x11", Pair: "ETHUSD"}, {Address: "0x1111111111111111111111111111111111111111", Pair: "BTC/USD"}},
+			errs: []string{
+				"[0].address: invalid address: 0x11",
+				"[0].pair: couldn't parse pair \"ETHUSD\"",
+				"[1].pair: unknown pair BTC/USD",
+			},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var errs []string
+			err := config.Join(tt.monitor.Validate(), tt.contracts.Validate([]string{"ETH/USD"}))
+			if err != nil {
+				for _, e := range err.(config.Errors) {
+					errs = append(errs, e.Error())
+				}
+			}
+			assert.Equal(t, tt.errs, errs)
+		})
+	}
+}
+
+func TestMonitor_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Monitor{}, "monitor", "testdata/schema.json")
+}
@@ -0,0 +1,176 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package schema generates JSON Schema documents from the config structs.
+//
+// Field names are taken from the yaml struct tags, the same way as the YAML
+// decoder does. Types that need a custom schema, e.g. because they hold raw
+// YAML nodes, can implement the Provider interface.
+package schema
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Draft is the JSON Schema version used by generated documents.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema document. Only a subset of the keywords used by
+// the generator is supported.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	If                   *Schema            `json:"if,omitempty"`
+	Then                 *Schema            `json:"then,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// Provider is implemented by types that define their own schema.
+type Provider interface {
+	JSONSchema() *Schema
+}
+
+var (
+	providerType        = reflect.TypeOf((*Provider)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	yamlNodeType        = reflect.TypeOf(yaml.Node{})
+)
+
+// Generate returns the root schema document for the given value.
+func Generate(v interface{}, title string) *Schema {
+	s := Reflect(v)
+	s.Schema = Draft
+	s.Title = title
+	return s
+}
+
+// Reflect returns the schema for the given value.
+func Reflect(v interface{}) *Schema {
+	return reflectType(reflect.TypeOf(v))
+}
+
+// Struct returns the schema for the given struct value. Unlike Reflect, it
+// does not use the Provider interface of the value itself, so it may be used
+// to implement the Provider interface.
+func Struct(v interface{}) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	reflectFields(t, s)
+	return s
+}
+
+func reflectType(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Implements(providerType) {
+		return reflect.Zero(t).Interface().(Provider).JSONSchema()
+	}
+	if reflect.PtrTo(t).Implements(providerType) {
+		return reflect.New(t).Interface().(Provider).JSONSchema()
+	}
+	if t == yamlNodeType {
+		return &Schema{}
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return &Schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: reflectType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reflectType(t.Elem())}
+	case reflect.Struct:
+		return Struct(reflect.Zero(t).Interface())
+	default:
+		// Interfaces and other types may hold any value.
+		return &Schema{}
+	}
+}
+
+func reflectFields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue // unexported
+		}
+		name, inline, skip := fieldName(f)
+		if skip {
+			continue
+		}
+		if inline {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				reflectFields(ft, s)
+				continue
+			}
+		}
+		s.Properties[name] = reflectType(f.Type)
+	}
+}
+
+// fieldName returns the name of the field in the same way the YAML decoder
+// does.
+func fieldName(f reflect.StructField) (name string, inline bool, skip bool) {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, p := range parts[1:] {
+		if p == "inline" {
+			inline = true
+		}
+	}
+	if parts[0] != "" {
+		return parts[0], inline, false
+	}
+	if f.PkgPath != "" {
+		// Unexported embedded structs are only decoded when inlined.
+		return "", inline, !inline
+	}
+	return strings.ToLower(f.Name), inline, false
+}
@@ -0,0 +1,89 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+type inlined struct {
+	Inlined string `yaml:"inlined"`
+}
+
+type custom struct{}
+
+func (custom) JSONSchema() *Schema {
+	return &Schema{Type: "string", Enum: []interface{}{"a", "b"}}
+}
+
+func TestReflect(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: true, want: `{"type":"boolean"}`},
+		{value: 1, want: `{"type":"integer"}`},
+		{value: uint8(1), want: `{"type":"integer"}`},
+		{value: 1.0, want: `{"type":"number"}`},
+		{value: "", want: `{"type":"string"}`},
+		{value: []string{}, want: `{"type":"array","items":{"type":"string"}}`},
+		{value: map[string]int{}, want: `{"type":"object","additionalProperties":{"type":"integer"}}`},
+		{value: yaml.Node{}, want: `{}`},
+		{value: types.Address{}, want: `{"type":"string"}`},
+		{value: custom{}, want: `{"type":"string","enum":["a","b"]}`},
+		{
+			value: struct {
+				Default   int
+				Tagged    int      `yaml:"tagged,omitempty"`
+				Skipped   int      `yaml:"-"`
+				Pointer   *string  `yaml:"pointer"`
+				Interface any      `yaml:"interface"`
+				Custom    []custom `yaml:"custom"`
+				inlined   `yaml:",inline"`
+				private   int //nolint:unused,structcheck
+			}{},
+			want: `{"type":"object","properties":{` +
+				`"custom":{"type":"array","items":{"type":"string","enum":["a","b"]}},` +
+				`"default":{"type":"integer"},` +
+				`"inlined":{"type":"string"},` +
+				`"interface":{},` +
+				`"pointer":{"type":"string"},` +
+				`"tagged":{"type":"integer"}}}`,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			b, err := json.Marshal(Reflect(tt.value))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(struct{}{}, "test")
+	assert.Equal(t, Draft, s.Schema)
+	assert.Equal(t, "test", s.Title)
+	assert.Equal(t, "object", s.Type)
+}
@@ -0,0 +1,48 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package schematest provides helpers for testing JSON Schemas generated
+// from the config structs.
+package schematest
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// AssertGolden compares the schema generated for the given config value
+// with the golden file. The golden file must be updated every time the
+// config structs are changed. To do this, run tests with the -update flag.
+func AssertGolden(t *testing.T, config interface{}, title, golden string) {
+	b, err := json.MarshalIndent(schema.Generate(config, title), "", "  ")
+	require.NoError(t, err)
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755)) //nolint:gosec
+		require.NoError(t, os.WriteFile(golden, b, 0o644))           //nolint:gosec
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err, "golden file is missing, run tests with the -update flag")
+	assert.JSONEq(t, string(want), string(b))
+}
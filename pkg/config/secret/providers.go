@@ -0,0 +1,147 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const execTimeout = 30 * time.Second
+const vaultTimeout = 10 * time.Second
+
+// getEnv is used by the env and vault providers, it may be replaced in tests.
+var getEnv = os.LookupEnv
+
+// fileProvider reads the secret from a file. A trailing newline is removed.
+func fileProvider(ref string) (string, error) {
+	b, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// envProvider reads the secret from an environment variable.
+func envProvider(ref string) (string, error) {
+	v, ok := getEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s not set", ref)
+	}
+	return v, nil
+}
+
+// execProvider runs the command and uses its output as the secret. A trailing
+// newline is removed. The command is not run in a shell.
+func execProvider(ref string) (string, error) {
+	args := strings.Fields(ref)
+	if len(args) == 0 {
+		return "", errors.New("missing command")
+	}
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to run %s: %w", args[0], err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		// Errors returned by Wait do not contain the command output.
+		if err != nil {
+			return "", fmt.Errorf("failed to run %s: %w", args[0], err)
+		}
+	case <-time.After(execTimeout):
+		_ = cmd.Process.Kill()
+		return "", fmt.Errorf("failed to run %s: timeout", args[0])
+	}
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
+
+// VaultProvider reads secrets from HashiCorp Vault using its HTTP API. The
+// reference has the form "path#field", where the path is the API path of
+// the secret, without the "/v1/" prefix, e.g. "secret/data/oracle#password".
+// Both KV v1 and KV v2 secrets engines are supported.
+//
+// The Vault address and token are read from the VAULT_ADDR and VAULT_TOKEN
+// environment variables.
+type VaultProvider struct {
+	Client *http.Client
+}
+
+// NewVaultProvider returns a new VaultProvider instance.
+func NewVaultProvider() *VaultProvider {
+	return &VaultProvider{Client: &http.Client{Timeout: vaultTimeout}}
+}
+
+// Resolve implements the Provider interface.
+func (p *VaultProvider) Resolve(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || field == "" {
+		return "", errors.New("vault reference must have the path#field form")
+	}
+	addr, ok := getEnv("VAULT_ADDR")
+	if !ok {
+		return "", errors.New("VAULT_ADDR environment variable not set")
+	}
+	token, _ := getEnv("VAULT_TOKEN")
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault request failed: %s", res.Status)
+	}
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("unable to decode vault response: %w", err)
+	}
+	data := body.Data
+	// KV v2 secrets engine wraps the data in an additional "data" field.
+	if raw, ok := data["data"]; ok {
+		if _, ok := data["metadata"]; ok {
+			data = nil
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return "", fmt.Errorf("unable to decode vault response: %w", err)
+			}
+		}
+	}
+	raw, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("field %s not found in the vault secret", field)
+	}
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", fmt.Errorf("field %s in the vault secret is not a string", field)
+	}
+	return v, nil
+}
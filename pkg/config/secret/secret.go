@@ -0,0 +1,131 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package secret implements secret references that can be used in config
+// values.
+//
+// A secret reference has the form "scheme://reference", where the scheme
+// selects a Provider. The following providers are registered by default:
+//
+//	file://path               reads the secret from a file
+//	env://NAME                reads the secret from an environment variable
+//	exec://command [args...]  reads the secret from the command's stdout
+//	vault://path#field        reads the secret from HashiCorp Vault
+//
+// Values without a registered scheme are used as-is.
+package secret
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Redacted is used in place of secret values in logs and error messages.
+const Redacted = "[REDACTED]"
+
+// Provider resolves secret references with a specific scheme.
+type Provider interface {
+	// Resolve returns the secret for the given reference. The reference does
+	// not contain the scheme prefix.
+	Resolve(ref string) (string, error)
+}
+
+// ProviderFunc is an adapter to allow the use of functions as providers.
+type ProviderFunc func(ref string) (string, error)
+
+// Resolve implements the Provider interface.
+func (f ProviderFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]Provider{
+		"file":  ProviderFunc(fileProvider),
+		"env":   ProviderFunc(envProvider),
+		"exec":  ProviderFunc(execProvider),
+		"vault": NewVaultProvider(),
+	}
+)
+
+// Register registers a provider for the given scheme. If a provider for the
+// scheme is already registered, it is replaced.
+func Register(scheme string, p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers[scheme] = p
+}
+
+// Value is a config value that may contain a secret reference. The value is
+// never revealed when printed, logged or marshaled to JSON.
+type Value string
+
+// IsReference returns true if the value is a reference to a registered
+// provider.
+func (v Value) IsReference() bool {
+	_, _, ok := v.provider()
+	return ok
+}
+
+// Resolve returns the secret. If the value is not a reference, it is
+// returned as-is. Returned errors never contain the secret.
+func (v Value) Resolve() (string, error) {
+	p, ref, ok := v.provider()
+	if !ok {
+		return string(v), nil
+	}
+	s, err := p.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve secret %s: %w", v.scheme(), err)
+	}
+	return s, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (v Value) String() string {
+	return Redacted
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (v Value) GoString() string {
+	return Redacted
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + Redacted + `"`), nil
+}
+
+func (v Value) scheme() string {
+	if i := strings.Index(string(v), "://"); i > 0 {
+		return string(v)[:i]
+	}
+	return ""
+}
+
+func (v Value) provider() (Provider, string, bool) {
+	scheme := v.scheme()
+	if scheme == "" {
+		return nil, "", false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := providers[scheme]
+	if !ok {
+		return nil, "", false
+	}
+	return p, string(v)[len(scheme)+len("://"):], true
+}
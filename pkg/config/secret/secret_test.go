@@ -0,0 +1,178 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/dump"
+)
+
+func TestValue_Resolve(t *testing.T) {
+	getEnv = func(v string) (string, bool) {
+		if v == "SECRET" {
+			return "env-secret", true
+		}
+		return "", false
+	}
+	defer func() { getEnv = os.LookupEnv }()
+
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("file-secret\n"), 0o600))
+
+	tests := []struct {
+		value   Value
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: ""},
+		{value: "plain", want: "plain"},
+		{value: "unknown://plain", want: "unknown://plain"},
+		{value: Value("file://" + file), want: "file-secret"},
+		{value: "file:///nonexistent", wantErr: true},
+		{value: "env://SECRET", want: "env-secret"},
+		{value: "env://MISSING", wantErr: true},
+		{value: "exec://echo exec-secret", want: "exec-secret"},
+		{value: "exec://false", wantErr: true},
+		{value: "exec://", wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := tt.value.Resolve()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValue_Redacted(t *testing.T) {
+	v := Value("plain-secret")
+	b, err := json.Marshal(struct{ V Value }{V: v})
+	require.NoError(t, err)
+	assert.Equal(t, `{"V":"[REDACTED]"}`, string(b))
+	assert.Equal(t, Redacted, fmt.Sprintf("%s", v))
+	assert.Equal(t, Redacted, fmt.Sprintf("%v", v))
+	assert.Equal(t, Redacted, fmt.Sprintf("%#v", v))
+	assert.Equal(t, Redacted, dump.Dump(v))
+	assert.NotContains(t, fmt.Sprintf("%+v", struct{ V Value }{V: v}), "plain-secret")
+}
+
+func TestValue_ResolveErrorDoesNotContainSecret(t *testing.T) {
+	Register("test", ProviderFunc(func(ref string) (string, error) {
+		return "", fmt.Errorf("failed")
+	}))
+	defer func() {
+		mu.Lock()
+		delete(providers, "test")
+		mu.Unlock()
+	}()
+	_, err := Value("test://plain-secret").Resolve()
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "plain-secret")
+}
+
+func TestVaultProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/oracle":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"kv2-secret"},"metadata":{"version":1}}}`))
+		case "/v1/kv/oracle":
+			_, _ = w.Write([]byte(`{"data":{"password":"kv1-secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	getEnv = func(v string) (string, bool) {
+		switch v {
+		case "VAULT_ADDR":
+			return srv.URL, true
+		case "VAULT_TOKEN":
+			return "token", true
+		}
+		return "", false
+	}
+	defer func() { getEnv = os.LookupEnv }()
+
+	tests := []struct {
+		value   Value
+		want    string
+		wantErr bool
+	}{
+		{value: "vault://secret/data/oracle#password", want: "kv2-secret"},
+		{value: "vault://kv/oracle#password", want: "kv1-secret"},
+		{value: "vault://kv/oracle#missing", wantErr: true},
+		{value: "vault://kv/missing#password", wantErr: true},
+		{value: "vault://kv/oracle", wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := tt.value.Resolve()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestVaultProvider_DevServer runs against a Vault dev server, e.g.:
+//
+//	vault server -dev -dev-root-token-id=root
+//	vault kv put secret/oracle password=secret
+//	TEST_VAULT_ADDR=http://127.0.0.1:8200 TEST_VAULT_TOKEN=root go test ./...
+func TestVaultProvider_DevServer(t *testing.T) {
+	addr := os.Getenv("TEST_VAULT_ADDR")
+	token := os.Getenv("TEST_VAULT_TOKEN")
+	if len(addr) == 0 {
+		t.Skip()
+		return
+	}
+	getEnv = func(v string) (string, bool) {
+		switch v {
+		case "VAULT_ADDR":
+			return addr, true
+		case "VAULT_TOKEN":
+			return token, true
+		}
+		return "", false
+	}
+	defer func() { getEnv = os.LookupEnv }()
+
+	got, err := Value("vault://secret/data/oracle#password").Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, "secret", got)
+}
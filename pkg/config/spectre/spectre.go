@@ -16,12 +16,17 @@
 package spectre
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	oracleGeth "github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
 	"github.com/chronicleprotocol/oracle-suite/pkg/spectre"
@@ -41,6 +46,7 @@ var priceStoreFactory = func(cfg store.Config) (*store.PriceStore, error) {
 type Spectre struct {
 	Interval    int64                 `yaml:"interval"`
 	Medianizers map[string]Medianizer `yaml:"medianizers"`
+	OSMs        map[string]OSM        `yaml:"osms"`
 }
 
 type Medianizer struct {
@@ -50,11 +56,19 @@ type Medianizer struct {
 	MsgExpiration    int64   `yaml:"msgExpiration"`
 }
 
+// OSM is an Oracle Security Module contract that is poked whenever enough
+// time has passed since its last update.
+type OSM struct {
+	Contract string `yaml:"oracle"`
+}
+
 type Dependencies struct {
 	Signer         ethereum.Signer
 	PriceStore     *store.PriceStore
 	EthereumClient ethereum.Client
+	RPCClient      ethereumv2.Client
 	Feeds          []ethereum.Address
+	FeederAliases  map[ethereum.Address]ethereum.Address
 	Logger         log.Logger
 }
 
@@ -67,10 +81,12 @@ type PriceStoreDependencies struct {
 
 func (c *Spectre) ConfigureSpectre(d Dependencies) (*spectre.Spectre, error) {
 	cfg := spectre.Config{
-		Signer:     d.Signer,
-		Interval:   time.Second * time.Duration(c.Interval),
-		PriceStore: d.PriceStore,
-		Logger:     d.Logger,
+		Signer:        d.Signer,
+		Interval:      time.Second * time.Duration(c.Interval),
+		PriceStore:    d.PriceStore,
+		FeederAliases: d.FeederAliases,
+		Client:        d.RPCClient,
+		Logger:        d.Logger,
 	}
 	for name, pair := range c.Medianizers {
 		cfg.Pairs = append(cfg.Pairs, &spectre.Pair{
@@ -81,9 +97,53 @@ func (c *Spectre) ConfigureSpectre(d Dependencies) (*spectre.Spectre, error) {
 			Median:           oracleGeth.NewMedian(d.EthereumClient, ethereum.HexToAddress(pair.Contract)),
 		})
 	}
+	osmNames := maputil.Keys(c.OSMs)
+	sort.Strings(osmNames)
+	for _, name := range osmNames {
+		cfg.OSMs = append(cfg.OSMs, &spectre.OSM{
+			Name: name,
+			OSM:  oracleGeth.NewOSM(d.EthereumClient, ethereum.HexToAddress(c.OSMs[name].Contract)),
+		})
+	}
 	return spectreFactory(cfg)
 }
 
+// Validate verifies that all medianizers have valid asset pair names and
+// contract addresses and that all OSMs have valid contract addresses.
+// Returned errors are config.PathErrors relative to the spectre config.
+func (c *Spectre) Validate() error {
+	var errs []error
+	names := maputil.Keys(c.Medianizers)
+	sort.Strings(names)
+	for _, name := range names {
+		// Asset pairs are compared with price messages, which use names
+		// without a separator, e.g. ETHUSD:
+		if name == "" || strings.Contains(name, "/") {
+			errs = append(errs, config.WithPath(
+				fmt.Errorf("invalid asset pair name: %q, expected a name like ETHUSD", name),
+				"medianizers", name,
+			))
+		}
+		if !ethereum.IsHexAddress(c.Medianizers[name].Contract) {
+			errs = append(errs, config.WithPath(
+				fmt.Errorf("invalid oracle address: %s", c.Medianizers[name].Contract),
+				"medianizers", name, "oracle",
+			))
+		}
+	}
+	osmNames := maputil.Keys(c.OSMs)
+	sort.Strings(osmNames)
+	for _, name := range osmNames {
+		if !ethereum.IsHexAddress(c.OSMs[name].Contract) {
+			errs = append(errs, config.WithPath(
+				fmt.Errorf("invalid oracle address: %s", c.OSMs[name].Contract),
+				"osms", name, "oracle",
+			))
+		}
+	}
+	return config.Join(errs...)
+}
+
 func (c *Spectre) ConfigurePriceStore(d PriceStoreDependencies) (*store.PriceStore, error) {
 	cfg := store.Config{
 		Storage:   store.NewMemoryStorage(),
@@ -22,8 +22,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcclient"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
 	"github.com/chronicleprotocol/oracle-suite/pkg/spectre"
@@ -40,6 +42,7 @@ func TestSpectre_Configure(t *testing.T) {
 	interval := int64(10)
 	signer := &ethereumMocks.Signer{}
 	ethClient := &ethereumMocks.Client{}
+	txClient := &rpcclient.Client{}
 	feeds := []ethereum.Address{ethereum.HexToAddress("0x07a35a1d4b751a818d93aa38e615c0df23064881")}
 	ps := &store.PriceStore{}
 	logger := null.New()
@@ -54,6 +57,9 @@ func TestSpectre_Configure(t *testing.T) {
 				MsgExpiration:    1800,
 			},
 		},
+		OSMs: map[string]OSM{
+			"AAABBB": {Contract: "0x2d800d93b065ce011af83f316cef9f0d005b0aa4"},
+		},
 	}
 
 	spectreFactory = func(cfg spectre.Config) (*spectre.Spectre, error) {
@@ -66,6 +72,9 @@ func TestSpectre_Configure(t *testing.T) {
 		assert.Equal(t, secToDuration(config.Medianizers["AAABBB"].MsgExpiration), cfg.Pairs[0].PriceExpiration)
 		assert.Equal(t, config.Medianizers["AAABBB"].OracleSpread, cfg.Pairs[0].OracleSpread)
 		assert.Equal(t, ethereum.HexToAddress(config.Medianizers["AAABBB"].Contract), cfg.Pairs[0].Median.Address())
+		assert.Equal(t, "AAABBB", cfg.OSMs[0].Name)
+		assert.Equal(t, ethereum.HexToAddress(config.OSMs["AAABBB"].Contract), cfg.OSMs[0].OSM.Address())
+		assert.Equal(t, txClient, cfg.Client)
 		return &spectre.Spectre{}, nil
 	}
 
@@ -73,6 +82,7 @@ func TestSpectre_Configure(t *testing.T) {
 		Signer:         signer,
 		PriceStore:     ps,
 		EthereumClient: ethClient,
+		RPCClient:      txClient,
 		Feeds:          feeds,
 		Logger:         logger,
 	})
@@ -80,6 +90,31 @@ func TestSpectre_Configure(t *testing.T) {
 	require.NotNil(t, s)
 }
 
+func TestSpectre_Validate(t *testing.T) {
+	config := Spectre{
+		Medianizers: map[string]Medianizer{
+			"AAABBB":  {Contract: "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f"},
+			"CCC/DDD": {Contract: "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f"},
+			"EEEFFF":  {Contract: "foo"},
+		},
+		OSMs: map[string]OSM{
+			"AAABBB": {Contract: "0x2d800d93b065ce011af83f316cef9f0d005b0aa4"},
+			"CCCDDD": {Contract: "foo"},
+		},
+	}
+
+	err := config.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "medianizers.CCC/DDD: invalid asset pair name")
+	assert.Contains(t, err.Error(), "medianizers.EEEFFF.oracle: invalid oracle address: foo")
+	assert.Contains(t, err.Error(), "osms.CCCDDD.oracle: invalid oracle address: foo")
+	assert.NotContains(t, err.Error(), "AAABBB")
+}
+
 func secToDuration(s int64) time.Duration {
 	return time.Duration(s) * time.Second
 }
+
+func TestSpectre_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Spectre{}, "spectre", "testdata/schema.json")
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -89,3 +90,7 @@ func TestSpire_ConfigureClient(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, c)
 }
+
+func TestSpire_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Spire{}, "spire", "testdata/schema.json")
+}
@@ -25,8 +25,11 @@ import (
 	"strings"
 
 	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/multiformats/go-multiaddr"
 
 	suite "github.com/chronicleprotocol/oracle-suite"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
@@ -49,12 +52,12 @@ type Transport struct {
 }
 
 type P2P struct {
-	PrivKeySeed      string   `yaml:"privKeySeed"`
-	ListenAddrs      []string `yaml:"listenAddrs"`
-	BootstrapAddrs   []string `yaml:"bootstrapAddrs"`
-	DirectPeersAddrs []string `yaml:"directPeersAddrs"`
-	BlockedAddrs     []string `yaml:"blockedAddrs"`
-	DisableDiscovery bool     `yaml:"disableDiscovery"`
+	PrivKeySeed      secret.Value `yaml:"privKeySeed"`
+	ListenAddrs      []string     `yaml:"listenAddrs"`
+	BootstrapAddrs   []string     `yaml:"bootstrapAddrs"`
+	DirectPeersAddrs []string     `yaml:"directPeersAddrs"`
+	BlockedAddrs     []string     `yaml:"blockedAddrs"`
+	DisableDiscovery bool         `yaml:"disableDiscovery"`
 }
 
 type Scuttlebutt struct {
@@ -68,9 +71,10 @@ type Caps struct {
 }
 
 type Dependencies struct {
-	Signer ethereum.Signer
-	Feeds  []ethereum.Address
-	Logger log.Logger
+	Signer        ethereum.Signer
+	Feeds         []ethereum.Address
+	FeederAliases map[ethereum.Address]ethereum.Address
+	Logger        log.Logger
 }
 
 type BootstrapDependencies struct {
@@ -102,6 +106,7 @@ func (c *Transport) Configure(d Dependencies, t map[string]transport.Message) (t
 			DirectPeersAddrs: c.P2P.DirectPeersAddrs,
 			BlockedAddrs:     c.P2P.BlockedAddrs,
 			FeedersAddrs:     d.Feeds,
+			FeederAliases:    d.FeederAliases,
 			Discovery:        !c.P2P.DisableDiscovery,
 			Signer:           d.Signer,
 			Logger:           d.Logger,
@@ -139,12 +144,52 @@ func (c *Transport) ConfigureP2PBoostrap(d BootstrapDependencies) (transport.Tra
 	return p, nil
 }
 
+// Validate verifies the transport type, the private key seed and all
+// multiaddresses. Returned errors are config.PathErrors relative to the
+// transport config.
+func (c *Transport) Validate() error {
+	var errs []error
+	switch strings.ToLower(c.Transport) {
+	case "", LibP2P:
+	case LibSSB:
+		errs = append(errs, config.WithPath(errors.New("ssb not yet implemented"), "transport"))
+	default:
+		errs = append(errs, config.WithPath(fmt.Errorf("unknown transport %s", c.Transport), "transport"))
+	}
+	if _, err := c.generatePrivKey(); err != nil {
+		errs = append(errs, config.WithPath(err, "libp2p", "privKeySeed"))
+	}
+	addrs := map[string][]string{
+		"listenAddrs":      c.P2P.ListenAddrs,
+		"bootstrapAddrs":   c.P2P.BootstrapAddrs,
+		"directPeersAddrs": c.P2P.DirectPeersAddrs,
+		"blockedAddrs":     c.P2P.BlockedAddrs,
+	}
+	for _, key := range []string{"listenAddrs", "bootstrapAddrs", "directPeersAddrs", "blockedAddrs"} {
+		for i, addr := range addrs[key] {
+			if _, err := multiaddr.NewMultiaddr(addr); err != nil {
+				errs = append(errs, config.WithPath(
+					fmt.Errorf("invalid multiaddr %s: %w", addr, err),
+					"libp2p", key, i,
+				))
+			}
+		}
+	}
+	return config.Join(errs...)
+}
+
 func (c *Transport) generatePrivKey() (crypto.PrivKey, error) {
 	seedReader := rand.Reader
 	if len(c.P2P.PrivKeySeed) != 0 {
-		seed, err := hex.DecodeString(c.P2P.PrivKeySeed)
+		seedHex, err := c.P2P.PrivKeySeed.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("invalid privKeySeed value: %w", err)
+		}
+		seed, err := hex.DecodeString(seedHex)
 		if err != nil {
-			return nil, fmt.Errorf("invalid privKeySeed value, failed to decode hex data: %w", err)
+			// The hex decoder error is not wrapped because it may contain
+			// a part of the seed.
+			return nil, errors.New("invalid privKeySeed value, failed to decode hex data")
 		}
 		if len(seed) != ed25519.SeedSize {
 			return nil, fmt.Errorf("invalid privKeySeed value, 32 bytes expected")
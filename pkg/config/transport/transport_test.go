@@ -21,6 +21,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/schema/schematest"
+	"github.com/chronicleprotocol/oracle-suite/pkg/config/secret"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -96,7 +98,7 @@ func TestTransport_P2P_CustomValues(t *testing.T) {
 
 	config := Transport{
 		P2P: P2P{
-			PrivKeySeed:      privKeySeed,
+			PrivKeySeed:      secret.Value(privKeySeed),
 			ListenAddrs:      listenAddrs,
 			BootstrapAddrs:   bootstrapAddrs,
 			DirectPeersAddrs: directPeersAddrs,
@@ -163,3 +165,7 @@ func TestTransport_P2P_InvalidSeed(t *testing.T) {
 	}, nil)
 	require.Error(t, err)
 }
+
+func TestTransport_JSONSchema(t *testing.T) {
+	schematest.AssertGolden(t, Transport{}, "transport", "testdata/schema.json")
+}
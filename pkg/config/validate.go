@@ -0,0 +1,231 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Errors is a list of errors returned by the config validation.
+type Errors []error
+
+// Error implements the error interface.
+func (e Errors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// LineError is an error that occurred at a specific line of a YAML config
+// file.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+// Error implements the error interface.
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// PathError is an error related to a specific field of a config. The Path
+// field contains map keys (strings) and sequence indices (ints) leading from
+// the root node to the field.
+type PathError struct {
+	Path []interface{}
+	Err  error
+}
+
+// Error implements the error interface.
+func (e PathError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", pathString(e.Path), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e PathError) Unwrap() error {
+	return e.Err
+}
+
+// WithPath prepends the given path to all PathErrors in err. Other errors
+// are converted to PathErrors. If err is nil, nil is returned.
+func WithPath(err error, path ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	var errs Errors
+	if errors.As(err, &errs) {
+		var res Errors
+		for _, err := range errs {
+			res = append(res, WithPath(err, path...))
+		}
+		return res
+	}
+	var pathErr PathError
+	if errors.As(err, &pathErr) {
+		return PathError{Path: append(append([]interface{}{}, path...), pathErr.Path...), Err: pathErr.Err}
+	}
+	return PathError{Path: path, Err: err}
+}
+
+// Join combines the given errors into Errors. Nil errors are skipped. If all
+// errors are nil, nil is returned.
+func Join(errs ...error) error {
+	var res Errors
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var list Errors
+		if errors.As(err, &list) {
+			res = append(res, list...)
+			continue
+		}
+		res = append(res, err)
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+// ValidateFile parses the given YAML config file, decodes it into the out
+// value and then invokes the validate function. Contrary to ParseFile, it
+// does not stop at the first error, but returns all the errors found.
+// Errors are annotated with the line number in the config file whenever
+// it is possible to determine it.
+func ValidateFile(out interface{}, path string, validate func() error) []error {
+	b, err := LoadFile(path)
+	if err != nil {
+		return []error{err}
+	}
+	return Validate(out, b, validate)
+}
+
+// Validate works like ValidateFile but reads the config from the byte slice.
+func Validate(out interface{}, config []byte, validate func() error) []error {
+	n := yaml.Node{}
+	if err := yaml.Unmarshal(config, &n); err != nil {
+		return []error{err}
+	}
+	var res []error
+	if err := yamlReplaceEnvVars(&n); err != nil {
+		res = append(res, flatten(err)...)
+	}
+	if err := n.Decode(out); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return append(res, err)
+		}
+		// Unlike other errors, the TypeError does not interrupt decoding,
+		// so it is still possible to validate the rest of the config.
+		for _, msg := range typeErr.Errors {
+			res = append(res, errors.New(msg))
+		}
+	}
+	if len(res) > 0 || validate == nil {
+		return res
+	}
+	for _, err := range flatten(validate()) {
+		var pathErr PathError
+		if errors.As(err, &pathErr) {
+			if line := yamlLine(&n, pathErr.Path); line > 0 {
+				err = LineError{Line: line, Err: err}
+			}
+		}
+		res = append(res, err)
+	}
+	return res
+}
+
+func flatten(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var errs Errors
+	if errors.As(err, &errs) {
+		return errs
+	}
+	return []error{err}
+}
+
+// yamlLine returns the line number of the node pointed by the path. If the
+// node cannot be found, the line number of the closest parent node is
+// returned.
+func yamlLine(n *yaml.Node, path []interface{}) int {
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for _, p := range path {
+		c := yamlChild(n, p)
+		if c == nil {
+			break
+		}
+		n = c
+	}
+	return n.Line
+}
+
+func yamlChild(n *yaml.Node, p interface{}) *yaml.Node {
+	switch n.Kind {
+	case yaml.MappingNode:
+		k, ok := p.(string)
+		if !ok {
+			return nil
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == k {
+				return n.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		i, ok := p.(int)
+		if !ok || i < 0 || i >= len(n.Content) {
+			return nil
+		}
+		return n.Content[i]
+	}
+	return nil
+}
+
+func pathString(path []interface{}) string {
+	s := strings.Builder{}
+	for _, p := range path {
+		switch v := p.(type) {
+		case int:
+			s.WriteString(fmt.Sprintf("[%d]", v))
+		default:
+			if s.Len() > 0 {
+				s.WriteString(".")
+			}
+			s.WriteString(fmt.Sprint(v))
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,94 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	getEnv = func(v string) (string, bool) {
+		return "", false
+	}
+	defer func() { getEnv = os.LookupEnv }()
+	tests := []struct {
+		config   string
+		validate func() error
+		want     []string
+	}{
+		{
+			config: "foo: bar\n",
+			want:   nil,
+		},
+		{
+			config: "foo: ${a}\nbar: ${b}\n",
+			want: []string{
+				"line 1: environment variable a not set",
+				"line 2: environment variable b not set",
+			},
+		},
+		{
+			config: "foo: bar\nbaz: [1]\n",
+			want: []string{
+				"line 2: cannot unmarshal !!seq into int",
+			},
+		},
+		{
+			config: "foo: bar\nqux:\n  - a: 1\n  - a: 2\n",
+			validate: func() error {
+				return Join(
+					WithPath(errors.New("err1"), "qux", 1, "a"),
+					WithPath(errors.New("err2"), "foo"),
+					errors.New("err3"),
+				)
+			},
+			want: []string{
+				"line 4: qux[1].a: err1",
+				"line 1: foo: err2",
+				"err3",
+			},
+		},
+		{
+			config: "foo: bar\n",
+			validate: func() error {
+				return WithPath(Join(errors.New("err1"), WithPath(errors.New("err2"), "b")), "a")
+			},
+			want: []string{
+				"line 1: a: err1",
+				"line 1: a.b: err2",
+			},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			out := &struct {
+				Foo string
+				Baz int
+				Qux []struct{ A int }
+			}{}
+			var got []string
+			for _, err := range Validate(out, []byte(tt.config), tt.validate) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -18,6 +18,7 @@ package ethereum
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 // Aliases for the go-ethereum types and functions used in multiple packages.
@@ -29,8 +30,9 @@ import (
 const AddressLength = common.AddressLength
 
 type (
-	Address = common.Address
-	Hash    = common.Hash
+	Address   = common.Address
+	Hash      = common.Hash
+	TypedData = apitypes.TypedData
 )
 
 // HexToAddress returns Address from hex representation.
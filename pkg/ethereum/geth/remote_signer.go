@@ -0,0 +1,257 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+const defaultRemoteSignerTimeout = 10 * time.Second
+
+// RemoteSignerAPI is the JSON-RPC API used to communicate with the remote
+// signer.
+type RemoteSignerAPI string
+
+const (
+	// ClefAPI uses the account_signData, account_signTypedData and
+	// account_signTransaction methods.
+	ClefAPI RemoteSignerAPI = "clef"
+	// Web3SignerAPI uses the eth_sign, eth_signTypedData and
+	// eth_signTransaction methods.
+	Web3SignerAPI RemoteSignerAPI = "web3signer"
+)
+
+var ErrRemoteSignerAddress = errors.New("remote signer returned a transaction signed by a different address")
+
+// RemoteSignerConfig is the configuration for the RemoteSigner.
+type RemoteSignerConfig struct {
+	// URL is the JSON-RPC endpoint of the remote signer.
+	URL string
+	// API is the JSON-RPC API supported by the remote signer.
+	API RemoteSignerAPI
+	// Address is the address of the account used to sign data.
+	Address ethereum.Address
+	// HTTPClient is the HTTP client used to communicate with the remote
+	// signer. It may be used to configure TLS client certificates. If nil,
+	// the default client is used.
+	HTTPClient *http.Client
+	// Timeout is the timeout for a single request. If zero, the default
+	// timeout is used.
+	Timeout time.Duration
+}
+
+// RemoteSigner implements the ethereum.Signer interface. It delegates
+// signing to an external service, so private keys do not have to be stored
+// on the same host.
+type RemoteSigner struct {
+	client  *rpc.Client
+	api     RemoteSignerAPI
+	address ethereum.Address
+	timeout time.Duration
+}
+
+// NewRemoteSigner returns a new RemoteSigner instance.
+func NewRemoteSigner(cfg RemoteSignerConfig) (*RemoteSigner, error) {
+	switch cfg.API {
+	case ClefAPI, Web3SignerAPI:
+	default:
+		return nil, fmt.Errorf("unsupported remote signer API: %s", cfg.API)
+	}
+	if cfg.Address == ethereum.EmptyAddress {
+		return nil, errors.New("remote signer address must be specified")
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	client, err := rpc.DialHTTPWithClient(cfg.URL, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the remote signer: %w", err)
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultRemoteSignerTimeout
+	}
+	return &RemoteSigner{
+		client:  client,
+		api:     cfg.API,
+		address: cfg.Address,
+		timeout: timeout,
+	}, nil
+}
+
+// Address implements the ethereum.Signer interface.
+func (s *RemoteSigner) Address() ethereum.Address {
+	return s.address
+}
+
+// SignTransaction implements the ethereum.Signer interface.
+func (s *RemoteSigner) SignTransaction(transaction *ethereum.Transaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	args := remoteSignerTx{
+		From:                 s.address,
+		To:                   &transaction.Address,
+		Nonce:                hexutil.Uint64(transaction.Nonce),
+		MaxFeePerGas:         (*hexutil.Big)(transaction.MaxFee),
+		MaxPriorityFeePerGas: (*hexutil.Big)(transaction.PriorityFee),
+		Value:                (*hexutil.Big)(big.NewInt(0)),
+		Data:                 transaction.Data,
+		ChainID:              (*hexutil.Big)(transaction.ChainID),
+	}
+	if transaction.GasLimit != nil {
+		args.Gas = hexutil.Uint64(transaction.GasLimit.Uint64())
+	}
+	var raw hexutil.Bytes
+	switch s.api {
+	case ClefAPI:
+		var res struct {
+			Raw hexutil.Bytes `json:"raw"`
+		}
+		if err := s.client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+			return fmt.Errorf("remote signer error: %w", err)
+		}
+		raw = res.Raw
+	case Web3SignerAPI:
+		if err := s.client.CallContext(ctx, &raw, "eth_signTransaction", args); err != nil {
+			return fmt.Errorf("remote signer error: %w", err)
+		}
+	}
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return fmt.Errorf("remote signer returned an invalid transaction: %w", err)
+	}
+	from, err := types.LatestSignerForChainID(tx.ChainId()).Sender(tx)
+	if err != nil {
+		return fmt.Errorf("remote signer returned an invalid transaction: %w", err)
+	}
+	if from != s.address {
+		return ErrRemoteSignerAddress
+	}
+	transaction.SignedTx = tx
+	return nil
+}
+
+// Signature implements the ethereum.Signer interface.
+func (s *RemoteSigner) Signature(data []byte) (ethereum.Signature, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	var sig hexutil.Bytes
+	switch s.api {
+	case ClefAPI:
+		// The "text/plain" content type makes Clef to sign the data using
+		// the same prefix as the Signature function.
+		err := s.client.CallContext(ctx, &sig, "account_signData", "text/plain", s.address, hexutil.Bytes(data))
+		if err != nil {
+			return ethereum.Signature{}, fmt.Errorf("remote signer error: %w", err)
+		}
+	case Web3SignerAPI:
+		err := s.client.CallContext(ctx, &sig, "eth_sign", s.address, hexutil.Bytes(data))
+		if err != nil {
+			return ethereum.Signature{}, fmt.Errorf("remote signer error: %w", err)
+		}
+	}
+	if len(sig) != ethereum.SignatureLength {
+		return ethereum.Signature{}, fmt.Errorf("remote signer returned a signature with invalid length: %d", len(sig))
+	}
+	// Some signers return V as 0/1, transform it to 27/28 according to
+	// the yellow paper:
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	signature := ethereum.SignatureFromBytes(sig)
+	addr, err := Recover(signature, data)
+	if err != nil {
+		return ethereum.Signature{}, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+	if *addr != s.address {
+		return ethereum.Signature{}, errors.New("remote signer returned a signature of a different address")
+	}
+	return signature, nil
+}
+
+// SignTypedData implements the ethereum.TypedDataSigner interface.
+func (s *RemoteSigner) SignTypedData(data ethereum.TypedData) (ethereum.Signature, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	var sig hexutil.Bytes
+	method := "account_signTypedData"
+	if s.api == Web3SignerAPI {
+		method = "eth_signTypedData"
+	}
+	if err := s.client.CallContext(ctx, &sig, method, s.address, data); err != nil {
+		return ethereum.Signature{}, fmt.Errorf("remote signer error: %w", err)
+	}
+	if len(sig) != ethereum.SignatureLength {
+		return ethereum.Signature{}, fmt.Errorf("remote signer returned a signature with invalid length: %d", len(sig))
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	signature := ethereum.SignatureFromBytes(sig)
+	addr, err := RecoverTypedData(signature, data)
+	if err != nil {
+		return ethereum.Signature{}, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+	if *addr != s.address {
+		return ethereum.Signature{}, errors.New("remote signer returned a signature of a different address")
+	}
+	return signature, nil
+}
+
+// RecoverTypedData implements the ethereum.TypedDataSigner interface.
+func (s *RemoteSigner) RecoverTypedData(signature ethereum.Signature, data ethereum.TypedData) (*ethereum.Address, error) {
+	return RecoverTypedData(signature, data)
+}
+
+// Recover implements the ethereum.Signer interface.
+func (s *RemoteSigner) Recover(signature ethereum.Signature, data []byte) (*ethereum.Address, error) {
+	return Recover(signature, data)
+}
+
+type remoteSignerTx struct {
+	From                 ethereum.Address  `json:"from"`
+	To                   *ethereum.Address `json:"to"`
+	Gas                  hexutil.Uint64    `json:"gas"`
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big      `json:"value"`
+	Nonce                hexutil.Uint64    `json:"nonce"`
+	Data                 hexutil.Bytes     `json:"data"`
+	ChainID              *hexutil.Big      `json:"chainId,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. Clef expects the
+// "input" field, while Web3Signer expects the "data" field, so both are set.
+func (t remoteSignerTx) MarshalJSON() ([]byte, error) {
+	type tx remoteSignerTx
+	return json.Marshal(struct {
+		tx
+		Input hexutil.Bytes `json:"input"`
+	}{tx: tx(t), Input: t.Data})
+}
@@ -0,0 +1,218 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// mockSignerService implements both Clef and Web3Signer APIs using a local
+// keystore account.
+type mockSignerService struct {
+	signer *Signer
+}
+
+type mockSignerTx struct {
+	From                 ethereum.Address `json:"from"`
+	To                   ethereum.Address `json:"to"`
+	Gas                  hexutil.Uint64   `json:"gas"`
+	MaxFeePerGas         *hexutil.Big     `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big     `json:"maxPriorityFeePerGas"`
+	Nonce                hexutil.Uint64   `json:"nonce"`
+	Data                 hexutil.Bytes    `json:"data"`
+	Input                hexutil.Bytes    `json:"input"`
+	ChainID              *hexutil.Big     `json:"chainId"`
+}
+
+func (s *mockSignerService) sign(addr ethereum.Address, data []byte) (hexutil.Bytes, error) {
+	if addr != s.signer.Address() {
+		return nil, errors.New("unknown account")
+	}
+	sig, err := s.signer.Signature(data)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:], nil
+}
+
+func (s *mockSignerService) signTypedData(addr ethereum.Address, data ethereum.TypedData) (hexutil.Bytes, error) {
+	if addr != s.signer.Address() {
+		return nil, errors.New("unknown account")
+	}
+	sig, err := s.signer.SignTypedData(data)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:], nil
+}
+
+func (s *mockSignerService) signTransaction(args mockSignerTx) (hexutil.Bytes, error) {
+	if args.From != s.signer.Address() {
+		return nil, errors.New("unknown account")
+	}
+	tx := &ethereum.Transaction{
+		Address:     args.To,
+		Nonce:       uint64(args.Nonce),
+		PriorityFee: args.MaxPriorityFeePerGas.ToInt(),
+		MaxFee:      args.MaxFeePerGas.ToInt(),
+		GasLimit:    new(big.Int).SetUint64(uint64(args.Gas)),
+		Data:        args.Input,
+		ChainID:     args.ChainID.ToInt(),
+	}
+	if err := s.signer.SignTransaction(tx); err != nil {
+		return nil, err
+	}
+	return tx.SignedTx.(*types.Transaction).MarshalBinary()
+}
+
+type mockClefService struct{ mockSignerService }
+
+func (s *mockClefService) SignData(contentType string, addr ethereum.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != "text/plain" {
+		return nil, errors.New("unsupported content type")
+	}
+	return s.sign(addr, data)
+}
+
+func (s *mockClefService) SignTypedData(addr ethereum.Address, data ethereum.TypedData) (hexutil.Bytes, error) {
+	return s.signTypedData(addr, data)
+}
+
+func (s *mockClefService) SignTransaction(args mockSignerTx) (map[string]interface{}, error) {
+	raw, err := s.signTransaction(args)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": raw}, nil
+}
+
+type mockWeb3SignerService struct{ mockSignerService }
+
+func (s *mockWeb3SignerService) Sign(addr ethereum.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	sig, err := s.sign(addr, data)
+	if err != nil {
+		return nil, err
+	}
+	// Return V as 0/1 to test normalization.
+	sig[64] -= 27
+	return sig, nil
+}
+
+func (s *mockWeb3SignerService) SignTypedData(addr ethereum.Address, data ethereum.TypedData) (hexutil.Bytes, error) {
+	sig, err := s.signTypedData(addr, data)
+	if err != nil {
+		return nil, err
+	}
+	// Return V as 0/1 to test normalization.
+	sig[64] -= 27
+	return sig, nil
+}
+
+func (s *mockWeb3SignerService) SignTransaction(args mockSignerTx) (hexutil.Bytes, error) {
+	return s.signTransaction(args)
+}
+
+func newMockSignerServer(t *testing.T) *httptest.Server {
+	account, err := NewAccount("./testdata/keystore", "test123", signerAddress)
+	require.NoError(t, err)
+	svc := mockSignerService{signer: NewSigner(account)}
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("account", &mockClefService{svc}))
+	require.NoError(t, srv.RegisterName("eth", &mockWeb3SignerService{svc}))
+	return httptest.NewServer(srv)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	srv := newMockSignerServer(t)
+	defer srv.Close()
+
+	for n, api := range []RemoteSignerAPI{ClefAPI, Web3SignerAPI} {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			signer, err := NewRemoteSigner(RemoteSignerConfig{
+				URL:     srv.URL,
+				API:     api,
+				Address: signerAddress,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, signerAddress, signer.Address())
+
+			// Signature:
+			sig, err := signer.Signature(signerData)
+			require.NoError(t, err)
+			assert.Equal(t, signerSignature, sig)
+
+			// SignTypedData:
+			sig, err = signer.SignTypedData(signerTypedData)
+			require.NoError(t, err)
+			addr, err := RecoverTypedData(sig, signerTypedData)
+			require.NoError(t, err)
+			assert.Equal(t, signerAddress, *addr)
+
+			// SignTransaction:
+			tx := &ethereum.Transaction{
+				Address:     ethereum.HexToAddress("0x1111111111111111111111111111111111111111"),
+				Nonce:       10,
+				PriorityFee: big.NewInt(1),
+				MaxFee:      big.NewInt(2),
+				GasLimit:    big.NewInt(100000),
+				Data:        []byte{1, 2, 3},
+				ChainID:     big.NewInt(1),
+			}
+			require.NoError(t, signer.SignTransaction(tx))
+			signedTx := tx.SignedTx.(*types.Transaction)
+			from, err := types.LatestSignerForChainID(big.NewInt(1)).Sender(signedTx)
+			require.NoError(t, err)
+			assert.Equal(t, signerAddress, from)
+			assert.Equal(t, uint64(10), signedTx.Nonce())
+			assert.Equal(t, uint64(100000), signedTx.Gas())
+			assert.Equal(t, []byte{1, 2, 3}, signedTx.Data())
+			assert.Equal(t, tx.Address, *signedTx.To())
+		})
+	}
+}
+
+func TestRemoteSigner_UnknownAccount(t *testing.T) {
+	srv := newMockSignerServer(t)
+	defer srv.Close()
+
+	signer, err := NewRemoteSigner(RemoteSignerConfig{
+		URL:     srv.URL,
+		API:     ClefAPI,
+		Address: ethereum.HexToAddress("0x1111111111111111111111111111111111111111"),
+	})
+	require.NoError(t, err)
+	_, err = signer.Signature(signerData)
+	assert.Error(t, err)
+}
+
+func TestNewRemoteSigner_InvalidConfig(t *testing.T) {
+	_, err := NewRemoteSigner(RemoteSignerConfig{URL: "http://localhost", API: "unknown", Address: signerAddress})
+	assert.Error(t, err)
+	_, err = NewRemoteSigner(RemoteSignerConfig{URL: "http://localhost", API: ClefAPI})
+	assert.Error(t, err)
+}
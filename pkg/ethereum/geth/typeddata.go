@@ -0,0 +1,103 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// SignTypedData implements the ethereum.TypedDataSigner interface.
+func (s *Signer) SignTypedData(data ethereum.TypedData) (ethereum.Signature, error) {
+	return SignatureTypedData(s.account, data)
+}
+
+// RecoverTypedData implements the ethereum.TypedDataSigner interface.
+func (s *Signer) RecoverTypedData(signature ethereum.Signature, data ethereum.TypedData) (*ethereum.Address, error) {
+	return RecoverTypedData(signature, data)
+}
+
+// TypedDataHash returns the EIP-712 hash of the typed data:
+// keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message)).
+func TypedDataHash(data ethereum.TypedData) ([]byte, error) {
+	msg, err := typedDataMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(msg), nil
+}
+
+// SignatureTypedData signs the EIP-712 hash of the typed data.
+func SignatureTypedData(account *Account, data ethereum.TypedData) (ethereum.Signature, error) {
+	msg, err := typedDataMessage(data)
+	if err != nil {
+		return ethereum.Signature{}, err
+	}
+
+	// The SignDataWithPassphrase method hashes the message before signing:
+	signature, err := account.wallet.SignDataWithPassphrase(*account.account, account.passphrase, "", msg)
+	if err != nil {
+		return ethereum.Signature{}, err
+	}
+
+	// Transform V from 0/1 to 27/28 according to the yellow paper:
+	signature[64] += 27
+
+	return ethereum.SignatureFromBytes(signature), nil
+}
+
+// RecoverTypedData returns the address that created the EIP-712 signature
+// of the typed data.
+func RecoverTypedData(signature ethereum.Signature, data ethereum.TypedData) (*ethereum.Address, error) {
+	if signature[64] != 27 && signature[64] != 28 {
+		return nil, ErrInvalidSignature
+	}
+
+	// Transform V from 27/28 to 0/1 according to yellow paper:
+	signature[64] -= 27
+
+	hash, err := TypedDataHash(data)
+	if err != nil {
+		return nil, err
+	}
+
+	rpk, err := crypto.SigToPub(hash, signature[:])
+	if err != nil {
+		return nil, err
+	}
+
+	address := crypto.PubkeyToAddress(*rpk)
+	return &address, nil
+}
+
+// typedDataMessage returns the EIP-712 message that has to be hashed and
+// signed.
+func typedDataMessage(data ethereum.TypedData) ([]byte, error) {
+	domainSeparator, err := data.HashStruct("EIP712Domain", data.Domain.Map())
+	if err != nil {
+		return nil, err
+	}
+	structHash, err := data.HashStruct(data.PrimaryType, data.Message)
+	if err != nil {
+		return nil, err
+	}
+	msg := make([]byte, 0, 2+len(domainSeparator)+len(structHash))
+	msg = append(msg, 0x19, 0x01)
+	msg = append(msg, domainSeparator...)
+	msg = append(msg, structHash...)
+	return msg, nil
+}
@@ -0,0 +1,67 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+var signerTypedData = ethereum.TypedData{
+	Types: apitypes.Types{
+		"EIP712Domain": {{Name: "name", Type: "string"}},
+		"Message":      {{Name: "data", Type: "bytes"}},
+	},
+	PrimaryType: "Message",
+	Domain:      apitypes.TypedDataDomain{Name: "test"},
+	Message:     apitypes.TypedDataMessage{"data": "0x666f6f"},
+}
+
+func TestSigner_SignTypedData(t *testing.T) {
+	account, err := NewAccount("./testdata/keystore", "test123", signerAddress)
+	require.NoError(t, err)
+
+	signer := NewSigner(account)
+	sig, err := signer.SignTypedData(signerTypedData)
+	require.NoError(t, err)
+
+	addr, err := NewSigner(nil).RecoverTypedData(sig, signerTypedData)
+	require.NoError(t, err)
+	assert.Equal(t, signerAddress, *addr)
+
+	// The typed data signature must not be valid as a regular signature
+	// of the same data:
+	addr, err = NewSigner(nil).Recover(sig, []byte("foo"))
+	require.NoError(t, err)
+	assert.NotEqual(t, signerAddress, *addr)
+
+	// Signature of different domain must not be valid:
+	td := signerTypedData
+	td.Domain = apitypes.TypedDataDomain{Name: "other"}
+	addr, err = NewSigner(nil).RecoverTypedData(sig, td)
+	require.NoError(t, err)
+	assert.NotEqual(t, signerAddress, *addr)
+}
+
+func TestRecoverTypedData_InvalidV(t *testing.T) {
+	_, err := RecoverTypedData(ethereum.Signature{}, signerTypedData)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
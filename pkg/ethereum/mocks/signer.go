@@ -44,3 +44,13 @@ func (s *Signer) Recover(signature ethereum.Signature, data []byte) (*ethereum.A
 	args := s.Called(signature, data)
 	return args.Get(0).(*ethereum.Address), args.Error(1)
 }
+
+func (s *Signer) SignTypedData(data ethereum.TypedData) (ethereum.Signature, error) {
+	args := s.Called(data)
+	return args.Get(0).(ethereum.Signature), args.Error(1)
+}
+
+func (s *Signer) RecoverTypedData(signature ethereum.Signature, data ethereum.TypedData) (*ethereum.Address, error) {
+	args := s.Called(signature, data)
+	return args.Get(0).(*ethereum.Address), args.Error(1)
+}
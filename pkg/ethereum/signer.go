@@ -74,3 +74,13 @@ type Signer interface {
 	// TODO: Move Recover to a separate interface.
 	Recover(signature Signature, data []byte) (*Address, error)
 }
+
+// TypedDataSigner is an optional interface implemented by signers that
+// support EIP-712 typed data signatures.
+type TypedDataSigner interface {
+	// SignTypedData signs the EIP-712 hash of the given typed data.
+	SignTypedData(data TypedData) (Signature, error)
+	// RecoverTypedData returns the wallet address that created the given
+	// typed data signature.
+	RecoverTypedData(signature Signature, data TypedData) (*Address, error)
+}
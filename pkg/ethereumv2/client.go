@@ -17,12 +17,17 @@ package ethereumv2
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
 )
 
 // Client is a lightweight Ethereum RPC.
 type Client interface {
+	// ChainID performs eth_chainId RPC call.
+	//
+	// It returns the current chain ID.
+	ChainID(ctx context.Context) (uint64, error)
 	// BlockNumber performs eth_blockNumber RPC call.
 	//
 	// It returns the current block number.
@@ -35,6 +40,16 @@ type Client interface {
 	//
 	// It returns the block with the given number along with all transactions.
 	FullBlockByNumber(ctx context.Context, number types.BlockNumber) (*types.BlockTxObjects, error)
+	// TransactionByHash performs eth_getTransactionByHash RPC call.
+	//
+	// It returns the transaction with the given hash. If the transaction
+	// is unknown, nil is returned.
+	TransactionByHash(ctx context.Context, hash types.Hash) (*types.Transaction, error)
+	// TransactionReceipt performs eth_getTransactionReceipt RPC call.
+	//
+	// It returns the receipt of the transaction with the given hash. If the
+	// transaction is unknown or pending, nil is returned.
+	TransactionReceipt(ctx context.Context, hash types.Hash) (*types.TransactionReceiptType, error)
 	// GetTransactionCount performs eth_getTransactionCount RPC call.
 	//
 	// It returns the number of transactions sent from the given address.
@@ -52,4 +67,45 @@ type Client interface {
 	//
 	// FilterLogs returns logs that match the given query.
 	FilterLogs(ctx context.Context, q types.FilterLogsQuery) ([]types.Log, error)
+	// Call performs eth_call RPC call.
+	//
+	// It executes a new message call immediately without creating a
+	// transaction on the blockchain.
+	Call(ctx context.Context, call types.Call, block types.BlockNumber) ([]byte, error)
+	// CallWithOverrides performs eth_call RPC call with the state and block
+	// overrides.
+	//
+	// It works like Call, but the call is executed on top of the state and
+	// the block header modified by the given overrides. Both overrides are
+	// optional and may be nil. Not all nodes support overrides.
+	CallWithOverrides(
+		ctx context.Context,
+		call types.Call,
+		block types.BlockNumber,
+		state *types.StateOverride,
+		header *types.BlockOverride,
+	) ([]byte, error)
+	// EstimateGas performs eth_estimateGas RPC call.
+	//
+	// It estimates the gas necessary to execute a specific transaction.
+	EstimateGas(ctx context.Context, call types.Call, block types.BlockNumber) (uint64, error)
+	// GasPrice performs eth_gasPrice RPC call.
+	//
+	// It returns the current price per gas in wei.
+	GasPrice(ctx context.Context) (*big.Int, error)
+	// MaxPriorityFeePerGas performs eth_maxPriorityFeePerGas RPC call.
+	//
+	// It returns the estimated priority fee per gas in wei required for a
+	// transaction to be included in a block.
+	MaxPriorityFeePerGas(ctx context.Context) (*big.Int, error)
+	// FeeHistory performs eth_feeHistory RPC call.
+	//
+	// It returns the base fees and the priority fees at the given reward
+	// percentiles for the blockCount blocks ending at newestBlock.
+	FeeHistory(
+		ctx context.Context,
+		blockCount uint64,
+		newestBlock types.BlockNumber,
+		rewardPercentiles []float64,
+	) (*types.FeeHistory, error)
 }
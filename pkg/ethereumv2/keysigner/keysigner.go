@@ -0,0 +1,167 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package keysigner provides an implementation of the ethereumv2.Signer
+// interface that signs data using a private key.
+package keysigner
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+var ErrMissingAccount = errors.New("unable to find account for requested address")
+var ErrInvalidSignature = errors.New("invalid Ethereum signature (V is not 27 or 28)")
+var ErrInvalidTxSignature = errors.New("invalid transaction signature")
+
+// Signer implements the ethereumv2.Signer interface using a private key.
+type Signer struct {
+	key     *ecdsa.PrivateKey
+	address types.Address
+}
+
+// New returns a new Signer instance that uses the given private key.
+func New(key *ecdsa.PrivateKey) *Signer {
+	return &Signer{
+		key:     key,
+		address: types.Address(crypto.PubkeyToAddress(key.PublicKey)),
+	}
+}
+
+// NewFromKeystore returns a new Signer instance that uses the key for the
+// given address stored in the keystore directory. The key is decrypted
+// using the given passphrase.
+func NewFromKeystore(path string, address types.Address, passphrase string) (*Signer, error) {
+	files, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(path, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var header struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			continue // Not a key file.
+		}
+		if types.HexToAddress(header.Address) != address {
+			continue
+		}
+		key, err := keystore.DecryptKey(data, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt key for address %s: %w", address.String(), err)
+		}
+		return New(key.PrivateKey), nil
+	}
+	return nil, ErrMissingAccount
+}
+
+// Address implements the ethereumv2.Signer interface.
+func (s *Signer) Address() types.Address {
+	return s.address
+}
+
+// SignMessage implements the ethereumv2.Signer interface.
+func (s *Signer) SignMessage(data []byte) (*types.Signature, error) {
+	sig, err := crypto.Sign(messageHash(data), s.key)
+	if err != nil {
+		return nil, err
+	}
+	// Transform V from 0/1 to 27/28 according to the yellow paper.
+	sig[64] += 27
+	signature := types.BytesToSignature(sig)
+	return &signature, nil
+}
+
+// SignTransaction implements the ethereumv2.Signer interface.
+func (s *Signer) SignTransaction(tx *types.DynamicFeeTransaction) error {
+	if tx.ChainID == 0 {
+		return errors.New("unable to sign transaction: chain ID is not set")
+	}
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return err
+	}
+	sig, err := crypto.Sign(hash.Bytes(), s.key)
+	if err != nil {
+		return err
+	}
+	signature := types.BytesToSignature(sig)
+	tx.Signature = &signature
+	return nil
+}
+
+// RecoverMessage returns the address of the account that signed the given
+// data using the SignMessage method.
+func RecoverMessage(data []byte, signature types.Signature) (*types.Address, error) {
+	if signature.V() != 27 && signature.V() != 28 {
+		return nil, ErrInvalidSignature
+	}
+	// Transform V from 27/28 to 0/1 according to the yellow paper.
+	sig := types.VRSToSignature(signature.V()-27, signature.R(), signature.S())
+	pub, err := crypto.SigToPub(messageHash(data), sig.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	address := types.Address(crypto.PubkeyToAddress(*pub))
+	return &address, nil
+}
+
+// RecoverTransaction returns the address of the account that signed the
+// given transaction.
+func RecoverTransaction(tx *types.DynamicFeeTransaction) (*types.Address, error) {
+	if tx.Signature == nil {
+		return nil, types.ErrUnsignedTransaction
+	}
+	if tx.Signature.V() > 1 || tx.Signature.S().Cmp(secp256k1HalfN) > 0 {
+		return nil, ErrInvalidTxSignature
+	}
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.SigToPub(hash.Bytes(), tx.Signature.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	address := types.Address(crypto.PubkeyToAddress(*pub))
+	return &address, nil
+}
+
+// secp256k1HalfN is the half of the secp256k1 curve order. Signatures with
+// the S value greater than this are malleable and rejected (EIP-2).
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// messageHash returns the hash of the data formatted as an Ethereum signed
+// message.
+func messageHash(data []byte) []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)))
+}
@@ -0,0 +1,107 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package keysigner
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+var accountAddress = types.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+
+func TestNewFromKeystore(t *testing.T) {
+	tests := []struct {
+		address    types.Address
+		passphrase string
+		wantErr    bool
+	}{
+		{address: accountAddress, passphrase: "test123"},
+		{address: accountAddress, passphrase: "invalid", wantErr: true},
+		{address: types.HexToAddress("0x1"), passphrase: "test123", wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			s, err := NewFromKeystore("./testdata/keystore", tt.address, tt.passphrase)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.address, s.Address())
+		})
+	}
+}
+
+func TestSigner_SignMessage(t *testing.T) {
+	s, err := NewFromKeystore("./testdata/keystore", accountAddress, "test123")
+	require.NoError(t, err)
+	data := []byte("hello world")
+
+	sig, err := s.SignMessage(data)
+	require.NoError(t, err)
+	assert.Contains(t, []uint8{27, 28}, sig.V())
+
+	// The signature must be recoverable:
+	addr, err := RecoverMessage(data, *sig)
+	require.NoError(t, err)
+	assert.Equal(t, accountAddress, *addr)
+
+	// The signature must be compatible with the old signer:
+	account, err := geth.NewAccount("../../ethereum/geth/testdata/keystore", "test123", common.Address(accountAddress))
+	require.NoError(t, err)
+	oldSig, err := geth.NewSigner(account).Signature(data)
+	require.NoError(t, err)
+	assert.Equal(t, oldSig.Bytes(), sig.Bytes())
+}
+
+func TestRecoverMessage_InvalidV(t *testing.T) {
+	_, err := RecoverMessage([]byte("hello world"), types.Signature{})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigner_SignTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	s := New(key)
+	to := types.HexToAddress("0x00112233445566778899aabbccddeeff00112233")
+	nonce := uint64(1)
+	tx := &types.DynamicFeeTransaction{
+		ChainID:              1,
+		Nonce:                &nonce,
+		MaxPriorityFeePerGas: big.NewInt(1),
+		MaxFeePerGas:         big.NewInt(2),
+		GasLimit:             21000,
+		To:                   &to,
+	}
+
+	require.NoError(t, s.SignTransaction(tx))
+	require.NotNil(t, tx.Signature)
+	addr, err := RecoverTransaction(tx)
+	require.NoError(t, err)
+	assert.Equal(t, s.Address(), *addr)
+
+	// Chain ID is required:
+	assert.Error(t, s.SignTransaction(&types.DynamicFeeTransaction{}))
+}
@@ -17,6 +17,7 @@ package rpcclient
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/rpc"
 
@@ -39,7 +40,14 @@ func New(rpc *rpc.Client) *Client {
 	return &Client{rpc: rpc}
 }
 
-// TODO: eth_call
+// ChainID implements the ethereumv2.Client.
+func (c *Client) ChainID(ctx context.Context) (uint64, error) {
+	var id types.Number
+	if err := c.rpc.CallContext(ctx, &id, "eth_chainId"); err != nil {
+		return 0, err
+	}
+	return id.Big().Uint64(), nil
+}
 
 // BlockNumber implements the ethereumv2.Client.
 func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
@@ -70,7 +78,14 @@ func (c *Client) FullBlockByNumber(ctx context.Context, number types.BlockNumber
 	return block, nil
 }
 
-// TODO: eth_getTransactionByHash
+// TransactionByHash implements the ethereumv2.Client.
+func (c *Client) TransactionByHash(ctx context.Context, hash types.Hash) (*types.Transaction, error) {
+	var tx *types.Transaction
+	if err := c.rpc.CallContext(ctx, &tx, "eth_getTransactionByHash", hash); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
 
 // GetTransactionCount implements the ethereumv2.Client.
 func (c *Client) GetTransactionCount(ctx context.Context, acc types.Address, block types.BlockNumber) (uint64, error) {
@@ -81,7 +96,15 @@ func (c *Client) GetTransactionCount(ctx context.Context, acc types.Address, blo
 	return count.Big().Uint64(), nil
 }
 
-// TODO: eth_getTransactionReceipt
+// TransactionReceipt implements the ethereumv2.Client.
+func (c *Client) TransactionReceipt(ctx context.Context, hash types.Hash) (*types.TransactionReceiptType, error) {
+	var receipt *types.TransactionReceiptType
+	if err := c.rpc.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
 // TODO: eth_getBlockTransactionCountByHash
 // TODO: eth_getBlockTransactionCountByNumber
 // TODO: eth_getTransactionByBlockHashAndIndex
@@ -118,7 +141,42 @@ func (c *Client) GetStorageAt(
 // TODO: eth_getCode
 // TODO: eth_accounts
 // TODO: eth_getProof
-// TODO: eth_call
+
+// Call implements the ethereumv2.Client.
+func (c *Client) Call(ctx context.Context, call types.Call, block types.BlockNumber) ([]byte, error) {
+	var res types.Bytes
+	if err := c.rpc.CallContext(ctx, &res, "eth_call", call, block); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CallWithOverrides implements the ethereumv2.Client.
+func (c *Client) CallWithOverrides(
+	ctx context.Context,
+	call types.Call,
+	block types.BlockNumber,
+	state *types.StateOverride,
+	header *types.BlockOverride) ([]byte, error) {
+
+	// Trailing nil arguments are omitted to stay compatible with nodes that
+	// do not support the block override argument.
+	args := []interface{}{call, block}
+	switch {
+	case header != nil:
+		if state == nil {
+			state = &types.StateOverride{}
+		}
+		args = append(args, state, header)
+	case state != nil:
+		args = append(args, state)
+	}
+	var res types.Bytes
+	if err := c.rpc.CallContext(ctx, &res, "eth_call", args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
 
 // FilterLogs implements the ethereumv2.Client.
 func (c *Client) FilterLogs(ctx context.Context, q types.FilterLogsQuery) ([]types.Log, error) {
@@ -136,11 +194,59 @@ func (c *Client) FilterLogs(ctx context.Context, q types.FilterLogsQuery) ([]typ
 // TODO: eth_newPendingTransactionFilter
 // TODO: eth_uninstallFilter
 // TODO: eth_protocolVersion
-// TODO: eth_gasPrice
-// TODO: eth_estimateGas
-// TODO: eth_feeHistory
-// TODO: eth_maxPriorityFeePerGas
-// TODO: eth_chainId
+
+// GasPrice implements the ethereumv2.Client.
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	var price types.Number
+	if err := c.rpc.CallContext(ctx, &price, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return price.Big(), nil
+}
+
+// EstimateGas implements the ethereumv2.Client.
+func (c *Client) EstimateGas(ctx context.Context, call types.Call, block types.BlockNumber) (uint64, error) {
+	var gas types.Number
+	if err := c.rpc.CallContext(ctx, &gas, "eth_estimateGas", call, block); err != nil {
+		return 0, err
+	}
+	return gas.Big().Uint64(), nil
+}
+
+// FeeHistory implements the ethereumv2.Client.
+func (c *Client) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	newestBlock types.BlockNumber,
+	rewardPercentiles []float64) (*types.FeeHistory, error) {
+
+	if rewardPercentiles == nil {
+		rewardPercentiles = []float64{}
+	}
+	var history types.FeeHistory
+	err := c.rpc.CallContext(
+		ctx,
+		&history,
+		"eth_feeHistory",
+		types.Uint64ToNumber(blockCount),
+		newestBlock,
+		rewardPercentiles,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// MaxPriorityFeePerGas implements the ethereumv2.Client.
+func (c *Client) MaxPriorityFeePerGas(ctx context.Context) (*big.Int, error) {
+	var fee types.Number
+	if err := c.rpc.CallContext(ctx, &fee, "eth_maxPriorityFeePerGas"); err != nil {
+		return nil, err
+	}
+	return fee.Big(), nil
+}
+
 // TODO: net_version
 // TODO: net_listening
 // TODO: eth_getUncleByBlockHashAndIndex
@@ -18,7 +18,9 @@ package rpcclient
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"testing"
 
@@ -118,10 +120,62 @@ const (
 			]
 		}
 	}`
+	transactionByHashResponse = `{
+	   "jsonrpc":"2.0",
+	   "id":1,
+	   "result":{
+		  "hash":"0x1",
+		  "nonce":"0x2",
+		  "blockHash":"0x3",
+		  "blockNumber":"0x4",
+		  "transactionIndex":"0x5",
+		  "from":"0x6",
+		  "to":"0x7",
+		  "value":"0x8",
+		  "gasPrice":"0x9",
+		  "gas":"0xa",
+		  "input":"0xb"
+	   }
+	}`
+	transactionReceiptResponse = `{
+	   "jsonrpc":"2.0",
+	   "id":1,
+	   "result":{
+		  "transactionHash":"0x1",
+		  "transactionIndex":"0x2",
+		  "blockHash":"0x3",
+		  "blockNumber":"0x4",
+		  "from":"0x5",
+		  "to":"0x6",
+		  "cumulativeGasUsed":"0x7",
+		  "gasUsed":"0x8",
+		  "effectiveGasPrice":"0x9",
+		  "contractAddress":null,
+		  "logs":[],
+		  "logsBloom":"0xa",
+		  "status":"0x1"
+	   }
+	}`
+	nullResponse                = `{"jsonrpc":"2.0","id":1,"result":null}`
 	getTransactionCountResponse = `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
 	sendRawTransactionResponse  = `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
 	getStorageAtResponse        = `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
-	filterLogsResponse          = `{
+	chainIDResponse             = `{"jsonrpc":"2.0","id":1,"result":"0x5"}`
+	callResponse                = `{"jsonrpc":"2.0","id":1,"result":"0x00112233"}`
+	estimateGasResponse         = `{"jsonrpc":"2.0","id":1,"result":"0x5208"}`
+	gasPriceResponse            = `{"jsonrpc":"2.0","id":1,"result":"0x3b9aca00"}`
+	maxPriorityFeeResponse      = `{"jsonrpc":"2.0","id":1,"result":"0x77359400"}`
+	feeHistoryResponse          = `{
+	   "jsonrpc":"2.0",
+	   "id":1,
+	   "result":{
+		  "oldestBlock":"0x1",
+		  "reward":[["0x1","0x2"]],
+		  "baseFeePerGas":["0x3","0x4"],
+		  "gasUsedRatio":[0.5]
+	   }
+	}`
+	filterLogsResponse = `{
 	   "jsonrpc":"2.0",
 	   "id":1,
 	   "result":[
@@ -277,6 +331,73 @@ func TestClient_GetTransactionCount(t *testing.T) {
 	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_getTransactionCount","params":["0x00112233445566778899aabbccddeeff00112233","latest"],"id":1}`, readAll(t, cli.req.Body))
 }
 
+func TestClient_TransactionByHash(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(transactionByHashResponse))),
+	}
+	tx, err := cli.TransactionByHash(
+		context.Background(),
+		types.HexToHash("0x1"),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, types.HexToHash("0x1"), tx.Hash)
+	assert.Equal(t, types.HexToNumber("0x2"), tx.Nonce)
+	assert.Equal(t, types.HexToHash("0x3"), tx.BlockHash)
+	assert.Equal(t, types.HexToNumber("0x4"), tx.BlockNumber)
+	assert.Equal(t, types.HexToNumber("0x5"), tx.TransactionIndex)
+	assert.Equal(t, types.HexToAddress("0x6"), tx.From)
+	assert.Equal(t, types.HexToAddress("0x7"), tx.To)
+	assert.Equal(t, types.HexToNumber("0x8"), tx.Value)
+	assert.Equal(t, types.HexToNumber("0x9"), tx.GasPrice)
+	assert.Equal(t, types.HexToNumber("0xa"), tx.Gas)
+	assert.Equal(t, types.HexToBytes("0xb"), tx.Input)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_getTransactionByHash","params":["0x0000000000000000000000000000000000000000000000000000000000000001"],"id":1}`, readAll(t, cli.req.Body))
+}
+
+func TestClient_TransactionByHash_Unknown(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(nullResponse))),
+	}
+	tx, err := cli.TransactionByHash(
+		context.Background(),
+		types.HexToHash("0x1"),
+	)
+
+	require.NoError(t, err)
+	assert.Nil(t, tx)
+}
+
+func TestClient_TransactionReceipt(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(transactionReceiptResponse))),
+	}
+	receipt, err := cli.TransactionReceipt(
+		context.Background(),
+		types.HexToHash("0x1"),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, types.HexToHash("0x1"), receipt.TransactionHash)
+	assert.Equal(t, types.HexToNumber("0x2"), receipt.TransactionIndex)
+	assert.Equal(t, types.HexToHash("0x3"), receipt.BlockHash)
+	assert.Equal(t, types.HexToNumber("0x4"), receipt.BlockNumber)
+	assert.Equal(t, types.HexToAddress("0x5"), receipt.From)
+	assert.Equal(t, types.HexToAddress("0x6"), receipt.To)
+	assert.Equal(t, types.HexToNumber("0x7"), receipt.CumulativeGasUsed)
+	assert.Equal(t, types.HexToNumber("0x8"), receipt.GasUsed)
+	assert.Equal(t, types.HexToNumber("0x9"), *receipt.EffectiveGasPrice)
+	assert.Nil(t, receipt.ContractAddress)
+	assert.Equal(t, types.HexToNumber("0x1"), *receipt.Status)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_getTransactionReceipt","params":["0x0000000000000000000000000000000000000000000000000000000000000001"],"id":1}`, readAll(t, cli.req.Body))
+}
+
 func TestClient_SendRawTransaction(t *testing.T) {
 	cli := newTestableClient()
 	cli.res = &http.Response{
@@ -359,6 +480,165 @@ func TestClient_FilterLogs(t *testing.T) {
 	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_getLogs","params":[{"fromBlock":"0x2710","toBlock":"latest","address":["0x00112233445566778899aabbccddeeff00112233"],"topics":[["0x00000000000000000000000000112233445566778899aabbccddeeff00112233"]]}],"id":1}`, readAll(t, cli.req.Body))
 }
 
+func TestClient_ChainID(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(chainIDResponse))),
+	}
+	chainID, err := cli.ChainID(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), chainID)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_chainId","id":1}`, readAll(t, cli.req.Body))
+}
+
+func TestClient_Call(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(callResponse))),
+	}
+	res, err := cli.Call(
+		context.Background(),
+		types.Call{
+			From: ptr(types.HexToAddress("0x00112233445566778899aabbccddeeff00112233")),
+			To:   ptr(types.HexToAddress("0x112233445566778899aabbccddeeff0011223344")),
+			Data: types.HexToBytes("0xaabbccdd"),
+		},
+		types.StringToBlockNumber("latest"),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x11, 0x22, 0x33}, res)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_call","params":[{"from":"0x00112233445566778899aabbccddeeff00112233","to":"0x112233445566778899aabbccddeeff0011223344","data":"0xaabbccdd"},"latest"],"id":1}`, readAll(t, cli.req.Body))
+}
+
+func TestClient_CallWithOverrides(t *testing.T) {
+	call := types.Call{
+		To:   ptr(types.HexToAddress("0x00112233445566778899aabbccddeeff00112233")),
+		Data: types.HexToBytes("0xaabbccdd"),
+	}
+	state := &types.StateOverride{
+		types.HexToAddress("0x00112233445566778899aabbccddeeff00112233"): {
+			Code: types.HexToBytes("0x6080"),
+		},
+	}
+	header := &types.BlockOverride{
+		Time: ptr(types.Uint64ToNumber(1000)),
+	}
+	tests := []struct {
+		state  *types.StateOverride
+		header *types.BlockOverride
+		want   string
+	}{
+		{
+			want: `{"jsonrpc":"2.0","method":"eth_call","params":[{"to":"0x00112233445566778899aabbccddeeff00112233","data":"0xaabbccdd"},"latest"],"id":1}`,
+		},
+		{
+			state: state,
+			want:  `{"jsonrpc":"2.0","method":"eth_call","params":[{"to":"0x00112233445566778899aabbccddeeff00112233","data":"0xaabbccdd"},"latest",{"0x00112233445566778899aabbccddeeff00112233":{"code":"0x6080"}}],"id":1}`,
+		},
+		{
+			header: header,
+			want:   `{"jsonrpc":"2.0","method":"eth_call","params":[{"to":"0x00112233445566778899aabbccddeeff00112233","data":"0xaabbccdd"},"latest",{},{"time":"0x3e8"}],"id":1}`,
+		},
+		{
+			state:  state,
+			header: header,
+			want:   `{"jsonrpc":"2.0","method":"eth_call","params":[{"to":"0x00112233445566778899aabbccddeeff00112233","data":"0xaabbccdd"},"latest",{"0x00112233445566778899aabbccddeeff00112233":{"code":"0x6080"}},{"time":"0x3e8"}],"id":1}`,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			cli := newTestableClient()
+			cli.res = &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte(callResponse))),
+			}
+			res, err := cli.CallWithOverrides(
+				context.Background(),
+				call,
+				types.StringToBlockNumber("latest"),
+				tt.state,
+				tt.header,
+			)
+
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x00, 0x11, 0x22, 0x33}, res)
+			assert.JSONEq(t, tt.want, readAll(t, cli.req.Body))
+		})
+	}
+}
+
+func TestClient_EstimateGas(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(estimateGasResponse))),
+	}
+	gas, err := cli.EstimateGas(
+		context.Background(),
+		types.Call{
+			From:  ptr(types.HexToAddress("0x00112233445566778899aabbccddeeff00112233")),
+			To:    ptr(types.HexToAddress("0x112233445566778899aabbccddeeff0011223344")),
+			Value: ptr(types.Uint64ToNumber(1)),
+		},
+		types.StringToBlockNumber("pending"),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(21000), gas)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_estimateGas","params":[{"from":"0x00112233445566778899aabbccddeeff00112233","to":"0x112233445566778899aabbccddeeff0011223344","value":"0x1"},"pending"],"id":1}`, readAll(t, cli.req.Body))
+}
+
+func TestClient_GasPrice(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(gasPriceResponse))),
+	}
+	price, err := cli.GasPrice(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1e9), price)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_gasPrice","id":1}`, readAll(t, cli.req.Body))
+}
+
+func TestClient_MaxPriorityFeePerGas(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(maxPriorityFeeResponse))),
+	}
+	fee, err := cli.MaxPriorityFeePerGas(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2e9), fee)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_maxPriorityFeePerGas","id":1}`, readAll(t, cli.req.Body))
+}
+
+func TestClient_FeeHistory(t *testing.T) {
+	cli := newTestableClient()
+	cli.res = &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(feeHistoryResponse))),
+	}
+	history, err := cli.FeeHistory(
+		context.Background(),
+		1,
+		types.StringToBlockNumber("latest"),
+		[]float64{25, 75},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, types.Uint64ToNumber(1), history.OldestBlock)
+	assert.Equal(t, [][]types.Number{{types.Uint64ToNumber(1), types.Uint64ToNumber(2)}}, history.Reward)
+	assert.Equal(t, []types.Number{types.Uint64ToNumber(3), types.Uint64ToNumber(4)}, history.BaseFeePerGas)
+	assert.Equal(t, []float64{0.5}, history.GasUsedRatio)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_feeHistory","params":["0x1","latest",[25,75]],"id":1}`, readAll(t, cli.req.Body))
+}
+
 func readAll(t *testing.T, r io.Reader) string {
 	buf := bytes.NewBuffer(nil)
 	_, err := buf.ReadFrom(r)
@@ -17,6 +17,7 @@ package mocks
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/stretchr/testify/mock"
 
@@ -27,6 +28,11 @@ type Client struct {
 	mock.Mock
 }
 
+func (c *Client) ChainID(ctx context.Context) (uint64, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
 func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
 	args := c.Called(ctx)
 	return args.Get(0).(uint64), args.Error(1)
@@ -42,6 +48,16 @@ func (c *Client) FullBlockByNumber(ctx context.Context, number types.BlockNumber
 	return args.Get(0).(*types.BlockTxObjects), args.Error(1)
 }
 
+func (c *Client) TransactionByHash(ctx context.Context, hash types.Hash) (*types.Transaction, error) {
+	args := c.Called(ctx, hash)
+	return args.Get(0).(*types.Transaction), args.Error(1)
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, hash types.Hash) (*types.TransactionReceiptType, error) {
+	args := c.Called(ctx, hash)
+	return args.Get(0).(*types.TransactionReceiptType), args.Error(1)
+}
+
 func (c *Client) GetTransactionCount(ctx context.Context, acc types.Address, block types.BlockNumber) (uint64, error) {
 	args := c.Called(ctx, acc, block)
 	return args.Get(0).(uint64), args.Error(1)
@@ -61,3 +77,44 @@ func (c *Client) FilterLogs(ctx context.Context, q types.FilterLogsQuery) ([]typ
 	args := c.Called(ctx, q)
 	return args.Get(0).([]types.Log), args.Error(1)
 }
+
+func (c *Client) Call(ctx context.Context, call types.Call, block types.BlockNumber) ([]byte, error) {
+	args := c.Called(ctx, call, block)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (c *Client) CallWithOverrides(
+	ctx context.Context,
+	call types.Call,
+	block types.BlockNumber,
+	state *types.StateOverride,
+	header *types.BlockOverride) ([]byte, error) {
+
+	args := c.Called(ctx, call, block, state, header)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (c *Client) EstimateGas(ctx context.Context, call types.Call, block types.BlockNumber) (uint64, error) {
+	args := c.Called(ctx, call, block)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
+func (c *Client) MaxPriorityFeePerGas(ctx context.Context) (*big.Int, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
+func (c *Client) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	newestBlock types.BlockNumber,
+	rewardPercentiles []float64) (*types.FeeHistory, error) {
+
+	args := c.Called(ctx, blockCount, newestBlock, rewardPercentiles)
+	return args.Get(0).(*types.FeeHistory), args.Error(1)
+}
@@ -0,0 +1,310 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxRequestContentLength is the maximum size of a request body, it is the
+// same as in the geth RPC server.
+const maxRequestContentLength = 1024 * 1024 * 5
+
+// defaultMaxBatchSize is the default maximum number of elements in a batch
+// request.
+const defaultMaxBatchSize = 100
+
+// batchConcurrency is the maximum number of batch elements processed
+// concurrently.
+const batchConcurrency = 10
+
+// batchCaller is an endpoint that supports JSON-RPC batch requests. It is
+// implemented by the gethRPC.Client type.
+type batchCaller interface {
+	BatchCallContext(ctx context.Context, b []gethRPC.BatchElem) error
+}
+
+type batchCtxKey struct{}
+
+// batch coordinates calls made to the endpoints by elements of a single
+// batch request.
+//
+// Elements of a batch request are processed concurrently by a limited number
+// of workers. Instead of calling the endpoints directly, the elements add
+// their calls to the batch queue. When every worker that is still processing
+// elements is waiting for its calls, all queued calls are sent to every
+// endpoint as a single batch request.
+// Elements that make more than one call, for example to convert the "latest"
+// tag to a block number, cause multiple batch requests to be sent.
+type batch struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	timeout time.Duration
+	active  int // active is the number of workers that are still processing elements
+	blocked int // blocked is the number of workers waiting for their calls
+	queue   map[string]*batchQueue
+}
+
+// batchQueue is a list of queued calls for a single endpoint.
+type batchQueue struct {
+	caller caller
+	calls  []*batchCall
+}
+
+// batchCall is a queued call. It implements the caller interface, so it can
+// be used in place of the endpoint caller in the server.call method.
+type batchCall struct {
+	method string
+	args   []any
+	res    chan batchResult
+}
+
+type batchResult struct {
+	data json.RawMessage
+	err  error
+}
+
+func newBatch(ctx context.Context, elements int, timeout time.Duration) *batch {
+	return &batch{
+		ctx:     ctx,
+		timeout: timeout,
+		active:  elements,
+		queue:   map[string]*batchQueue{},
+	}
+}
+
+// batchFromContext returns the batch the request belongs to, or nil if it is
+// not a batch request.
+func batchFromContext(ctx context.Context) *batch {
+	b, _ := ctx.Value(batchCtxKey{}).(*batch)
+	return b
+}
+
+// callers queues a call for every given endpoint and returns callers that
+// return the results of queued calls. It must be called only once for every
+// call made by an element.
+func (b *batch) callers(callers map[string]caller, method string, args []any) map[string]caller {
+	if len(callers) == 0 {
+		return callers
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bc := make(map[string]caller, len(callers))
+	for n, c := range callers {
+		q, ok := b.queue[n]
+		if !ok {
+			q = &batchQueue{caller: c}
+			b.queue[n] = q
+		}
+		call := &batchCall{method: method, args: args, res: make(chan batchResult, 1)}
+		q.calls = append(q.calls, call)
+		bc[n] = call
+	}
+	b.blocked++
+	b.flush()
+	return bc
+}
+
+// done must be called when a worker that processes elements has finished.
+func (b *batch) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.active--
+	b.flush()
+}
+
+// flush sends queued calls if all active elements are waiting for them. It
+// must be called with the mutex locked.
+func (b *batch) flush() {
+	if len(b.queue) == 0 || b.blocked < b.active {
+		return
+	}
+	for _, q := range b.queue {
+		go b.send(q)
+	}
+	b.queue = map[string]*batchQueue{}
+	b.blocked = 0
+}
+
+// send sends queued calls to the endpoint. Identical calls are sent only
+// once. If the endpoint does not support batch requests, calls are sent
+// separately.
+func (b *batch) send(q *batchQueue) {
+	ctx, ctxCancel := context.WithTimeout(b.ctx, b.timeout)
+	defer ctxCancel()
+
+	var elems []gethRPC.BatchElem
+	var calls [][]*batchCall
+	keys := map[string]int{}
+	for _, call := range q.calls {
+		key, err := cacheKey(call.method, call.args)
+		if i, ok := keys[key]; ok && err == nil {
+			calls[i] = append(calls[i], call)
+			continue
+		}
+		keys[key] = len(elems)
+		elems = append(elems, gethRPC.BatchElem{
+			Method: call.method,
+			Args:   call.args,
+			Result: &json.RawMessage{},
+		})
+		calls = append(calls, []*batchCall{call})
+	}
+	switch c := q.caller.(type) {
+	case batchCaller:
+		if err := c.BatchCallContext(ctx, elems); err != nil {
+			for i := range elems {
+				elems[i].Error = err
+			}
+		}
+	default:
+		wg := sync.WaitGroup{}
+		for i := range elems {
+			i := i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				elems[i].Error = c.CallContext(ctx, elems[i].Result, elems[i].Method, elems[i].Args...)
+			}()
+		}
+		wg.Wait()
+	}
+	for i, e := range elems {
+		for _, call := range calls[i] {
+			call.res <- batchResult{data: *e.Result.(*json.RawMessage), err: e.Error}
+		}
+	}
+}
+
+// CallContext implements the caller interface.
+func (c *batchCall) CallContext(ctx context.Context, result any, _ string, _ ...any) error {
+	select {
+	case r := <-c.res:
+		if r.err != nil {
+			return r.err
+		}
+		return json.Unmarshal(r.data, result)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// serveBatch handles HTTP batch requests. Batch elements are processed
+// concurrently as separate requests, and calls made by them to the endpoints
+// are sent as batch requests. Responses are returned in the same order as
+// the elements.
+//
+// Batches with more elements than allowed are rejected. At most
+// batchConcurrency elements are processed at the same time.
+func (s *server) serveBatch(rw http.ResponseWriter, req *http.Request, msgs []json.RawMessage) {
+	if s.maxBatchSize > 0 && len(msgs) > s.maxBatchSize {
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(passthroughResponse{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error: &passthroughError{
+				Code:    -32600,
+				Message: fmt.Sprintf("batch too large, the maximum number of elements is %d", s.maxBatchSize),
+			},
+		})
+		return
+	}
+
+	// Every worker remains active in the batch until all elements are
+	// processed, so the batch does not wait for calls from elements that
+	// have not been started yet.
+	workers := batchConcurrency
+	if len(msgs) < workers {
+		workers = len(msgs)
+	}
+	b := newBatch(req.Context(), workers, s.totalTimeout)
+	ctx := context.WithValue(req.Context(), batchCtxKey{}, b)
+	resps := make([][]byte, len(msgs))
+	elems := make(chan int, len(msgs))
+	for i := range msgs {
+		elems <- i
+	}
+	close(elems)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer b.done()
+			for i := range elems {
+				r := req.Clone(ctx)
+				r.Body = io.NopCloser(bytes.NewReader(msgs[i]))
+				r.ContentLength = int64(len(msgs[i]))
+				rec := newRecorder()
+				s.serveSingle(rec, r, msgs[i])
+				resps[i] = bytes.TrimSpace(rec.body.Bytes())
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Notifications do not have responses.
+	var body [][]byte
+	for _, r := range resps {
+		if len(r) > 0 {
+			body = append(body, r)
+		}
+	}
+	if len(body) == 0 {
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write([]byte("["))
+	_, _ = rw.Write(bytes.Join(body, []byte(",")))
+	_, _ = rw.Write([]byte("]"))
+}
+
+// readBody reads the request body. The request body is restored, so it can
+// be read again. Bodies larger than maxRequestContentLength are not read.
+func readBody(req *http.Request) []byte {
+	if req.Method != http.MethodPost || req.Body == nil {
+		return nil
+	}
+	orig := req.Body
+	body, err := io.ReadAll(io.LimitReader(orig, maxRequestContentLength+1))
+	req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), orig))
+	if err != nil || len(body) > maxRequestContentLength {
+		return nil
+	}
+	return bytes.TrimSpace(body)
+}
+
+// parseBatch returns batch elements if the body is a batch request with more
+// than one element.
+func parseBatch(body []byte) []json.RawMessage {
+	if len(body) == 0 || body[0] != '[' {
+		return nil
+	}
+	var msgs []json.RawMessage
+	if err := json.Unmarshal(body, &msgs); err != nil || len(msgs) < 2 {
+		return nil
+	}
+	return msgs
+}
@@ -0,0 +1,222 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchClient is a caller that supports batch requests. It returns
+// responses from the results map.
+type batchClient struct {
+	mu      sync.Mutex
+	results map[string]any
+	batches [][]string
+}
+
+func (c *batchClient) CallContext(context.Context, any, string, ...any) error {
+	return errors.New("unexpected call")
+}
+
+func (c *batchClient) BatchCallContext(_ context.Context, b []gethRPC.BatchElem) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var methods []string
+	for i, e := range b {
+		methods = append(methods, e.Method)
+		res, ok := c.results[e.Method]
+		if !ok {
+			b[i].Error = errors.New("method not found")
+			continue
+		}
+		data, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, b[i].Result); err != nil {
+			return err
+		}
+	}
+	c.batches = append(c.batches, methods)
+	return nil
+}
+
+func Test_RPC_Batch(t *testing.T) {
+	newClient := func() *batchClient {
+		return &batchClient{results: map[string]any{
+			"eth_blockNumber": "0x10",
+			"eth_chainId":     "0x1",
+			"eth_getBalance":  "0x64",
+		}}
+	}
+	c1 := newClient()
+	c2 := newClient()
+	h, err := NewServer(
+		withCallers(map[string]caller{"0": c1, "1": c2}),
+		WithRequirements(2, 10),
+	)
+	require.NoError(t, err)
+
+	req := `[
+		{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x0000000000000000000000000000000000000001","latest"]},
+		{"jsonrpc":"2.0","id":2,"method":"eth_getBalance","params":["0x0000000000000000000000000000000000000002","latest"]},
+		{"jsonrpc":"2.0","id":3,"method":"eth_chainId"},
+		{"jsonrpc":"2.0","method":"eth_chainId"},
+		{"jsonrpc":"2.0","id":4,"method":"eth_unknown"}
+	]`
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(req)))
+	r.Header.Set("Content-Type", "application/json")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+
+	var res []rpcRes
+	jsonUnmarshal(t, rw.Body.Bytes(), &res)
+	require.Len(t, res, 4)
+	assert.Equal(t, 1, res[0].ID)
+	assert.Equal(t, "0x64", res[0].Result)
+	assert.Equal(t, 2, res[1].ID)
+	assert.Equal(t, "0x64", res[1].Result)
+	assert.Equal(t, 3, res[2].ID)
+	assert.Equal(t, "0x1", res[2].Result)
+	assert.Equal(t, 4, res[3].ID)
+	assert.NotEmpty(t, res[3].Error.Message)
+
+	// Tags are converted to block numbers in the first batch, and identical
+	// calls are sent only once.
+	for _, c := range []*batchClient{c1, c2} {
+		require.Len(t, c.batches, 2)
+		assert.ElementsMatch(t, []string{"eth_blockNumber", "eth_chainId"}, c.batches[0])
+		assert.ElementsMatch(t, []string{"eth_getBalance", "eth_getBalance"}, c.batches[1])
+	}
+}
+
+func Test_RPC_Batch_NoBatchSupport(t *testing.T) {
+	c1 := &stubCaller{block: "0x10"}
+	c2 := &stubCaller{block: "0x10"}
+	h, err := NewServer(
+		withCallers(map[string]caller{"0": c1, "1": c2}),
+		WithRequirements(2, 10),
+	)
+	require.NoError(t, err)
+
+	req := `[
+		{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"},
+		{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber"}
+	]`
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(req)))
+	r.Header.Set("Content-Type", "application/json")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+
+	var res []rpcRes
+	jsonUnmarshal(t, rw.Body.Bytes(), &res)
+	require.Len(t, res, 2)
+	assert.Equal(t, "0x10", res[0].Result)
+	assert.Equal(t, "0x10", res[1].Result)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&c1.calls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&c2.calls))
+}
+
+func Test_RPC_Batch_Concurrency(t *testing.T) {
+	c := &batchClient{results: map[string]any{"eth_chainId": "0x1"}}
+	h, err := NewServer(
+		withCallers(map[string]caller{"0": c}),
+		WithRequirements(1, 10),
+	)
+	require.NoError(t, err)
+
+	// More elements than batchConcurrency must not block the batch.
+	var elems []string
+	for i := 1; i <= batchConcurrency*3; i++ {
+		elems = append(elems, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"eth_chainId"}`, i))
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("["+strings.Join(elems, ",")+"]"))
+	r.Header.Set("Content-Type", "application/json")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+
+	var res []rpcRes
+	jsonUnmarshal(t, rw.Body.Bytes(), &res)
+	require.Len(t, res, len(elems))
+	for i, r := range res {
+		assert.Equal(t, i+1, r.ID)
+		assert.Equal(t, "0x1", r.Result)
+	}
+}
+
+func Test_RPC_Batch_TooLarge(t *testing.T) {
+	c := &batchClient{results: map[string]any{"eth_chainId": "0x1"}}
+	h, err := NewServer(
+		withCallers(map[string]caller{"0": c}),
+		WithRequirements(1, 10),
+		WithMaxBatchSize(2),
+	)
+	require.NoError(t, err)
+
+	req := `[
+		{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},
+		{"jsonrpc":"2.0","id":2,"method":"eth_chainId"},
+		{"jsonrpc":"2.0","id":3,"method":"eth_chainId"}
+	]`
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(req)))
+	r.Header.Set("Content-Type", "application/json")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+
+	res := &rpcRes{}
+	jsonUnmarshal(t, rw.Body.Bytes(), res)
+	assert.Equal(t, -32600, res.Error.Code)
+	assert.Equal(t, "batch too large, the maximum number of elements is 2", res.Error.Message)
+	assert.Empty(t, c.batches)
+}
+
+func Test_parseBatch(t *testing.T) {
+	tests := []struct {
+		body string
+		want int
+	}{
+		{body: `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`, want: 0},
+		{body: `[{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}]`, want: 0},
+		{body: ` [{"id":1},{"id":2}]`, want: 2},
+		{body: `[{"id":1},`, want: 0},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(tt.body)))
+			assert.Len(t, parseBatch(readBody(r)), tt.want)
+
+			// The body must be restored.
+			b := new(bytes.Buffer)
+			_, err := b.ReadFrom(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, b.String())
+		})
+	}
+}
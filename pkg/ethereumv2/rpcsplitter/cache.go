@@ -0,0 +1,148 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+)
+
+// CacheStats is a summary of the response cache returned by the
+// "rpcsplitter_cacheStats" method. Size is in bytes.
+type CacheStats struct {
+	Entries   int     `json:"entries"`
+	Size      int     `json:"size"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRate   float64 `json:"hitRate"`
+}
+
+// responseCache is an LRU cache of resolved responses. It is used only for
+// queries whose responses can never change, so entries do not expire and
+// are removed only when the cache is full.
+//
+// Responses are stored in the JSON form, so every cache hit returns a fresh
+// copy that can be safely modified by the caller.
+type responseCache struct {
+	mu    sync.Mutex
+	items map[string]*list.Element
+	lru   *list.List // most recently used items are at the front
+
+	maxEntries int // maximum number of entries
+	maxSize    int // maximum total size of keys and values in bytes
+	size       int
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+type cacheItem struct {
+	key   string
+	value []byte
+}
+
+func newResponseCache(maxEntries, maxSize int) *responseCache {
+	return &responseCache{
+		items:      map[string]*list.Element{},
+		lru:        list.New(),
+		maxEntries: maxEntries,
+		maxSize:    maxSize,
+	}
+}
+
+// get unmarshalls a cached response into the result. It returns false if
+// there is no response for the given key.
+func (c *responseCache) get(key string, result any) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return false
+	}
+	if err := json.Unmarshal(e.Value.(*cacheItem).value, result); err != nil {
+		c.remove(e)
+		c.misses++
+		return false
+	}
+	c.lru.MoveToFront(e)
+	c.hits++
+	return true
+}
+
+// add stores a response in the cache, evicting the least recently used
+// entries if necessary. Responses larger than the cache are not stored.
+func (c *responseCache) add(key string, result any) {
+	value, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	size := len(key) + len(value)
+	if size > c.maxSize {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+	for c.lru.Len() > 0 && (c.lru.Len() >= c.maxEntries || c.size+size > c.maxSize) {
+		c.remove(c.lru.Back())
+		c.evictions++
+	}
+	c.items[key] = c.lru.PushFront(&cacheItem{key: key, value: value})
+	c.size += size
+}
+
+// stats returns the cache statistics.
+func (c *responseCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := CacheStats{
+		Entries:   c.lru.Len(),
+		Size:      c.size,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+	if c.hits+c.misses > 0 {
+		s.HitRate = float64(c.hits) / float64(c.hits+c.misses)
+	}
+	return s
+}
+
+// remove removes an element from the cache. It must be called with the mutex
+// locked.
+func (c *responseCache) remove(e *list.Element) {
+	i := c.lru.Remove(e).(*cacheItem)
+	delete(c.items, i.key)
+	c.size -= len(i.key) + len(i.value)
+}
+
+// cacheKey returns a cache key for the given method and arguments. Arguments
+// are canonicalized by encoding them to JSON, which normalizes whitespace
+// and the order of object keys. Typed arguments, such as addresses and block
+// numbers, are also normalized to the same hex form.
+func cacheKey(method string, args []any) (string, error) {
+	b, err := json.Marshal(removeTrailingNilArgs(args))
+	if err != nil {
+		return "", err
+	}
+	return method + string(b), nil
+}
@@ -0,0 +1,163 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+func Test_responseCache(t *testing.T) {
+	t.Run("max-entries", func(t *testing.T) {
+		c := newResponseCache(2, 1000)
+		c.add("a", "a")
+		c.add("b", "b")
+		var s string
+		require.True(t, c.get("a", &s)) // "a" is now the most recently used
+		assert.Equal(t, "a", s)
+		c.add("c", "c")
+		assert.True(t, c.get("a", &s))
+		assert.False(t, c.get("b", &s))
+		assert.True(t, c.get("c", &s))
+		assert.Equal(t, CacheStats{Entries: 2, Size: 8, Hits: 3, Misses: 1, Evictions: 1, HitRate: 0.75}, c.stats())
+	})
+	t.Run("max-size", func(t *testing.T) {
+		c := newResponseCache(10, 10)
+		c.add("a", "aaa") // size 6
+		c.add("b", "bbb") // size 6
+		var s string
+		assert.False(t, c.get("a", &s))
+		assert.True(t, c.get("b", &s))
+		assert.Equal(t, 6, c.stats().Size)
+	})
+	t.Run("too-large", func(t *testing.T) {
+		c := newResponseCache(10, 5)
+		c.add("a", "aaaa")
+		var s string
+		assert.False(t, c.get("a", &s))
+		assert.Equal(t, 0, c.stats().Entries)
+	})
+	t.Run("replace", func(t *testing.T) {
+		c := newResponseCache(10, 100)
+		c.add("a", "a")
+		c.add("a", "bb")
+		var s string
+		assert.True(t, c.get("a", &s))
+		assert.Equal(t, "bb", s)
+		assert.Equal(t, CacheStats{Entries: 1, Size: 5, Hits: 1, HitRate: 1}, c.stats())
+	})
+}
+
+func Test_cacheKey(t *testing.T) {
+	tests := []struct {
+		a, b  []any
+		equal bool
+	}{
+		{
+			a:     []any{newAny(`{"to": "0x01", "data": "0x02"}`), types.StringToBlockNumber("0x10")},
+			b:     []any{newAny(`{"data":"0x02","to":"0x01"}`), types.Uint64ToBlockNumber(16)},
+			equal: true,
+		},
+		{
+			a:     []any{types.HexToAddress("0xC94770007DDA54CF92009BFF0DE90C06F603A09F"), (*Any)(nil)},
+			b:     []any{types.HexToAddress("0xc94770007dda54cf92009bff0de90c06f603a09f")},
+			equal: true,
+		},
+		{
+			a:     []any{newAny(`{"to": "0x01"}`), types.StringToBlockNumber("0x10")},
+			b:     []any{newAny(`{"to": "0x01"}`), types.StringToBlockNumber("0x11")},
+			equal: false,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			a, err := cacheKey("eth_call", tt.a)
+			require.NoError(t, err)
+			b, err := cacheKey("eth_call", tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.equal, a == b)
+		})
+	}
+}
+
+func Test_RPC_Cache(t *testing.T) {
+	call := newAny(`{"to": "0xd46e8dd67c5d32be8058bb8eb970870f07244567", "data": "0x01"}`)
+	head := types.StringToBlockNumber("0x20")
+	confirmed := types.StringToBlockNumber("0x10")
+	unconfirmed := types.StringToBlockNumber("0x1e")
+	callRes := types.HexToBytes("0x01")
+
+	// Mocked clients fail on unexpected calls, so every cached call must be
+	// mocked only once.
+	c1 := &mockClient{t: t}
+	c2 := &mockClient{t: t}
+	for _, c := range []*mockClient{c1, c2} {
+		c.mockCall(head, "eth_blockNumber")
+		c.mockCall(callRes, "eth_call", call, confirmed)
+		c.mockCall(callRes, "eth_call", call, unconfirmed)
+		c.mockCall(callRes, "eth_call", call, unconfirmed)
+		c.mockCall(nil, "eth_getBlockByHash", types.Hash{}, false)
+		c.mockCall(nil, "eth_getBlockByHash", types.Hash{}, false)
+	}
+	h, err := NewServer(
+		withCallers(map[string]caller{"0": c1, "1": c2}),
+		WithRequirements(2, 10),
+		WithCache(100, 1<<20, 5),
+	)
+	require.NoError(t, err)
+
+	serveRPC(t, h, "eth_blockNumber")
+	for i := 0; i < 2; i++ {
+		res := &rpcRes{}
+		jsonUnmarshal(t, serveRPC(t, h, "eth_call", call, confirmed), res)
+		assert.Equal(t, "0x01", res.Result)
+	}
+	for i := 0; i < 2; i++ {
+		res := &rpcRes{}
+		jsonUnmarshal(t, serveRPC(t, h, "eth_call", call, unconfirmed), res)
+		assert.Equal(t, "0x01", res.Result)
+	}
+	for i := 0; i < 2; i++ {
+		serveRPC(t, h, "eth_getBlockByHash", types.Hash{}, false)
+	}
+	assert.Equal(t, 6, c1.currCall)
+	assert.Equal(t, 6, c2.currCall)
+
+	res := &struct {
+		Result CacheStats `json:"result"`
+	}{}
+	jsonUnmarshal(t, serveRPC(t, h, "rpcsplitter_cacheStats"), res)
+	assert.Equal(t, 1, res.Result.Entries)
+	assert.Equal(t, uint64(1), res.Result.Hits)
+	assert.Equal(t, uint64(5), res.Result.Misses)
+}
+
+func Test_RPC_CacheDisabled(t *testing.T) {
+	h, err := NewServer(
+		withCallers(map[string]caller{"0": &mockClient{t: t}}),
+		WithRequirements(1, 10),
+	)
+	require.NoError(t, err)
+
+	res := &rpcRes{}
+	jsonUnmarshal(t, serveRPC(t, h, "rpcsplitter_cacheStats"), res)
+	assert.Equal(t, "cache is disabled", res.Error.Message)
+}
@@ -0,0 +1,312 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+)
+
+const defaultHealthWindow = 100
+const defaultHealthMinSamples = 10
+const defaultProbeInterval = 30 * time.Second
+
+// EndpointStatus is a health summary of a single endpoint returned by the
+// "rpcsplitter_status" method. Error rate and latencies are calculated from
+// the most recent calls only. Latencies are in milliseconds.
+type EndpointStatus struct {
+	Endpoint       string     `json:"endpoint"`
+	Evicted        bool       `json:"evicted"`
+	EvictionReason string     `json:"evictionReason,omitempty"`
+	EvictedAt      *time.Time `json:"evictedAt,omitempty"`
+	Requests       int        `json:"requests"`
+	Errors         int        `json:"errors"`
+	ErrorRate      float64    `json:"errorRate"`
+	LatencyP50     int64      `json:"latencyP50"`
+	LatencyP90     int64      `json:"latencyP90"`
+	LatencyP99     int64      `json:"latencyP99"`
+	BlockNumber    uint64     `json:"blockNumber"`
+	BlocksBehind   uint64     `json:"blocksBehind"`
+}
+
+// sample is a single call result recorded by the healthChecker.
+type sample struct {
+	duration time.Duration
+	failed   bool
+}
+
+// endpointHealth holds the recent call statistics of a single endpoint.
+type endpointHealth struct {
+	samples     []sample // samples is a ring buffer with the most recent calls
+	next        int      // next is the position of the next sample in the ring buffer
+	blockNumber uint64   // blockNumber is the last block number reported by the endpoint
+	evicted     bool
+	evictedAt   time.Time
+	reason      string
+	probing     bool
+	probedAt    time.Time
+}
+
+// healthChecker collects per-endpoint call statistics and decides which
+// endpoints should be temporarily evicted. Evicted endpoints are re-probed
+// with the "eth_blockNumber" call and restored once they are healthy again.
+//
+// A zero threshold disables the corresponding eviction rule, so by default
+// statistics are collected but no endpoint is ever evicted.
+type healthChecker struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointHealth
+	log       log.Logger
+
+	window          int           // number of recent calls used to calculate statistics
+	minSamples      int           // minimum number of calls before the error rate and latency rules apply
+	maxErrorRate    float64       // maximum ratio of failed calls
+	maxLatency      time.Duration // maximum 90th percentile of call latency
+	maxBlocksBehind uint64        // maximum number of blocks behind the highest known block
+	probeInterval   time.Duration // interval between probes of an evicted endpoint
+}
+
+func newHealthChecker() *healthChecker {
+	return &healthChecker{
+		endpoints:     map[string]*endpointHealth{},
+		window:        defaultHealthWindow,
+		minSamples:    defaultHealthMinSamples,
+		probeInterval: defaultProbeInterval,
+	}
+}
+
+// record adds a call result to the endpoint statistics and evicts the
+// endpoint if it exceeds any of the thresholds. JSON-RPC error responses,
+// like reverted calls, are valid responses, so only transport errors,
+// HTTP errors and timeouts are counted as failed calls.
+func (h *healthChecker) record(name, method string, d time.Duration, res any, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e := h.endpoint(name)
+	var rpcErr gethRPC.Error
+	failed := err != nil && !errors.As(err, &rpcErr)
+	if len(e.samples) < h.window {
+		e.samples = append(e.samples, sample{duration: d, failed: failed})
+	} else {
+		e.samples[e.next] = sample{duration: d, failed: failed}
+	}
+	e.next = (e.next + 1) % h.window
+	if n, ok := res.(*types.Number); ok && err == nil && method == "eth_blockNumber" {
+		e.blockNumber = n.Big().Uint64()
+	}
+	if e.evicted {
+		return
+	}
+	if reason := h.check(e); reason != "" {
+		e.evicted = true
+		e.evictedAt = time.Now()
+		e.probedAt = e.evictedAt
+		e.reason = reason
+		h.log.
+			WithField("name", name).
+			WithField("reason", reason).
+			Warn("Endpoint evicted")
+	}
+}
+
+// isEvicted returns true if the endpoint is currently evicted.
+func (h *healthChecker) isEvicted(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e, ok := h.endpoints[name]
+	return ok && e.evicted
+}
+
+// startProbe returns true if an evicted endpoint should be probed now. Only
+// one probe per endpoint may be in progress at a time.
+func (h *healthChecker) startProbe(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e, ok := h.endpoints[name]
+	if !ok || !e.evicted || e.probing || time.Since(e.probedAt) < h.probeInterval {
+		return false
+	}
+	e.probing = true
+	return true
+}
+
+// finishProbe records the result of a probe. If the probe succeeded and the
+// endpoint is not too far behind other endpoints, the endpoint is restored
+// with cleared statistics.
+func (h *healthChecker) finishProbe(name string, res *types.Number, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e := h.endpoint(name)
+	e.probing = false
+	e.probedAt = time.Now()
+	if err != nil {
+		h.log.
+			WithField("name", name).
+			WithError(err).
+			Debug("Endpoint probe failed")
+		return
+	}
+	e.blockNumber = res.Big().Uint64()
+	if h.maxBlocksBehind > 0 && h.blocksBehind(e) > h.maxBlocksBehind {
+		h.log.
+			WithField("name", name).
+			WithField("blocksBehind", h.blocksBehind(e)).
+			Debug("Endpoint is still behind")
+		return
+	}
+	e.samples = nil
+	e.next = 0
+	e.evicted = false
+	e.reason = ""
+	h.log.
+		WithField("name", name).
+		Info("Endpoint restored")
+}
+
+// status returns the health summary of the given endpoints.
+func (h *healthChecker) status(names []string) []EndpointStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sort.Strings(names)
+	var s []EndpointStatus
+	for _, name := range names {
+		e := h.endpoint(name)
+		st := EndpointStatus{
+			Endpoint:     redactEndpoint(name),
+			Evicted:      e.evicted,
+			Requests:     len(e.samples),
+			Errors:       e.errors(),
+			ErrorRate:    e.errorRate(),
+			LatencyP50:   e.latency(0.5).Milliseconds(),
+			LatencyP90:   e.latency(0.9).Milliseconds(),
+			LatencyP99:   e.latency(0.99).Milliseconds(),
+			BlockNumber:  e.blockNumber,
+			BlocksBehind: h.blocksBehind(e),
+		}
+		if e.evicted {
+			evictedAt := e.evictedAt
+			st.EvictionReason = e.reason
+			st.EvictedAt = &evictedAt
+		}
+		s = append(s, st)
+	}
+	return s
+}
+
+// check returns the reason for evicting the endpoint or an empty string if
+// the endpoint is healthy. It must be called with the mutex locked.
+func (h *healthChecker) check(e *endpointHealth) string {
+	if h.maxBlocksBehind > 0 {
+		if b := h.blocksBehind(e); b > h.maxBlocksBehind {
+			return fmt.Sprintf("%d blocks behind exceeds %d", b, h.maxBlocksBehind)
+		}
+	}
+	if len(e.samples) < h.minSamples {
+		return ""
+	}
+	if h.maxErrorRate > 0 {
+		if r := e.errorRate(); r > h.maxErrorRate {
+			return fmt.Sprintf("error rate %.2f exceeds %.2f", r, h.maxErrorRate)
+		}
+	}
+	if h.maxLatency > 0 {
+		if l := e.latency(0.9); l > h.maxLatency {
+			return fmt.Sprintf("p90 latency %s exceeds %s", l, h.maxLatency)
+		}
+	}
+	return ""
+}
+
+// blocksBehind returns the number of blocks between the last block reported
+// by the endpoint and the highest block reported by any endpoint. It must be
+// called with the mutex locked.
+func (h *healthChecker) blocksBehind(e *endpointHealth) uint64 {
+	if e.blockNumber == 0 {
+		return 0
+	}
+	var high uint64
+	for _, o := range h.endpoints {
+		if o.blockNumber > high {
+			high = o.blockNumber
+		}
+	}
+	return high - e.blockNumber
+}
+
+// endpoint returns the statistics of the given endpoint, creating them if
+// necessary. It must be called with the mutex locked.
+func (h *healthChecker) endpoint(name string) *endpointHealth {
+	e, ok := h.endpoints[name]
+	if !ok {
+		e = &endpointHealth{}
+		h.endpoints[name] = e
+	}
+	return e
+}
+
+func (e *endpointHealth) errors() (n int) {
+	for _, s := range e.samples {
+		if s.failed {
+			n++
+		}
+	}
+	return n
+}
+
+func (e *endpointHealth) errorRate() float64 {
+	if len(e.samples) == 0 {
+		return 0
+	}
+	return float64(e.errors()) / float64(len(e.samples))
+}
+
+// latency returns the p-th percentile of the call latency using the
+// nearest-rank method.
+func (e *endpointHealth) latency(p float64) time.Duration {
+	if len(e.samples) == 0 {
+		return 0
+	}
+	ds := make([]time.Duration, len(e.samples))
+	for i, s := range e.samples {
+		ds[i] = s.duration
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	return ds[int(math.Ceil(p*float64(len(ds))))-1]
+}
+
+// redactEndpoint removes credentials, the path and the query from the
+// endpoint URL because RPC providers often put API keys there.
+func redactEndpoint(name string) string {
+	u, err := url.Parse(name)
+	if err != nil || u.Host == "" {
+		return name
+	}
+	r := u.Scheme + "://" + u.Host
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		r += "/..."
+	}
+	return r
+}
@@ -0,0 +1,328 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+)
+
+// stubCaller is a caller that always returns the same block number or error.
+type stubCaller struct {
+	calls int32
+	block string
+	err   error
+}
+
+func (c *stubCaller) CallContext(_ context.Context, result any, _ string, _ ...any) error {
+	atomic.AddInt32(&c.calls, 1)
+	if c.err != nil {
+		return c.err
+	}
+	return json.Unmarshal([]byte(`"`+c.block+`"`), result)
+}
+
+func Test_healthChecker_record(t *testing.T) {
+	tests := []struct {
+		maxErrorRate    float64
+		maxLatency      time.Duration
+		maxBlocksBehind uint64
+		samples         []sample
+		blocks          map[string]uint64
+		wantEvicted     bool
+		wantReason      string
+	}{
+		{
+			// No rules.
+			samples: []sample{{failed: true}, {failed: true}, {failed: true}},
+		},
+		{
+			maxErrorRate: 0.5,
+			samples:      []sample{{failed: true}, {failed: false}, {failed: true}},
+			wantEvicted:  true,
+			wantReason:   "error rate 0.67 exceeds 0.50",
+		},
+		{
+			// Not enough samples.
+			maxErrorRate: 0.5,
+			samples:      []sample{{failed: true}, {failed: true}},
+		},
+		{
+			maxLatency:  time.Second,
+			samples:     []sample{{duration: time.Millisecond}, {duration: time.Millisecond}, {duration: 2 * time.Second}},
+			wantEvicted: true,
+			wantReason:  "p90 latency 2s exceeds 1s",
+		},
+		{
+			maxLatency: time.Second,
+			samples:    []sample{{duration: time.Millisecond}, {duration: time.Millisecond}, {duration: time.Second}},
+		},
+		{
+			maxBlocksBehind: 10,
+			samples:         []sample{{}},
+			blocks:          map[string]uint64{"a": 100, "b": 111},
+			wantEvicted:     true,
+			wantReason:      "11 blocks behind exceeds 10",
+		},
+		{
+			maxBlocksBehind: 10,
+			samples:         []sample{{}},
+			blocks:          map[string]uint64{"a": 100, "b": 110},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			h := newHealthChecker()
+			h.log = null.New()
+			h.minSamples = 3
+			h.maxErrorRate = tt.maxErrorRate
+			h.maxLatency = tt.maxLatency
+			h.maxBlocksBehind = tt.maxBlocksBehind
+			for name, b := range tt.blocks {
+				h.endpoint(name).blockNumber = b
+			}
+			for _, s := range tt.samples {
+				var err error
+				if s.failed {
+					err = errors.New("error")
+				}
+				h.record("a", "eth_chainId", s.duration, nil, err)
+			}
+			assert.Equal(t, tt.wantEvicted, h.isEvicted("a"))
+			assert.Equal(t, tt.wantReason, h.endpoint("a").reason)
+		})
+	}
+}
+
+func Test_healthChecker_window(t *testing.T) {
+	h := newHealthChecker()
+	h.log = null.New()
+	h.window = 3
+	for i := 1; i <= 5; i++ {
+		h.record("a", "eth_chainId", time.Duration(i)*time.Millisecond, nil, nil)
+	}
+	s := h.status([]string{"a"})
+	require.Len(t, s, 1)
+	assert.Equal(t, 3, s[0].Requests)
+	assert.Equal(t, int64(4), s[0].LatencyP50)
+	assert.Equal(t, int64(5), s[0].LatencyP99)
+}
+
+func Test_healthChecker_probe(t *testing.T) {
+	h := newHealthChecker()
+	h.log = null.New()
+	h.minSamples = 1
+	h.maxErrorRate = 0.5
+	h.maxBlocksBehind = 10
+	h.probeInterval = 0
+	h.endpoint("b").blockNumber = 100
+
+	h.record("a", "eth_chainId", 0, nil, errors.New("error"))
+	require.True(t, h.isEvicted("a"))
+
+	// Only one probe at a time.
+	require.True(t, h.startProbe("a"))
+	require.False(t, h.startProbe("a"))
+
+	// Failed probe.
+	h.finishProbe("a", nil, errors.New("error"))
+	assert.True(t, h.isEvicted("a"))
+
+	// Endpoint is too far behind.
+	require.True(t, h.startProbe("a"))
+	h.finishProbe("a", ptr(types.Uint64ToNumber(50)), nil)
+	assert.True(t, h.isEvicted("a"))
+
+	// Endpoint is healthy again.
+	require.True(t, h.startProbe("a"))
+	h.finishProbe("a", ptr(types.Uint64ToNumber(95)), nil)
+	assert.False(t, h.isEvicted("a"))
+	assert.Equal(t, 0, h.status([]string{"a"})[0].Requests)
+
+	// Probe interval.
+	h.probeInterval = time.Hour
+	h.record("a", "eth_chainId", 0, nil, errors.New("error"))
+	require.True(t, h.isEvicted("a"))
+	assert.False(t, h.startProbe("a"))
+}
+
+func Test_RPC_Status(t *testing.T) {
+	healthy1 := &stubCaller{block: "0x10"}
+	healthy2 := &stubCaller{block: "0x10"}
+	failing := &stubCaller{err: errors.New("error")}
+	h, err := NewServer(
+		withCallers(map[string]caller{
+			"https://a.example/v3/secret": healthy1,
+			"https://b.example":           healthy2,
+			"https://c.example?key=1":     failing,
+		}),
+		WithRequirements(2, 10),
+		WithEviction(0.5, 0, 0),
+		WithProbeInterval(time.Hour),
+		withHealthWindow(2, 2),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		res := &rpcRes{}
+		jsonUnmarshal(t, serveRPC(t, h, "eth_blockNumber"), res)
+		assert.Equal(t, "0x10", res.Result)
+	}
+
+	// The failing endpoint must not be called after it was evicted.
+	assert.Equal(t, int32(3), atomic.LoadInt32(&healthy1.calls))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&healthy2.calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&failing.calls))
+
+	res := &struct {
+		Result []EndpointStatus `json:"result"`
+	}{}
+	jsonUnmarshal(t, serveRPC(t, h, "rpcsplitter_status"), res)
+	require.Len(t, res.Result, 3)
+	assert.Equal(t, "https://a.example/...", res.Result[0].Endpoint)
+	assert.False(t, res.Result[0].Evicted)
+	assert.Equal(t, uint64(16), res.Result[0].BlockNumber)
+	assert.Equal(t, "https://b.example", res.Result[1].Endpoint)
+	assert.False(t, res.Result[1].Evicted)
+	assert.Equal(t, "https://c.example/...", res.Result[2].Endpoint)
+	assert.True(t, res.Result[2].Evicted)
+	assert.Equal(t, 1.0, res.Result[2].ErrorRate)
+	assert.Equal(t, "error rate 1.00 exceeds 0.50", res.Result[2].EvictionReason)
+	assert.NotNil(t, res.Result[2].EvictedAt)
+}
+
+// revertError is a JSON-RPC error response returned for reverted calls.
+type revertError struct{}
+
+func (revertError) Error() string  { return "execution reverted" }
+func (revertError) ErrorCode() int { return 3 }
+
+// revertCaller is a caller that reverts all "eth_call" calls.
+type revertCaller struct {
+	calls int32
+}
+
+func (c *revertCaller) CallContext(_ context.Context, result any, method string, _ ...any) error {
+	atomic.AddInt32(&c.calls, 1)
+	if method == "eth_call" {
+		return revertError{}
+	}
+	return json.Unmarshal([]byte(`"0x10"`), result)
+}
+
+func Test_RPC_Status_RevertedCalls(t *testing.T) {
+	c := &revertCaller{}
+	h, err := NewServer(
+		withCallers(map[string]caller{"a": c}),
+		WithRequirements(1, 10),
+		WithEviction(0.5, 0, 0),
+		WithProbeInterval(time.Hour),
+		withHealthWindow(2, 2),
+	)
+	require.NoError(t, err)
+
+	// Reverted calls are valid responses and must not be counted as errors.
+	for i := 0; i < 3; i++ {
+		res := &rpcRes{}
+		jsonUnmarshal(t, serveRPC(t, h, "eth_call", map[string]string{"to": "0x2d800d93b065ce011af83f316cef9f0d005b0aa4"}, "0x10"), res)
+		assert.NotEmpty(t, res.Error.Message)
+	}
+	s := h.(*server).health.status([]string{"a"})
+	require.Len(t, s, 1)
+	assert.False(t, s[0].Evicted)
+	assert.Equal(t, 0, s[0].Errors)
+	assert.Equal(t, 0.0, s[0].ErrorRate)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&c.calls))
+}
+
+func Test_RPC_Status_NotEnoughHealthyEndpoints(t *testing.T) {
+	healthy := &stubCaller{block: "0x10"}
+	failing := &stubCaller{err: errors.New("error")}
+	h, err := NewServer(
+		withCallers(map[string]caller{"a": healthy, "b": failing}),
+		WithRequirements(1, 10),
+		WithEviction(0.5, 0, 0),
+		WithProbeInterval(time.Hour),
+		withHealthWindow(1, 1),
+	)
+	require.NoError(t, err)
+
+	// Evict the failing endpoint, then make the remaining one fail too.
+	serveRPC(t, h, "eth_blockNumber")
+	healthy.err = errors.New("error")
+	serveRPC(t, h, "eth_blockNumber")
+	healthy.err = nil
+
+	// Both endpoints are evicted, so requests go to all of them.
+	serveRPC(t, h, "eth_blockNumber")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&healthy.calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&failing.calls))
+}
+
+func serveRPC(t *testing.T, h http.Handler, method string, params ...any) []byte {
+	msg := jsonMarshal(t, rpcReq{ID: 1, JSONRPC: "2.0", Method: method, Params: params})
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(msg))
+	r.Header.Set("Content-Type", "application/json")
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+	return rw.Body.Bytes()
+}
+
+// slowCaller blocks until the context is canceled.
+type slowCaller struct{}
+
+func (c *slowCaller) CallContext(ctx context.Context, _ any, _ string, _ ...any) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func Test_RPC_Status_CanceledCalls(t *testing.T) {
+	h, err := NewServer(
+		withCallers(map[string]caller{"a": &stubCaller{block: "0x10"}, "b": &slowCaller{}}),
+		WithRequirements(1, 10),
+		WithGracefulTimeout(10*time.Millisecond),
+		WithEviction(0.5, 0, 0),
+		WithProbeInterval(time.Hour),
+		withHealthWindow(2, 2),
+	)
+	require.NoError(t, err)
+
+	// Calls to the slow endpoint are canceled after the response is
+	// returned and must be recorded as timeouts.
+	for i := 0; i < 2; i++ {
+		res := &rpcRes{}
+		jsonUnmarshal(t, serveRPC(t, h, "eth_blockNumber"), res)
+		assert.Equal(t, "0x10", res.Result)
+	}
+	assert.Eventually(t, func() bool {
+		s := h.(*server).health.status([]string{"b"})
+		return s[0].Errors == 2 && s[0].LatencyP50 >= 10
+	}, time.Second, 10*time.Millisecond)
+}
@@ -16,6 +16,8 @@
 package rpcsplitter
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	gethRPC "github.com/ethereum/go-ethereum/rpc"
@@ -26,7 +28,8 @@ import (
 type Option func(s *server) error
 
 // WithEndpoints options instructs RPC-Splitter to use provided list of
-// Ethereum RPC nodes.
+// Ethereum RPC nodes. Nodes with WebSocket URLs are also used for
+// subscriptions.
 func WithEndpoints(endpoints []string) Option {
 	return func(s *server) error {
 		for _, e := range endpoints {
@@ -35,6 +38,9 @@ func WithEndpoints(endpoints []string) Option {
 				return err
 			}
 			s.callers[e] = c
+			if isWebsocketURL(e) {
+				s.subscribers[e] = clientSubscriber{client: c}
+			}
 		}
 		return nil
 	}
@@ -82,6 +88,123 @@ func WithGracefulTimeout(t time.Duration) Option {
 	}
 }
 
+// WithEviction enables temporary eviction of unhealthy endpoints. Evicted
+// endpoints do not take part in requests and are periodically re-probed
+// until they are healthy again.
+//
+// maxErrorRate - the maximum ratio of failed calls among the most recent
+// calls.
+//
+// maxLatency - the maximum 90th percentile of the latency of the most recent
+// calls.
+//
+// maxBlocksBehind - the maximum number of blocks the endpoint can be behind
+// the highest block reported by any endpoint.
+//
+// A zero value disables the corresponding rule. Endpoints are never evicted
+// if the remaining ones would not be enough to meet the requirements set by
+// the WithRequirements option.
+func WithEviction(maxErrorRate float64, maxLatency time.Duration, maxBlocksBehind int) Option {
+	return func(s *server) error {
+		if maxErrorRate < 0 || maxErrorRate > 1 {
+			return fmt.Errorf("max error rate must be between 0 and 1")
+		}
+		if maxLatency < 0 || maxBlocksBehind < 0 {
+			return fmt.Errorf("max latency and max blocks behind must not be negative")
+		}
+		s.health.maxErrorRate = maxErrorRate
+		s.health.maxLatency = maxLatency
+		s.health.maxBlocksBehind = uint64(maxBlocksBehind)
+		return nil
+	}
+}
+
+// WithProbeInterval sets how often evicted endpoints are re-probed.
+func WithProbeInterval(t time.Duration) Option {
+	return func(s *server) error {
+		s.health.probeInterval = t
+		return nil
+	}
+}
+
+// WithCache enables caching of responses for queries that can never change,
+// such as eth_getBlockByHash or eth_call at a specific block number.
+//
+// maxEntries - the maximum number of cached responses, zero disables the
+// cache.
+//
+// maxSize - the maximum total size of cached responses in bytes.
+//
+// confirmations - how many blocks a block must be behind the last known
+// block to be considered immutable. Queries for newer blocks and for block
+// tags are never cached.
+func WithCache(maxEntries int, maxSize int, confirmations int) Option {
+	return func(s *server) error {
+		if maxEntries < 0 || maxSize < 0 || confirmations < 0 {
+			return fmt.Errorf("cache parameters must not be negative")
+		}
+		if maxEntries == 0 {
+			s.cache = nil
+			return nil
+		}
+		s.cache = newResponseCache(maxEntries, maxSize)
+		s.confirmations = uint64(confirmations)
+		return nil
+	}
+}
+
+// WithSubscriptionRequirements specifies the minimum number of endpoints that
+// must send the same notification before it is sent to subscribers. If not
+// set, the minResponses value from the WithRequirements option is used.
+func WithSubscriptionRequirements(minResponses int) Option {
+	return func(s *server) error {
+		if minResponses < 0 {
+			return fmt.Errorf("min responses must not be negative")
+		}
+		s.minSubscriptionResponses = minResponses
+		return nil
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of elements in a batch request.
+// Larger batches are rejected with an error. Zero disables the limit. The
+// default limit is 100 elements.
+func WithMaxBatchSize(size int) Option {
+	return func(s *server) error {
+		if size < 0 {
+			return fmt.Errorf("max batch size must not be negative")
+		}
+		s.maxBatchSize = size
+		return nil
+	}
+}
+
+// WithWebsocketOrigins sets the allowed origins for WebSocket connections.
+// The "*" origin allows all origins. By default, only localhost is allowed.
+// Requests without the Origin header are always allowed.
+func WithWebsocketOrigins(origins []string) Option {
+	return func(s *server) error {
+		s.wsOrigins = origins
+		return nil
+	}
+}
+
+// WithMethodPolicy sets the policy for the method. The method may be a method
+// name, like "eth_call", or a namespace wildcard, like "debug_*". Methods
+// that are not implemented by the RPC-Splitter but have a policy are
+// forwarded to the endpoints with unmodified params.
+// Such methods are forwarded only for HTTP requests, because WebSocket
+// requests are handled entirely by the geth RPC server.
+func WithMethodPolicy(method string, policy Policy) Option {
+	return func(s *server) error {
+		if !strings.Contains(method, "_") {
+			return fmt.Errorf("invalid method name: %s", method)
+		}
+		s.policies[method] = policy
+		return nil
+	}
+}
+
 // WithLogger sets logger.
 func WithLogger(logger log.Logger) Option {
 	return func(s *server) error {
@@ -96,3 +219,25 @@ func withCallers(callers map[string]caller) Option {
 		return nil
 	}
 }
+
+func withHealthWindow(window, minSamples int) Option {
+	return func(s *server) error {
+		s.health.window = window
+		s.health.minSamples = minSamples
+		return nil
+	}
+}
+
+func withSubscribers(subscribers map[string]subscriber) Option {
+	return func(s *server) error {
+		s.subscribers = subscribers
+		return nil
+	}
+}
+
+func withResubscribeInterval(t time.Duration) Option {
+	return func(s *server) error {
+		s.resubscribeInterval = t
+		return nil
+	}
+}
@@ -0,0 +1,219 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// PolicyType specifies how calls to a method are sent to endpoints.
+type PolicyType int
+
+const (
+	// ConsensusPolicy sends calls to all endpoints and compares responses
+	// as described in the WithRequirements option. This is the default
+	// policy.
+	ConsensusPolicy PolicyType = iota
+	// FirstSuccessPolicy sends calls to endpoints one by one and returns the
+	// first successful response.
+	FirstSuccessPolicy
+	// PinnedPolicy sends calls only to a single endpoint.
+	PinnedPolicy
+	// DenyPolicy rejects calls.
+	DenyPolicy
+)
+
+// Policy specifies how calls to a method are sent to endpoints.
+type Policy struct {
+	Type     PolicyType
+	Endpoint string // Endpoint is used only by the PinnedPolicy.
+}
+
+// defaultPolicies are used for methods whose responses are expected to be
+// different for every endpoint.
+var defaultPolicies = map[string]Policy{
+	"web3_clientVersion": {Type: FirstSuccessPolicy},
+}
+
+// ParsePolicy parses a policy in one of the following forms: "consensus",
+// "first", "pinned:<endpoint>" or "deny".
+func ParsePolicy(s string) (Policy, error) {
+	switch {
+	case s == "consensus":
+		return Policy{Type: ConsensusPolicy}, nil
+	case s == "first":
+		return Policy{Type: FirstSuccessPolicy}, nil
+	case s == "deny":
+		return Policy{Type: DenyPolicy}, nil
+	case strings.HasPrefix(s, "pinned:") && len(s) > len("pinned:"):
+		return Policy{Type: PinnedPolicy, Endpoint: strings.TrimPrefix(s, "pinned:")}, nil
+	}
+	return Policy{}, fmt.Errorf("invalid policy: %s", s)
+}
+
+// firstResolver returns the first successful response.
+type firstResolver struct{}
+
+// resolve implements resolver interface.
+func (r *firstResolver) resolve(resps []any) (any, error) {
+	for _, r := range resps {
+		if _, ok := r.(error); !ok {
+			return r, nil
+		}
+	}
+	return nil, addError(errNotEnoughResponses, collectErrors(resps)...)
+}
+
+// policy returns the policy for the given method. Policies set for a whole
+// namespace, like "debug_*", are used if there is no policy for the method.
+func (s *server) policy(method string) Policy {
+	if p, ok := s.policies[method]; ok {
+		return p
+	}
+	if i := strings.Index(method, "_"); i > 0 {
+		if p, ok := s.policies[method[:i]+"_*"]; ok {
+			return p
+		}
+	}
+	if p, ok := defaultPolicies[method]; ok {
+		return p
+	}
+	return Policy{Type: ConsensusPolicy}
+}
+
+// callFirstSuccess sends the call to healthy endpoints one by one, in the
+// order of their names, until one of them returns a successful response.
+func (s *server) callFirstSuccess(ctx context.Context, result any, method string, args ...any) error {
+	callers := s.activeCallers()
+	names := make([]string, 0, len(callers))
+	for n := range callers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, n := range names {
+		err := s.callEndpoints(ctx, map[string]caller{n: callers[n]}, &firstResolver{}, result, method, args...)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return addError(errNotEnoughResponses, errs...)
+}
+
+// passthroughRequest is a JSON-RPC request for a method that is not
+// implemented by the RPC-Splitter.
+type passthroughRequest struct {
+	ID     json.RawMessage   `json:"id,omitempty"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type passthroughResponse struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Result  *Any              `json:"result,omitempty"`
+	Error   *passthroughError `json:"error,omitempty"`
+}
+
+type passthroughError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// servePassthrough handles requests for methods that are not implemented by
+// the RPC-Splitter but have a policy set. Params are sent to endpoints
+// unmodified, and responses are resolved as described in the policy. It
+// returns false if the request is not a passthrough request.
+//
+// It is used only for HTTP requests. WebSocket connections are served by
+// the geth RPC server, which rejects methods that are not registered.
+func (s *server) servePassthrough(rw http.ResponseWriter, req *http.Request, body []byte) bool {
+	if len(body) == 0 || body[0] != '{' {
+		return false
+	}
+	msg := &passthroughRequest{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return false
+	}
+	if _, ok := s.methods[msg.Method]; ok || !s.hasPolicy(msg.Method) {
+		return false
+	}
+
+	ctx, ctxCancel := context.WithTimeout(req.Context(), s.totalTimeout)
+	defer ctxCancel()
+
+	args := make([]any, len(msg.Params))
+	for i, p := range msg.Params {
+		a := &Any{}
+		if err := a.UnmarshalJSON(p); err != nil {
+			return false
+		}
+		args[i] = a
+	}
+	res := &Any{}
+	err := s.call(ctx, s.defaultResolver, res, msg.Method, args...)
+
+	// Notifications do not have responses.
+	if len(msg.ID) == 0 {
+		return true
+	}
+	resp := passthroughResponse{JSONRPC: "2.0", ID: msg.ID}
+	if err != nil {
+		resp.Error = &passthroughError{Code: -32000, Message: err.Error()}
+	} else {
+		resp.Result = res
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(resp)
+	return true
+}
+
+// hasPolicy returns true if a policy was set for the method or for its
+// namespace.
+func (s *server) hasPolicy(method string) bool {
+	if _, ok := s.policies[method]; ok {
+		return true
+	}
+	if i := strings.Index(method, "_"); i > 0 {
+		_, ok := s.policies[method[:i]+"_*"]
+		return ok
+	}
+	return false
+}
+
+// apiMethods returns the names of RPC methods implemented by the api in
+// the given namespace.
+func apiMethods(namespace string, api any) []string {
+	var methods []string
+	t := reflect.TypeOf(api)
+	for i := 0; i < t.NumMethod(); i++ {
+		name := []rune(t.Method(i).Name)
+		name[0] = unicode.ToLower(name[0])
+		methods = append(methods, namespace+"_"+string(name))
+	}
+	return methods
+}
@@ -0,0 +1,180 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		want    Policy
+		wantErr bool
+	}{
+		{policy: "consensus", want: Policy{Type: ConsensusPolicy}},
+		{policy: "first", want: Policy{Type: FirstSuccessPolicy}},
+		{policy: "deny", want: Policy{Type: DenyPolicy}},
+		{policy: "pinned:https://example.com", want: Policy{Type: PinnedPolicy, Endpoint: "https://example.com"}},
+		{policy: "pinned:", wantErr: true},
+		{policy: "foo", wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			p, err := ParsePolicy(tt.policy)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func Test_RPC_Policy(t *testing.T) {
+	t.Run("deny", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "eth_chainId").
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("eth_chainId", Policy{Type: DenyPolicy})).
+			expectedError("the method eth_chainId is not allowed").
+			test()
+	})
+	t.Run("deny-cached", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "eth_getBlockByHash", types.Hash{}, false).
+			setOptions(
+				WithRequirements(2, 10),
+				WithCache(100, 1<<20, 5),
+				WithMethodPolicy("eth_getBlockByHash", Policy{Type: DenyPolicy}),
+			).
+			expectedError("the method eth_getBlockByHash is not allowed").
+			test()
+	})
+	t.Run("pinned", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "eth_chainId").
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("eth_chainId", Policy{Type: PinnedPolicy, Endpoint: "1"})).
+			mockClientCall(1, `0x1`, "eth_chainId").
+			expectedResult(`0x1`).
+			test()
+	})
+	t.Run("pinned-failed", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "eth_chainId").
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("eth_chainId", Policy{Type: PinnedPolicy, Endpoint: "1"})).
+			mockClientCall(1, errors.New("error#1"), "eth_chainId").
+			expectedError("error#1").
+			test()
+	})
+	t.Run("first-success", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "eth_chainId").
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("eth_*", Policy{Type: FirstSuccessPolicy})).
+			mockClientCall(0, `0x1`, "eth_chainId").
+			expectedResult(`0x1`).
+			test()
+	})
+	t.Run("first-success-all-failed", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "eth_chainId").
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("eth_chainId", Policy{Type: FirstSuccessPolicy})).
+			mockClientCall(0, errors.New("error#1"), "eth_chainId").
+			mockClientCall(1, errors.New("error#2"), "eth_chainId").
+			expectedError("error#1").
+			expectedError("error#2").
+			test()
+	})
+	t.Run("method-overrides-namespace", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "eth_chainId").
+			setOptions(
+				WithRequirements(2, 10),
+				WithMethodPolicy("eth_*", Policy{Type: DenyPolicy}),
+				WithMethodPolicy("eth_chainId", Policy{Type: ConsensusPolicy}),
+			).
+			mockClientCall(0, `0x1`, "eth_chainId").
+			mockClientCall(1, `0x1`, "eth_chainId").
+			expectedResult(`0x1`).
+			test()
+	})
+}
+
+func Test_RPC_Passthrough(t *testing.T) {
+	txHash := newAny(`"0xab059a62e22e230fe0f56d8555340a29b2e9532360368f810595453f6fdd213b"`)
+	opts := newAny(`{"tracer":"callTracer"}`)
+	trace := newAny(`{"type":"CALL","gas":"0x1"}`)
+	t.Run("consensus", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "debug_traceTransaction", txHash, opts).
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("debug_*", Policy{Type: ConsensusPolicy})).
+			mockClientCall(0, trace, "debug_traceTransaction", txHash, opts).
+			mockClientCall(1, trace, "debug_traceTransaction", txHash, opts).
+			mockClientCall(2, errors.New("error#1"), "debug_traceTransaction", txHash, opts).
+			expectedResult(trace).
+			test()
+	})
+	t.Run("pinned", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "trace_transaction", txHash).
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("trace_transaction", Policy{Type: PinnedPolicy, Endpoint: "2"})).
+			mockClientCall(2, trace, "trace_transaction", txHash).
+			expectedResult(trace).
+			test()
+	})
+	t.Run("deny", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "debug_traceTransaction", txHash).
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("debug_*", Policy{Type: DenyPolicy})).
+			expectedError("the method debug_traceTransaction is not allowed").
+			test()
+	})
+	t.Run("no-policy", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "debug_traceTransaction", txHash).
+			setOptions(WithRequirements(2, 10)).
+			expectedError("the method debug_traceTransaction does not exist").
+			test()
+	})
+}
+
+func Test_RPC_Passthrough_Websocket(t *testing.T) {
+	h, err := NewServer(
+		withCallers(map[string]caller{"0": &mockClient{t: t}}),
+		WithRequirements(1, 10),
+		WithMethodPolicy("debug_*", Policy{Type: ConsensusPolicy}),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	client, err := gethRPC.Dial("ws" + strings.TrimPrefix(srv.URL, "http"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Methods that are not implemented are not forwarded over WebSocket.
+	err = client.Call(&Any{}, "debug_traceTransaction", types.Hash{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+}
+
+func Test_RPC_Policy_UnknownEndpoint(t *testing.T) {
+	_, err := NewServer(
+		withCallers(map[string]caller{"0": &mockClient{t: t}}),
+		WithRequirements(1, 10),
+		WithMethodPolicy("eth_call", Policy{Type: PinnedPolicy, Endpoint: "1"}),
+	)
+	assert.Error(t, err)
+}
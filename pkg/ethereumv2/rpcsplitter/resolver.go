@@ -25,6 +25,7 @@ import (
 
 var errNotEnoughResponses = errors.New("not enough responses from RPC servers")
 var errDifferentResponses = errors.New("RPC servers returned different responses")
+var errCallTimeout = errors.New("call canceled after the graceful timeout")
 
 // resolver takes responses from different endpoints and returns a single
 // response.
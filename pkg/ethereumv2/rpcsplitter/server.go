@@ -20,8 +20,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"reflect"
+	"sync/atomic"
 	"time"
 
 	gethRPC "github.com/ethereum/go-ethereum/rpc"
@@ -36,6 +38,7 @@ const LoggerTag = "RPCSPLITTER"
 
 const defaultTotalTimeout = 10 * time.Second
 const defaultGracefulTimeout = 1 * time.Second
+const defaultResubscribeInterval = 5 * time.Second
 
 type caller interface {
 	CallContext(ctx context.Context, result any, method string, args ...any) error
@@ -44,17 +47,43 @@ type caller interface {
 // server is an RPC proxy server. It merges multiple RPC endpoints into one.
 type server struct {
 	rpc *gethRPC.Server // rpc is an RPC server.
+	ws  http.Handler    // ws is a WebSocket handler for the RPC server.
 	eth *rpcETHAPI      // eth implements procedures with the "eth_" prefix.
 	net *rpcNETAPI      // net implements procedures with the "net_" prefix.
+	web *rpcWEB3API     // web implements procedures with the "web3_" prefix.
+	spl *rpcSplitterAPI // spl implements procedures with the "rpcsplitter_" prefix.
 	log log.Logger
 
 	// List of endpoint callers.
 	callers map[string]caller
+	// List of endpoints that support subscriptions.
+	subscribers map[string]subscriber
+	// Minimum number of endpoints that must send the same notification.
+	minSubscriptionResponses int
+	// Interval between attempts to restore failed upstream subscriptions.
+	resubscribeInterval time.Duration
+	// Allowed origins for WebSocket connections.
+	wsOrigins []string
+	// Maximum number of elements in a batch request, zero means no limit.
+	maxBatchSize int
+	// Per-method policies, keys are method names or namespace wildcards.
+	policies map[string]Policy
+	// Names of all implemented methods.
+	methods map[string]struct{}
 	// Total timeout for all endpoints.
 	totalTimeout time.Duration
 	// Timeout for slower endpoints, when it exceeds, request will be canceled
 	// if there is enough responses.
 	gracefulTimeout time.Duration
+	// Health statistics of endpoints.
+	health *healthChecker
+	// Cache of responses for immutable queries, nil if disabled.
+	cache *responseCache
+	// Number of blocks after which a block is considered immutable.
+	confirmations uint64
+	// The last block number returned by the eth_blockNumber method. Must be
+	// accessed atomically.
+	lastBlock uint64
 
 	// Resolvers used to convert multiple responses into a single response:
 	defaultResolver     *defaultResolver
@@ -70,21 +99,51 @@ type rpcNETAPI struct {
 	handler *server
 }
 
+type rpcWEB3API struct {
+	handler *server
+}
+
+type rpcSplitterAPI struct {
+	handler *server
+}
+
 func NewServer(opts ...Option) (http.Handler, error) {
 	h := &server{
-		rpc:     gethRPC.NewServer(),
-		callers: map[string]caller{},
+		rpc:         gethRPC.NewServer(),
+		callers:     map[string]caller{},
+		subscribers: map[string]subscriber{},
+		health:      newHealthChecker(),
+		policies:    map[string]Policy{},
+		methods:     map[string]struct{}{},
+
+		maxBatchSize:        defaultMaxBatchSize,
+		resubscribeInterval: defaultResubscribeInterval,
 	}
 	eth := &rpcETHAPI{handler: h}
 	net := &rpcNETAPI{handler: h}
+	web := &rpcWEB3API{handler: h}
+	spl := &rpcSplitterAPI{handler: h}
 	h.eth = eth
 	h.net = net
+	h.web = web
+	h.spl = spl
 	if err := h.rpc.RegisterName("eth", eth); err != nil {
 		return nil, err
 	}
 	if err := h.rpc.RegisterName("net", net); err != nil {
 		return nil, err
 	}
+	if err := h.rpc.RegisterName("web3", web); err != nil {
+		return nil, err
+	}
+	if err := h.rpc.RegisterName("rpcsplitter", spl); err != nil {
+		return nil, err
+	}
+	for ns, api := range map[string]any{"eth": eth, "net": net, "web3": web, "rpcsplitter": spl} {
+		for _, m := range apiMethods(ns, api) {
+			h.methods[m] = struct{}{}
+		}
+	}
 	for _, opt := range opts {
 		err := opt(h)
 		if err != nil {
@@ -100,17 +159,44 @@ func NewServer(opts ...Option) (http.Handler, error) {
 	if h.defaultResolver == nil || h.gasValueResolver == nil || h.blockNumberResolver == nil {
 		return nil, fmt.Errorf("rpc-splitter error: WithRequirements option is required")
 	}
+	for m, p := range h.policies {
+		if _, ok := h.callers[p.Endpoint]; p.Type == PinnedPolicy && !ok {
+			return nil, fmt.Errorf("rpc-splitter error: unknown endpoint %s in the %s method policy", p.Endpoint, m)
+		}
+	}
 	if h.totalTimeout == 0 {
 		h.totalTimeout = defaultTotalTimeout
 	}
 	if h.gracefulTimeout == 0 {
 		h.gracefulTimeout = defaultGracefulTimeout
 	}
+	if h.minSubscriptionResponses == 0 {
+		h.minSubscriptionResponses = h.defaultResolver.minResponses
+	}
+	h.ws = h.rpc.WebsocketHandler(h.wsOrigins)
 	h.log = h.log.WithField("tag", LoggerTag)
+	h.health.log = h.log
 	return h, nil
 }
 
 func (s *server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if isWebsocket(req) {
+		s.ws.ServeHTTP(rw, req)
+		return
+	}
+	body := readBody(req)
+	if msgs := parseBatch(body); msgs != nil {
+		s.serveBatch(rw, req, msgs)
+		return
+	}
+	s.serveSingle(rw, req, body)
+}
+
+// serveSingle handles a single JSON-RPC request.
+func (s *server) serveSingle(rw http.ResponseWriter, req *http.Request, body []byte) {
+	if s.servePassthrough(rw, req, body) {
+		return
+	}
 	s.rpc.ServeHTTP(rw, req)
 }
 
@@ -118,12 +204,15 @@ func (s *server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 //
 // It returns the most common response that occurred at least as many times as
 // specified in the minRes method.
-func (r *rpcETHAPI) BlockNumber() (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) BlockNumber(ctx context.Context) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	res := &types.Number{}
 	err := r.handler.call(ctx, r.handler.blockNumberResolver, res, "eth_blockNumber")
+	if err == nil {
+		r.handler.updateLastBlock(res)
+	}
 
 	return res, err
 }
@@ -132,8 +221,8 @@ func (r *rpcETHAPI) BlockNumber() (any, error) {
 //
 // The number returned by this method is the median of all numbers returned
 // by the endpoints.
-func (r *rpcETHAPI) GetBlockByHash(blockHash types.Hash, obj bool) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetBlockByHash(ctx context.Context, blockHash types.Hash, obj bool) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	var res any
@@ -143,7 +232,9 @@ func (r *rpcETHAPI) GetBlockByHash(blockHash types.Hash, obj bool) (any, error)
 	case false:
 		res = &types.BlockTxHashes{}
 	}
-	err := r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getBlockByHash", blockHash, obj)
+	err := r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return !isEmptyBlock(res)
+	}, "eth_getBlockByHash", blockHash, obj)
 
 	return res, err
 }
@@ -152,8 +243,8 @@ func (r *rpcETHAPI) GetBlockByHash(blockHash types.Hash, obj bool) (any, error)
 //
 // It returns the most common response that occurred at least as many times as
 // specified in the minRes method.
-func (r *rpcETHAPI) GetBlockByNumber(blockNumber types.Number, obj bool) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetBlockByNumber(ctx context.Context, blockNumber types.Number, obj bool) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	var res any
@@ -163,7 +254,9 @@ func (r *rpcETHAPI) GetBlockByNumber(blockNumber types.Number, obj bool) (any, e
 	case false:
 		res = &types.BlockTxHashes{}
 	}
-	err := r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getBlockByNumber", blockNumber, obj)
+	err := r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(types.BlockNumber(blockNumber)) && !isEmptyBlock(res)
+	}, "eth_getBlockByNumber", blockNumber, obj)
 
 	return res, err
 }
@@ -172,12 +265,14 @@ func (r *rpcETHAPI) GetBlockByNumber(blockNumber types.Number, obj bool) (any, e
 //
 // It returns the most common response that occurred at least as many times as
 // specified in the minRes method.
-func (r *rpcETHAPI) GetTransactionByHash(txHash types.Hash) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetTransactionByHash(ctx context.Context, txHash types.Hash) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	res := &types.Transaction{}
-	err := r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getTransactionByHash", txHash)
+	err := r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return res.BlockHash != (types.Hash{}) && r.handler.isConfirmed(types.BlockNumber(res.BlockNumber))
+	}, "eth_getTransactionByHash", txHash)
 
 	return res, err
 }
@@ -190,8 +285,8 @@ func (r *rpcETHAPI) GetTransactionByHash(txHash types.Hash) (any, error) {
 // If the block number is set to "latest" or "pending", it will be replaced by
 // the block number returned by the BlockNumber method. The "earliest" tag is
 // not supported.
-func (r *rpcETHAPI) GetTransactionCount(addr types.Address, blockID types.BlockNumber) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetTransactionCount(ctx context.Context, addr types.Address, blockID types.BlockNumber) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
@@ -199,7 +294,9 @@ func (r *rpcETHAPI) GetTransactionCount(addr types.Address, blockID types.BlockN
 		return nil, err
 	}
 	res := &types.Number{}
-	err = r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getTransactionCount", addr, blockNumber)
+	err = r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(blockNumber)
+	}, "eth_getTransactionCount", addr, blockNumber)
 
 	return res, err
 }
@@ -208,12 +305,14 @@ func (r *rpcETHAPI) GetTransactionCount(addr types.Address, blockID types.BlockN
 //
 // It returns the most common response that occurred at least as many times as
 // specified in the minRes method.
-func (r *rpcETHAPI) GetTransactionReceipt(txHash types.Hash) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetTransactionReceipt(ctx context.Context, txHash types.Hash) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	res := &types.TransactionReceiptType{}
-	err := r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getTransactionReceipt", txHash)
+	err := r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return res.BlockHash != (types.Hash{}) && r.handler.isConfirmed(types.BlockNumber(res.BlockNumber))
+	}, "eth_getTransactionReceipt", txHash)
 
 	return res, err
 }
@@ -226,8 +325,8 @@ func (r *rpcETHAPI) GetTransactionReceipt(txHash types.Hash) (any, error) {
 // SendRawTransaction implements the "eth_sendRawTransaction" call.
 //
 // It returns the most common response.
-func (r *rpcETHAPI) SendRawTransaction(data types.Bytes) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) SendRawTransaction(ctx context.Context, data types.Bytes) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	res := &types.Hash{}
@@ -244,8 +343,8 @@ func (r *rpcETHAPI) SendRawTransaction(data types.Bytes) (any, error) {
 // If the block number is set to "latest" or "pending", it will be replaced by
 // the block number returned by the BlockNumber method. The "earliest" tag is
 // not supported.
-func (r *rpcETHAPI) GetBalance(addr types.Address, blockID types.BlockNumber) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetBalance(ctx context.Context, addr types.Address, blockID types.BlockNumber) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
@@ -253,7 +352,9 @@ func (r *rpcETHAPI) GetBalance(addr types.Address, blockID types.BlockNumber) (a
 		return nil, err
 	}
 	res := &types.Number{}
-	err = r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getBalance", addr, blockNumber)
+	err = r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(blockNumber)
+	}, "eth_getBalance", addr, blockNumber)
 
 	return res, err
 }
@@ -266,8 +367,8 @@ func (r *rpcETHAPI) GetBalance(addr types.Address, blockID types.BlockNumber) (a
 // If the block number is set to "latest" or "pending", it will be replaced by
 // the block number returned by the BlockNumber method. The "earliest" tag is
 // not supported.
-func (r *rpcETHAPI) GetCode(addr types.Address, blockID types.BlockNumber) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetCode(ctx context.Context, addr types.Address, blockID types.BlockNumber) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
@@ -275,7 +376,9 @@ func (r *rpcETHAPI) GetCode(addr types.Address, blockID types.BlockNumber) (any,
 		return nil, err
 	}
 	res := &types.Bytes{}
-	err = r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getCode", addr, blockNumber)
+	err = r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(blockNumber)
+	}, "eth_getCode", addr, blockNumber)
 
 	return res, err
 }
@@ -288,8 +391,8 @@ func (r *rpcETHAPI) GetCode(addr types.Address, blockID types.BlockNumber) (any,
 // If the block number is set to "latest" or "pending", it will be replaced by
 // the block number returned by the BlockNumber method. The "earliest" tag is
 // not supported.
-func (r *rpcETHAPI) GetStorageAt(data types.Address, pos types.Number, blockID types.BlockNumber) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetStorageAt(ctx context.Context, data types.Address, pos types.Number, blockID types.BlockNumber) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
@@ -297,13 +400,38 @@ func (r *rpcETHAPI) GetStorageAt(data types.Address, pos types.Number, blockID t
 		return nil, err
 	}
 	res := &types.Hash{}
-	err = r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getStorageAt", data, pos, blockNumber)
+	err = r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(blockNumber)
+	}, "eth_getStorageAt", data, pos, blockNumber)
 
 	return res, err
 }
 
 // TODO: eth_accounts
-// TODO: eth_getProof
+
+// GetProof implements the "eth_getProof" call.
+//
+// It returns the most common response that occurred at least as many times as
+// specified in the minRes method.
+//
+// If the block number is set to "latest" or "pending", it will be replaced by
+// the block number returned by the BlockNumber method. The "earliest" tag is
+// not supported.
+func (r *rpcETHAPI) GetProof(ctx context.Context, addr types.Address, keys []types.Hash, blockID types.BlockNumber) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
+	defer ctxCancel()
+
+	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	res := &Any{}
+	err = r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(blockNumber)
+	}, "eth_getProof", addr, keys, blockNumber)
+
+	return res, err
+}
 
 // Call implements the "eth_call" call.
 //
@@ -313,8 +441,8 @@ func (r *rpcETHAPI) GetStorageAt(data types.Address, pos types.Number, blockID t
 // If the block number is set to "latest" or "pending", it will be replaced by
 // the block number returned by the BlockNumber method. The "earliest" tag is
 // not supported.
-func (r *rpcETHAPI) Call(args Any, blockID types.BlockNumber, overrides *Any) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) Call(ctx context.Context, args Any, blockID types.BlockNumber, overrides *Any) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
@@ -322,7 +450,9 @@ func (r *rpcETHAPI) Call(args Any, blockID types.BlockNumber, overrides *Any) (a
 		return nil, err
 	}
 	res := &types.Bytes{}
-	err = r.handler.call(ctx, r.handler.defaultResolver, res, "eth_call", args, blockNumber, overrides)
+	err = r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(blockNumber)
+	}, "eth_call", args, blockNumber, overrides)
 
 	return res, err
 }
@@ -335,8 +465,8 @@ func (r *rpcETHAPI) Call(args Any, blockID types.BlockNumber, overrides *Any) (a
 // If the block number is set to "latest" or "pending", it will be replaced by
 // the block number returned by the BlockNumber method. The "earliest" tag is
 // not supported.
-func (r *rpcETHAPI) GetLogs(logFilter types.FilterLogsQuery) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GetLogs(ctx context.Context, logFilter types.FilterLogsQuery) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	if logFilter.FromBlock != nil {
@@ -354,7 +484,12 @@ func (r *rpcETHAPI) GetLogs(logFilter types.FilterLogsQuery) (any, error) {
 		*logFilter.ToBlock = blockNumber
 	}
 	res := &[]types.Log{}
-	err := r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getLogs", logFilter)
+	err := r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		if logFilter.BlockHash != nil {
+			return true
+		}
+		return logFilter.FromBlock != nil && logFilter.ToBlock != nil && r.handler.isConfirmed(*logFilter.ToBlock)
+	}, "eth_getLogs", logFilter)
 
 	return res, err
 }
@@ -365,8 +500,8 @@ func (r *rpcETHAPI) GetLogs(logFilter types.FilterLogsQuery) (any, error) {
 //
 // The number returned by this method is the median of all numbers returned
 // by the endpoints.
-func (r *rpcETHAPI) GasPrice() (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) GasPrice(ctx context.Context) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	res := &types.Number{}
@@ -383,8 +518,8 @@ func (r *rpcETHAPI) GasPrice() (any, error) {
 // If the block number is set to "latest" or "pending", it will be replaced by
 // the block number returned by the BlockNumber method. The "earliest" tag is
 // not supported.
-func (r *rpcETHAPI) EstimateGas(args Any, blockID types.BlockNumber) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) EstimateGas(ctx context.Context, args Any, blockID types.BlockNumber) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
@@ -401,8 +536,8 @@ func (r *rpcETHAPI) EstimateGas(args Any, blockID types.BlockNumber) (any, error
 //
 // It returns the most common response that occurred at least as many times as
 // specified in the minRes method.
-func (r *rpcETHAPI) FeeHistory(count types.Number, newestBlockID types.BlockNumber, percentiles Any) (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) FeeHistory(ctx context.Context, count types.Number, newestBlockID types.BlockNumber, percentiles Any) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	blockNumber, err := r.handler.taggedBlockToNumber(ctx, newestBlockID)
@@ -419,8 +554,8 @@ func (r *rpcETHAPI) FeeHistory(count types.Number, newestBlockID types.BlockNumb
 //
 // The number returned by this method is the median of all numbers returned
 // by the endpoints.
-func (r *rpcETHAPI) MaxPriorityFeePerGas() (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) MaxPriorityFeePerGas(ctx context.Context) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	res := &types.Number{}
@@ -433,8 +568,8 @@ func (r *rpcETHAPI) MaxPriorityFeePerGas() (any, error) {
 //
 // It returns the most common response that occurred at least as many times as
 // specified in the minRes method.
-func (r *rpcETHAPI) ChainId() (any, error) { //nolint:revive,stylecheck
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcETHAPI) ChainId(ctx context.Context) (any, error) { //nolint:revive,stylecheck
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	res := &types.Number{}
@@ -445,8 +580,6 @@ func (r *rpcETHAPI) ChainId() (any, error) { //nolint:revive,stylecheck
 
 // TODO: eth_getUncleByBlockNumberAndIndex
 // TODO: eth_getUncleByBlockHashAndIndex
-// TODO: eth_getUncleCountByBlockHash
-// TODO: eth_getUncleCountByBlockNumber
 // TODO: eth_getFilterChanges
 // TODO: eth_getFilterLogs
 // TODO: eth_newBlockFilter
@@ -454,12 +587,89 @@ func (r *rpcETHAPI) ChainId() (any, error) { //nolint:revive,stylecheck
 // TODO: eth_newPendingTransactionFilter
 // TODO: eth_uninstallFilter
 
+// GetUncleCountByBlockHash implements the "eth_getUncleCountByBlockHash" call.
+//
+// It returns the most common response that occurred at least as many times as
+// specified in the minRes method.
+func (r *rpcETHAPI) GetUncleCountByBlockHash(ctx context.Context, blockHash types.Hash) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
+	defer ctxCancel()
+
+	res := &types.Number{}
+	err := r.handler.call(ctx, r.handler.defaultResolver, res, "eth_getUncleCountByBlockHash", blockHash)
+
+	return res, err
+}
+
+// GetUncleCountByBlockNumber implements the "eth_getUncleCountByBlockNumber"
+// call.
+//
+// It returns the most common response that occurred at least as many times as
+// specified in the minRes method.
+//
+// If the block number is set to "latest" or "pending", it will be replaced by
+// the block number returned by the BlockNumber method. The "earliest" tag is
+// not supported.
+func (r *rpcETHAPI) GetUncleCountByBlockNumber(ctx context.Context, blockID types.BlockNumber) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
+	defer ctxCancel()
+
+	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	res := &types.Number{}
+	err = r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(blockNumber)
+	}, "eth_getUncleCountByBlockNumber", blockNumber)
+
+	return res, err
+}
+
+// GetBlockReceipts implements the "eth_getBlockReceipts" call.
+//
+// It returns the most common response that occurred at least as many times as
+// specified in the minRes method.
+//
+// If the block number is set to "latest" or "pending", it will be replaced by
+// the block number returned by the BlockNumber method. The "earliest" tag is
+// not supported.
+func (r *rpcETHAPI) GetBlockReceipts(ctx context.Context, blockID types.BlockNumber) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
+	defer ctxCancel()
+
+	blockNumber, err := r.handler.taggedBlockToNumber(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	res := &[]types.TransactionReceiptType{}
+	err = r.handler.cachedCall(ctx, r.handler.defaultResolver, res, func() bool {
+		return r.handler.isConfirmed(blockNumber)
+	}, "eth_getBlockReceipts", blockNumber)
+
+	return res, err
+}
+
+// Syncing implements the "eth_syncing" call.
+//
+// It returns the most common response that occurred at least as many times as
+// specified in the minRes method.
+func (r *rpcETHAPI) Syncing(ctx context.Context) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
+	defer ctxCancel()
+
+	res := &Any{}
+	err := r.handler.call(ctx, r.handler.defaultResolver, res, "eth_syncing")
+
+	return res, err
+}
+
 // Version implements the "net_version" call.
 //
 // It returns the most common response that occurred at least as many times as
 // specified in the minRes method.
-func (r *rpcNETAPI) Version() (any, error) {
-	ctx, ctxCancel := context.WithTimeout(context.Background(), r.handler.totalTimeout)
+func (r *rpcNETAPI) Version(ctx context.Context) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
 	defer ctxCancel()
 
 	res := &Any{}
@@ -468,6 +678,43 @@ func (r *rpcNETAPI) Version() (any, error) {
 	return res, err
 }
 
+// ClientVersion implements the "web3_clientVersion" call.
+//
+// Because every endpoint may run a different client, by default it returns
+// the first successful response.
+func (r *rpcWEB3API) ClientVersion(ctx context.Context) (any, error) {
+	ctx, ctxCancel := context.WithTimeout(ctx, r.handler.totalTimeout)
+	defer ctxCancel()
+
+	res := &Any{}
+	err := r.handler.call(ctx, r.handler.defaultResolver, res, "web3_clientVersion")
+
+	return res, err
+}
+
+// Status implements the "rpcsplitter_status" call.
+//
+// It returns the health statistics of all endpoints. Endpoint URLs are
+// redacted because they often contain API keys.
+func (r *rpcSplitterAPI) Status() []EndpointStatus {
+	names := make([]string, 0, len(r.handler.callers))
+	for n := range r.handler.callers {
+		names = append(names, n)
+	}
+	return r.handler.health.status(names)
+}
+
+// CacheStats implements the "rpcsplitter_cacheStats" call.
+//
+// It returns the statistics of the response cache.
+func (r *rpcSplitterAPI) CacheStats() (*CacheStats, error) {
+	if r.handler.cache == nil {
+		return nil, errors.New("cache is disabled")
+	}
+	s := r.handler.cache.stats()
+	return &s, nil
+}
+
 // taggedBlockToNumber returns a block number for tagged blocks. This is
 // necessary because different RPC endpoints may convert tags to different
 // block numbers.
@@ -489,11 +736,83 @@ func (s *server) taggedBlockToNumber(ctx context.Context, blockID types.BlockNum
 	if err != nil {
 		return types.BlockNumber{}, err
 	}
+	s.updateLastBlock(res)
 	return types.BlockNumber(*res), nil
 }
 
-// call executes RPC on all endpoints with the given arguments. If the context is
-// canceled before the call has successfully returned, call returns immediately.
+// cachedCall works like call, but for queries whose responses never change,
+// it returns the response from the cache if available. The response is
+// stored in the cache only if the cacheable function returns true. The
+// function is invoked after the result is populated.
+//
+// The method policy is checked before the cache lookup, so denied methods
+// are never served from the cache.
+func (s *server) cachedCall(
+	ctx context.Context,
+	resolver resolver,
+	result any,
+	cacheable func() bool,
+	method string,
+	args ...any,
+) error {
+	if s.cache == nil || s.policy(method).Type == DenyPolicy {
+		return s.call(ctx, resolver, result, method, args...)
+	}
+	key, err := cacheKey(method, args)
+	if err != nil {
+		return s.call(ctx, resolver, result, method, args...)
+	}
+	if s.cache.get(key, result) {
+		return nil
+	}
+	if err := s.call(ctx, resolver, result, method, args...); err != nil {
+		return err
+	}
+	if cacheable() {
+		s.cache.add(key, result)
+	}
+	return nil
+}
+
+// updateLastBlock updates the last known block number.
+func (s *server) updateLastBlock(n *types.Number) {
+	b := n.Big().Uint64()
+	for {
+		last := atomic.LoadUint64(&s.lastBlock)
+		if b <= last || atomic.CompareAndSwapUint64(&s.lastBlock, last, b) {
+			return
+		}
+	}
+}
+
+// isConfirmed returns true if the block has at least as many confirmations
+// as required for its data to be considered immutable. Tags are never
+// confirmed, and no block is confirmed until the last block is known.
+func (s *server) isConfirmed(blockID types.BlockNumber) bool {
+	if blockID.IsTag() {
+		return false
+	}
+	last := atomic.LoadUint64(&s.lastBlock)
+	if last == 0 || last < s.confirmations {
+		return false
+	}
+	return blockID.Big().Cmp(new(big.Int).SetUint64(last-s.confirmations)) <= 0
+}
+
+// isEmptyBlock returns true if the block was not found.
+func isEmptyBlock(res any) bool {
+	switch b := res.(type) {
+	case *types.BlockTxHashes:
+		return b.Hash == types.Hash{}
+	case *types.BlockTxObjects:
+		return b.Hash == types.Hash{}
+	}
+	return true
+}
+
+// call executes RPC on endpoints selected by the method policy with the
+// given arguments. If the context is canceled before the call has
+// successfully returned, call returns immediately.
 //
 // The result must be a pointer with a proper type.
 func (s *server) call(
@@ -506,10 +825,37 @@ func (s *server) call(
 	if reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer")
 	}
-	// Send request to all endpoints.
-	ch := make(chan any, len(s.callers))
+	p := s.policy(method)
+	switch p.Type {
+	case DenyPolicy:
+		return fmt.Errorf("the method %s is not allowed", method)
+	case PinnedPolicy:
+		return s.callEndpoints(ctx, map[string]caller{p.Endpoint: s.callers[p.Endpoint]}, &firstResolver{}, result, method, args...)
+	case FirstSuccessPolicy:
+		return s.callFirstSuccess(ctx, result, method, args...)
+	default:
+		return s.callEndpoints(ctx, s.activeCallers(), resolver, result, method, args...)
+	}
+}
+
+// callEndpoints executes RPC on the given endpoints and resolves responses
+// using the resolver.
+func (s *server) callEndpoints(
+	ctx context.Context,
+	callers map[string]caller,
+	resolver resolver,
+	result any,
+	method string,
+	args ...any,
+) error {
+	// Calls made by elements of a batch request are queued and sent as
+	// batch requests.
+	if b := batchFromContext(ctx); b != nil {
+		callers = b.callers(callers, method, removeTrailingNilArgs(args))
+	}
+	ch := make(chan any, len(callers))
 	rt := reflect.TypeOf(result).Elem()
-	for n, c := range s.callers {
+	for n, c := range callers {
 		n, c := n, c
 		go func() {
 			t := time.Now()
@@ -519,6 +865,13 @@ func (s *server) call(
 				if r := recover(); r != nil {
 					err = fmt.Errorf("panic: %s", r)
 				}
+				// Requests canceled after the response was returned took
+				// longer than the graceful timeout, so they are recorded
+				// as timeouts with the time elapsed until cancellation.
+				if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+					err = errCallTimeout
+				}
+				s.health.record(n, method, time.Since(t), res, err)
 				switch {
 				case err != nil:
 					s.log.
@@ -558,7 +911,7 @@ func (s *server) call(
 		case <-t.C:
 			wait = false
 		}
-		if len(rs) == len(s.callers) {
+		if len(rs) == len(callers) {
 			wait = false
 		}
 		if !wait {
@@ -567,13 +920,53 @@ func (s *server) call(
 			case err == nil:
 				reflect.ValueOf(result).Elem().Set(reflect.ValueOf(res).Elem())
 				return nil
-			case len(rs) >= len(s.callers):
+			case len(rs) >= len(callers):
 				return err
 			}
 		}
 	}
 }
 
+// activeCallers returns the callers of endpoints that are not evicted and
+// schedules probes of the evicted ones. If there are not enough healthy
+// endpoints to meet the requirements, all endpoints are returned.
+func (s *server) activeCallers() map[string]caller {
+	active := make(map[string]caller, len(s.callers))
+	for n, c := range s.callers {
+		if s.health.isEvicted(n) {
+			s.probe(n, c)
+			continue
+		}
+		active[n] = c
+	}
+	if len(active) < s.defaultResolver.minResponses {
+		return s.callers
+	}
+	return active
+}
+
+// probe checks in the background whether an evicted endpoint is healthy
+// again.
+func (s *server) probe(name string, c caller) {
+	if !s.health.startProbe(name) {
+		return
+	}
+	go func() {
+		ctx, ctxCancel := context.WithTimeout(context.Background(), s.totalTimeout)
+		defer ctxCancel()
+
+		res := &types.Number{}
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %s", r)
+			}
+			s.health.finishProbe(name, res, err)
+		}()
+		err = c.CallContext(ctx, res, "eth_blockNumber")
+	}()
+}
+
 // removeTrailingNilArgs removes trailing nil parameters from the params
 // slice. Some RPC servers do not like null parameters and will return a
 // "bad request" error if they occur.
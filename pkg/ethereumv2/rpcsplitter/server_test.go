@@ -838,10 +838,51 @@ func Test_RPC_Accounts(t *testing.T) {
 }
 
 func Test_RPC_GetProof(t *testing.T) {
+	address := types.HexToAddress("0xc94770007dda54cF92009BFF0dE90c06F603a09f")
+	keys := []types.Hash{types.HexToHash("0x01")}
+	blockNumber := types.StringToBlockNumber("0x10")
+	proof := newAny(`{"address":"0xc94770007dda54cf92009bff0de90c06f603a09f","balance":"0x1","storageProof":[]}`)
 	t.Run("simple", func(t *testing.T) {
-		prepareHandlerTest(t, 3, "eth_getProof").
-			setOptions(WithRequirements(1, 10)).
-			expectedError("the method eth_getProof does not exist").
+		prepareHandlerTest(t, 3, "eth_getProof", address, keys, blockNumber).
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, proof, "eth_getProof", address, keys, blockNumber).
+			mockClientCall(1, proof, "eth_getProof", address, keys, blockNumber).
+			mockClientCall(2, errors.New("error#1"), "eth_getProof", address, keys, blockNumber).
+			expectedResult(proof).
+			test()
+	})
+	t.Run("latest-block", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "eth_getProof", address, keys, types.StringToBlockNumber("latest")).
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, blockNumber, "eth_blockNumber").
+			mockClientCall(1, blockNumber, "eth_blockNumber").
+			mockClientCall(0, proof, "eth_getProof", address, keys, blockNumber).
+			mockClientCall(1, proof, "eth_getProof", address, keys, blockNumber).
+			expectedResult(proof).
+			test()
+	})
+}
+
+func Test_RPC_GetBlockReceipts(t *testing.T) {
+	blockNumber := types.StringToBlockNumber("0x10")
+	receipts := []json.RawMessage{transactionReceipt1Resp}
+	t.Run("simple", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "eth_getBlockReceipts", blockNumber).
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, receipts, "eth_getBlockReceipts", blockNumber).
+			mockClientCall(1, receipts, "eth_getBlockReceipts", blockNumber).
+			mockClientCall(2, errors.New("error#1"), "eth_getBlockReceipts", blockNumber).
+			expectedResult(receipts).
+			test()
+	})
+	t.Run("two-failed", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "eth_getBlockReceipts", blockNumber).
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, receipts, "eth_getBlockReceipts", blockNumber).
+			mockClientCall(1, errors.New("error#1"), "eth_getBlockReceipts", blockNumber).
+			mockClientCall(2, errors.New("error#2"), "eth_getBlockReceipts", blockNumber).
+			expectedError("error#1").
+			expectedError("error#2").
 			test()
 	})
 }
@@ -1330,6 +1371,73 @@ func Test_RPC_ChainId(t *testing.T) {
 	})
 }
 
+func Test_RPC_GetUncleCountByBlockHash(t *testing.T) {
+	blockHash := types.HexToHash("0xc0f4906fea23cf6f3cce98cb44e8e1449e455b28d684dfa9ff65426495584de6")
+	t.Run("simple", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "eth_getUncleCountByBlockHash", blockHash).
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, `0x1`, "eth_getUncleCountByBlockHash", blockHash).
+			mockClientCall(1, `0x1`, "eth_getUncleCountByBlockHash", blockHash).
+			mockClientCall(2, `0x2`, "eth_getUncleCountByBlockHash", blockHash).
+			expectedResult(`0x1`).
+			test()
+	})
+}
+
+func Test_RPC_GetUncleCountByBlockNumber(t *testing.T) {
+	blockNumber := types.StringToBlockNumber("0x10")
+	t.Run("simple", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "eth_getUncleCountByBlockNumber", blockNumber).
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, `0x1`, "eth_getUncleCountByBlockNumber", blockNumber).
+			mockClientCall(1, `0x1`, "eth_getUncleCountByBlockNumber", blockNumber).
+			mockClientCall(2, `0x2`, "eth_getUncleCountByBlockNumber", blockNumber).
+			expectedResult(`0x1`).
+			test()
+	})
+	t.Run("latest-block", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "eth_getUncleCountByBlockNumber", types.StringToBlockNumber("latest")).
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, blockNumber, "eth_blockNumber").
+			mockClientCall(1, blockNumber, "eth_blockNumber").
+			mockClientCall(0, `0x1`, "eth_getUncleCountByBlockNumber", blockNumber).
+			mockClientCall(1, `0x1`, "eth_getUncleCountByBlockNumber", blockNumber).
+			expectedResult(`0x1`).
+			test()
+	})
+}
+
+func Test_RPC_Syncing(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "eth_syncing").
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, false, "eth_syncing").
+			mockClientCall(1, false, "eth_syncing").
+			mockClientCall(2, errors.New("error#1"), "eth_syncing").
+			expectedResult(false).
+			test()
+	})
+}
+
+func Test_RPC_ClientVersion(t *testing.T) {
+	t.Run("first-success", func(t *testing.T) {
+		prepareHandlerTest(t, 3, "web3_clientVersion").
+			setOptions(WithRequirements(2, 10)).
+			mockClientCall(0, errors.New("error#1"), "web3_clientVersion").
+			mockClientCall(1, "Geth/v1.10.19", "web3_clientVersion").
+			expectedResult("Geth/v1.10.19").
+			test()
+	})
+	t.Run("consensus", func(t *testing.T) {
+		prepareHandlerTest(t, 2, "web3_clientVersion").
+			setOptions(WithRequirements(2, 10), WithMethodPolicy("web3_clientVersion", Policy{Type: ConsensusPolicy})).
+			mockClientCall(0, "Geth/v1.10.19", "web3_clientVersion").
+			mockClientCall(1, "Erigon/2.20.0", "web3_clientVersion").
+			expectedError("").
+			test()
+	})
+}
+
 func Test_RPC_Version(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
 		prepareHandlerTest(t, 3, "net_version").
@@ -0,0 +1,352 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+// subscriptionHistory is the number of recent notifications remembered for
+// deduplication.
+const subscriptionHistory = 1024
+
+var errNotEnoughSubscribers = errors.New("not enough RPC servers support subscriptions")
+
+// subscriber is an endpoint that supports the "eth_subscribe" method.
+type subscriber interface {
+	ethSubscribe(ctx context.Context, ch chan<- json.RawMessage, args ...any) (subscription, error)
+}
+
+// subscription is an upstream subscription, it is implemented by the
+// gethRPC.ClientSubscription type.
+type subscription interface {
+	Unsubscribe()
+	Err() <-chan error
+}
+
+// clientSubscriber implements the subscriber interface for the geth RPC
+// client.
+type clientSubscriber struct {
+	client *gethRPC.Client
+}
+
+// ethSubscribe implements the subscriber interface.
+func (c clientSubscriber) ethSubscribe(ctx context.Context, ch chan<- json.RawMessage, args ...any) (subscription, error) {
+	sub, err := c.client.EthSubscribe(ctx, ch, args...)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// newHead is a block header sent in the "newHeads" notifications. It contains
+// only the consensus fields, because some nodes add extra fields to headers.
+type newHead struct {
+	Number           types.Number  `json:"number"`
+	Hash             types.Hash    `json:"hash"`
+	ParentHash       types.Hash    `json:"parentHash"`
+	Nonce            types.Nonce   `json:"nonce"`
+	Sha3Uncles       types.Hash    `json:"sha3Uncles"`
+	LogsBloom        types.Bloom   `json:"logsBloom"`
+	TransactionsRoot types.Hash    `json:"transactionsRoot"`
+	StateRoot        types.Hash    `json:"stateRoot"`
+	ReceiptsRoot     types.Hash    `json:"receiptsRoot"`
+	Miner            types.Address `json:"miner"`
+	MixHash          types.Hash    `json:"mixHash"`
+	Difficulty       types.Number  `json:"difficulty"`
+	ExtraData        types.Bytes   `json:"extraData"`
+	GasLimit         types.Number  `json:"gasLimit"`
+	GasUsed          types.Number  `json:"gasUsed"`
+	Timestamp        types.Number  `json:"timestamp"`
+	BaseFeePerGas    *types.Number `json:"baseFeePerGas,omitempty"`
+}
+
+// NewHeads implements the "eth_subscribe" call for the "newHeads"
+// subscription.
+//
+// The header is sent once the same header is received from at least as many
+// endpoints as specified in the WithSubscriptionRequirements option.
+func (r *rpcETHAPI) NewHeads(ctx context.Context) (*gethRPC.Subscription, error) {
+	return r.handler.subscribe(ctx, canonicalize[newHead], "newHeads")
+}
+
+// Logs implements the "eth_subscribe" call for the "logs" subscription.
+//
+// The log is sent once the same log is received from at least as many
+// endpoints as specified in the WithSubscriptionRequirements option. Logs
+// removed due to a chain reorganization are sent again with the "removed"
+// field set to true.
+func (r *rpcETHAPI) Logs(ctx context.Context, filter types.FilterLogsQuery) (*gethRPC.Subscription, error) {
+	return r.handler.subscribe(ctx, canonicalize[types.Log], "logs", filter)
+}
+
+// notification is a canonicalized notification received from an endpoint.
+type notification struct {
+	endpoint string
+	data     json.RawMessage
+}
+
+// subscribe subscribes to all endpoints that support subscriptions and
+// merges the received notifications into a single subscription. Because
+// notifications are canonicalized, notifications with the same block or log
+// hash but with different contents are treated as different notifications.
+//
+// Failed upstream subscriptions are restored in the background. While there
+// are fewer upstream subscriptions than required, notifications cannot reach
+// the quorum and are not sent.
+func (s *server) subscribe(
+	ctx context.Context,
+	canonicalize func(json.RawMessage) (json.RawMessage, error),
+	args ...any,
+) (*gethRPC.Subscription, error) {
+	notifier, supported := gethRPC.NotifierFromContext(ctx)
+	if !supported {
+		return nil, gethRPC.ErrNotificationsUnsupported
+	}
+	if len(s.subscribers) < s.minSubscriptionResponses {
+		return nil, errNotEnoughSubscribers
+	}
+
+	// Subscribe to all endpoints.
+	args = removeTrailingNilArgs(args)
+	upstream := make(map[string]*upstreamSubscription, len(s.subscribers))
+	active := int32(0)
+	for n, sub := range s.subscribers {
+		us := &upstreamSubscription{name: n, subscriber: sub, args: args, active: &active}
+		if err := us.subscribe(ctx); err != nil {
+			s.log.
+				WithField("name", n).
+				WithField("args", args).
+				WithError(err).
+				Error("Subscribe error")
+		}
+		upstream[n] = us
+	}
+	if int(active) < s.minSubscriptionResponses {
+		for _, us := range upstream {
+			us.unsubscribe()
+		}
+		return nil, errNotEnoughSubscribers
+	}
+	subCtx, subCtxCancel := context.WithCancel(context.Background())
+	ch := make(chan notification)
+	for _, us := range upstream {
+		go s.forwardNotifications(subCtx, us, ch, canonicalize)
+	}
+
+	// Merge notifications.
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		defer subCtxCancel()
+		d := newDeduplicator(s.minSubscriptionResponses, subscriptionHistory)
+		for {
+			select {
+			case <-rpcSub.Err():
+				return
+			case n := <-ch:
+				if !d.add(n.endpoint, n.data) {
+					continue
+				}
+				if err := notifier.Notify(rpcSub.ID, n.data); err != nil {
+					s.log.WithError(err).Error("Notify error")
+					return
+				}
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// upstreamSubscription is a subscription to a single endpoint.
+type upstreamSubscription struct {
+	name       string
+	subscriber subscriber
+	args       []any
+	sub        subscription         // sub is nil if the subscription is not active
+	msgs       chan json.RawMessage // msgs receives notifications of the active subscription
+	active     *int32               // active counts active subscriptions of all endpoints, must be accessed atomically
+}
+
+// subscribe subscribes to the endpoint.
+func (u *upstreamSubscription) subscribe(ctx context.Context) error {
+	msgs := make(chan json.RawMessage)
+	sub, err := u.subscriber.ethSubscribe(ctx, msgs, u.args...)
+	if err != nil {
+		return err
+	}
+	u.sub = sub
+	u.msgs = msgs
+	atomic.AddInt32(u.active, 1)
+	return nil
+}
+
+// unsubscribe cancels the subscription if it is active. It returns the
+// number of remaining active subscriptions of all endpoints.
+func (u *upstreamSubscription) unsubscribe() int {
+	if u.sub == nil {
+		return int(atomic.LoadInt32(u.active))
+	}
+	u.sub.Unsubscribe()
+	u.sub = nil
+	u.msgs = nil
+	return int(atomic.AddInt32(u.active, -1))
+}
+
+// forwardNotifications canonicalizes notifications received from an
+// upstream subscription and sends them to the ch channel until the context
+// is canceled. If the upstream subscription fails, it is restored after the
+// resubscribe interval.
+func (s *server) forwardNotifications(
+	ctx context.Context,
+	us *upstreamSubscription,
+	ch chan<- notification,
+	canonicalize func(json.RawMessage) (json.RawMessage, error),
+) {
+	defer us.unsubscribe()
+	for {
+		if us.sub == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.resubscribeInterval):
+			}
+			subCtx, subCtxCancel := context.WithTimeout(ctx, s.totalTimeout)
+			err := us.subscribe(subCtx)
+			subCtxCancel()
+			if err != nil {
+				s.log.
+					WithField("name", us.name).
+					WithError(err).
+					Error("Resubscribe error")
+				continue
+			}
+			s.log.
+				WithField("name", us.name).
+				Info("Subscription restored")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-us.sub.Err():
+			if err != nil {
+				s.log.
+					WithField("name", us.name).
+					WithError(err).
+					Error("Subscription error")
+			}
+			if active := us.unsubscribe(); active < s.minSubscriptionResponses {
+				s.log.
+					WithField("active", active).
+					WithField("required", s.minSubscriptionResponses).
+					Warn("Not enough upstream subscriptions, notifications are paused until subscriptions are restored")
+			}
+		case msg := <-us.msgs:
+			data, err := canonicalize(msg)
+			if err != nil {
+				s.log.
+					WithField("name", us.name).
+					WithError(err).
+					Error("Invalid notification")
+				continue
+			}
+			select {
+			case ch <- notification{endpoint: us.name, data: data}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// canonicalize decodes a notification into the T type and encodes it again
+// to remove unknown fields and normalize the formatting.
+func canonicalize[T any](msg json.RawMessage) (json.RawMessage, error) {
+	var v T
+	if err := json.Unmarshal(msg, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// deduplicator counts how many endpoints sent the same notification.
+type deduplicator struct {
+	minResponses int
+	history      int
+	seen         map[string]*seenNotification
+	order        []string // order is used to forget the oldest notifications
+}
+
+type seenNotification struct {
+	endpoints map[string]struct{}
+	sent      bool
+}
+
+func newDeduplicator(minResponses, history int) *deduplicator {
+	return &deduplicator{
+		minResponses: minResponses,
+		history:      history,
+		seen:         map[string]*seenNotification{},
+	}
+}
+
+// add records a notification received from the endpoint. It returns true
+// only once for every notification, when it has been received from enough
+// endpoints.
+func (d *deduplicator) add(endpoint string, data []byte) bool {
+	key := string(data)
+	n, ok := d.seen[key]
+	if !ok {
+		n = &seenNotification{endpoints: map[string]struct{}{}}
+		d.seen[key] = n
+		d.order = append(d.order, key)
+		if len(d.order) > d.history {
+			delete(d.seen, d.order[0])
+			d.order = d.order[1:]
+		}
+	}
+	if n.sent {
+		return false
+	}
+	n.endpoints[endpoint] = struct{}{}
+	if len(n.endpoints) < d.minResponses {
+		return false
+	}
+	n.sent = true
+	return true
+}
+
+// isWebsocket returns true if the request is a WebSocket upgrade request.
+func isWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+// isWebsocketURL returns true if the endpoint URL uses the WebSocket
+// protocol.
+func isWebsocketURL(endpoint string) bool {
+	e := strings.ToLower(endpoint)
+	return strings.HasPrefix(e, "ws://") || strings.HasPrefix(e, "wss://")
+}
@@ -0,0 +1,256 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsplitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSubscriber struct {
+	args []any
+	subs chan chan<- json.RawMessage
+	sub  *mockSubscription
+}
+
+type mockSubscription struct {
+	err          chan error
+	unsubscribed int32
+}
+
+func newMockSubscriber() *mockSubscriber {
+	return &mockSubscriber{
+		subs: make(chan chan<- json.RawMessage, 1),
+		sub:  &mockSubscription{err: make(chan error)},
+	}
+}
+
+func (m *mockSubscriber) ethSubscribe(_ context.Context, ch chan<- json.RawMessage, args ...any) (subscription, error) {
+	m.args = args
+	m.subs <- ch
+	return m.sub, nil
+}
+
+func (m *mockSubscription) Unsubscribe() {
+	atomic.StoreInt32(&m.unsubscribed, 1)
+}
+
+func (m *mockSubscription) Err() <-chan error {
+	return m.err
+}
+
+func Test_deduplicator(t *testing.T) {
+	tests := []struct {
+		minResponses int
+		history      int
+		notes        [][2]string // endpoint, data
+		want         []bool
+	}{
+		{
+			minResponses: 1,
+			history:      10,
+			notes:        [][2]string{{"a", "x"}, {"b", "x"}, {"a", "y"}},
+			want:         []bool{true, false, true},
+		},
+		{
+			minResponses: 2,
+			history:      10,
+			notes:        [][2]string{{"a", "x"}, {"a", "x"}, {"b", "y"}, {"b", "x"}, {"c", "x"}},
+			want:         []bool{false, false, false, true, false},
+		},
+		{
+			// The oldest notification is forgotten.
+			minResponses: 1,
+			history:      2,
+			notes:        [][2]string{{"a", "x"}, {"a", "y"}, {"a", "z"}, {"a", "x"}},
+			want:         []bool{true, true, true, true},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			d := newDeduplicator(tt.minResponses, tt.history)
+			for i, n := range tt.notes {
+				assert.Equal(t, tt.want[i], d.add(n[0], []byte(n[1])), "notification %d", i)
+			}
+		})
+	}
+}
+
+func Test_RPC_Subscribe(t *testing.T) {
+	const headA = `{"number":"0x1","hash":"0x0000000000000000000000000000000000000000000000000000000000000001","gasLimit":"0x1"}`
+	const headB = `{"number":"0x2","hash":"0x0000000000000000000000000000000000000000000000000000000000000002","gasLimit":"0x1"}`
+
+	subs := map[string]*mockSubscriber{"0": newMockSubscriber(), "1": newMockSubscriber(), "2": newMockSubscriber()}
+	h, err := NewServer(
+		withSubscribers(map[string]subscriber{"0": subs["0"], "1": subs["1"], "2": subs["2"]}),
+		WithRequirements(2, 10),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxCancel()
+	client, err := gethRPC.DialContext(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ch := make(chan json.RawMessage, 10)
+	sub, err := client.EthSubscribe(ctx, ch, "newHeads")
+	require.NoError(t, err)
+
+	upstream := map[string]chan<- json.RawMessage{}
+	for n, s := range subs {
+		upstream[n] = <-s.subs
+		assert.Equal(t, []any{"newHeads"}, s.args)
+	}
+
+	// The same head from two endpoints, with an extra field that must be
+	// ignored, then the third one that must not be sent again.
+	upstream["0"] <- json.RawMessage(headA)
+	upstream["1"] <- json.RawMessage(strings.Replace(headA, "{", `{"totalDifficulty":"0x1",`, 1))
+	upstream["2"] <- json.RawMessage(headA)
+
+	// Different heads from two endpoints must not be sent.
+	upstream["0"] <- json.RawMessage(headB)
+	upstream["1"] <- json.RawMessage(strings.Replace(headB, `"gasLimit":"0x1"`, `"gasLimit":"0x2"`, 1))
+	upstream["2"] <- json.RawMessage(headB)
+
+	var heads []newHead
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-ch:
+			var head newHead
+			require.NoError(t, json.Unmarshal(msg, &head))
+			heads = append(heads, head)
+		case <-ctx.Done():
+			require.Fail(t, "timeout")
+		}
+	}
+	assert.Equal(t, uint64(1), heads[0].Number.Big().Uint64())
+	assert.Equal(t, uint64(2), heads[1].Number.Big().Uint64())
+	assert.Equal(t, uint64(1), heads[1].GasLimit.Big().Uint64())
+	select {
+	case msg := <-ch:
+		assert.Fail(t, "unexpected notification", string(msg))
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Unsubscribing must cancel all upstream subscriptions.
+	sub.Unsubscribe()
+	assert.Eventually(t, func() bool {
+		for _, s := range subs {
+			if atomic.LoadInt32(&s.sub.unsubscribed) == 0 {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_RPC_Subscribe_Resubscribe(t *testing.T) {
+	const head = `{"number":"0x1","hash":"0x0000000000000000000000000000000000000000000000000000000000000001","gasLimit":"0x1"}`
+
+	subs := map[string]*mockSubscriber{"0": newMockSubscriber(), "1": newMockSubscriber()}
+	h, err := NewServer(
+		withSubscribers(map[string]subscriber{"0": subs["0"], "1": subs["1"]}),
+		WithRequirements(2, 10),
+		withResubscribeInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxCancel()
+	client, err := gethRPC.DialContext(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ch := make(chan json.RawMessage, 10)
+	_, err = client.EthSubscribe(ctx, ch, "newHeads")
+	require.NoError(t, err)
+	<-subs["0"].subs
+	upstream := <-subs["1"].subs
+
+	// The first upstream subscription fails, so the quorum cannot be
+	// reached until it is restored.
+	subs["0"].sub.err <- errors.New("connection lost")
+	upstream <- json.RawMessage(head)
+	select {
+	case msg := <-ch:
+		assert.Fail(t, "unexpected notification", string(msg))
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// After resubscribing, the notification reaches the quorum.
+	select {
+	case restored := <-subs["0"].subs:
+		restored <- json.RawMessage(head)
+	case <-ctx.Done():
+		require.Fail(t, "timeout")
+	}
+	select {
+	case msg := <-ch:
+		var h newHead
+		require.NoError(t, json.Unmarshal(msg, &h))
+		assert.Equal(t, uint64(1), h.Number.Big().Uint64())
+	case <-ctx.Done():
+		require.Fail(t, "timeout")
+	}
+}
+
+func Test_RPC_Subscribe_Errors(t *testing.T) {
+	t.Run("not-enough-subscribers", func(t *testing.T) {
+		h, err := NewServer(
+			withSubscribers(map[string]subscriber{"0": newMockSubscriber()}),
+			WithRequirements(2, 10),
+		)
+		require.NoError(t, err)
+		srv := httptest.NewServer(h)
+		defer srv.Close()
+
+		client, err := gethRPC.Dial("ws" + strings.TrimPrefix(srv.URL, "http"))
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.EthSubscribe(context.Background(), make(chan json.RawMessage), "newHeads")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), errNotEnoughSubscribers.Error())
+	})
+	t.Run("http", func(t *testing.T) {
+		h, err := NewServer(
+			withSubscribers(map[string]subscriber{"0": newMockSubscriber()}),
+			WithRequirements(1, 10),
+		)
+		require.NoError(t, err)
+
+		res := &rpcRes{}
+		jsonUnmarshal(t, serveRPC(t, h, "eth_subscribe", "newHeads"), res)
+		assert.Contains(t, res.Error.Message, "notifications not supported")
+	})
+}
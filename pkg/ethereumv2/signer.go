@@ -0,0 +1,32 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ethereumv2
+
+import (
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+// Signer signs messages and transactions.
+type Signer interface {
+	// Address returns the address of the account used to sign data.
+	Address() types.Address
+	// SignMessage signs the given data as an Ethereum signed message
+	// (EIP-191). The V value of the returned signature is 27 or 28.
+	SignMessage(data []byte) (*types.Signature, error)
+	// SignTransaction signs the given transaction and sets its Signature
+	// field. The ChainID field must be set before signing.
+	SignTransaction(tx *types.DynamicFeeTransaction) error
+}
@@ -0,0 +1,104 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ethereumv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+// maxFeeMultiplier is the multiplier of the base fee used to calculate the
+// default maximum fee per gas. It allows the transaction to stay valid even
+// if the base fee increases in the next few blocks.
+const maxFeeMultiplier = 2
+
+// PrepareTransaction fills the missing fields of the given transaction using
+// the data fetched from the client. The transaction is prepared to be sent
+// from the given address.
+//
+// Fields are filled only if they are empty. A nonce is empty only if it is
+// nil, so the zero nonce can be set explicitly:
+//   - ChainID is set to the value returned by eth_chainId.
+//   - Nonce is set to the pending transaction count of the from address.
+//   - MaxPriorityFeePerGas is set to the value returned by
+//     eth_maxPriorityFeePerGas.
+//   - MaxFeePerGas is set to double the base fee of the next block plus
+//     the priority fee.
+//   - GasLimit is set to the value returned by eth_estimateGas.
+func PrepareTransaction(ctx context.Context, cli Client, from types.Address, tx *types.DynamicFeeTransaction) error {
+	var err error
+	if tx.ChainID == 0 {
+		if tx.ChainID, err = cli.ChainID(ctx); err != nil {
+			return fmt.Errorf("unable to get chain ID: %w", err)
+		}
+	}
+	if tx.Nonce == nil {
+		nonce, err := cli.GetTransactionCount(ctx, from, types.StringToBlockNumber("pending"))
+		if err != nil {
+			return fmt.Errorf("unable to get nonce: %w", err)
+		}
+		tx.Nonce = &nonce
+	}
+	if tx.MaxPriorityFeePerGas == nil {
+		if tx.MaxPriorityFeePerGas, err = cli.MaxPriorityFeePerGas(ctx); err != nil {
+			return fmt.Errorf("unable to get priority fee: %w", err)
+		}
+	}
+	if tx.MaxFeePerGas == nil {
+		var history *types.FeeHistory
+		history, err = cli.FeeHistory(ctx, 1, types.StringToBlockNumber("latest"), nil)
+		if err != nil {
+			return fmt.Errorf("unable to get fee history: %w", err)
+		}
+		if len(history.BaseFeePerGas) == 0 {
+			return errors.New("unable to get base fee: empty fee history")
+		}
+		// The last element of the base fee list is the base fee of the
+		// next block.
+		baseFee := history.BaseFeePerGas[len(history.BaseFeePerGas)-1]
+		tx.MaxFeePerGas = new(big.Int).Add(
+			new(big.Int).Mul(baseFee.Big(), big.NewInt(maxFeeMultiplier)),
+			tx.MaxPriorityFeePerGas,
+		)
+	}
+	if tx.GasLimit == 0 {
+		if tx.GasLimit, err = cli.EstimateGas(ctx, tx.Call(from), types.StringToBlockNumber("pending")); err != nil {
+			return fmt.Errorf("unable to estimate gas: %w", err)
+		}
+	}
+	return nil
+}
+
+// SendTransaction prepares the given transaction using PrepareTransaction,
+// signs it using the signer and sends it to the network. It returns the
+// transaction hash.
+func SendTransaction(ctx context.Context, cli Client, signer Signer, tx *types.DynamicFeeTransaction) (*types.Hash, error) {
+	if err := PrepareTransaction(ctx, cli, signer.Address(), tx); err != nil {
+		return nil, err
+	}
+	if err := signer.SignTransaction(tx); err != nil {
+		return nil, fmt.Errorf("unable to sign transaction: %w", err)
+	}
+	raw, err := tx.Raw()
+	if err != nil {
+		return nil, err
+	}
+	return cli.SendRawTransaction(ctx, raw)
+}
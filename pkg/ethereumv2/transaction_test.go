@@ -0,0 +1,108 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ethereumv2
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/keysigner"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcclient/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+func TestPrepareTransaction(t *testing.T) {
+	ctx := context.Background()
+	cli := &mocks.Client{}
+	from := types.HexToAddress("0x1")
+	to := types.HexToAddress("0x2")
+	tx := &types.DynamicFeeTransaction{To: &to, Data: []byte{0x1}}
+
+	cli.On("ChainID", ctx).Return(uint64(5), nil)
+	cli.On("GetTransactionCount", ctx, from, types.StringToBlockNumber("pending")).Return(uint64(10), nil)
+	cli.On("MaxPriorityFeePerGas", ctx).Return(big.NewInt(2), nil)
+	cli.On("FeeHistory", ctx, uint64(1), types.StringToBlockNumber("latest"), []float64(nil)).Return(&types.FeeHistory{
+		BaseFeePerGas: []types.Number{types.Uint64ToNumber(100), types.Uint64ToNumber(110)},
+	}, nil)
+	cli.On("EstimateGas", ctx, mock.Anything, types.StringToBlockNumber("pending")).Return(uint64(21000), nil)
+
+	require.NoError(t, PrepareTransaction(ctx, cli, from, tx))
+	assert.Equal(t, uint64(5), tx.ChainID)
+	require.NotNil(t, tx.Nonce)
+	assert.Equal(t, uint64(10), *tx.Nonce)
+	assert.Equal(t, big.NewInt(2), tx.MaxPriorityFeePerGas)
+	assert.Equal(t, big.NewInt(222), tx.MaxFeePerGas)
+	assert.Equal(t, uint64(21000), tx.GasLimit)
+
+	// Gas estimation must use the filled fees:
+	call := cli.Calls[len(cli.Calls)-1].Arguments.Get(1).(types.Call)
+	assert.Equal(t, &from, call.From)
+	assert.Equal(t, "0xde", call.MaxFeePerGas.String())
+}
+
+func TestPrepareTransaction_Filled(t *testing.T) {
+	ctx := context.Background()
+	cli := &mocks.Client{}
+	to := types.HexToAddress("0x2")
+	nonce := uint64(0)
+	tx := &types.DynamicFeeTransaction{
+		ChainID:              1,
+		Nonce:                &nonce,
+		MaxPriorityFeePerGas: big.NewInt(1),
+		MaxFeePerGas:         big.NewInt(2),
+		GasLimit:             3,
+		To:                   &to,
+	}
+
+	// No RPC calls are expected, also for the explicitly set zero nonce.
+	require.NoError(t, PrepareTransaction(ctx, cli, types.HexToAddress("0x1"), tx))
+	cli.AssertExpectations(t)
+	assert.Equal(t, uint64(0), *tx.Nonce)
+}
+
+func TestSendTransaction(t *testing.T) {
+	ctx := context.Background()
+	cli := &mocks.Client{}
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := keysigner.New(key)
+	to := types.HexToAddress("0x2")
+	nonce := uint64(1)
+	tx := &types.DynamicFeeTransaction{
+		ChainID:              1,
+		Nonce:                &nonce,
+		MaxPriorityFeePerGas: big.NewInt(1),
+		MaxFeePerGas:         big.NewInt(2),
+		GasLimit:             21000,
+		To:                   &to,
+	}
+	txHash := types.HexToHash("0x3")
+
+	cli.On("SendRawTransaction", ctx, mock.Anything).Return(&txHash, nil)
+
+	hash, err := SendTransaction(ctx, cli, signer, tx)
+	require.NoError(t, err)
+	assert.Equal(t, txHash, *hash)
+	raw, err := tx.Raw()
+	require.NoError(t, err)
+	assert.Equal(t, raw, cli.Calls[0].Arguments.Get(1))
+}
@@ -0,0 +1,58 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+// Call represents a message call that is executed by the eth_call and
+// eth_estimateGas RPC methods.
+type Call struct {
+	From                 *Address `json:"from,omitempty"`
+	To                   *Address `json:"to,omitempty"`
+	GasLimit             *Number  `json:"gas,omitempty"`
+	GasPrice             *Number  `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *Number  `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *Number  `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *Number  `json:"value,omitempty"`
+	Data                 Bytes    `json:"data,omitempty"`
+}
+
+// StateOverride represents the state override set used by the eth_call
+// RPC method. It maps account addresses to the overridden account state.
+type StateOverride map[Address]AccountOverride
+
+// AccountOverride represents the overridden state of a single account.
+//
+// The State field replaces the whole account storage, while the StateDiff
+// field overrides only the given storage slots. Only one of them may be
+// specified.
+type AccountOverride struct {
+	Nonce     *Number       `json:"nonce,omitempty"`
+	Code      Bytes         `json:"code,omitempty"`
+	Balance   *Number       `json:"balance,omitempty"`
+	State     map[Hash]Hash `json:"state,omitempty"`
+	StateDiff map[Hash]Hash `json:"stateDiff,omitempty"`
+}
+
+// BlockOverride represents the block header fields that may be overridden
+// during the eth_call RPC method.
+type BlockOverride struct {
+	Number     *Number  `json:"number,omitempty"`
+	Difficulty *Number  `json:"difficulty,omitempty"`
+	Time       *Number  `json:"time,omitempty"`
+	GasLimit   *Number  `json:"gasLimit,omitempty"`
+	Coinbase   *Address `json:"coinbase,omitempty"`
+	Random     *Hash    `json:"random,omitempty"`
+	BaseFee    *Number  `json:"baseFee,omitempty"`
+}
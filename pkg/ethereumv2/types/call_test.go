@@ -0,0 +1,50 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Call_Marshal(t *testing.T) {
+	to := HexToAddress("0x00112233445566778899aabbccddeeff00112233")
+	j, err := json.Marshal(Call{To: &to, Data: HexToBytes("0x01")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"to":"0x00112233445566778899aabbccddeeff00112233","data":"0x01"}`, string(j))
+}
+
+func Test_StateOverride_Marshal(t *testing.T) {
+	balance := Uint64ToNumber(1)
+	j, err := json.Marshal(StateOverride{
+		HexToAddress("0x00112233445566778899aabbccddeeff00112233"): {
+			Balance:   &balance,
+			StateDiff: map[Hash]Hash{HexToHash("0x1"): HexToHash("0x2")},
+		},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"0x00112233445566778899aabbccddeeff00112233": {
+			"balance": "0x1",
+			"stateDiff": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+			}
+		}
+	}`, string(j))
+}
@@ -0,0 +1,89 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+)
+
+const SignatureLength = 65
+
+// Signature represents the 65 byte signature in the [R || S || V] format.
+type Signature [SignatureLength]byte
+
+// BytesToSignature returns a Signature from a byte slice.
+func BytesToSignature(bts []byte) Signature {
+	var s Signature
+	copy(s[:], bts)
+	return s
+}
+
+// VRSToSignature returns a Signature from the V, R and S values.
+func VRSToSignature(v uint8, r, s *big.Int) Signature {
+	var sig Signature
+	r.FillBytes(sig[0:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = v
+	return sig
+}
+
+// V returns the V value of the signature.
+func (t *Signature) V() uint8 {
+	return t[64]
+}
+
+// R returns the R value of the signature.
+func (t *Signature) R() *big.Int {
+	return new(big.Int).SetBytes(t[0:32])
+}
+
+// S returns the S value of the signature.
+func (t *Signature) S() *big.Int {
+	return new(big.Int).SetBytes(t[32:64])
+}
+
+// Bytes returns the byte representation of the signature.
+func (t *Signature) Bytes() []byte {
+	return t[:]
+}
+
+// String returns the hex string representation of the signature.
+func (t *Signature) String() string {
+	if t == nil {
+		return ""
+	}
+	return string(bytesToHex(t[:]))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Signature) MarshalJSON() ([]byte, error) {
+	return bytesMarshalJSON(t[:]), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Signature) UnmarshalJSON(input []byte) error {
+	return fixedBytesUnmarshalJSON(input, t[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t Signature) MarshalText() ([]byte, error) {
+	return bytesMarshalText(t[:]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *Signature) UnmarshalText(input []byte) error {
+	return fixedBytesUnmarshalText(input, t[:])
+}
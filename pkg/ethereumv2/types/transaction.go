@@ -15,6 +15,19 @@
 
 package types
 
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DynamicFeeTxType is the EIP-2718 type of the EIP-1559 transaction.
+const DynamicFeeTxType = 0x02
+
+var ErrUnsignedTransaction = errors.New("transaction is not signed")
+
 // Transaction represents a transaction.
 type Transaction struct {
 	Hash             Hash    `json:"hash"`
@@ -48,4 +61,126 @@ type TransactionReceiptType struct {
 	LogsBloom         Bytes    `json:"logsBloom"`
 	Root              *Hash    `json:"root"`
 	Status            *Number  `json:"status"`
+	EffectiveGasPrice *Number  `json:"effectiveGasPrice,omitempty"`
+}
+
+// DynamicFeeTransaction represents an EIP-1559 transaction.
+type DynamicFeeTransaction struct {
+	// ChainID is the transaction chain ID.
+	ChainID uint64
+	// Nonce is the transaction nonce. Nil if not set.
+	Nonce *uint64
+	// MaxPriorityFeePerGas is the maximum tip paid to the block producer.
+	MaxPriorityFeePerGas *big.Int
+	// MaxFeePerGas is the maximum total fee per gas, including the base fee.
+	MaxFeePerGas *big.Int
+	// GasLimit is the maximum gas available to be used for this transaction.
+	GasLimit uint64
+	// To is the recipient address. Nil for contract creation.
+	To *Address
+	// Value is the amount of wei sent with the transaction.
+	Value *big.Int
+	// Data is the raw transaction data.
+	Data []byte
+	// Signature is the transaction signature. The V value must be the
+	// recovery ID (0 or 1). It is nil for unsigned transactions.
+	Signature *Signature
+}
+
+// Call returns a Call that can be used to simulate or estimate the gas
+// of the transaction sent from the given address.
+func (t *DynamicFeeTransaction) Call(from Address) Call {
+	c := Call{
+		From: &from,
+		To:   t.To,
+		Data: t.Data,
+	}
+	if t.MaxPriorityFeePerGas != nil {
+		c.MaxPriorityFeePerGas = bigToNumberPtr(t.MaxPriorityFeePerGas)
+	}
+	if t.MaxFeePerGas != nil {
+		c.MaxFeePerGas = bigToNumberPtr(t.MaxFeePerGas)
+	}
+	if t.GasLimit != 0 {
+		c.GasLimit = bigToNumberPtr(new(big.Int).SetUint64(t.GasLimit))
+	}
+	if t.Value != nil {
+		c.Value = bigToNumberPtr(t.Value)
+	}
+	return c
+}
+
+// SigningHash returns the hash that must be signed to produce the
+// transaction signature.
+func (t *DynamicFeeTransaction) SigningHash() (Hash, error) {
+	b, err := rlp.EncodeToBytes(t.fields())
+	if err != nil {
+		return Hash{}, err
+	}
+	return BytesToHash(crypto.Keccak256([]byte{DynamicFeeTxType}, b)), nil
+}
+
+// Raw returns the EIP-2718 encoded signed transaction that can be sent
+// using the eth_sendRawTransaction RPC method.
+func (t *DynamicFeeTransaction) Raw() (Bytes, error) {
+	if t.Signature == nil {
+		return nil, ErrUnsignedTransaction
+	}
+	b, err := rlp.EncodeToBytes(append(
+		t.fields(),
+		uint64(t.Signature.V()),
+		t.Signature.R(),
+		t.Signature.S(),
+	))
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{DynamicFeeTxType}, b...), nil
+}
+
+// Hash returns the hash of the signed transaction.
+func (t *DynamicFeeTransaction) Hash() (Hash, error) {
+	raw, err := t.Raw()
+	if err != nil {
+		return Hash{}, err
+	}
+	return BytesToHash(crypto.Keccak256(raw)), nil
+}
+
+// fields returns the RLP list of the unsigned transaction fields.
+func (t *DynamicFeeTransaction) fields() []interface{} {
+	var to []byte
+	if t.To != nil {
+		to = t.To.Bytes()
+	}
+	return []interface{}{
+		t.ChainID,
+		uint64OrZero(t.Nonce),
+		bigOrZero(t.MaxPriorityFeePerGas),
+		bigOrZero(t.MaxFeePerGas),
+		t.GasLimit,
+		to,
+		bigOrZero(t.Value),
+		t.Data,
+		[]interface{}{}, // Access list is not supported.
+	}
+}
+
+func uint64OrZero(x *uint64) uint64 {
+	if x == nil {
+		return 0
+	}
+	return *x
+}
+
+func bigOrZero(x *big.Int) *big.Int {
+	if x == nil {
+		return new(big.Int)
+	}
+	return x
+}
+
+func bigToNumberPtr(x *big.Int) *Number {
+	n := BigToNumber(x)
+	return &n
 }
@@ -0,0 +1,122 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DynamicFeeTransaction_Encoding(t *testing.T) {
+	key, err := crypto.HexToECDSA("2d800d93b065ce011af83f316cef9f8f3a4b2b1b3a0d4e28f9d08e1d4ea2f0c1")
+	require.NoError(t, err)
+	to := HexToAddress("0x00112233445566778899aabbccddeeff00112233")
+	nonces := []uint64{0, 42, 1}
+	tests := []DynamicFeeTransaction{
+		{
+			ChainID:              1,
+			Nonce:                &nonces[0],
+			MaxPriorityFeePerGas: big.NewInt(1e9),
+			MaxFeePerGas:         big.NewInt(50e9),
+			GasLimit:             21000,
+			To:                   &to,
+			Value:                big.NewInt(1e18),
+		},
+		{
+			ChainID:  5,
+			Nonce:    &nonces[1],
+			GasLimit: 100000,
+			To:       &to,
+			Data:     []byte{0xde, 0xad, 0xbe, 0xef},
+		},
+		{
+			ChainID:  1,
+			Nonce:    &nonces[2],
+			GasLimit: 1000000,
+			Data:     []byte{0x60, 0x80},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var gethTo *common.Address
+			if tt.To != nil {
+				gethTo = (*common.Address)(tt.To)
+			}
+			gethTx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{
+				ChainID:   new(big.Int).SetUint64(tt.ChainID),
+				Nonce:     uint64OrZero(tt.Nonce),
+				GasTipCap: bigOrZero(tt.MaxPriorityFeePerGas),
+				GasFeeCap: bigOrZero(tt.MaxFeePerGas),
+				Gas:       tt.GasLimit,
+				To:        gethTo,
+				Value:     bigOrZero(tt.Value),
+				Data:      tt.Data,
+			})
+			gethSigner := gethTypes.NewLondonSigner(new(big.Int).SetUint64(tt.ChainID))
+
+			// Signing hash:
+			hash, err := tt.SigningHash()
+			require.NoError(t, err)
+			assert.Equal(t, gethSigner.Hash(gethTx).Bytes(), hash.Bytes())
+
+			// Unsigned transaction:
+			_, err = tt.Raw()
+			assert.ErrorIs(t, err, ErrUnsignedTransaction)
+
+			// Signed transaction:
+			sig, err := crypto.Sign(hash.Bytes(), key)
+			require.NoError(t, err)
+			signature := BytesToSignature(sig)
+			tt.Signature = &signature
+			gethSignedTx, err := gethTx.WithSignature(gethSigner, sig)
+			require.NoError(t, err)
+			gethRaw, err := gethSignedTx.MarshalBinary()
+			require.NoError(t, err)
+			raw, err := tt.Raw()
+			require.NoError(t, err)
+			assert.Equal(t, gethRaw, raw.Bytes())
+			txHash, err := tt.Hash()
+			require.NoError(t, err)
+			assert.Equal(t, gethSignedTx.Hash().Bytes(), txHash.Bytes())
+		})
+	}
+}
+
+func Test_DynamicFeeTransaction_Call(t *testing.T) {
+	from := HexToAddress("0x1")
+	to := HexToAddress("0x2")
+	tx := DynamicFeeTransaction{
+		MaxFeePerGas: big.NewInt(10),
+		GasLimit:     21000,
+		To:           &to,
+		Data:         []byte{0x1},
+	}
+	call := tx.Call(from)
+	assert.Equal(t, &from, call.From)
+	assert.Equal(t, &to, call.To)
+	assert.Equal(t, "0xa", call.MaxFeePerGas.String())
+	assert.Equal(t, "0x5208", call.GasLimit.String())
+	assert.Nil(t, call.MaxPriorityFeePerGas)
+	assert.Nil(t, call.Value)
+	assert.Equal(t, Bytes{0x1}, call.Data)
+}
@@ -24,8 +24,10 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/store"
 	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver"
 	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver/middleware"
@@ -49,12 +51,21 @@ const defaultTimeout = 3 * time.Second
 // Both parameters must be provided as hex encoded strings.
 //
 // Events are returned in JSON format.
+//
+// The versioned API is available under the /v2 path, it is described in the
+// v2.go file. New events can also be received using the event stream
+// (stream.go) and webhooks (webhook.go).
 type EventAPI struct {
+	mu  sync.Mutex
 	ctx context.Context
 
-	srv *httpserver.HTTPServer
-	es  *store.EventStore
-	log log.Logger
+	srv      *httpserver.HTTPServer
+	es       *store.EventStore
+	signers  map[ethereum.Address]bool
+	quorum   int
+	streams  map[chan *notification]notificationFilter
+	webhooks []*webhook
+	log      log.Logger
 }
 
 // Config is the configuration for the EventAPI.
@@ -64,6 +75,14 @@ type Config struct {
 	// Address specifies the TCP address for the server to listen on in the
 	// form "host:port".
 	Address string
+	// Signers is a list of known signers, used to determine whether an event
+	// has been signed by a quorum of signers.
+	Signers []ethereum.Address
+	// Quorum is the number of known signers required to consider an event as
+	// attested. If zero, the majority of signers is required.
+	Quorum int
+	// Webhooks is a list of HTTP endpoints notified about new events.
+	Webhooks []Webhook
 	// Logger is a current logger used by the EventAPI.
 	Logger log.Logger
 }
@@ -87,19 +106,44 @@ func New(cfg Config) (*EventAPI, error) {
 	if cfg.Address == "" {
 		return nil, errors.New("address must not be empty")
 	}
+	if cfg.Quorum < 0 || cfg.Quorum > len(cfg.Signers) {
+		return nil, errors.New("quorum must be between zero and the number of signers")
+	}
+	if cfg.Quorum == 0 {
+		cfg.Quorum = len(cfg.Signers)/2 + 1
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = null.New()
 	}
 	api := &EventAPI{
-		es:  cfg.EventStore,
-		log: cfg.Logger.WithField("tag", LoggerTag),
+		es:      cfg.EventStore,
+		signers: map[ethereum.Address]bool{},
+		quorum:  cfg.Quorum,
+		streams: map[chan *notification]notificationFilter{},
+		log:     cfg.Logger.WithField("tag", LoggerTag),
 	}
+	for _, s := range cfg.Signers {
+		api.signers[s] = true
+	}
+	for _, w := range cfg.Webhooks {
+		if w.URL == "" {
+			return nil, errors.New("webhook URL must not be empty")
+		}
+		api.webhooks = append(api.webhooks, newWebhook(w, api.log))
+	}
+	// The write timeout cannot be set for the whole server, because it
+	// would close event streams. Instead, other handlers are wrapped with
+	// the timeout handler.
+	mux := http.NewServeMux()
+	mux.Handle("/", timeoutHandler(api.handler))
+	mux.Handle("/v2/", timeoutHandler(api.v2NotFoundHandler))
+	mux.Handle("/v2/events", timeoutHandler(api.v2EventsHandler))
+	mux.HandleFunc("/v2/stream", api.v2StreamHandler)
 	api.srv = httpserver.New(&http.Server{
 		Addr:              cfg.Address,
-		Handler:           http.HandlerFunc(api.handler),
+		Handler:           mux,
 		IdleTimeout:       defaultTimeout,
 		ReadTimeout:       defaultTimeout,
-		WriteTimeout:      defaultTimeout,
 		ReadHeaderTimeout: defaultTimeout,
 	})
 	api.srv.Use(&middleware.CORS{
@@ -129,6 +173,10 @@ func (e *EventAPI) Start(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("unable to start the HTTP server: %w", err)
 	}
+	go e.notifyRoutine(e.es.Subscribe(ctx))
+	for _, w := range e.webhooks {
+		w.start(ctx)
+	}
 	go e.contextCancelHandler()
 	return nil
 }
@@ -204,6 +252,10 @@ func (e *EventAPI) contextCancelHandler() {
 	<-e.ctx.Done()
 }
 
+func timeoutHandler(h http.HandlerFunc) http.Handler {
+	return http.TimeoutHandler(h, defaultTimeout, `{"error":"timeout"}`)
+}
+
 func decodeHex(h string) ([]byte, error) {
 	h = strings.TrimPrefix(h, "0x")
 	if len(h)%2 != 0 {
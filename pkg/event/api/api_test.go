@@ -16,16 +16,22 @@
 package api
 
 import (
+	"bufio"
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/event/store"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
@@ -140,3 +146,327 @@ func read(res *http.Response) string {
 	b, _ := io.ReadAll(res.Body)
 	return string(b)
 }
+
+func TestEventAPI_v2(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	loc := local.New([]byte("test"), 0, map[string]transport.Message{messages.EventV1MessageName: (*messages.Event)(nil)})
+	mem := store.NewMemoryStorage(time.Minute)
+	evs, err := store.New(store.Config{
+		EventTypes: []string{"event1", "event2"},
+		Storage:    mem,
+		Transport:  loc,
+		Logger:     null.New(),
+	})
+	require.NoError(t, err)
+	signer1 := ethereum.HexToAddress("0x1111111111111111111111111111111111111111")
+	signer2 := ethereum.HexToAddress("0x2222222222222222222222222222222222222222")
+	signer3 := ethereum.HexToAddress("0x3333333333333333333333333333333333333333")
+	unknown := ethereum.HexToAddress("0x4444444444444444444444444444444444444444")
+	api, err := New(Config{
+		EventStore: evs,
+		Address:    "127.0.0.1:0",
+		Signers:    []ethereum.Address{signer1, signer2, signer3},
+		Quorum:     2,
+		Logger:     null.New(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, loc.Start(ctx))
+	require.NoError(t, evs.Start(ctx))
+	require.NoError(t, api.Start(ctx))
+	defer func() {
+		cancelFunc()
+		require.NoError(t, <-loc.Wait())
+		require.NoError(t, <-evs.Wait())
+		require.NoError(t, <-api.Wait())
+	}()
+
+	add := func(typ, id string, date int64, signer ethereum.Address) {
+		_, err := mem.Add(ctx, signer.Bytes(), &messages.Event{
+			Type:        typ,
+			ID:          []byte(id),
+			Index:       []byte("idx"),
+			EventDate:   time.Unix(date, 0),
+			MessageDate: time.Unix(date, 0),
+			Data:        map[string][]byte{"data_key": []byte("val")},
+			Signatures:  map[string]messages.EventSignature{"sig_key": {Signer: signer.Bytes(), Signature: []byte(id)}},
+		})
+		require.NoError(t, err)
+	}
+	add("event1", "id1", 10, signer1)
+	add("event1", "id1", 10, signer2)
+	add("event1", "id2", 20, signer1)
+	add("event1", "id3", 30, signer3)
+	add("event1", "id3", 30, unknown)
+	add("event2", "id4", 15, signer1)
+
+	get := func(query string) (int, *v2EventsResponse) {
+		res, err := http.Get(fmt.Sprintf("http://%s/v2/events?%s", api.srv.Addr().String(), query))
+		require.NoError(t, err)
+		r := &v2EventsResponse{}
+		require.NoError(t, json.Unmarshal([]byte(read(res)), r))
+		return res.StatusCode, r
+	}
+	ids := func(r *v2EventsResponse) (ids []string) {
+		for _, e := range r.Events {
+			id, _ := hex.DecodeString(e.ID)
+			ids = append(ids, string(id))
+		}
+		return ids
+	}
+
+	// List all events of the given type:
+	status, res := get("type=event1")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"id1", "id2", "id3"}, ids(res))
+	assert.False(t, res.HasMore)
+	assert.Equal(t, int64(10), res.Events[0].Timestamp)
+	assert.Equal(t, map[string]string{"data_key": "76616c"}, res.Events[0].Data)
+	assert.Len(t, res.Events[0].Signatures, 2)
+	assert.Equal(t, v2Quorum{Required: 2, Signed: 2, Reached: true}, res.Events[0].Quorum)
+	assert.Equal(t, v2Quorum{Required: 2, Signed: 1, Reached: false}, res.Events[1].Quorum)
+	assert.Equal(t, v2Quorum{Required: 2, Signed: 1, Reached: false}, res.Events[2].Quorum)
+	assert.Len(t, res.Events[2].Signatures, 2)
+
+	// Pagination:
+	status, res = get("type=event1&limit=2")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"id1", "id2"}, ids(res))
+	assert.True(t, res.HasMore)
+	status, res = get("type=event1&limit=2&cursor=" + res.NextCursor)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"id3"}, ids(res))
+	assert.False(t, res.HasMore)
+	cursor := res.NextCursor
+	status, res = get("type=event1&limit=2&cursor=" + cursor)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, res.Events)
+	assert.Equal(t, cursor, res.NextCursor)
+
+	// Filters:
+	_, res = get("type=event1&from=15&to=30")
+	assert.Equal(t, []string{"id2"}, ids(res))
+	_, res = get("type=event1&attested=true")
+	assert.Equal(t, []string{"id1"}, ids(res))
+	_, res = get("type=event1&signer=" + signer3.String())
+	assert.Equal(t, []string{"id3"}, ids(res))
+	_, res = get(fmt.Sprintf("type=event2&index=%x", "idx"))
+	assert.Equal(t, []string{"id4"}, ids(res))
+
+	// Events filtered out are skipped in batches, without missing events
+	// after the first batch:
+	now := time.Now().Unix()
+	for i := 0; i < listBatchSize; i++ {
+		add("event2", fmt.Sprintf("id%d", i+5), now, unknown)
+	}
+	add("event2", "attested", now+1, signer1)
+	add("event2", "attested", now+1, signer2)
+	_, res = get(fmt.Sprintf("type=event2&attested=true&from=%d", now))
+	assert.Equal(t, []string{"attested"}, ids(res))
+
+	// Errors:
+	for _, q := range []string{"", "type=event1&limit=0", "type=event1&from=abc", "type=event1&signer=0x1", "type=event1&cursor=!"} {
+		res, err := http.Get(fmt.Sprintf("http://%s/v2/events?%s", api.srv.Addr().String(), q))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Contains(t, read(res), `"error":`)
+	}
+	res2, err := http.Post(fmt.Sprintf("http://%s/v2/events", api.srv.Addr().String()), "application/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, res2.StatusCode)
+	assert.JSONEq(t, `{"error":"method not allowed"}`, read(res2))
+	res2, err = http.Get(fmt.Sprintf("http://%s/v2/unknown", api.srv.Addr().String()))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res2.StatusCode)
+	assert.JSONEq(t, `{"error":"not found"}`, read(res2))
+}
+
+func TestEventAPI_v2Stream(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	loc := local.New([]byte("test"), 4, map[string]transport.Message{messages.EventV1MessageName: (*messages.Event)(nil)})
+	mem := store.NewMemoryStorage(time.Minute)
+	evs, err := store.New(store.Config{
+		EventTypes: []string{"event1", "event2"},
+		Storage:    mem,
+		Transport:  loc,
+		Logger:     null.New(),
+	})
+	require.NoError(t, err)
+	signer1 := ethereum.HexToAddress("0x1111111111111111111111111111111111111111")
+	signer2 := ethereum.HexToAddress("0x2222222222222222222222222222222222222222")
+	api, err := New(Config{
+		EventStore: evs,
+		Address:    "127.0.0.1:0",
+		Signers:    []ethereum.Address{signer1, signer2},
+		Quorum:     2,
+		Logger:     null.New(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, loc.Start(ctx))
+	require.NoError(t, evs.Start(ctx))
+	require.NoError(t, api.Start(ctx))
+	defer func() {
+		cancelFunc()
+		require.NoError(t, <-loc.Wait())
+		require.NoError(t, <-evs.Wait())
+		require.NoError(t, <-api.Wait())
+	}()
+
+	all, err := http.Get(fmt.Sprintf("http://%s/v2/stream", api.srv.Addr().String()))
+	require.NoError(t, err)
+	defer all.Body.Close()
+	assert.Equal(t, "text/event-stream", all.Header.Get("Content-Type"))
+	attested, err := http.Get(fmt.Sprintf("http://%s/v2/stream?type=event1&attested=true", api.srv.Addr().String()))
+	require.NoError(t, err)
+	defer attested.Body.Close()
+
+	evt := func(typ, id string, signer ethereum.Address) *messages.Event {
+		return &messages.Event{
+			Type:        typ,
+			ID:          []byte(id),
+			Index:       []byte("idx"),
+			EventDate:   time.Unix(1, 0),
+			MessageDate: time.Unix(1, 0),
+			Data:        map[string][]byte{"data_key": []byte("val")},
+			Signatures:  map[string]messages.EventSignature{"sig_key": {Signer: signer.Bytes(), Signature: []byte(id)}},
+		}
+	}
+
+	// The first signature is stored directly to simulate another author.
+	_, err = mem.Add(ctx, []byte("other"), evt("event1", "id1", signer2))
+	require.NoError(t, err)
+	require.NoError(t, loc.Broadcast(messages.EventV1MessageName, evt("event2", "id2", signer1)))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, loc.Broadcast(messages.EventV1MessageName, evt("event1", "id1", signer1)))
+
+	allEvents := readStream(t, all.Body, 2)
+	assert.Equal(t, "event2", allEvents[0].Type)
+	assert.False(t, allEvents[0].Quorum.Reached)
+	assert.Equal(t, "event1", allEvents[1].Type)
+	assert.True(t, allEvents[1].Quorum.Reached)
+	assert.Len(t, allEvents[1].Signatures, 2)
+
+	attestedEvents := readStream(t, attested.Body, 1)
+	assert.Equal(t, hex.EncodeToString([]byte("id1")), attestedEvents[0].ID)
+}
+
+func TestEventAPI_webhook(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	loc := local.New([]byte("test"), 4, map[string]transport.Message{messages.EventV1MessageName: (*messages.Event)(nil)})
+	evs, err := store.New(store.Config{
+		EventTypes: []string{"event1", "event2"},
+		Storage:    store.NewMemoryStorage(time.Minute),
+		Transport:  loc,
+		Logger:     null.New(),
+	})
+	require.NoError(t, err)
+
+	secret := []byte("secret")
+	calls := 0
+	bodies := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			res.WriteHeader(http.StatusInternalServerError) // First call fails, must be retried.
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(signBody(secret, body)), req.Header.Get(SignatureHeader))
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	signer := ethereum.HexToAddress("0x1111111111111111111111111111111111111111")
+	api, err := New(Config{
+		EventStore: evs,
+		Address:    "127.0.0.1:0",
+		Signers:    []ethereum.Address{signer},
+		Webhooks:   []Webhook{{URL: srv.URL, Secret: secret, EventTypes: []string{"event1"}, Attested: true}},
+		Logger:     null.New(),
+	})
+	require.NoError(t, err)
+	api.webhooks[0].retryDelay = 10 * time.Millisecond
+
+	require.NoError(t, loc.Start(ctx))
+	require.NoError(t, evs.Start(ctx))
+	require.NoError(t, api.Start(ctx))
+	defer func() {
+		cancelFunc()
+		require.NoError(t, <-loc.Wait())
+		require.NoError(t, <-evs.Wait())
+		require.NoError(t, <-api.Wait())
+	}()
+
+	for _, typ := range []string{"event2", "event1"} {
+		require.NoError(t, loc.Broadcast(messages.EventV1MessageName, &messages.Event{
+			Type:        typ,
+			ID:          []byte("id1"),
+			Index:       []byte("idx"),
+			EventDate:   time.Unix(1, 0),
+			MessageDate: time.Unix(1, 0),
+			Data:        map[string][]byte{"data_key": []byte("val")},
+			Signatures:  map[string]messages.EventSignature{"sig_key": {Signer: signer.Bytes(), Signature: []byte("sig")}},
+		}))
+	}
+
+	select {
+	case body := <-bodies:
+		e := &v2Event{}
+		require.NoError(t, json.Unmarshal(body, e))
+		assert.Equal(t, "event1", e.Type)
+		assert.True(t, e.Quorum.Reached)
+	case <-time.After(time.Second):
+		require.Fail(t, "webhook not called")
+	}
+	select {
+	case <-bodies:
+		require.Fail(t, "unexpected webhook call")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhook_concurrentDelivery(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	unblock := make(chan struct{})
+	delivered := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		e := &v2Event{}
+		_ = json.NewDecoder(req.Body).Decode(e)
+		if e.ID == "slow" {
+			<-unblock
+		}
+		delivered <- e.ID
+	}))
+	defer srv.Close()
+	defer close(unblock)
+
+	w := newWebhook(Webhook{URL: srv.URL}, null.New())
+	w.start(ctx)
+	w.queue <- &v2Event{ID: "slow"}
+	w.queue <- &v2Event{ID: "fast"}
+
+	// A slow delivery must not block other events:
+	select {
+	case id := <-delivered:
+		assert.Equal(t, "fast", id)
+	case <-time.After(time.Second):
+		require.Fail(t, "webhook not called")
+	}
+}
+
+func readStream(t *testing.T, r io.Reader, n int) (events []*v2Event) {
+	s := bufio.NewScanner(r)
+	for len(events) < n && s.Scan() {
+		if !strings.HasPrefix(s.Text(), "data: ") {
+			continue
+		}
+		e := &v2Event{}
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(s.Text(), "data: ")), e))
+		events = append(events, e)
+	}
+	require.Len(t, events, n)
+	return events
+}
@@ -0,0 +1,132 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+// notification is sent to streams and webhooks for every new event or
+// signature received by the event store.
+type notification struct {
+	event *v2Event
+	// attested is true if the event has just reached the quorum. It is true
+	// only once for every event.
+	attested bool
+}
+
+// notificationFilter decides which notifications are sent to a stream or
+// a webhook.
+type notificationFilter struct {
+	// types is a list of event types, if empty, all types are accepted.
+	types []string
+	// attested specifies whether to accept only notifications about events
+	// that have just reached the quorum.
+	attested bool
+}
+
+func (f notificationFilter) match(n *notification) bool {
+	if f.attested && !n.attested {
+		return false
+	}
+	if len(f.types) == 0 {
+		return true
+	}
+	for _, t := range f.types {
+		if t == n.event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyRoutine receives new events from the event store and sends
+// notifications to streams and webhooks.
+func (e *EventAPI) notifyRoutine(ch <-chan *messages.Event) {
+	for evt := range ch {
+		n, err := e.notification(evt)
+		if err != nil {
+			e.log.WithError(err).Error("Unable to prepare the event notification")
+			continue
+		}
+		e.dispatch(n)
+	}
+}
+
+// notification prepares a notification for the given event. Events that
+// reached the quorum are marked as attested in the event store, so the
+// attested notification is sent only once, even after a restart.
+func (e *EventAPI) notification(evt *messages.Event) (*notification, error) {
+	ctx, ctxCancel := context.WithTimeout(e.ctx, defaultTimeout)
+	defer ctxCancel()
+	events, err := e.es.Events(ctx, evt.Type, evt.Index)
+	if err != nil {
+		return nil, err
+	}
+	key := eventKey(evt)
+	group := &eventGroup{key: key[:], date: evt.EventDate, events: []*messages.Event{evt}}
+	for _, g := range groupEvents(events) {
+		if bytes.Equal(g.key, key[:]) {
+			group = g
+			break
+		}
+	}
+	n := &notification{event: e.mapEventGroup(group)}
+	if n.event.Quorum.Reached {
+		isNew, err := e.es.MarkAttested(ctx, evt, key[:])
+		if err != nil {
+			// It is better to send the notification twice than not at all.
+			e.log.WithError(err).Error("Unable to mark the event as attested")
+			isNew = true
+		}
+		n.attested = isNew
+	}
+	return n, nil
+}
+
+// dispatch sends the notification to all matching streams and webhooks.
+func (e *EventAPI) dispatch(n *notification) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for ch, f := range e.streams {
+		if !f.match(n) {
+			continue
+		}
+		select {
+		case ch <- n:
+		default:
+			e.log.
+				WithField("id", n.event.ID).
+				Warn("Stream client is too slow, the event was not sent to it")
+		}
+	}
+	for _, w := range e.webhooks {
+		if !w.filter.match(n) {
+			continue
+		}
+		select {
+		case w.queue <- n.event:
+		default:
+			e.log.
+				WithField("id", n.event.ID).
+				WithField("url", w.url).
+				Error("Webhook queue is full, the event was not sent to it")
+		}
+	}
+}
@@ -0,0 +1,104 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const streamBufferSize = 128
+const streamKeepAliveInterval = 15 * time.Second
+
+// v2StreamHandler is the HTTP handler for the /v2/stream endpoint.
+//
+// The endpoint sends events using Server-Sent Events. It accepts following
+// query parameters:
+//
+// type - the type of the event, may be used multiple times, optional
+// attested - if true, events are sent only once, when they are signed by a quorum
+//
+// If the type parameter is not provided, events of all types are sent. If the
+// attested parameter is not true, events are sent every time a new signature
+// is received.
+//
+// Every event is sent as a message of the "event" type, the data contains
+// the event in the same format as in the /v2/events endpoint.
+func (e *EventAPI) v2StreamHandler(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(res, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var f notificationFilter
+	f.types = req.URL.Query()["type"]
+	if s := req.URL.Query().Get("attested"); s != "" {
+		attested, err := strconv.ParseBool(s)
+		if err != nil {
+			writeError(res, http.StatusBadRequest, fmt.Sprintf("invalid attested parameter: %s", err))
+			return
+		}
+		f.attested = attested
+	}
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		writeError(res, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	ch := make(chan *notification, streamBufferSize)
+	e.mu.Lock()
+	e.streams[ch] = f
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		delete(e.streams, ch)
+		e.mu.Unlock()
+	}()
+
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	t := time.NewTicker(streamKeepAliveInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-req.Context().Done():
+			return
+		case <-t.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case n := <-ch:
+			b, err := json.Marshal(n.event)
+			if err != nil {
+				e.log.WithError(err).Error("Unable to marshal the event")
+				continue
+			}
+			if _, err := fmt.Fprintf(res, "event: event\ndata: %s\n\n", b); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
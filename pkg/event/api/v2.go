@@ -0,0 +1,440 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+// defaultLimit is the default number of events returned on a single page.
+const defaultLimit = 100
+
+// maxLimit is the maximum number of events returned on a single page.
+const maxLimit = 1000
+
+// listBatchSize is the number of messages fetched from the event store at
+// once. A single event may consist of many messages from different signers.
+const listBatchSize = 1000
+
+// The v2 API provides one GET endpoint at the /v2/events path that accepts
+// following query parameters:
+//
+// type - the type of the event, required
+// index - the hex encoded search index for the events, optional
+// from - the unix timestamp, only events with the event date equal or later
+//        are returned, optional
+// to - the unix timestamp, only events with the event date earlier are
+//      returned, optional
+// signer - the hex encoded address, only events signed by this address are
+//          returned, optional
+// attested - if true, only events signed by a quorum of known signers are
+//            returned, optional
+// limit - the maximum number of events on a page, 100 by default
+// cursor - the cursor returned in the previous response, optional
+//
+// Messages for the same event received from different signers are merged
+// into a single event. Messages are considered to be the same event if they
+// have the same ID and data. Signatures are verified by the event store when
+// events are received, if the store is configured to do so.
+//
+// Events are sorted by the event date. Every response contains the cursor
+// pointing after the last returned event, which can be used to fetch the
+// next page or to poll for new events. Because the cursor is based on the
+// event date, events that reach the quorum after the cursor passed them are
+// not returned again, so clients interested only in attested events should
+// use the "from" parameter with a margin instead of relying on the cursor.
+//
+// Errors are returned as a JSON object with the "error" field.
+
+type v2EventsResponse struct {
+	Events     []*v2Event `json:"events"`
+	NextCursor string     `json:"nextCursor"`
+	HasMore    bool       `json:"hasMore"`
+}
+
+type v2Event struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Index      string            `json:"index"`
+	Timestamp  int64             `json:"timestamp"`
+	Data       map[string]string `json:"data"`
+	Signatures []v2Signature     `json:"signatures"`
+	Quorum     v2Quorum          `json:"quorum"`
+}
+
+type v2Signature struct {
+	Type      string `json:"type"`
+	Signer    string `json:"signer"`
+	Signature string `json:"signature"`
+}
+
+type v2Quorum struct {
+	Required int  `json:"required"`
+	Signed   int  `json:"signed"`
+	Reached  bool `json:"reached"`
+}
+
+type v2Error struct {
+	Error string `json:"error"`
+}
+
+// eventGroup is a group of messages for the same event received from
+// different signers.
+type eventGroup struct {
+	key    []byte
+	date   time.Time
+	events []*messages.Event
+}
+
+// cursor points to the position after the given event group.
+type cursor struct {
+	date int64
+	key  []byte
+}
+
+// v2EventsQuery contains parsed query parameters of the /v2/events endpoint.
+type v2EventsQuery struct {
+	typ      string
+	index    []byte
+	from     time.Time
+	to       time.Time
+	signer   *ethereum.Address
+	attested bool
+	limit    int
+	cursor   *cursor
+}
+
+// v2EventsHandler is the HTTP handler for the /v2/events endpoint.
+func (e *EventAPI) v2EventsHandler(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(res, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	q, err := parseV2EventsQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	from := q.from
+	if q.cursor != nil && time.Unix(q.cursor.date, 0).After(from) {
+		from = time.Unix(q.cursor.date, 0)
+	}
+	ctx, ctxCancel := context.WithTimeout(e.ctx, defaultTimeout)
+	defer ctxCancel()
+	var groups []*eventGroup
+	if q.index != nil {
+		events, err := e.es.Events(ctx, q.typ, q.index)
+		if err != nil {
+			e.log.WithError(err).Error("Event store error")
+			writeError(res, http.StatusInternalServerError, "event store error")
+			return
+		}
+		groups = e.filterGroups(q, from, groupEvents(events))
+	} else {
+		// Events are fetched in batches until there are more groups than
+		// the limit, because some of them may be filtered out. Batches never
+		// split events with the same date, so the next batch starts at the
+		// next second.
+		for {
+			events, err := e.es.List(ctx, q.typ, from, q.to, listBatchSize)
+			if err != nil {
+				e.log.WithError(err).Error("Event store error")
+				writeError(res, http.StatusInternalServerError, "event store error")
+				return
+			}
+			groups = append(groups, e.filterGroups(q, from, groupEvents(events))...)
+			if len(events) < listBatchSize || len(groups) > q.limit {
+				break
+			}
+			from = time.Unix(events[len(events)-1].EventDate.Unix()+1, 0)
+		}
+	}
+	r := &v2EventsResponse{Events: make([]*v2Event, 0), HasMore: len(groups) > q.limit}
+	if len(groups) > q.limit {
+		groups = groups[:q.limit]
+	}
+	for _, g := range groups {
+		r.Events = append(r.Events, e.mapEventGroup(g))
+	}
+	switch {
+	case len(groups) > 0:
+		r.NextCursor = (&cursor{date: groups[len(groups)-1].date.Unix(), key: groups[len(groups)-1].key}).String()
+	case q.cursor != nil:
+		r.NextCursor = q.cursor.String()
+	}
+	writeJSON(res, http.StatusOK, r)
+}
+
+// filterGroups returns event groups that match the query.
+func (e *EventAPI) filterGroups(q *v2EventsQuery, from time.Time, gs []*eventGroup) []*eventGroup {
+	var groups []*eventGroup
+	for _, g := range gs {
+		if g.date.Before(from) || (!q.to.IsZero() && !g.date.Before(q.to)) {
+			continue
+		}
+		if q.cursor != nil && !q.cursor.before(g) {
+			continue
+		}
+		if q.signer != nil && !g.signedBy(*q.signer) {
+			continue
+		}
+		if q.attested && !e.quorumOf(g).Reached {
+			continue
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// v2NotFoundHandler handles requests to unknown paths of the v2 API.
+func (e *EventAPI) v2NotFoundHandler(res http.ResponseWriter, _ *http.Request) {
+	writeError(res, http.StatusNotFound, "not found")
+}
+
+// quorumOf returns the quorum status for the given event group.
+func (e *EventAPI) quorumOf(g *eventGroup) v2Quorum {
+	signed := map[ethereum.Address]bool{}
+	for _, evt := range g.events {
+		for _, s := range evt.Signatures {
+			if len(s.Signer) != ethereum.AddressLength {
+				continue
+			}
+			var addr ethereum.Address
+			copy(addr[:], s.Signer)
+			if e.signers[addr] {
+				signed[addr] = true
+			}
+		}
+	}
+	return v2Quorum{
+		Required: e.quorum,
+		Signed:   len(signed),
+		Reached:  len(e.signers) > 0 && len(signed) >= e.quorum,
+	}
+}
+
+// mapEventGroup converts an event group to a JSON event.
+func (e *EventAPI) mapEventGroup(g *eventGroup) *v2Event {
+	first := g.events[0]
+	j := &v2Event{
+		ID:         hex.EncodeToString(first.ID),
+		Type:       first.Type,
+		Index:      hex.EncodeToString(first.Index),
+		Timestamp:  g.date.Unix(),
+		Data:       map[string]string{},
+		Signatures: make([]v2Signature, 0),
+		Quorum:     e.quorumOf(g),
+	}
+	for k, v := range first.Data {
+		j.Data[k] = hex.EncodeToString(v)
+	}
+	seen := map[v2Signature]bool{}
+	for _, evt := range g.events {
+		for k, v := range evt.Signatures {
+			s := v2Signature{
+				Type:      k,
+				Signer:    hex.EncodeToString(v.Signer),
+				Signature: hex.EncodeToString(v.Signature),
+			}
+			if !seen[s] {
+				seen[s] = true
+				j.Signatures = append(j.Signatures, s)
+			}
+		}
+	}
+	sort.Slice(j.Signatures, func(i, k int) bool {
+		if j.Signatures[i].Signer != j.Signatures[k].Signer {
+			return j.Signatures[i].Signer < j.Signatures[k].Signer
+		}
+		return j.Signatures[i].Type < j.Signatures[k].Type
+	})
+	return j
+}
+
+// signedBy returns true if any message in the group has a signature
+// of the given signer.
+func (g *eventGroup) signedBy(addr ethereum.Address) bool {
+	for _, evt := range g.events {
+		for _, s := range evt.Signatures {
+			if bytes.Equal(s.Signer, addr.Bytes()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groupEvents groups messages for the same event and sorts the groups by
+// the event date and the group key.
+func groupEvents(es []*messages.Event) []*eventGroup {
+	idx := map[[sha256.Size]byte]*eventGroup{}
+	var groups []*eventGroup
+	for _, evt := range es {
+		key := eventKey(evt)
+		g, ok := idx[key]
+		if !ok {
+			g = &eventGroup{key: key[:], date: evt.EventDate}
+			idx[key] = g
+			groups = append(groups, g)
+		}
+		if evt.EventDate.Before(g.date) {
+			g.date = evt.EventDate
+		}
+		g.events = append(g.events, evt)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if !groups[i].date.Equal(groups[j].date) {
+			return groups[i].date.Before(groups[j].date)
+		}
+		return bytes.Compare(groups[i].key, groups[j].key) < 0
+	})
+	return groups
+}
+
+// eventKey returns a key that identifies messages for the same event. Only
+// messages with the same ID and data are considered to be the same event.
+func eventKey(evt *messages.Event) [sha256.Size]byte {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%d:%s:%x;", len(evt.Type), evt.Type, evt.ID)
+	keys := make([]string, 0, len(evt.Data))
+	for k := range evt.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(h, "%d:%s:%x;", len(k), k, evt.Data[k])
+	}
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// before returns true if the cursor points before the given event group.
+func (c *cursor) before(g *eventGroup) bool {
+	if d := g.date.Unix(); d != c.date {
+		return d > c.date
+	}
+	return bytes.Compare(g.key, c.key) > 0
+}
+
+// String returns the encoded cursor.
+func (c *cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%x", c.date, c.key)))
+}
+
+func parseCursor(s string) (*cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("unexpected format")
+	}
+	date, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return &cursor{date: date, key: key}, nil
+}
+
+func parseV2EventsQuery(v url.Values) (*v2EventsQuery, error) {
+	q := &v2EventsQuery{limit: defaultLimit}
+	q.typ = v.Get("type")
+	if q.typ == "" {
+		return nil, errors.New("type parameter is required")
+	}
+	if s := v.Get("index"); s != "" {
+		idx, err := decodeHex(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index parameter: %w", err)
+		}
+		q.index = idx
+	}
+	if s := v.Get("from"); s != "" {
+		from, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from parameter: %w", err)
+		}
+		q.from = time.Unix(from, 0)
+	}
+	if s := v.Get("to"); s != "" {
+		to, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to parameter: %w", err)
+		}
+		q.to = time.Unix(to, 0)
+	}
+	if s := v.Get("signer"); s != "" {
+		if !ethereum.IsHexAddress(s) {
+			return nil, errors.New("invalid signer parameter: must be an address")
+		}
+		addr := ethereum.HexToAddress(s)
+		q.signer = &addr
+	}
+	if s := v.Get("attested"); s != "" {
+		attested, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attested parameter: %w", err)
+		}
+		q.attested = attested
+	}
+	if s := v.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return nil, fmt.Errorf("invalid limit parameter: must be between 1 and %d", maxLimit)
+		}
+		q.limit = limit
+	}
+	if s := v.Get("cursor"); s != "" {
+		c, err := parseCursor(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor parameter: %w", err)
+		}
+		q.cursor = c
+	}
+	return q, nil
+}
+
+func writeJSON(res http.ResponseWriter, status int, v interface{}) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(status)
+	_ = json.NewEncoder(res).Encode(v)
+}
+
+func writeError(res http.ResponseWriter, status int, msg string) {
+	writeJSON(res, status, v2Error{Error: msg})
+}
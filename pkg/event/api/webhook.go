@@ -0,0 +1,155 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/retry"
+)
+
+// SignatureHeader is the name of the HTTP header that contains the
+// HMAC-SHA256 signature of the webhook request body.
+const SignatureHeader = "X-Signature-256"
+
+const webhookQueueSize = 1024
+const webhookTimeout = 10 * time.Second
+const webhookAttempts = 5
+const webhookRetryDelay = 5 * time.Second
+
+// webhookWorkers is the number of events delivered to a single webhook
+// concurrently, so one slow or failing delivery does not block others.
+const webhookWorkers = 4
+
+// Webhook is the configuration of an HTTP endpoint that is notified about
+// new events.
+//
+// Events are sent using the POST method, the request body contains a single
+// event in the same format as in the v2 API. The body is signed using
+// HMAC-SHA256 with the secret key, the signature is sent in the
+// X-Signature-256 header as "sha256=" followed by the hex encoded signature.
+// Any response status other than 2xx is considered a failure and the request
+// is retried. Events are delivered concurrently, so they may arrive in
+// a different order than they were received.
+type Webhook struct {
+	// URL is the address of the endpoint.
+	URL string
+	// Secret is the key used to sign the request body.
+	Secret []byte
+	// EventTypes is a list of event types to send. If empty, events of all
+	// types are sent.
+	EventTypes []string
+	// Attested specifies whether to send events only once, when they are
+	// signed by a quorum of signers. Otherwise, events are sent every time
+	// a new signature is received.
+	Attested bool
+}
+
+// webhook delivers events to a single webhook endpoint.
+type webhook struct {
+	url        string
+	secret     []byte
+	filter     notificationFilter
+	queue      chan *v2Event
+	client     *http.Client
+	attempts   int
+	retryDelay time.Duration
+	workers    int
+	log        log.Logger
+}
+
+func newWebhook(cfg Webhook, logger log.Logger) *webhook {
+	return &webhook{
+		url:        cfg.URL,
+		secret:     cfg.Secret,
+		filter:     notificationFilter{types: cfg.EventTypes, attested: cfg.Attested},
+		queue:      make(chan *v2Event, webhookQueueSize),
+		client:     &http.Client{Timeout: webhookTimeout},
+		attempts:   webhookAttempts,
+		retryDelay: webhookRetryDelay,
+		workers:    webhookWorkers,
+		log:        logger.WithField("url", cfg.URL),
+	}
+}
+
+// start starts the delivery workers.
+func (w *webhook) start(ctx context.Context) {
+	for i := 0; i < w.workers; i++ {
+		go w.deliveryRoutine(ctx)
+	}
+}
+
+// deliveryRoutine sends queued events to the endpoint until the context is
+// canceled.
+func (w *webhook) deliveryRoutine(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt := <-w.queue:
+			err := retry.Try(ctx, func() error {
+				return w.send(ctx, evt)
+			}, w.attempts, w.retryDelay)
+			if err != nil {
+				w.log.
+					WithError(err).
+					WithField("id", evt.ID).
+					Error("Unable to deliver the event to the webhook")
+			}
+		}
+	}
+}
+
+// send sends a single event to the endpoint.
+func (w *webhook) send(ctx context.Context, evt *v2Event) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(signBody(w.secret, body)))
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status: %d", res.StatusCode)
+	}
+	return nil
+}
+
+// signBody returns the HMAC-SHA256 signature of the body.
+func signBody(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
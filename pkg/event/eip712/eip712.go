@@ -0,0 +1,121 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package eip712
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+// SignatureKey is the key of the EIP-712 signature in the event signatures
+// map.
+const SignatureKey = "eip712"
+
+// PrimaryType is the name of the EIP-712 struct that represents an event.
+const PrimaryType = "Event"
+
+var ErrEmptyDomain = errors.New("EIP-712 domain must have at least one field set")
+var ErrMissingHash = errors.New("event has no hash field")
+var ErrInvalidHash = errors.New("event hash must be 32 bytes long")
+
+// Domain is the EIP-712 domain used to separate event signatures from
+// signatures created in other contexts. Only non-empty fields are included
+// in the domain separator.
+type Domain struct {
+	Name              string
+	Version           string
+	ChainID           uint64
+	VerifyingContract ethereum.Address
+}
+
+// Validate returns an error if all domain fields are empty.
+func (d Domain) Validate() error {
+	if d.Name == "" && d.Version == "" && d.ChainID == 0 && d.VerifyingContract == ethereum.EmptyAddress {
+		return ErrEmptyDomain
+	}
+	return nil
+}
+
+// TypedData returns the EIP-712 typed data for the given event. The typed
+// data contains the following struct:
+//
+//	Event(string eventType,bytes32 hash)
+//
+// where eventType is the type of the event and hash is the value of the
+// "hash" field from the event data.
+func TypedData(domain Domain, evt *messages.Event) (ethereum.TypedData, error) {
+	if err := domain.Validate(); err != nil {
+		return ethereum.TypedData{}, err
+	}
+	h, ok := evt.Data["hash"]
+	if !ok {
+		return ethereum.TypedData{}, ErrMissingHash
+	}
+	if len(h) != 32 {
+		return ethereum.TypedData{}, ErrInvalidHash
+	}
+	td := ethereum.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": domainType(domain),
+			PrimaryType: {
+				{Name: "eventType", Type: "string"},
+				{Name: "hash", Type: "bytes32"},
+			},
+		},
+		PrimaryType: PrimaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:    domain.Name,
+			Version: domain.Version,
+		},
+		Message: apitypes.TypedDataMessage{
+			"eventType": evt.Type,
+			"hash":      hexutil.Encode(h),
+		},
+	}
+	if domain.ChainID != 0 {
+		td.Domain.ChainId = (*math.HexOrDecimal256)(new(big.Int).SetUint64(domain.ChainID))
+	}
+	if domain.VerifyingContract != ethereum.EmptyAddress {
+		td.Domain.VerifyingContract = domain.VerifyingContract.String()
+	}
+	return td, nil
+}
+
+// domainType returns the EIP712Domain type definition. The order of fields
+// is defined by the EIP-712 specification.
+func domainType(domain Domain) []apitypes.Type {
+	var t []apitypes.Type
+	if domain.Name != "" {
+		t = append(t, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		t = append(t, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainID != 0 {
+		t = append(t, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != ethereum.EmptyAddress {
+		t = append(t, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	return t
+}
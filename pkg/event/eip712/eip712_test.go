@@ -0,0 +1,95 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package eip712
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+func TestTypedData(t *testing.T) {
+	hash := crypto.Keccak256([]byte("event"))
+	contract := ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	evt := &messages.Event{Type: "teleport_evm", Data: map[string][]byte{"hash": hash}}
+	domain := Domain{Name: "Lair", Version: "1", ChainID: 1, VerifyingContract: contract}
+
+	td, err := TypedData(domain, evt)
+	require.NoError(t, err)
+
+	// Domain separator calculated according to the EIP-712 specification:
+	domainTypeHash := crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	expDomainSeparator := crypto.Keccak256(
+		domainTypeHash,
+		crypto.Keccak256([]byte("Lair")),
+		crypto.Keccak256([]byte("1")),
+		math.U256Bytes(big.NewInt(1)),
+		append(make([]byte, 12), contract.Bytes()...),
+	)
+	domainSeparator, err := td.HashStruct("EIP712Domain", td.Domain.Map())
+	require.NoError(t, err)
+	assert.Equal(t, expDomainSeparator, []byte(domainSeparator))
+
+	// Struct hash:
+	expStructHash := crypto.Keccak256(
+		crypto.Keccak256([]byte("Event(string eventType,bytes32 hash)")),
+		crypto.Keccak256([]byte("teleport_evm")),
+		hash,
+	)
+	structHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	require.NoError(t, err)
+	assert.Equal(t, expStructHash, []byte(structHash))
+}
+
+func TestTypedData_PartialDomain(t *testing.T) {
+	evt := &messages.Event{Type: "test", Data: map[string][]byte{"hash": crypto.Keccak256([]byte("event"))}}
+	td, err := TypedData(Domain{ChainID: 5}, evt)
+	require.NoError(t, err)
+
+	domainSeparator, err := td.HashStruct("EIP712Domain", td.Domain.Map())
+	require.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(uint256 chainId)")),
+		math.U256Bytes(big.NewInt(5)),
+	), []byte(domainSeparator))
+}
+
+func TestTypedData_Errors(t *testing.T) {
+	domain := Domain{Name: "Lair"}
+	tests := []struct {
+		domain Domain
+		event  *messages.Event
+		err    error
+	}{
+		{domain: Domain{}, event: &messages.Event{Data: map[string][]byte{"hash": make([]byte, 32)}}, err: ErrEmptyDomain},
+		{domain: domain, event: &messages.Event{Data: map[string][]byte{}}, err: ErrMissingHash},
+		{domain: domain, event: &messages.Event{Data: map[string][]byte{"hash": make([]byte, 31)}}, err: ErrInvalidHash},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			_, err := TypedData(tt.domain, tt.event)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
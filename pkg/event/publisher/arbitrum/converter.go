@@ -0,0 +1,169 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package arbitrum
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportevm"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+const L2ToL1TxEventType = "arbitrum_l2_to_l1_tx"
+
+// ArbSys is the address of the ArbSys precompile contract on Arbitrum.
+var ArbSys = types.HexToAddress("0x0000000000000000000000000000000000000064")
+
+var ErrInvalidSendHash = errors.New("hash field does not match the L2ToL1Tx event data")
+
+// Converter converts L2ToL1Tx events emitted by the Arbitrum ArbSys
+// precompile into event messages. It implements the teleportevm.LogConverter
+// interface, so it can be used with the teleportevm.EventProvider.
+//
+// Messages are created by teleportevm.NewLogEvent. The "hash" data field is
+// the hash of the outgoing message, which is the leaf of the outbox Merkle
+// tree. The hash is recalculated from the event data and compared with the
+// hash field of the event, so the signature always covers the event data.
+// The timestamp field is used as the event date.
+type Converter struct{}
+
+// NewConverter returns a new instance of the Converter struct.
+func NewConverter() *Converter {
+	return &Converter{}
+}
+
+// EventType implements the teleportevm.LogConverter interface.
+func (c *Converter) EventType() string {
+	return L2ToL1TxEventType
+}
+
+// Topic0 implements the teleportevm.LogConverter interface.
+func (c *Converter) Topic0() types.Hash {
+	return types.Hash(l2ToL1TxEvent.ID)
+}
+
+// Convert implements the teleportevm.LogConverter interface.
+func (c *Converter) Convert(l types.Log, _ teleportevm.LogContext) (*messages.Event, error) {
+	if len(l.Topics) != 4 || l.Topics[0] != c.Topic0() {
+		return nil, fmt.Errorf("log is not a L2ToL1Tx event")
+	}
+	tx, err := unpackL2ToL1Tx(l)
+	if err != nil {
+		return nil, err
+	}
+	hash := tx.sendHash()
+	if !bytes.Equal(hash, l.Topics[2].Bytes()) {
+		return nil, ErrInvalidSendHash
+	}
+	if !tx.timestamp.IsInt64() {
+		return nil, fmt.Errorf("invalid L2ToL1Tx timestamp field")
+	}
+	return teleportevm.NewLogEvent(l, L2ToL1TxEventType, hash, time.Unix(tx.timestamp.Int64(), 0)), nil
+}
+
+// l2ToL1Tx is the L2ToL1Tx event:
+//
+//	event L2ToL1Tx(address caller, address indexed destination, uint256 indexed hash, uint256 indexed position,
+//	    uint256 arbBlockNum, uint256 ethBlockNum, uint256 timestamp, uint256 callvalue, bytes data)
+type l2ToL1Tx struct {
+	caller      common.Address
+	destination common.Address
+	arbBlockNum *big.Int
+	ethBlockNum *big.Int
+	timestamp   *big.Int
+	callvalue   *big.Int
+	data        []byte
+}
+
+// sendHash calculates the hash of the outgoing message in the same way as
+// the ArbSys precompile.
+func (tx *l2ToL1Tx) sendHash() []byte {
+	return crypto.Keccak256(
+		tx.caller.Bytes(),
+		tx.destination.Bytes(),
+		math.U256Bytes(new(big.Int).Set(tx.arbBlockNum)),
+		math.U256Bytes(new(big.Int).Set(tx.ethBlockNum)),
+		math.U256Bytes(new(big.Int).Set(tx.timestamp)),
+		math.U256Bytes(new(big.Int).Set(tx.callvalue)),
+		tx.data,
+	)
+}
+
+func unpackL2ToL1Tx(l types.Log) (*l2ToL1Tx, error) {
+	values := map[string]interface{}{}
+	if err := l2ToL1TxEvent.Inputs.UnpackIntoMap(values, l.Data); err != nil {
+		return nil, fmt.Errorf("unable to unpack L2ToL1Tx event: %w", err)
+	}
+	tx := &l2ToL1Tx{destination: common.BytesToAddress(l.Topics[1].Bytes())}
+	var ok bool
+	if tx.caller, ok = values["caller"].(common.Address); !ok {
+		return nil, fmt.Errorf("invalid L2ToL1Tx caller field")
+	}
+	if tx.arbBlockNum, ok = values["arbBlockNum"].(*big.Int); !ok {
+		return nil, fmt.Errorf("invalid L2ToL1Tx arbBlockNum field")
+	}
+	if tx.ethBlockNum, ok = values["ethBlockNum"].(*big.Int); !ok {
+		return nil, fmt.Errorf("invalid L2ToL1Tx ethBlockNum field")
+	}
+	if tx.timestamp, ok = values["timestamp"].(*big.Int); !ok {
+		return nil, fmt.Errorf("invalid L2ToL1Tx timestamp field")
+	}
+	if tx.callvalue, ok = values["callvalue"].(*big.Int); !ok {
+		return nil, fmt.Errorf("invalid L2ToL1Tx callvalue field")
+	}
+	if tx.data, ok = values["data"].([]byte); !ok {
+		return nil, fmt.Errorf("invalid L2ToL1Tx data field")
+	}
+	return tx, nil
+}
+
+const l2ToL1TxABI = `[{
+  "anonymous": false,
+  "inputs": [
+    {"indexed": false, "name": "caller", "type": "address"},
+    {"indexed": true, "name": "destination", "type": "address"},
+    {"indexed": true, "name": "hash", "type": "uint256"},
+    {"indexed": true, "name": "position", "type": "uint256"},
+    {"indexed": false, "name": "arbBlockNum", "type": "uint256"},
+    {"indexed": false, "name": "ethBlockNum", "type": "uint256"},
+    {"indexed": false, "name": "timestamp", "type": "uint256"},
+    {"indexed": false, "name": "callvalue", "type": "uint256"},
+    {"indexed": false, "name": "data", "type": "bytes"}
+  ],
+  "name": "L2ToL1Tx",
+  "type": "event"
+}]`
+
+var l2ToL1TxEvent abi.Event
+
+func init() {
+	a, err := abi.JSON(strings.NewReader(l2ToL1TxABI))
+	if err != nil {
+		panic(err)
+	}
+	l2ToL1TxEvent = a.Events["L2ToL1Tx"]
+}
@@ -0,0 +1,112 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package arbitrum
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+func testLog(t *testing.T, hash common.Hash) types.Log {
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	destination := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	data, err := l2ToL1TxEvent.Inputs.NonIndexed().Pack(
+		caller,
+		big.NewInt(1000),       // arbBlockNum
+		big.NewInt(900),        // ethBlockNum
+		big.NewInt(1652698140), // timestamp
+		big.NewInt(5),          // callvalue
+		[]byte{1, 2, 3},        // data
+	)
+	require.NoError(t, err)
+	return types.Log{
+		Address: ArbSys,
+		Topics: []types.Hash{
+			types.Hash(crypto.Keccak256Hash([]byte("L2ToL1Tx(address,address,uint256,uint256,uint256,uint256,uint256,uint256,bytes)"))),
+			types.Hash(common.BytesToHash(destination.Bytes())),
+			types.Hash(hash),
+			types.Hash(common.BigToHash(big.NewInt(7))), // position
+		},
+		Data:     data,
+		TxHash:   types.HexToHash("0x66b68b4e9f0c1e8a26b1b0f8d9b3a7a3d2c1e5f4a3b2c1d0e9f8a7b6c5d4e3f2"),
+		LogIndex: types.Uint64ToNumber(1),
+	}
+}
+
+func TestConverter(t *testing.T) {
+	// keccak256(abi.encodePacked(caller, destination, arbBlockNum, ethBlockNum, timestamp, callvalue, data)):
+	sendHash := crypto.Keccak256Hash(common.FromHex(
+		"1111111111111111111111111111111111111111" +
+			"2222222222222222222222222222222222222222" +
+			"00000000000000000000000000000000000000000000000000000000000003e8" +
+			"0000000000000000000000000000000000000000000000000000000000000384" +
+			"0000000000000000000000000000000000000000000000000000000062822c1c" +
+			"0000000000000000000000000000000000000000000000000000000000000005" +
+			"010203",
+	))
+	l := testLog(t, sendHash)
+
+	c := NewConverter()
+	assert.Equal(t, L2ToL1TxEventType, c.EventType())
+	assert.Equal(t, l.Topics[0], c.Topic0())
+
+	evt, err := c.Convert(l, nil)
+	require.NoError(t, err)
+	assert.Equal(t, L2ToL1TxEventType, evt.Type)
+	assert.Equal(t, sendHash.Bytes(), evt.Data["hash"])
+	assert.Equal(t, []byte(l.Data), evt.Data["event"])
+	assert.Len(t, evt.Data["topics"], 128)
+	assert.Equal(t, l.TxHash.Bytes(), evt.Index)
+	assert.Equal(t, time.Unix(1652698140, 0), evt.EventDate)
+
+	// ID must be stable:
+	evt2, err := c.Convert(l, nil)
+	require.NoError(t, err)
+	assert.Equal(t, evt.ID, evt2.ID)
+
+	// Another message sent in the same transaction has the same index, but
+	// a different ID:
+	l.LogIndex = types.Uint64ToNumber(2)
+	evt3, err := c.Convert(l, nil)
+	require.NoError(t, err)
+	assert.Equal(t, evt.Index, evt3.Index)
+	assert.NotEqual(t, evt.ID, evt3.ID)
+}
+
+func TestConverter_InvalidSendHash(t *testing.T) {
+	_, err := NewConverter().Convert(testLog(t, common.HexToHash("0x01")), nil)
+	assert.ErrorIs(t, err, ErrInvalidSendHash)
+}
+
+func TestConverter_InvalidLog(t *testing.T) {
+	c := NewConverter()
+
+	// Invalid topic:
+	_, err := c.Convert(types.Log{Topics: []types.Hash{{}, {}, {}, {}}}, nil)
+	assert.Error(t, err)
+
+	// Invalid data:
+	_, err = c.Convert(types.Log{Topics: []types.Hash{c.Topic0(), {}, {}, {}}, Data: []byte{1}}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package checkpoint
+
+import (
+	"context"
+	"sync"
+)
+
+// Store provides an interface to the checkpoint storage. Checkpoints are used
+// by event providers to record the last fully processed block, so they can
+// resume from that block after a restart.
+type Store interface {
+	// Get returns the last fully processed block for the given key. If there
+	// is no checkpoint for the key, false is returned as a second value.
+	// The method is thread-safe.
+	Get(ctx context.Context, key string) (uint64, bool, error)
+	// Set updates the last fully processed block for the given key.
+	// The method is thread-safe.
+	Set(ctx context.Context, key string, block uint64) error
+}
+
+// MemoryStore implements the Store interface. It keeps checkpoints in the
+// local memory, so they are lost after a restart.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]uint64
+}
+
+// NewMemoryStore returns a new instance of MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{checkpoints: map[string]uint64{}}
+}
+
+// Get implements the Store interface.
+func (m *MemoryStore) Get(_ context.Context, key string) (uint64, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	block, ok := m.checkpoints[key]
+	return block, ok, nil
+}
+
+// Set implements the Store interface.
+func (m *MemoryStore) Set(_ context.Context, key string, block uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checkpoints[key] = block
+	return nil
+}
@@ -0,0 +1,86 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "checkpoints.json"))
+	require.NoError(t, err)
+	tests := map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   fileStore,
+	}
+	for name, s := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, ok, err := s.Get(ctx, "a")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, s.Set(ctx, "a", 10))
+			require.NoError(t, s.Set(ctx, "b", 20))
+			require.NoError(t, s.Set(ctx, "a", 11))
+
+			block, ok, err := s.Get(ctx, "a")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, uint64(11), block)
+
+			block, ok, err = s.Get(ctx, "b")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, uint64(20), block)
+		})
+	}
+}
+
+func TestFileStore_Reload(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+
+	s, err := NewFileStore(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Set(ctx, "a", 42))
+
+	s, err = NewFileStore(path)
+	require.NoError(t, err)
+	block, ok, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), block)
+
+	// Temporary files must be removed after update.
+	files, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestFileStore_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0600))
+
+	_, err := NewFileStore(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore implements the Store interface. It keeps checkpoints in a JSON
+// file. The file is replaced atomically on every update, so it is never left
+// in a partially written state.
+type FileStore struct {
+	mu          sync.RWMutex
+	path        string
+	checkpoints map[string]uint64
+}
+
+// NewFileStore returns a new instance of FileStore. If the file exists,
+// checkpoints are loaded from it, otherwise it will be created on the first
+// update.
+func NewFileStore(path string) (*FileStore, error) {
+	f := &FileStore{path: path, checkpoints: map[string]uint64{}}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("unable to read checkpoint file: %w", err)
+	}
+	if len(b) == 0 {
+		return f, nil
+	}
+	if err := json.Unmarshal(b, &f.checkpoints); err != nil {
+		return nil, fmt.Errorf("unable to parse checkpoint file: %w", err)
+	}
+	return f, nil
+}
+
+// Get implements the Store interface.
+func (f *FileStore) Get(_ context.Context, key string) (uint64, bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	block, ok := f.checkpoints[key]
+	return block, ok, nil
+}
+
+// Set implements the Store interface.
+func (f *FileStore) Set(_ context.Context, key string, block uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	prev, ok := f.checkpoints[key]
+	f.checkpoints[key] = block
+	if err := f.write(); err != nil {
+		if ok {
+			f.checkpoints[key] = prev
+		} else {
+			delete(f.checkpoints, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// write writes checkpoints to a temporary file and then renames it to the
+// target path.
+func (f *FileStore) write() error {
+	b, err := json.MarshalIndent(f.checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create checkpoint file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write checkpoint file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write checkpoint file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write checkpoint file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("unable to write checkpoint file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package evmlog
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportevm"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+// Config contains a configuration options for NewConverter.
+type Config struct {
+	// EventType is the type of created event messages.
+	EventType string
+	// ABI is a JSON ABI containing the event. It may be a full contract ABI,
+	// or a fragment with a single event.
+	ABI string
+	// EventName is the name of the event in the ABI.
+	EventName string
+	// IndexFields is a list of event fields used as the event index. Values
+	// of the fields are ABI encoded in the given order. If empty, the
+	// transaction hash is used as the index.
+	IndexFields []string
+	// HashFields is a list of event fields used to calculate the signed hash.
+	// The hash is the Keccak256 of ABI encoded values of the fields in the
+	// given order.
+	HashFields []string
+	// TimestampField is an optional name of the integer field that contains
+	// the event time as a Unix timestamp. If empty, the timestamp of the
+	// block in which the log was emitted is used as the event date.
+	TimestampField string
+}
+
+// Converter converts Ethereum logs of any event described by an ABI into
+// event messages. It implements the teleportevm.LogConverter interface, so
+// it can be used with the teleportevm.EventProvider.
+//
+// Messages are created by teleportevm.NewLogEvent. The "hash" data field is
+// the Keccak256 of ABI encoded hash fields. If index fields are configured,
+// their ABI encoded values are used as the event index instead of the
+// transaction hash.
+//
+// Indexed fields of dynamic types (string, bytes, arrays) are stored in topics
+// as Keccak256 hashes of their values, so they are encoded as bytes32.
+type Converter struct {
+	eventType      string
+	event          abi.Event
+	indexed        abi.Arguments
+	indexFields    []string
+	indexArgs      abi.Arguments
+	hashFields     []string
+	hashArgs       abi.Arguments
+	timestampField string
+}
+
+// NewConverter returns a new instance of the Converter struct.
+func NewConverter(cfg Config) (*Converter, error) {
+	if cfg.EventType == "" {
+		return nil, errors.New("event type must be set")
+	}
+	if len(cfg.HashFields) == 0 {
+		return nil, errors.New("at least one hash field must be set")
+	}
+	contractABI, err := parseABI(cfg.ABI)
+	if err != nil {
+		return nil, err
+	}
+	event, ok := contractABI.Events[cfg.EventName]
+	if !ok {
+		return nil, fmt.Errorf("event %q not found in the ABI", cfg.EventName)
+	}
+	if event.Anonymous {
+		return nil, fmt.Errorf("anonymous events are not supported")
+	}
+	c := &Converter{
+		eventType:      cfg.EventType,
+		event:          event,
+		indexFields:    cfg.IndexFields,
+		hashFields:     cfg.HashFields,
+		timestampField: cfg.TimestampField,
+	}
+	for _, arg := range event.Inputs {
+		if !arg.Indexed {
+			continue
+		}
+		if arg.Type.T == abi.TupleTy {
+			return nil, fmt.Errorf("indexed tuple field %q is not supported", arg.Name)
+		}
+		c.indexed = append(c.indexed, arg)
+	}
+	if c.indexArgs, err = c.arguments(cfg.IndexFields); err != nil {
+		return nil, err
+	}
+	if c.hashArgs, err = c.arguments(cfg.HashFields); err != nil {
+		return nil, err
+	}
+	if cfg.TimestampField != "" {
+		args, err := c.arguments([]string{cfg.TimestampField})
+		if err != nil {
+			return nil, err
+		}
+		if t := args[0].Type.T; t != abi.UintTy && t != abi.IntTy {
+			return nil, fmt.Errorf("timestamp field %q must be an integer", cfg.TimestampField)
+		}
+	}
+	return c, nil
+}
+
+// EventType implements the teleportevm.LogConverter interface.
+func (c *Converter) EventType() string {
+	return c.eventType
+}
+
+// Topic0 implements the teleportevm.LogConverter interface.
+func (c *Converter) Topic0() types.Hash {
+	return types.Hash(c.event.ID)
+}
+
+// Convert implements the teleportevm.LogConverter interface.
+func (c *Converter) Convert(l types.Log, lc teleportevm.LogContext) (*messages.Event, error) {
+	if len(l.Topics) == 0 || l.Topics[0] != c.Topic0() {
+		return nil, fmt.Errorf("log is not a %s event", c.event.Name)
+	}
+	values := map[string]interface{}{}
+	if err := c.event.Inputs.UnpackIntoMap(values, l.Data); err != nil {
+		return nil, fmt.Errorf("unable to unpack %s event: %w", c.event.Name, err)
+	}
+	topics := make([]common.Hash, len(l.Topics)-1)
+	for i, t := range l.Topics[1:] {
+		topics[i] = common.Hash(t)
+	}
+	if err := abi.ParseTopicsIntoMap(values, c.indexed, topics); err != nil {
+		return nil, fmt.Errorf("unable to parse %s event topics: %w", c.event.Name, err)
+	}
+	hashData, err := pack(c.hashArgs, c.hashFields, values)
+	if err != nil {
+		return nil, err
+	}
+	var eventDate time.Time
+	if c.timestampField != "" {
+		ts, err := toInt64(values[c.timestampField])
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp field: %w", err)
+		}
+		eventDate = time.Unix(ts, 0)
+	} else {
+		if eventDate, err = lc.BlockTimestamp(); err != nil {
+			return nil, fmt.Errorf("unable to get block timestamp: %w", err)
+		}
+	}
+	evt := teleportevm.NewLogEvent(l, c.eventType, crypto.Keccak256(hashData), eventDate)
+	if len(c.indexFields) > 0 {
+		if evt.Index, err = pack(c.indexArgs, c.indexFields, values); err != nil {
+			return nil, err
+		}
+	}
+	return evt, nil
+}
+
+// arguments returns ABI arguments used to encode the given fields.
+func (c *Converter) arguments(fields []string) (abi.Arguments, error) {
+	var args abi.Arguments
+	for _, field := range fields {
+		arg, ok := c.argument(field)
+		if !ok {
+			return nil, fmt.Errorf("field %q not found in the %s event", field, c.event.Name)
+		}
+		if arg.Indexed && isDynamic(arg.Type) {
+			// Only hash of the value is stored in the topic.
+			arg.Type = bytes32Type
+		}
+		arg.Indexed = false
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// argument returns the event argument with the given name.
+func (c *Converter) argument(name string) (abi.Argument, bool) {
+	for _, arg := range c.event.Inputs {
+		if arg.Name == name {
+			return arg, true
+		}
+	}
+	return abi.Argument{}, false
+}
+
+// parseABI parses a JSON ABI. A single object is treated as an ABI with one
+// element.
+func parseABI(s string) (abi.ABI, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		s = "[" + s + "]"
+	}
+	a, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("unable to parse ABI: %w", err)
+	}
+	return a, nil
+}
+
+// pack ABI encodes values of the given fields.
+func pack(args abi.Arguments, fields []string, values map[string]interface{}) ([]byte, error) {
+	vals := make([]interface{}, len(fields))
+	for i, field := range fields {
+		v := values[field]
+		if h, ok := v.(common.Hash); ok {
+			v = [32]byte(h)
+		}
+		vals[i] = v
+	}
+	b, err := args.Pack(vals...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode fields %s: %w", strings.Join(fields, ", "), err)
+	}
+	return b, nil
+}
+
+// isDynamic returns true if indexed values of the given type are stored in
+// topics as hashes.
+func isDynamic(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+		return true
+	}
+	return false
+}
+
+// toInt64 converts an unpacked ABI integer to int64.
+func toInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		if !n.IsInt64() {
+			return 0, errors.New("value out of range")
+		}
+		return n.Int64(), nil
+	case uint8:
+		return int64(n), nil
+	case uint16:
+		return int64(n), nil
+	case uint32:
+		return int64(n), nil
+	case uint64:
+		if n > uint64(1<<63-1) {
+			return 0, errors.New("value out of range")
+		}
+		return int64(n), nil
+	case int8:
+		return int64(n), nil
+	case int16:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	}
+	return 0, fmt.Errorf("unexpected type %T", v)
+}
+
+var bytes32Type abi.Type
+
+func init() {
+	bytes32Type, _ = abi.NewType("bytes32", "", nil)
+}
@@ -0,0 +1,216 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package evmlog
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+const teleportABI = `{
+  "anonymous": false,
+  "inputs": [
+    {
+      "components": [
+        {"internalType": "bytes32", "name": "sourceDomain", "type": "bytes32"},
+        {"internalType": "bytes32", "name": "targetDomain", "type": "bytes32"},
+        {"internalType": "bytes32", "name": "receiver", "type": "bytes32"},
+        {"internalType": "bytes32", "name": "operator", "type": "bytes32"},
+        {"internalType": "uint128", "name": "amount", "type": "uint128"},
+        {"internalType": "uint80", "name": "nonce", "type": "uint80"},
+        {"internalType": "uint48", "name": "timestamp", "type": "uint48"}
+      ],
+      "indexed": false,
+      "internalType": "struct TeleportGUID",
+      "name": "teleport",
+      "type": "tuple"
+    }
+  ],
+  "name": "TeleportInitialized",
+  "type": "event"
+}`
+
+const messageABI = `[{
+  "anonymous": false,
+  "inputs": [
+    {"indexed": true, "name": "id", "type": "bytes32"},
+    {"indexed": true, "name": "tag", "type": "string"},
+    {"indexed": false, "name": "sender", "type": "address"},
+    {"indexed": false, "name": "amount", "type": "uint256"},
+    {"indexed": false, "name": "timestamp", "type": "uint64"}
+  ],
+  "name": "MessageSent",
+  "type": "event"
+}]`
+
+type testLogContext struct {
+	timestamp time.Time
+}
+
+func (lc testLogContext) BlockTimestamp() (time.Time, error) {
+	return lc.timestamp, nil
+}
+
+func (lc testLogContext) Receipt() (*types.TransactionReceiptType, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestConverter_Teleport(t *testing.T) {
+	// The generic converter configured with the TeleportInitialized event
+	// must produce the same hash as the teleportevm package.
+	c, err := NewConverter(Config{
+		EventType:  "teleport",
+		ABI:        teleportABI,
+		EventName:  "TeleportInitialized",
+		HashFields: []string{"teleport"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.HexToHash("0x61aedca97129bac4264ec6356bd1f66431e65ab80e2d07b7983647d72776f545"), c.Topic0())
+
+	txHash := types.HexToHash("0x66e8ab5a41d4b109c7f6ea5303e3c292771e57fb0b93a8474ca6f72e53eac0e8")
+	data := types.HexToBytes("0x111111111111111111111111111111111111111111111111111111111111111122222222222222222222222222222222222222222222222222222222222222220000000000000000000000003333333333333333333333333333333333333333000000000000000000000000444444444444444444444444444444444444444400000000000000000000000000000000000000000000000000000000000000370000000000000000000000000000000000000000000000000000000000000042000000000000000000000000000000000000000000000000000000000000004d")
+	evt, err := c.Convert(types.Log{
+		Topics: []types.Hash{c.Topic0()},
+		Data:   data,
+		TxHash: txHash,
+	}, testLogContext{timestamp: time.Unix(1500000000, 0)})
+	require.NoError(t, err)
+	assert.Equal(t, "teleport", evt.Type)
+	assert.Equal(t, txHash.Bytes(), evt.Index)
+	assert.Equal(t, common.FromHex("0x69515a78ae1ad8c4650b57eb6dcd0c866b71e828316dabbc64f430588d043452"), evt.Data["hash"])
+	assert.Equal(t, data.Bytes(), evt.Data["event"])
+
+	// Without the timestamp field, the block timestamp is used:
+	assert.Equal(t, time.Unix(1500000000, 0), evt.EventDate)
+}
+
+func TestConverter_IndexedFields(t *testing.T) {
+	c, err := NewConverter(Config{
+		EventType:      "message",
+		ABI:            messageABI,
+		EventName:      "MessageSent",
+		IndexFields:    []string{"id"},
+		HashFields:     []string{"id", "tag", "amount"},
+		TimestampField: "timestamp",
+	})
+	require.NoError(t, err)
+
+	id := common.HexToHash("0x0101010101010101010101010101010101010101010101010101010101010101")
+	tag := crypto.Keccak256Hash([]byte("tag"))
+	sender := common.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	data, err := c.event.Inputs.NonIndexed().Pack(sender, big.NewInt(42), uint64(1600000000))
+	require.NoError(t, err)
+
+	evt, err := c.Convert(types.Log{
+		Topics: []types.Hash{c.Topic0(), types.Hash(id), types.Hash(tag)},
+		Data:   data,
+	}, nil)
+	require.NoError(t, err)
+
+	amount := common.LeftPadBytes(big.NewInt(42).Bytes(), 32)
+	assert.Equal(t, id.Bytes(), evt.Index)
+	assert.Equal(t, crypto.Keccak256(id.Bytes(), tag.Bytes(), amount), evt.Data["hash"])
+	assert.Equal(t, data, evt.Data["event"])
+	assert.Equal(t, append(c.event.ID.Bytes(), append(id.Bytes(), tag.Bytes()...)...), evt.Data["topics"])
+	assert.Equal(t, time.Unix(1600000000, 0), evt.EventDate)
+
+	// Log of a different event:
+	_, err = c.Convert(types.Log{Topics: []types.Hash{types.Hash(id)}, Data: data}, nil)
+	assert.Error(t, err)
+}
+
+func TestConverter_SameTransaction(t *testing.T) {
+	c, err := NewConverter(Config{
+		EventType:      "message",
+		ABI:            messageABI,
+		EventName:      "MessageSent",
+		HashFields:     []string{"id"},
+		TimestampField: "timestamp",
+	})
+	require.NoError(t, err)
+
+	txHash := types.HexToHash("0x66e8ab5a41d4b109c7f6ea5303e3c292771e57fb0b93a8474ca6f72e53eac0e8")
+	tag := crypto.Keccak256Hash([]byte("tag"))
+	sender := common.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	data, err := c.event.Inputs.NonIndexed().Pack(sender, big.NewInt(42), uint64(1600000000))
+	require.NoError(t, err)
+
+	var evts []*messages.Event
+	for i, id := range []string{"0x01", "0x02"} {
+		evt, err := c.Convert(types.Log{
+			Topics:   []types.Hash{c.Topic0(), types.HexToHash(id), types.Hash(tag)},
+			Data:     data,
+			TxHash:   txHash,
+			LogIndex: types.Uint64ToNumber(uint64(i)),
+		}, nil)
+		require.NoError(t, err)
+		evts = append(evts, evt)
+	}
+
+	// Without index fields, both messages are indexed by the transaction
+	// hash, clients tell them apart by the hash field:
+	assert.Equal(t, txHash.Bytes(), evts[0].Index)
+	assert.Equal(t, txHash.Bytes(), evts[1].Index)
+	assert.NotEqual(t, evts[0].ID, evts[1].ID)
+	assert.NotEqual(t, evts[0].Data["hash"], evts[1].Data["hash"])
+}
+
+func TestNewConverter_InvalidConfig(t *testing.T) {
+	valid := Config{
+		EventType:  "message",
+		ABI:        messageABI,
+		EventName:  "MessageSent",
+		HashFields: []string{"id"},
+	}
+	tests := []func(c *Config){
+		func(c *Config) { c.EventType = "" },
+		func(c *Config) { c.HashFields = nil },
+		func(c *Config) { c.ABI = "invalid" },
+		func(c *Config) { c.EventName = "Unknown" },
+		func(c *Config) { c.HashFields = []string{"unknown"} },
+		func(c *Config) { c.IndexFields = []string{"unknown"} },
+		func(c *Config) { c.TimestampField = "sender" },
+	}
+	_, err := NewConverter(valid)
+	require.NoError(t, err)
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			cfg := valid
+			tt(&cfg)
+			_, err := NewConverter(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_pack(t *testing.T) {
+	bytes32, _ := abi.NewType("bytes32", "", nil)
+	b, err := pack(abi.Arguments{{Type: bytes32}}, []string{"a"}, map[string]interface{}{"a": common.Hash{1}})
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{1}.Bytes(), b)
+}
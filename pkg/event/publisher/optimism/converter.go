@@ -0,0 +1,249 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package optimism
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/teleportevm"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+const SentMessageEventType = "optimism_sent_message"
+
+// L2CrossDomainMessenger is the address of the L2CrossDomainMessenger
+// predeploy contract on Optimism.
+var L2CrossDomainMessenger = types.HexToAddress("0x4200000000000000000000000000000000000007")
+
+// Converter converts SentMessage events emitted by the Optimism
+// L2CrossDomainMessenger contract into event messages. It implements
+// the teleportevm.LogConverter interface, so it can be used with the
+// teleportevm.EventProvider.
+//
+// Messages are created by teleportevm.NewLogEvent. The "hash" data field is
+// the versioned hash of the message, the same as the one calculated by the
+// Optimism CrossDomainMessenger.
+//
+// The version of the message is encoded in the two most significant bytes
+// of the message nonce. Version 0 messages are hashed as the legacy
+// relayMessage(address,address,bytes,uint256) calldata. Version 1 messages
+// are hashed as the relayMessage(uint256,address,address,uint256,uint256,bytes)
+// calldata, which also includes the value sent with the message. The value
+// is read from the SentMessageExtension1 event emitted right after the
+// SentMessage event in the same transaction.
+//
+// The timestamp of the block is used as the event date.
+type Converter struct{}
+
+// NewConverter returns a new instance of the Converter struct.
+func NewConverter() *Converter {
+	return &Converter{}
+}
+
+// EventType implements the teleportevm.LogConverter interface.
+func (c *Converter) EventType() string {
+	return SentMessageEventType
+}
+
+// Topic0 implements the teleportevm.LogConverter interface.
+func (c *Converter) Topic0() types.Hash {
+	return types.Hash(sentMessageEvent.ID)
+}
+
+// Convert implements the teleportevm.LogConverter interface.
+func (c *Converter) Convert(l types.Log, lc teleportevm.LogContext) (*messages.Event, error) {
+	if len(l.Topics) != 2 || l.Topics[0] != c.Topic0() {
+		return nil, fmt.Errorf("log is not a SentMessage event")
+	}
+	msg, err := unpackSentMessage(l)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := messageHash(l, msg, lc)
+	if err != nil {
+		return nil, err
+	}
+	blockTime, err := lc.BlockTimestamp()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get block timestamp: %w", err)
+	}
+	return teleportevm.NewLogEvent(l, SentMessageEventType, hash, blockTime), nil
+}
+
+// messageHash returns the versioned hash of the message as calculated by
+// the Optimism CrossDomainMessenger contract.
+func messageHash(l types.Log, msg *sentMessage, lc teleportevm.LogContext) ([]byte, error) {
+	version := messageVersion(msg.messageNonce)
+	switch version {
+	case 0:
+		data, err := relayMessageV0.Inputs.Pack(msg.target, msg.sender, msg.message, msg.messageNonce)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode SentMessage event: %w", err)
+		}
+		return crypto.Keccak256(relayMessageV0.ID, data), nil
+	case 1:
+		receipt, err := lc.Receipt()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get transaction receipt: %w", err)
+		}
+		value, err := findMessageValue(l, receipt)
+		if err != nil {
+			return nil, err
+		}
+		data, err := relayMessageV1.Inputs.Pack(msg.messageNonce, msg.sender, msg.target, value, msg.gasLimit, msg.message)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode SentMessage event: %w", err)
+		}
+		return crypto.Keccak256(relayMessageV1.ID, data), nil
+	default:
+		return nil, fmt.Errorf("unsupported SentMessage version %d", version)
+	}
+}
+
+// messageVersion returns the message version stored in the two most
+// significant bytes of the message nonce.
+func messageVersion(nonce *big.Int) uint64 {
+	return new(big.Int).Rsh(nonce, 240).Uint64()
+}
+
+// findMessageValue returns the value from the SentMessageExtension1 event
+// that was emitted by the same contract directly after the given
+// SentMessage event.
+func findMessageValue(l types.Log, receipt *types.TransactionReceiptType) (*big.Int, error) {
+	logIndex := l.LogIndex.Big().Uint64()
+	for _, r := range receipt.Logs {
+		if r.Address != l.Address || r.LogIndex.Big().Uint64() != logIndex+1 {
+			continue
+		}
+		if len(r.Topics) != 2 || r.Topics[0] != types.Hash(sentMessageExtension1Event.ID) {
+			break
+		}
+		values := map[string]interface{}{}
+		if err := sentMessageExtension1Event.Inputs.UnpackIntoMap(values, r.Data); err != nil {
+			return nil, fmt.Errorf("unable to unpack SentMessageExtension1 event: %w", err)
+		}
+		value, ok := values["value"].(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("invalid SentMessageExtension1 value field")
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("SentMessageExtension1 event not found for log %d in transaction %s", logIndex, l.TxHash.String())
+}
+
+// sentMessage is the SentMessage event:
+//
+//	event SentMessage(address indexed target, address sender, bytes message, uint256 messageNonce, uint256 gasLimit)
+type sentMessage struct {
+	target       common.Address
+	sender       common.Address
+	message      []byte
+	messageNonce *big.Int
+	gasLimit     *big.Int
+}
+
+func unpackSentMessage(l types.Log) (*sentMessage, error) {
+	values := map[string]interface{}{}
+	if err := sentMessageEvent.Inputs.UnpackIntoMap(values, l.Data); err != nil {
+		return nil, fmt.Errorf("unable to unpack SentMessage event: %w", err)
+	}
+	msg := &sentMessage{target: common.BytesToAddress(l.Topics[1].Bytes())}
+	var ok bool
+	if msg.sender, ok = values["sender"].(common.Address); !ok {
+		return nil, fmt.Errorf("invalid SentMessage sender field")
+	}
+	if msg.message, ok = values["message"].([]byte); !ok {
+		return nil, fmt.Errorf("invalid SentMessage message field")
+	}
+	if msg.messageNonce, ok = values["messageNonce"].(*big.Int); !ok {
+		return nil, fmt.Errorf("invalid SentMessage messageNonce field")
+	}
+	if msg.gasLimit, ok = values["gasLimit"].(*big.Int); !ok {
+		return nil, fmt.Errorf("invalid SentMessage gasLimit field")
+	}
+	return msg, nil
+}
+
+const sentMessageABI = `[{
+  "anonymous": false,
+  "inputs": [
+    {"indexed": true, "name": "target", "type": "address"},
+    {"indexed": false, "name": "sender", "type": "address"},
+    {"indexed": false, "name": "message", "type": "bytes"},
+    {"indexed": false, "name": "messageNonce", "type": "uint256"},
+    {"indexed": false, "name": "gasLimit", "type": "uint256"}
+  ],
+  "name": "SentMessage",
+  "type": "event"
+}, {
+  "anonymous": false,
+  "inputs": [
+    {"indexed": true, "name": "sender", "type": "address"},
+    {"indexed": false, "name": "value", "type": "uint256"}
+  ],
+  "name": "SentMessageExtension1",
+  "type": "event"
+}, {
+  "inputs": [
+    {"name": "_target", "type": "address"},
+    {"name": "_sender", "type": "address"},
+    {"name": "_message", "type": "bytes"},
+    {"name": "_messageNonce", "type": "uint256"}
+  ],
+  "name": "relayMessageV0",
+  "outputs": [],
+  "stateMutability": "nonpayable",
+  "type": "function"
+}, {
+  "inputs": [
+    {"name": "_nonce", "type": "uint256"},
+    {"name": "_sender", "type": "address"},
+    {"name": "_target", "type": "address"},
+    {"name": "_value", "type": "uint256"},
+    {"name": "_minGasLimit", "type": "uint256"},
+    {"name": "_message", "type": "bytes"}
+  ],
+  "name": "relayMessageV1",
+  "outputs": [],
+  "stateMutability": "payable",
+  "type": "function"
+}]`
+
+var sentMessageEvent abi.Event
+var sentMessageExtension1Event abi.Event
+var relayMessageV0 abi.Method
+var relayMessageV1 abi.Method
+
+func init() {
+	a, err := abi.JSON(strings.NewReader(sentMessageABI))
+	if err != nil {
+		panic(err)
+	}
+	sentMessageEvent = a.Events["SentMessage"]
+	sentMessageExtension1Event = a.Events["SentMessageExtension1"]
+	// Both versions of the relayMessage function have the same name in the
+	// CrossDomainMessenger contract, they are renamed here because the ABI
+	// does not support overloaded functions with the same name:
+	relayMessageV0 = abi.NewMethod("relayMessage", "relayMessage", abi.Function, "nonpayable", false, false, a.Methods["relayMessageV0"].Inputs, nil)
+	relayMessageV1 = abi.NewMethod("relayMessage", "relayMessage", abi.Function, "payable", false, true, a.Methods["relayMessageV1"].Inputs, nil)
+}
@@ -0,0 +1,201 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package optimism
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+type testLogContext struct {
+	timestamp time.Time
+	receipt   *types.TransactionReceiptType
+}
+
+func (lc testLogContext) BlockTimestamp() (time.Time, error) {
+	return lc.timestamp, nil
+}
+
+func (lc testLogContext) Receipt() (*types.TransactionReceiptType, error) {
+	if lc.receipt == nil {
+		return nil, errors.New("receipt not found")
+	}
+	return lc.receipt, nil
+}
+
+var (
+	testTarget = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	testSender = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	testTxHash = types.HexToHash("0x66b68b4e9f0c1e8a26b1b0f8d9b3a7a3d2c1e5f4a3b2c1d0e9f8a7b6c5d4e3f2")
+)
+
+func testSentMessageLog(t *testing.T, nonce *big.Int) types.Log {
+	data, err := sentMessageEvent.Inputs.NonIndexed().Pack(testSender, []byte{1, 2, 3}, nonce, big.NewInt(100000))
+	require.NoError(t, err)
+	return types.Log{
+		Address:  L2CrossDomainMessenger,
+		Topics:   []types.Hash{types.Hash(crypto.Keccak256Hash([]byte("SentMessage(address,address,bytes,uint256,uint256)"))), types.Hash(common.BytesToHash(testTarget.Bytes()))},
+		Data:     data,
+		TxHash:   testTxHash,
+		LogIndex: types.Uint64ToNumber(3),
+	}
+}
+
+func testSentMessageExtension1Log(t *testing.T, logIndex uint64, value *big.Int) types.Log {
+	data, err := sentMessageExtension1Event.Inputs.NonIndexed().Pack(value)
+	require.NoError(t, err)
+	return types.Log{
+		Address:  L2CrossDomainMessenger,
+		Topics:   []types.Hash{types.Hash(crypto.Keccak256Hash([]byte("SentMessageExtension1(address,uint256)"))), types.Hash(common.BytesToHash(testSender.Bytes()))},
+		Data:     data,
+		TxHash:   testTxHash,
+		LogIndex: types.Uint64ToNumber(logIndex),
+	}
+}
+
+func TestConverter(t *testing.T) {
+	l := testSentMessageLog(t, big.NewInt(42))
+	lc := testLogContext{timestamp: time.Unix(1600000000, 0)}
+
+	c := NewConverter()
+	assert.Equal(t, SentMessageEventType, c.EventType())
+	assert.Equal(t, l.Topics[0], c.Topic0())
+
+	evt, err := c.Convert(l, lc)
+	require.NoError(t, err)
+
+	// relayMessage(target, sender, message, messageNonce):
+	expHash := crypto.Keccak256(common.FromHex(
+		"cbd4ece9" +
+			"0000000000000000000000001111111111111111111111111111111111111111" +
+			"0000000000000000000000002222222222222222222222222222222222222222" +
+			"0000000000000000000000000000000000000000000000000000000000000080" +
+			"000000000000000000000000000000000000000000000000000000000000002a" +
+			"0000000000000000000000000000000000000000000000000000000000000003" +
+			"0102030000000000000000000000000000000000000000000000000000000000",
+	))
+	assert.Equal(t, SentMessageEventType, evt.Type)
+	assert.Equal(t, expHash, evt.Data["hash"])
+	assert.Equal(t, []byte(l.Data), evt.Data["event"])
+	assert.Equal(t, append(l.Topics[0].Bytes(), l.Topics[1].Bytes()...), evt.Data["topics"])
+	assert.Equal(t, l.TxHash.Bytes(), evt.Index)
+	assert.Equal(t, lc.timestamp, evt.EventDate)
+
+	// ID must be stable:
+	evt2, err := c.Convert(l, lc)
+	require.NoError(t, err)
+	assert.Equal(t, evt.ID, evt2.ID)
+
+	// Different log index must produce a different ID:
+	l.LogIndex = types.Uint64ToNumber(4)
+	evt3, err := c.Convert(l, lc)
+	require.NoError(t, err)
+	assert.NotEqual(t, evt.ID, evt3.ID)
+}
+
+func TestConverter_SameTransaction(t *testing.T) {
+	l1 := testSentMessageLog(t, big.NewInt(42))
+	l2 := testSentMessageLog(t, big.NewInt(43))
+	l2.LogIndex = types.Uint64ToNumber(4)
+	lc := testLogContext{timestamp: time.Unix(1600000000, 0)}
+
+	c := NewConverter()
+	evt1, err := c.Convert(l1, lc)
+	require.NoError(t, err)
+	evt2, err := c.Convert(l2, lc)
+	require.NoError(t, err)
+
+	// Both messages are indexed by the transaction hash, clients tell them
+	// apart by the hash field:
+	assert.Equal(t, evt1.Index, evt2.Index)
+	assert.NotEqual(t, evt1.ID, evt2.ID)
+	assert.NotEqual(t, evt1.Data["hash"], evt2.Data["hash"])
+}
+
+func TestConverter_Version1(t *testing.T) {
+	nonce := new(big.Int).Or(new(big.Int).Lsh(big.NewInt(1), 240), big.NewInt(42))
+	l := testSentMessageLog(t, nonce)
+	lc := testLogContext{
+		timestamp: time.Unix(1600000000, 0),
+		receipt: &types.TransactionReceiptType{
+			TransactionHash: testTxHash,
+			Logs: []types.Log{
+				testSentMessageExtension1Log(t, 1, big.NewInt(5)), // Belongs to another message.
+				l,
+				testSentMessageExtension1Log(t, 4, big.NewInt(7)),
+			},
+		},
+	}
+
+	evt, err := NewConverter().Convert(l, lc)
+	require.NoError(t, err)
+
+	// relayMessage(messageNonce, sender, target, value, gasLimit, message):
+	expHash := crypto.Keccak256(common.FromHex(
+		"d764ad0b" +
+			"000100000000000000000000000000000000000000000000000000000000002a" +
+			"0000000000000000000000002222222222222222222222222222222222222222" +
+			"0000000000000000000000001111111111111111111111111111111111111111" +
+			"0000000000000000000000000000000000000000000000000000000000000007" +
+			"00000000000000000000000000000000000000000000000000000000000186a0" +
+			"00000000000000000000000000000000000000000000000000000000000000c0" +
+			"0000000000000000000000000000000000000000000000000000000000000003" +
+			"0102030000000000000000000000000000000000000000000000000000000000",
+	))
+	assert.Equal(t, expHash, evt.Data["hash"])
+	assert.Equal(t, lc.timestamp, evt.EventDate)
+}
+
+func TestConverter_Version1WithoutExtension(t *testing.T) {
+	nonce := new(big.Int).Or(new(big.Int).Lsh(big.NewInt(1), 240), big.NewInt(42))
+	l := testSentMessageLog(t, nonce)
+	c := NewConverter()
+
+	// Receipt is not available:
+	_, err := c.Convert(l, testLogContext{})
+	assert.Error(t, err)
+
+	// SentMessageExtension1 event is missing:
+	_, err = c.Convert(l, testLogContext{receipt: &types.TransactionReceiptType{Logs: []types.Log{l}}})
+	assert.Error(t, err)
+}
+
+func TestConverter_UnsupportedVersion(t *testing.T) {
+	nonce := new(big.Int).Lsh(big.NewInt(2), 240)
+	_, err := NewConverter().Convert(testSentMessageLog(t, nonce), testLogContext{})
+	assert.Error(t, err)
+}
+
+func TestConverter_InvalidLog(t *testing.T) {
+	c := NewConverter()
+
+	// Invalid topic:
+	_, err := c.Convert(types.Log{Topics: []types.Hash{{}, {}}}, testLogContext{})
+	assert.Error(t, err)
+
+	// Invalid data:
+	_, err = c.Convert(types.Log{Topics: []types.Hash{c.Topic0(), {}}, Data: []byte{1}}, testLogContext{})
+	assert.Error(t, err)
+}
@@ -28,6 +28,25 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
+// teleportConverter is the default LogConverter that converts TeleportGUID
+// events.
+type teleportConverter struct{}
+
+// EventType implements the LogConverter interface.
+func (teleportConverter) EventType() string {
+	return TeleportEventType
+}
+
+// Topic0 implements the LogConverter interface.
+func (teleportConverter) Topic0() types.Hash {
+	return teleportTopic0
+}
+
+// Convert implements the LogConverter interface.
+func (teleportConverter) Convert(l types.Log, _ LogContext) (*messages.Event, error) {
+	return logToMessage(l)
+}
+
 // logToMessage converts a TeleportGUID event to a transport message.
 func logToMessage(l types.Log) (*messages.Event, error) {
 	guid, err := unpackTeleportGUID(l.Data)
@@ -0,0 +1,107 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package teleportevm
+
+import (
+	"sync"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+// blockHeader contains the part of the block header required to detect
+// chain reorganizations.
+type blockHeader struct {
+	number     uint64
+	hash       types.Hash
+	parentHash types.Hash
+}
+
+// headerTracker keeps a contiguous list of recent block headers. It is used
+// to detect chain reorganizations and to verify that logs belong to blocks
+// on the canonical chain.
+type headerTracker struct {
+	mu      sync.RWMutex
+	headers []blockHeader // Sorted by block number, without gaps.
+}
+
+// last returns the header of the most recent tracked block.
+func (h *headerTracker) last() (blockHeader, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.headers) == 0 {
+		return blockHeader{}, false
+	}
+	return h.headers[len(h.headers)-1], true
+}
+
+// hash returns the hash of the block with the given number. If the block is
+// not tracked, false is returned as a second value.
+func (h *headerTracker) hash(number uint64) (types.Hash, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.headers) == 0 || number < h.headers[0].number {
+		return types.Hash{}, false
+	}
+	idx := number - h.headers[0].number
+	if idx >= uint64(len(h.headers)) {
+		return types.Hash{}, false
+	}
+	return h.headers[idx].hash, true
+}
+
+// append adds the header of the next block. The header must directly follow
+// the last tracked block, otherwise all tracked headers are replaced.
+func (h *headerTracker) append(hdr blockHeader) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.headers) > 0 && h.headers[len(h.headers)-1].number+1 != hdr.number {
+		h.headers = nil
+	}
+	h.headers = append(h.headers, hdr)
+}
+
+// truncate removes headers of blocks with a number greater than or equal to
+// the given one.
+func (h *headerTracker) truncate(number uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for len(h.headers) > 0 && h.headers[len(h.headers)-1].number >= number {
+		h.headers = h.headers[:len(h.headers)-1]
+	}
+}
+
+// prune removes headers of blocks with a number less than the given one.
+func (h *headerTracker) prune(number uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for len(h.headers) > 0 && h.headers[0].number < number {
+		h.headers = h.headers[1:]
+	}
+}
+
+// verify checks if logs were emitted in blocks on the tracked chain. Logs
+// from blocks that are not tracked are considered valid. If a log from
+// a different block is found, its block number and false are returned.
+func (h *headerTracker) verify(logs []types.Log) (uint64, bool) {
+	for _, l := range logs {
+		number := l.BlockNumber.Big().Uint64()
+		hash, ok := h.hash(number)
+		if ok && hash != l.BlockHash {
+			return number, false
+		}
+	}
+	return 0, true
+}
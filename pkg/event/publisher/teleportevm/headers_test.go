@@ -0,0 +1,104 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package teleportevm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+func testHeader(number uint64, fork byte) blockHeader {
+	return blockHeader{
+		number:     number,
+		hash:       types.BytesToHash([]byte{fork, byte(number)}),
+		parentHash: types.BytesToHash([]byte{fork, byte(number - 1)}),
+	}
+}
+
+func Test_headerTracker(t *testing.T) {
+	h := &headerTracker{}
+	_, ok := h.last()
+	assert.False(t, ok)
+
+	for n := uint64(10); n <= 15; n++ {
+		h.append(testHeader(n, 1))
+	}
+	last, ok := h.last()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(15), last.number)
+
+	hash, ok := h.hash(12)
+	assert.True(t, ok)
+	assert.Equal(t, testHeader(12, 1).hash, hash)
+	_, ok = h.hash(9)
+	assert.False(t, ok)
+	_, ok = h.hash(16)
+	assert.False(t, ok)
+
+	h.truncate(14)
+	last, _ = h.last()
+	assert.Equal(t, uint64(13), last.number)
+
+	h.prune(12)
+	_, ok = h.hash(11)
+	assert.False(t, ok)
+	_, ok = h.hash(12)
+	assert.True(t, ok)
+
+	// Appending a header that does not follow the last one resets the tracker.
+	h.append(testHeader(20, 1))
+	_, ok = h.hash(12)
+	assert.False(t, ok)
+	_, ok = h.hash(20)
+	assert.True(t, ok)
+}
+
+func Test_headerTracker_verify(t *testing.T) {
+	h := &headerTracker{}
+	for n := uint64(10); n <= 15; n++ {
+		h.append(testHeader(n, 1))
+	}
+	tests := []struct {
+		logs   []types.Log
+		number uint64
+		valid  bool
+	}{
+		{logs: nil, valid: true},
+		{logs: []types.Log{{BlockNumber: types.Uint64ToNumber(12), BlockHash: testHeader(12, 1).hash}}, valid: true},
+		// Block is not tracked:
+		{logs: []types.Log{{BlockNumber: types.Uint64ToNumber(5), BlockHash: testHeader(5, 2).hash}}, valid: true},
+		// Block from a different fork:
+		{
+			logs: []types.Log{
+				{BlockNumber: types.Uint64ToNumber(12), BlockHash: testHeader(12, 1).hash},
+				{BlockNumber: types.Uint64ToNumber(13), BlockHash: testHeader(13, 2).hash},
+			},
+			number: 13,
+			valid:  false,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			number, valid := h.verify(tt.logs)
+			assert.Equal(t, tt.valid, valid)
+			assert.Equal(t, tt.number, number)
+		})
+	}
+}
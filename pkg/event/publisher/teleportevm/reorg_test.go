@@ -0,0 +1,228 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package teleportevm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+)
+
+var errNotImplemented = errors.New("not implemented")
+
+// forkClient is an ethereumv2.Client that simulates a blockchain with forks.
+type forkClient struct {
+	// Client is embedded to satisfy the methods that are not used by the
+	// publisher. Calling them panics.
+	ethereumv2.Client
+
+	mu     sync.Mutex
+	blocks []types.Block              // Canonical chain.
+	logs   map[types.Hash][]types.Log // Logs indexed by block hash.
+
+	// lagging, if not nil, is the client used by FilterLogs instead of the
+	// canonical chain. It simulates a node that did not process a reorg yet.
+	lagging *forkClient
+}
+
+func newForkClient(length int) *forkClient {
+	c := &forkClient{logs: map[types.Hash][]types.Log{}}
+	c.fork(0, 0, length, nil)
+	return c
+}
+
+// fork replaces blocks starting from the given block with new blocks from
+// the given fork, so the chain has the given length. The txs map contains
+// transaction hashes of teleport logs indexed by block numbers.
+func (c *forkClient) fork(at uint64, fork byte, length int, txs map[uint64]types.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blocks = c.blocks[:at]
+	for n := at; n < uint64(length); n++ {
+		b := types.Block{
+			Number: types.Uint64ToNumber(n),
+			Hash:   types.BytesToHash([]byte{fork, byte(n >> 8), byte(n)}),
+		}
+		if n > 0 {
+			b.ParentHash = c.blocks[n-1].Hash
+		}
+		c.blocks = append(c.blocks, b)
+		if tx, ok := txs[n]; ok {
+			c.logs[b.Hash] = append(c.logs[b.Hash], types.Log{
+				Address:     teleportTestAddress,
+				Data:        teleportTestGUID,
+				BlockHash:   b.Hash,
+				BlockNumber: b.Number,
+				TxHash:      tx,
+			})
+		}
+	}
+}
+
+// setLagging makes FilterLogs use logs from the given client. If nil,
+// logs from the canonical chain are used.
+func (c *forkClient) setLagging(lagging *forkClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lagging = lagging
+}
+
+func (c *forkClient) BlockNumber(_ context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.blocks) - 1), nil
+}
+
+func (c *forkClient) BlockByNumber(_ context.Context, number types.BlockNumber) (*types.BlockTxHashes, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := number.Big().Uint64()
+	if n >= uint64(len(c.blocks)) {
+		return nil, errors.New("block not found")
+	}
+	return &types.BlockTxHashes{Block: c.blocks[n]}, nil
+}
+
+func (c *forkClient) FullBlockByNumber(_ context.Context, _ types.BlockNumber) (*types.BlockTxObjects, error) {
+	return nil, errNotImplemented
+}
+
+func (c *forkClient) GetTransactionCount(_ context.Context, _ types.Address, _ types.BlockNumber) (uint64, error) {
+	return 0, errNotImplemented
+}
+
+func (c *forkClient) SendRawTransaction(_ context.Context, _ types.Bytes) (*types.Hash, error) {
+	return nil, errNotImplemented
+}
+
+func (c *forkClient) GetStorageAt(_ context.Context, _ types.Address, _ types.Hash, _ types.BlockNumber) (*types.Hash, error) {
+	return nil, errNotImplemented
+}
+
+func (c *forkClient) FilterLogs(ctx context.Context, q types.FilterLogsQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	lagging := c.lagging
+	c.mu.Unlock()
+	if lagging != nil {
+		return lagging.FilterLogs(ctx, q)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	logs := []types.Log{}
+	for n := q.FromBlock.Big().Uint64(); n <= q.ToBlock.Big().Uint64() && n < uint64(len(c.blocks)); n++ {
+		logs = append(logs, c.logs[c.blocks[n].Hash]...)
+	}
+	return logs, nil
+}
+
+func newForkTestProvider(t *testing.T, cli *forkClient, blockConfirmations uint64) *EventProvider {
+	ep, err := New(Config{
+		Client:             cli,
+		Addresses:          types.Addresses{teleportTestAddress},
+		Interval:           50 * time.Millisecond,
+		BlockLimit:         100,
+		BlockConfirmations: blockConfirmations,
+		Logger:             null.New(),
+	})
+	require.NoError(t, err)
+	ep.disablePrefetchEventsRoutine = true
+	return ep
+}
+
+// waitForHeader waits until the provider fetches the header of the given
+// block.
+func waitForHeader(t *testing.T, ep *EventProvider, number uint64) {
+	require.Eventually(t, func() bool {
+		last, ok := ep.headers.last()
+		return ok && last.number >= number
+	}, time.Second, 10*time.Millisecond)
+}
+
+// nextEventIndex returns the index of the next event or nil if there is no
+// event within the given time.
+func nextEventIndex(ep *EventProvider, timeout time.Duration) []byte {
+	select {
+	case evt := <-ep.Events():
+		return evt.Index
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func Test_teleportEventProvider_LogsFromOrphanedBlock(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	txA := types.HexToHash("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	txB := types.HexToHash("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+
+	cli := newForkClient(11)
+	ep := newForkTestProvider(t, cli, 2)
+	require.NoError(t, ep.Start(ctx))
+	waitForHeader(t, ep, 10)
+
+	// The node used to fetch logs still sees the old fork with the txA log,
+	// but blocks from 11 were replaced by a new fork with the txB log.
+	old := newForkClient(11)
+	old.fork(11, 0, 15, map[uint64]types.Hash{11: txA})
+	cli.setLagging(old)
+	cli.fork(11, 1, 15, map[uint64]types.Hash{11: txB})
+
+	// The log from the orphaned block must not be sent.
+	assert.Nil(t, nextEventIndex(ep, 300*time.Millisecond))
+
+	// After the node catches up, the log from the new fork is sent.
+	cli.setLagging(nil)
+	assert.Equal(t, txB.Bytes(), nextEventIndex(ep, time.Second))
+	assert.Nil(t, nextEventIndex(ep, 200*time.Millisecond))
+}
+
+func Test_teleportEventProvider_DeepReorg(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	txA := types.HexToHash("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	txB := types.HexToHash("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+
+	cli := newForkClient(11)
+	ep := newForkTestProvider(t, cli, 1)
+	require.NoError(t, ep.Start(ctx))
+	waitForHeader(t, ep, 10)
+
+	// The block 11 is confirmed when the block 12 is mined.
+	cli.fork(11, 0, 13, map[uint64]types.Hash{11: txA})
+	assert.Equal(t, txA.Bytes(), nextEventIndex(ep, time.Second))
+	waitForHeader(t, ep, 12)
+
+	// Reorg deeper than the number of block confirmations replaces the
+	// already processed block 11. Logs from the new fork must be sent.
+	cli.fork(11, 1, 15, map[uint64]types.Hash{11: txB})
+	assert.Equal(t, txB.Bytes(), nextEventIndex(ep, time.Second))
+	assert.Nil(t, nextEventIndex(ep, 200*time.Millisecond))
+
+	hash, ok := ep.headers.hash(11)
+	require.True(t, ok)
+	assert.Equal(t, types.BytesToHash([]byte{1, 0, 11}), hash)
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/eip712"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
@@ -30,9 +31,15 @@ const SignatureKey = "ethereum"
 // value of that field is used to calculate the signature. The rest of the
 // fields in the data are ignored. The calculated signature is stored in the
 // "ethereum" field of the event's signatures map.
+//
+// If the signer is created using NewEIP712Signer, the hash is signed as
+// EIP-712 typed data in the given domain instead, and the signature is stored
+// in the "eip712" field. This prevents the signature from being valid in any
+// other context.
 type Signer struct {
 	signer ethereum.Signer
 	types  []string
+	domain *eip712.Domain
 }
 
 // NewSigner returns a new instance of the Signer struct.
@@ -40,6 +47,19 @@ func NewSigner(signer ethereum.Signer, types []string) *Signer {
 	return &Signer{signer: signer, types: types}
 }
 
+// NewEIP712Signer returns a new instance of the Signer struct that signs
+// events using EIP-712 typed data. The signer must implement the
+// ethereum.TypedDataSigner interface.
+func NewEIP712Signer(signer ethereum.Signer, types []string, domain eip712.Domain) (*Signer, error) {
+	if _, ok := signer.(ethereum.TypedDataSigner); !ok {
+		return nil, errors.New("signer does not support EIP-712 typed data")
+	}
+	if err := domain.Validate(); err != nil {
+		return nil, err
+	}
+	return &Signer{signer: signer, types: types, domain: &domain}, nil
+}
+
 // Sign implements the publisher.EventSigner interface.
 func (l *Signer) Sign(event *messages.Event) (bool, error) {
 	supports := false
@@ -59,16 +79,32 @@ func (l *Signer) Sign(event *messages.Event) (bool, error) {
 	if !ok {
 		return false, errors.New("missing hash field")
 	}
-	s, err := l.signer.Signature(h)
+	key := SignatureKey
+	var s ethereum.Signature
+	var err error
+	if l.domain != nil {
+		key = eip712.SignatureKey
+		s, err = l.signTypedData(event)
+	} else {
+		s, err = l.signer.Signature(h)
+	}
 	if err != nil {
 		return false, err
 	}
 	if event.Signatures == nil {
 		event.Signatures = map[string]messages.EventSignature{}
 	}
-	event.Signatures[SignatureKey] = messages.EventSignature{
+	event.Signatures[key] = messages.EventSignature{
 		Signer:    l.signer.Address().Bytes(),
 		Signature: s.Bytes(),
 	}
 	return true, nil
 }
+
+func (l *Signer) signTypedData(event *messages.Event) (ethereum.Signature, error) {
+	td, err := eip712.TypedData(*l.domain, event)
+	if err != nil {
+		return ethereum.Signature{}, err
+	}
+	return l.signer.(ethereum.TypedDataSigner).SignTypedData(td)
+}
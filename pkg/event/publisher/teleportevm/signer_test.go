@@ -24,6 +24,8 @@ import (
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/eip712"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
@@ -67,3 +69,39 @@ func TestSigner_Sign(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, address, *recovered)
 }
+
+func TestSigner_SignEIP712(t *testing.T) {
+	address := common.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	account, err := geth.NewAccount("./keystore", "test123", address)
+	require.NoError(t, err)
+	gethSigner := geth.NewSigner(account)
+	domain := eip712.Domain{Name: "Teleport", Version: "1", ChainID: 1}
+	msg := &messages.Event{Type: "foo", Data: map[string][]byte{"hash": common.HexToHash("f76b84eff86432f629ab567880256b50c8eb31cafaec58c5edb24d9b4c246470").Bytes()}}
+	signer, err := NewEIP712Signer(gethSigner, []string{"foo"}, domain)
+	require.NoError(t, err)
+
+	ok, err := signer.Sign(msg)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	// Only the EIP-712 signature must be added:
+	require.Len(t, msg.Signatures, 1)
+	assert.Equal(t, msg.Signatures[eip712.SignatureKey].Signer, address.Bytes())
+
+	// Verify signature:
+	td, err := eip712.TypedData(domain, msg)
+	require.NoError(t, err)
+	recovered, err := gethSigner.RecoverTypedData(ethereum.SignatureFromBytes(msg.Signatures[eip712.SignatureKey].Signature), td)
+	require.NoError(t, err)
+	assert.Equal(t, address, *recovered)
+}
+
+func TestNewEIP712Signer_Errors(t *testing.T) {
+	// Empty domain:
+	_, err := NewEIP712Signer(geth.NewSigner(nil), []string{"foo"}, eip712.Domain{})
+	assert.ErrorIs(t, err, eip712.ErrEmptyDomain)
+
+	// Signer without EIP-712 support:
+	_, err = NewEIP712Signer(struct{ ethereum.Signer }{&mocks.Signer{}}, []string{"foo"}, eip712.Domain{Name: "foo"})
+	assert.Error(t, err)
+}
@@ -16,12 +16,19 @@
 package teleportevm
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
+
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/checkpoint"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -45,11 +52,24 @@ type Config struct {
 	Client ethereumv2.Client
 	// Addresses is a list of contracts from which logs will be fetched.
 	Addresses []types.Address
+	// Converter converts logs into event messages. If nil, TeleportGUID
+	// events are used.
+	Converter LogConverter
 	// Interval specifies how often provider should check for new logs.
 	Interval time.Duration
 	// PrefetchPeriod specifies how far back in time provider should prefetch
-	// logs. It is used only during the initial start of the provider.
+	// logs. It is used only during the initial start of the provider for
+	// addresses without a checkpoint.
 	PrefetchPeriod time.Duration
+	// Checkpoint is an optional store used to record the last fully
+	// processed block for every address. If a checkpoint exists for an
+	// address, the provider resumes from it instead of prefetching logs.
+	Checkpoint checkpoint.Store
+	// Chain identifies the chain in checkpoint keys, so providers for
+	// different chains may share one checkpoint store even if they listen
+	// to the same addresses. If empty, the chain ID returned by the client
+	// is used.
+	Chain string
 	// BlockLimit specifies how from many blocks logs can be fetched at once.
 	BlockLimit uint64
 	// BlockConfirmations specifies how many blocks should be confirmed before
@@ -59,6 +79,62 @@ type Config struct {
 	Logger log.Logger
 }
 
+// LogConverter converts Ethereum logs into event messages.
+type LogConverter interface {
+	// EventType returns the type of events created by the converter.
+	EventType() string
+	// Topic0 returns the first topic of logs to be converted, which is the
+	// hash of the event signature.
+	Topic0() types.Hash
+	// Convert converts a log into an event message. The log context may be
+	// used to fetch data related to the log that is not part of the log.
+	Convert(l types.Log, lc LogContext) (*messages.Event, error)
+}
+
+// LogContext provides data related to a log that is not part of the log
+// itself. The data is fetched from the node only when requested.
+type LogContext interface {
+	// BlockTimestamp returns the timestamp of the block in which the log
+	// was emitted.
+	BlockTimestamp() (time.Time, error)
+	// Receipt returns the receipt of the transaction that emitted the log.
+	Receipt() (*types.TransactionReceiptType, error)
+}
+
+// NewLogEvent returns an event message of the given type created from the
+// log. It may be used by LogConverter implementations.
+//
+// The message is indexed by the transaction hash, so all messages created
+// from logs emitted in the same transaction share the same index and are
+// returned together by the event API. Clients must use the "hash" data field
+// to find the message they need. The ID is the hash of the transaction hash
+// and the log index, hashed again so that it is not misused as any other
+// field. It is intended to be used only by the event store.
+//
+// Created messages contain the following data fields:
+//   - "hash" - the given hash, used to calculate the signature,
+//   - "event" - non-indexed event data,
+//   - "topics" - concatenated log topics.
+func NewLogEvent(l types.Log, typ string, hash []byte, date time.Time) *messages.Event {
+	var topics bytes.Buffer
+	for _, t := range l.Topics {
+		topics.Write(t.Bytes())
+	}
+	return &messages.Event{
+		Type:        typ,
+		ID:          crypto.Keccak256Hash(append(l.TxHash.Bytes(), l.LogIndex.Big().Bytes()...)).Bytes(),
+		Index:       l.TxHash.Bytes(),
+		EventDate:   date,
+		MessageDate: time.Now(),
+		Data: map[string][]byte{
+			"hash":   hash,
+			"event":  l.Data,
+			"topics": topics.Bytes(),
+		},
+		Signatures: map[string]messages.EventSignature{},
+	}
+}
+
 // EventProvider listens to TeleportGUID events on Ethereum compatible
 // blockchains.
 //
@@ -68,25 +144,53 @@ type Config struct {
 // converts them into messages.Event and sends them to the channel provided
 // by Events method.
 //
+// A different LogConverter may be provided to listen to other events.
+//
 // During the initial start of the provider it also fetches older blocks
 // until it reaches the block that is older than the prefetch period. This is
 // done to fetch events that were emitted before the provider was started.
 //
+// If the checkpoint store is configured, the provider records the last fully
+// processed block for every address. After a restart, logs are fetched
+// starting from the block after the checkpoint, and the prefetch period is
+// used only for addresses without a checkpoint.
+//
+// To avoid signing logs from orphaned blocks, the provider tracks hashes of
+// recent blocks and detects chain reorganizations by comparing the parent
+// hash of every new block with the hash of the previous one. Logs that do not
+// belong to the tracked chain are never sent, instead the block range is
+// fetched again. If a reorganization replaces blocks whose logs were already
+// sent, the provider fetches logs from the new blocks again.
+//
 // In the event of an error in communication with a node, whether related to
 // network errors or the node itself, the provider will try to repeat requests
 // to the node indefinitely.
 type EventProvider struct {
+	mu      sync.Mutex
 	eventCh chan *messages.Event
 
 	// Configuration parameters copied from Config:
 	client         ethereumv2.Client
 	addresses      []types.Address
+	converter      LogConverter
 	interval       time.Duration
 	prefetchPeriod time.Duration
+	checkpoint     checkpoint.Store
+	chain          string
 	blockLimit     uint64
 	blockConfirms  uint64
 	log            log.Logger
 
+	// headers contains hashes of recent blocks used to detect chain
+	// reorganizations.
+	headers *headerTracker
+
+	// prefetching contains addresses for which the prefetch routine is
+	// still running. Checkpoints for these addresses are not updated until
+	// the prefetch is finished, otherwise a restart during the prefetch would
+	// skip older blocks.
+	prefetching map[types.Address]bool
+
 	// Used in tests only:
 	disablePrefetchEventsRoutine bool
 	disableFetchEventsRoutine    bool
@@ -103,6 +207,9 @@ func New(cfg Config) (*EventProvider, error) {
 	if cfg.BlockLimit <= 0 {
 		return nil, errors.New("block limit must be greater than 0")
 	}
+	if cfg.Converter == nil {
+		cfg.Converter = teleportConverter{}
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = null.New()
 	}
@@ -111,10 +218,15 @@ func New(cfg Config) (*EventProvider, error) {
 		client:         cfg.Client,
 		interval:       cfg.Interval,
 		addresses:      cfg.Addresses,
+		converter:      cfg.Converter,
 		prefetchPeriod: cfg.PrefetchPeriod,
+		checkpoint:     cfg.Checkpoint,
+		chain:          cfg.Chain,
 		blockLimit:     cfg.BlockLimit,
 		blockConfirms:  cfg.BlockConfirmations,
 		log:            cfg.Logger.WithField("tag", LoggerTag),
+		headers:        &headerTracker{},
+		prefetching:    map[types.Address]bool{},
 	}, nil
 }
 
@@ -125,22 +237,77 @@ func (ep *EventProvider) Events() chan *messages.Event {
 
 // Start implements the publisher.EventPublisher interface.
 func (ep *EventProvider) Start(ctx context.Context) error {
-	if !ep.disablePrefetchEventsRoutine {
-		go ep.prefetchEventsRoutine(ctx)
+	go ep.startRoutines(ctx)
+	return nil
+}
+
+// startRoutines loads checkpoints and starts the prefetch and fetch
+// routines. Loading checkpoints may require the chain ID, which is fetched
+// from the node, so this method is run in a separate goroutine.
+func (ep *EventProvider) startRoutines(ctx context.Context) {
+	if ep.checkpoint != nil && ep.chain == "" {
+		chainID, ok := ep.getChainID(ctx)
+		if !ok {
+			return // Context was canceled.
+		}
+		ep.mu.Lock()
+		ep.chain = strconv.FormatUint(chainID, 10)
+		ep.mu.Unlock()
+	}
+	checkpoints := ep.loadCheckpoints(ctx)
+	var prefetch []types.Address
+	for _, address := range ep.addresses {
+		if _, ok := checkpoints[address]; !ok {
+			prefetch = append(prefetch, address)
+		}
+	}
+	if !ep.disablePrefetchEventsRoutine && ep.prefetchPeriod > 0 && len(prefetch) > 0 {
+		ep.mu.Lock()
+		for _, address := range prefetch {
+			ep.prefetching[address] = true
+		}
+		ep.mu.Unlock()
+		go ep.prefetchEventsRoutine(ctx, prefetch)
 	}
 	if !ep.disableFetchEventsRoutine {
-		go ep.fetchEventsRoutine(ctx)
+		go ep.fetchEventsRoutine(ctx, checkpoints)
 	}
-	return nil
+}
+
+// loadCheckpoints returns the last fully processed blocks for addresses
+// that have a checkpoint.
+func (ep *EventProvider) loadCheckpoints(ctx context.Context) map[types.Address]uint64 {
+	checkpoints := map[types.Address]uint64{}
+	if ep.checkpoint == nil {
+		return checkpoints
+	}
+	for _, address := range ep.addresses {
+		block, ok, err := ep.checkpoint.Get(ctx, checkpointKey(ep.converter.EventType(), ep.chain, address))
+		if err != nil {
+			ep.log.
+				WithError(err).
+				WithField("address", address.String()).
+				Error("Unable to load checkpoint")
+			continue
+		}
+		if ok {
+			checkpoints[address] = block
+		}
+	}
+	return checkpoints
 }
 
 // prefetchEventsRoutine fetches events from older blocks until it reaches the
 // block that is older than the prefetch period. This is done to fetch events
 // that were emitted before the provider was started.
-func (ep *EventProvider) prefetchEventsRoutine(ctx context.Context) {
-	if ep.prefetchPeriod == 0 {
-		return
-	}
+func (ep *EventProvider) prefetchEventsRoutine(ctx context.Context, addresses []types.Address) {
+	defer func() {
+		ep.mu.Lock()
+		for _, address := range addresses {
+			delete(ep.prefetching, address)
+		}
+		ep.mu.Unlock()
+	}()
 	latestBlock, ok := ep.getBlockNumber(ctx)
 	if !ok {
 		return // Context was canceled.
@@ -155,7 +322,9 @@ func (ep *EventProvider) prefetchEventsRoutine(ctx context.Context) {
 			from = latestBlock - (d + ep.blockLimit - 1)
 		}
 		to = latestBlock - d
-		ep.handleEvents(ctx, from, to)
+		if !ep.handleEvents(ctx, addresses, from, to) {
+			return // Context was canceled.
+		}
 		ts, ok := ep.getBlockTimestamp(ctx, to)
 		if !ok {
 			return // Context was canceled.
@@ -167,12 +336,19 @@ func (ep *EventProvider) prefetchEventsRoutine(ctx context.Context) {
 }
 
 // fetchEventsRoutine periodically fetches new TeleportGUID logs from the
-// blockchain.
-func (ep *EventProvider) fetchEventsRoutine(ctx context.Context) {
+// blockchain. Before that, it fetches logs from blocks between the checkpoints
+// and the latest block.
+func (ep *EventProvider) fetchEventsRoutine(ctx context.Context, checkpoints map[types.Address]uint64) {
 	latestBlock, ok := ep.getBlockNumber(ctx)
 	if !ok {
 		return // Context was canceled.
 	}
+	if !ep.resumeFromCheckpoints(ctx, checkpoints, latestBlock) {
+		return // Context was canceled.
+	}
+	if _, ok := ep.syncHeaders(ctx, subUint64(latestBlock+1, ep.blockConfirms), latestBlock); !ok {
+		return // Context was canceled.
+	}
 	t := time.NewTicker(ep.interval)
 	defer t.Stop()
 	for {
@@ -187,20 +363,170 @@ func (ep *EventProvider) fetchEventsRoutine(ctx context.Context) {
 			if currentBlock <= latestBlock {
 				continue // There is no new blocks.
 			}
+			reorgBlock, ok := ep.syncHeaders(ctx, subUint64(latestBlock+1, ep.blockConfirms), currentBlock)
+			if !ok {
+				return // Context was canceled.
+			}
+			if reorgBlock > 0 && reorgBlock+ep.blockConfirms <= latestBlock {
+				// Logs from the replaced blocks were already sent. Logs from
+				// the new blocks must be fetched again.
+				ep.log.
+					WithFields(log.Fields{
+						"block":              reorgBlock,
+						"blockConfirmations": ep.blockConfirms,
+					}).
+					Error("Chain reorganization deeper than block confirmations, some events may have been signed in orphaned blocks")
+				latestBlock = reorgBlock + ep.blockConfirms - 1
+			}
+		ranges:
 			for _, b := range splitBlockRanges(latestBlock+1, currentBlock, ep.blockLimit) {
 				from := b[0] - ep.blockConfirms
 				to := b[1] - ep.blockConfirms
-				ep.handleEvents(ctx, from, to)
+				logs, ok := ep.fetchLogs(ctx, ep.addresses, from, to)
+				if !ok {
+					return // Context was canceled.
+				}
+				if number, ok := ep.headers.verify(logs); !ok {
+					// The block was replaced after its header was fetched.
+					// Headers will be fetched again during the next tick.
+					ep.log.
+						WithField("block", number).
+						Warn("Received logs from an orphaned block, logs will be fetched again")
+					ep.headers.truncate(number)
+					break ranges
+				}
+				if !ep.sendLogs(ctx, logs) {
+					return // Context was canceled.
+				}
+				ep.saveCheckpoints(ctx, ep.addresses, to)
+				latestBlock = b[1]
 			}
-			latestBlock = currentBlock
 		}
 	}
 }
 
-// handleEvents fetches TeleportGUID events from the given block range and
-// sends them to the eventCh channel.
-func (ep *EventProvider) handleEvents(ctx context.Context, from, to uint64) {
+// syncHeaders fetches headers of blocks up to the given block and adds them
+// to the header tracker. If the parent hash of a block does not match the hash
+// of the previous block, the previous block was replaced by a chain
+// reorganization, and its header is fetched again.
+//
+// It returns the lowest block number replaced by a chain reorganization or 0
+// if there was no reorganization. The second return value is false if the
+// context was canceled.
+func (ep *EventProvider) syncHeaders(ctx context.Context, from, to uint64) (uint64, bool) {
+	// Older blocks are not tracked to avoid fetching a large number of
+	// headers after a long outage.
+	if limit := ep.blockConfirms + ep.blockLimit; to-from+1 > limit {
+		from = to - limit + 1
+	}
+	start := from
+	if last, ok := ep.headers.last(); ok && last.number+1 >= from {
+		start = last.number + 1
+	}
+	var reorgBlock uint64
+	for n := start; n <= to; {
+		hdr, ok := ep.getBlockHeader(ctx, n)
+		if !ok {
+			return 0, false // Context was canceled.
+		}
+		if prev, ok := ep.headers.last(); ok && prev.number+1 == n && prev.hash != hdr.parentHash {
+			ep.headers.truncate(prev.number)
+			reorgBlock = prev.number
+			n = prev.number
+			continue
+		}
+		ep.headers.append(hdr)
+		n++
+	}
+	if reorgBlock > 0 {
+		ep.log.
+			WithFields(log.Fields{
+				"block": reorgBlock,
+				"depth": to - reorgBlock + 1,
+			}).
+			Warn("Chain reorganization detected")
+	}
+	// Headers of blocks below the processed range are kept to detect
+	// reorganizations deeper than the number of block confirmations.
+	ep.headers.prune(subUint64(from, ep.blockConfirms))
+	return reorgBlock, true
+}
+
+// resumeFromCheckpoints fetches logs from blocks after the checkpoints up to
+// the latest confirmed block. It returns false if the context was canceled.
+func (ep *EventProvider) resumeFromCheckpoints(
+	ctx context.Context,
+	checkpoints map[types.Address]uint64,
+	latestBlock uint64,
+) bool {
+
+	if latestBlock < ep.blockConfirms {
+		return true
+	}
 	for _, address := range ep.addresses {
+		block, ok := checkpoints[address]
+		if !ok {
+			continue
+		}
+		ep.log.
+			WithFields(log.Fields{
+				"address":    address.String(),
+				"checkpoint": block,
+			}).
+			Info("Resuming from checkpoint")
+		addresses := []types.Address{address}
+		for _, b := range splitBlockRanges(block+1, latestBlock-ep.blockConfirms, ep.blockLimit) {
+			if !ep.handleEvents(ctx, addresses, b[0], b[1]) {
+				return false
+			}
+			ep.saveCheckpoints(ctx, addresses, b[1])
+		}
+	}
+	return true
+}
+
+// saveCheckpoints records the given block as the last fully processed block
+// for the given addresses.
+func (ep *EventProvider) saveCheckpoints(ctx context.Context, addresses []types.Address, block uint64) {
+	if ep.checkpoint == nil {
+		return
+	}
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	for _, address := range addresses {
+		if ep.prefetching[address] {
+			continue
+		}
+		if err := ep.checkpoint.Set(ctx, checkpointKey(ep.converter.EventType(), ep.chain, address), block); err != nil {
+			ep.log.
+				WithError(err).
+				WithField("address", address.String()).
+				Error("Unable to save checkpoint")
+		}
+	}
+}
+
+// handleEvents fetches TeleportGUID events from the given block range and
+// sends them to the eventCh channel. It returns false if the context was
+// canceled.
+func (ep *EventProvider) handleEvents(ctx context.Context, addresses []types.Address, from, to uint64) bool {
+	logs, ok := ep.fetchLogs(ctx, addresses, from, to)
+	if !ok {
+		return false // Context was canceled.
+	}
+	return ep.sendLogs(ctx, logs)
+}
+
+// fetchLogs fetches logs emitted by the given addresses from
+// the given block range. It returns false if the context was canceled.
+func (ep *EventProvider) fetchLogs(
+	ctx context.Context,
+	addresses []types.Address,
+	from, to uint64,
+) ([]types.Log, bool) {
+
+	var res []types.Log
+	for _, address := range addresses {
 		ep.log.
 			WithFields(log.Fields{
 				"from":    from,
@@ -208,9 +534,9 @@ func (ep *EventProvider) handleEvents(ctx context.Context, from, to uint64) {
 				"address": address.String(),
 			}).
 			Info("Fetching logs")
-		logs, ok := ep.filterLogs(ctx, address, from, to, teleportTopic0)
+		logs, ok := ep.filterLogs(ctx, address, from, to, ep.converter.Topic0())
 		if !ok {
-			return // Context was canceled.
+			return nil, false // Context was canceled.
 		}
 		for _, l := range logs {
 			if l.Address != address {
@@ -237,16 +563,68 @@ func (ep *EventProvider) handleEvents(ctx context.Context, from, to uint64) {
 					Warn("Received removed log")
 				continue
 			}
-			evt, err := logToMessage(l)
-			if err != nil {
-				ep.log.
-					WithError(err).
-					Error("Unable to convert log to event")
-				continue
-			}
-			ep.eventCh <- evt
+			res = append(res, l)
+		}
+	}
+	return res, true
+}
+
+// sendLogs converts logs into event messages and sends them to the eventCh
+// channel. It returns false if the context was canceled.
+func (ep *EventProvider) sendLogs(ctx context.Context, logs []types.Log) bool {
+	timestamps := map[uint64]time.Time{}
+	for _, l := range logs {
+		evt, err := ep.converter.Convert(l, &logContext{
+			ctx:        ctx,
+			ep:         ep,
+			log:        l,
+			timestamps: timestamps,
+		})
+		if ctx.Err() != nil {
+			return false
+		}
+		if err != nil {
+			ep.log.
+				WithError(err).
+				Error("Unable to convert log to event")
+			continue
 		}
+		ep.eventCh <- evt
+	}
+	return true
+}
+
+// logContext implements the LogContext interface. Block timestamps are
+// shared between logs sent in the same batch, so every block is fetched
+// at most once.
+type logContext struct {
+	ctx        context.Context
+	ep         *EventProvider
+	log        types.Log
+	timestamps map[uint64]time.Time
+}
+
+// BlockTimestamp implements the LogContext interface.
+func (lc *logContext) BlockTimestamp() (time.Time, error) {
+	block := lc.log.BlockNumber.Big().Uint64()
+	if ts, ok := lc.timestamps[block]; ok {
+		return ts, nil
+	}
+	ts, ok := lc.ep.getBlockTimestamp(lc.ctx, block)
+	if !ok {
+		return time.Time{}, lc.ctx.Err()
 	}
+	lc.timestamps[block] = ts
+	return ts, nil
+}
+
+// Receipt implements the LogContext interface.
+func (lc *logContext) Receipt() (*types.TransactionReceiptType, error) {
+	receipt, ok := lc.ep.getReceipt(lc.ctx, lc.log.TxHash)
+	if !ok {
+		return nil, lc.ctx.Err()
+	}
+	return receipt, nil
 }
 
 // getBlockNumber returns the latest block number on the blockchain.
@@ -275,34 +653,117 @@ func (ep *EventProvider) getBlockNumber(ctx context.Context) (uint64, bool) {
 	return res, true
 }
 
-// getBlockNumber returns the latest block number on the blockchain.
+// getChainID returns the chain ID.
+//
+// The method will try to fetch the chain ID indefinitely in case of an
+// error. The only way to stop this method from trying again is to cancel
+// the context. In that case, the method will return false as a second
+// return value.
+func (ep *EventProvider) getChainID(ctx context.Context) (uint64, bool) {
+	var err error
+	var res uint64
+	retry.TryForever(
+		ctx,
+		func() error {
+			res, err = ep.client.ChainID(ctx)
+			if err != nil {
+				ep.log.WithError(err).Error("Unable to get chain ID")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if ctx.Err() != nil {
+		return 0, false
+	}
+	return res, true
+}
+
+// getBlockTimestamp returns the timestamp of the block with the given number.
 //
 // The method will try to fetch blocks indefinitely in case of an error.
 // The only way to stop this method from trying again is to cancel the
 // context. In that case, the method will return false as a second return
 // value.
 func (ep *EventProvider) getBlockTimestamp(ctx context.Context, block uint64) (time.Time, bool) {
+	res, ok := ep.getBlock(ctx, block)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(res.Timestamp.Big().Int64(), 0), true
+}
+
+// getReceipt returns the receipt of the transaction with the given hash.
+//
+// The method will try to fetch the receipt indefinitely in case of an error.
+// The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ep *EventProvider) getReceipt(ctx context.Context, hash types.Hash) (*types.TransactionReceiptType, bool) {
 	var err error
-	var res any
+	var res *types.TransactionReceiptType
 	retry.TryForever(
 		ctx,
 		func() error {
-			res, err = ep.client.BlockByNumber(ctx, types.Uint64ToBlockNumber(block))
+			res, err = ep.client.TransactionReceipt(ctx, hash)
+			if err == nil && res == nil {
+				err = fmt.Errorf("receipt for transaction %s not found", hash.String())
+			}
 			if err != nil {
-				ep.log.WithError(err).Error("Unable to get block timestamp")
+				ep.log.WithError(err).Error("Unable to get transaction receipt")
 			}
 			return err
 		},
 		retryInterval,
 	)
 	if res == nil || ctx.Err() != nil {
-		return time.Time{}, false
+		return nil, false
 	}
-	if res, ok := res.(*types.BlockTxHashes); ok {
-		return time.Unix(res.Timestamp.Big().Int64(), 0), true
+	return res, true
+}
+
+// getBlockHeader returns the header of the block with the given number.
+//
+// The method will try to fetch blocks indefinitely in case of an error.
+// The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ep *EventProvider) getBlockHeader(ctx context.Context, block uint64) (blockHeader, bool) {
+	res, ok := ep.getBlock(ctx, block)
+	if !ok {
+		return blockHeader{}, false
+	}
+	return blockHeader{
+		number:     block,
+		hash:       res.Hash,
+		parentHash: res.ParentHash,
+	}, true
+}
+
+// getBlock returns the block with the given number.
+//
+// The method will try to fetch blocks indefinitely in case of an error.
+// The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ep *EventProvider) getBlock(ctx context.Context, block uint64) (*types.BlockTxHashes, bool) {
+	var err error
+	var res *types.BlockTxHashes
+	retry.TryForever(
+		ctx,
+		func() error {
+			res, err = ep.client.BlockByNumber(ctx, types.Uint64ToBlockNumber(block))
+			if err != nil {
+				ep.log.WithError(err).Error("Unable to get block")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if res == nil || ctx.Err() != nil {
+		return nil, false
 	}
-	ep.log.Panic("BlockByNumber returned unexpected type")
-	return time.Time{}, true
+	return res, true
 }
 
 // filterLogs fetches TeleportGUID events from the blockchain.
@@ -344,6 +805,20 @@ func (ep *EventProvider) filterLogs(
 	return res, true
 }
 
+// checkpointKey returns the key under which the checkpoint for the given
+// event type, chain and address is stored.
+func checkpointKey(eventType, chain string, address types.Address) string {
+	return eventType + ":" + chain + ":" + address.String()
+}
+
+// subUint64 subtracts b from a. If the result would be negative, it returns 0.
+func subUint64(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
+
 // splitBlockRanges splits a block range into smaller ranges of at most
 // "limit" blocks. Some RPC providers have a limit on the number of blocks
 // that can be fetched in a single request and this method is used to
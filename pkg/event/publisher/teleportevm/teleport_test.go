@@ -27,6 +27,7 @@ import (
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/rpcclient/mocks"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/checkpoint"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/errutil"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -63,6 +64,9 @@ func Test_teleportEventProvider_FetchEventsRoutine(t *testing.T) {
 	cli.On("BlockNumber", ctx).Return(uint64(119), nil).Once()
 	cli.On("BlockNumber", ctx).Return(uint64(125), nil).Once()
 
+	// All blocks have the same empty hash, so there are no reorgs.
+	cli.On("BlockByNumber", ctx, mock.Anything).Return(dummyBlock(0, 0), nil)
+
 	// First two ranges must be split into two FilterLogs calls to avoid exceeding the block limit.
 	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
 		fq := args.Get(1).(types.FilterLogsQuery)
@@ -147,6 +151,128 @@ func Test_teleportEventProvider_PrefetchEventsRoutine(t *testing.T) {
 	waitForEvents(ctx, t, ep, 2)
 }
 
+func Test_teleportEventProvider_ResumeFromCheckpoint(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	cps := checkpoint.NewMemoryStore()
+	require.NoError(t, cps.Set(ctx, checkpointKey(TeleportEventType, "1", teleportTestAddress), 80))
+
+	cli := &mocks.Client{}
+	ep, err := New(Config{
+		Client:             cli,
+		Addresses:          types.Addresses{teleportTestAddress},
+		Interval:           100 * time.Millisecond,
+		PrefetchPeriod:     100 * time.Second,
+		Checkpoint:         cps,
+		BlockLimit:         10,
+		BlockConfirmations: 1,
+		Logger:             null.New(),
+	})
+	require.NoError(t, err)
+
+	txHash := types.HexToHash("0x66e8ab5a41d4b109c7f6ea5303e3c292771e57fb0b93a8474ca6f72e53eac0e8")
+	logs := []types.Log{
+		{TxIndex: types.Uint64ToNumber(1), Data: teleportTestGUID, TxHash: txHash, Address: teleportTestAddress},
+	}
+
+	// Prefetch routine must not be started because there is a checkpoint
+	// for the address, so the timestamp of the block 99 is never fetched.
+	cli.On("ChainID", ctx).Return(uint64(1), nil)
+	cli.On("BlockNumber", ctx).Return(uint64(100), nil)
+	cli.On("BlockByNumber", ctx, types.Uint64ToBlockNumber(100)).Return(dummyBlock(100, 0), nil)
+	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(81), fq.FromBlock.Big().Uint64()) // checkpoint plus one
+		assert.Equal(t, uint64(90), fq.ToBlock.Big().Uint64())
+	})
+	cli.On("FilterLogs", ctx, mock.Anything).Return(logs, nil).Once().Run(func(args mock.Arguments) {
+		fq := args.Get(1).(types.FilterLogsQuery)
+		assert.Equal(t, uint64(91), fq.FromBlock.Big().Uint64())
+		assert.Equal(t, uint64(99), fq.ToBlock.Big().Uint64()) // latest block minus block confirmations
+	})
+
+	require.NoError(t, ep.Start(ctx))
+
+	waitForEvents(ctx, t, ep, 2)
+	assert.Eventually(t, func() bool {
+		block, ok, err := cps.Get(ctx, checkpointKey(TeleportEventType, "1", teleportTestAddress))
+		return err == nil && ok && block == 99
+	}, time.Second, 10*time.Millisecond)
+	cli.AssertNotCalled(t, "BlockByNumber", mock.Anything, types.Uint64ToBlockNumber(99))
+}
+
+func Test_teleportEventProvider_saveCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	cps := checkpoint.NewMemoryStore()
+	addr1 := types.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := types.HexToAddress("0x2222222222222222222222222222222222222222")
+	ep, err := New(Config{
+		Client:     &mocks.Client{},
+		Addresses:  types.Addresses{addr1, addr2},
+		Interval:   time.Second,
+		Checkpoint: cps,
+		Chain:      "1",
+		BlockLimit: 10,
+	})
+	require.NoError(t, err)
+
+	// Checkpoints must not be updated for addresses that are still being
+	// prefetched.
+	ep.prefetching[addr2] = true
+	ep.saveCheckpoints(ctx, ep.addresses, 10)
+
+	block, ok, err := cps.Get(ctx, checkpointKey(TeleportEventType, "1", addr1))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10), block)
+	_, ok, err = cps.Get(ctx, checkpointKey(TeleportEventType, "1", addr2))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func Test_teleportEventProvider_checkpointsForChainsWithSameAddress(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+
+	// Providers for two chains share one checkpoint store and listen to
+	// the same address:
+	cps := checkpoint.NewMemoryStore()
+	newProvider := func(chainID uint64) (*EventProvider, *mocks.Client) {
+		cli := &mocks.Client{}
+		cli.On("ChainID", ctx).Return(chainID, nil)
+		ep, err := New(Config{
+			Client:     cli,
+			Addresses:  types.Addresses{teleportTestAddress},
+			Interval:   time.Second,
+			Checkpoint: cps,
+			BlockLimit: 10,
+		})
+		require.NoError(t, err)
+		ep.disablePrefetchEventsRoutine = true
+		ep.disableFetchEventsRoutine = true
+		return ep, cli
+	}
+	ep1, _ := newProvider(1)
+	ep2, _ := newProvider(10)
+	require.NoError(t, ep1.Start(ctx))
+	require.NoError(t, ep2.Start(ctx))
+	require.Eventually(t, func() bool {
+		ep1.mu.Lock()
+		defer ep1.mu.Unlock()
+		ep2.mu.Lock()
+		defer ep2.mu.Unlock()
+		return ep1.chain != "" && ep2.chain != ""
+	}, time.Second, 10*time.Millisecond)
+
+	ep1.saveCheckpoints(ctx, ep1.addresses, 100)
+	ep2.saveCheckpoints(ctx, ep2.addresses, 200)
+
+	// Checkpoints must not overwrite each other:
+	assert.Equal(t, map[types.Address]uint64{teleportTestAddress: 100}, ep1.loadCheckpoints(ctx))
+	assert.Equal(t, map[types.Address]uint64{teleportTestAddress: 200}, ep2.loadCheckpoints(ctx))
+}
+
 func waitForEvents(ctx context.Context, t *testing.T, ep *EventProvider, expectedEvents int) {
 	events := 0
 loop:
@@ -164,6 +290,68 @@ loop:
 	assert.Equal(t, expectedEvents, events)
 }
 
+func Test_logContext(t *testing.T) {
+	ctx := context.Background()
+	cli := &mocks.Client{}
+	ep, err := New(Config{
+		Client:     cli,
+		Addresses:  types.Addresses{teleportTestAddress},
+		Interval:   time.Second,
+		BlockLimit: 10,
+	})
+	require.NoError(t, err)
+
+	txHash := types.HexToHash("0x01")
+	receipt := &types.TransactionReceiptType{TransactionHash: txHash}
+	cli.On("BlockByNumber", ctx, types.Uint64ToBlockNumber(42)).Return(dummyBlock(42, 1600000000), nil).Once()
+	cli.On("TransactionReceipt", ctx, txHash).Return(receipt, nil).Once()
+
+	timestamps := map[uint64]time.Time{}
+	lc1 := &logContext{ctx: ctx, ep: ep, log: types.Log{BlockNumber: types.Uint64ToNumber(42), TxHash: txHash}, timestamps: timestamps}
+	lc2 := &logContext{ctx: ctx, ep: ep, log: types.Log{BlockNumber: types.Uint64ToNumber(42)}, timestamps: timestamps}
+
+	// The block must be fetched only once for logs from the same block:
+	ts, err := lc1.BlockTimestamp()
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1600000000, 0), ts)
+	ts, err = lc2.BlockTimestamp()
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1600000000, 0), ts)
+
+	r, err := lc1.Receipt()
+	require.NoError(t, err)
+	assert.Equal(t, receipt, r)
+	cli.AssertExpectations(t)
+}
+
+func TestNewLogEvent(t *testing.T) {
+	txHash := types.HexToHash("0x66e8ab5a41d4b109c7f6ea5303e3c292771e57fb0b93a8474ca6f72e53eac0e8")
+	date := time.Unix(1600000000, 0)
+	l1 := types.Log{
+		Topics:   []types.Hash{types.HexToHash("0x01"), types.HexToHash("0x02")},
+		Data:     types.HexToBytes("0x03"),
+		TxHash:   txHash,
+		LogIndex: types.Uint64ToNumber(1),
+	}
+	l2 := l1
+	l2.LogIndex = types.Uint64ToNumber(2)
+
+	evt1 := NewLogEvent(l1, "test", []byte{1}, date)
+	evt2 := NewLogEvent(l2, "test", []byte{2}, date)
+
+	assert.Equal(t, "test", evt1.Type)
+	assert.Equal(t, date, evt1.EventDate)
+	assert.Equal(t, []byte{1}, evt1.Data["hash"])
+	assert.Equal(t, l1.Data.Bytes(), evt1.Data["event"])
+	assert.Equal(t, append(l1.Topics[0].Bytes(), l1.Topics[1].Bytes()...), evt1.Data["topics"])
+
+	// Logs from the same transaction share the index, but not the ID:
+	assert.Equal(t, txHash.Bytes(), evt1.Index)
+	assert.Equal(t, evt1.Index, evt2.Index)
+	assert.NotEqual(t, evt1.ID, evt2.ID)
+	assert.Equal(t, evt1.ID, NewLogEvent(l1, "test", []byte{1}, date).ID)
+}
+
 func dummyBlock(number uint64, timestamp int64) *types.BlockTxHashes {
 	return &types.BlockTxHashes{
 		Block: types.Block{
@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/checkpoint"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/starknet"
@@ -37,24 +38,50 @@ const LoggerTag = "STARKNET_TELEPORT"
 // while communicating with a Starknet node.
 const retryInterval = 5 * time.Second
 
-// Sequencer is a Starknet sequencer.
+// Sequencer is a Starknet sequencer. It is implemented by the
+// starknet.Sequencer, which uses the feeder gateway, and by the
+// starknet.RPCSequencer, which uses the JSON-RPC API.
 type Sequencer interface {
 	GetPendingBlock(ctx context.Context) (*starknet.Block, error)
 	GetLatestBlock(ctx context.Context) (*starknet.Block, error)
 	GetBlockByNumber(ctx context.Context, blockNumber uint64) (*starknet.Block, error)
 }
 
+// RangeSequencer is a Sequencer that can fetch multiple blocks at once.
+// If the sequencer implements this interface, accepted blocks are fetched
+// in ranges of up to rangeSize blocks.
+type RangeSequencer interface {
+	Sequencer
+	GetBlocksByRange(ctx context.Context, from, to uint64) ([]*starknet.Block, error)
+}
+
+// rangeSize is the maximum number of blocks fetched at once using the
+// RangeSequencer.
+const rangeSize = 100
+
+var _ Sequencer = (*starknet.Sequencer)(nil)
+var _ RangeSequencer = (*starknet.RPCSequencer)(nil)
+
 // Config contains a configuration options for New.
 type Config struct {
-	// Sequencer is an instance of Ethereum RPC sequencer.
+	// Sequencer is an instance of Starknet sequencer.
 	Sequencer Sequencer
 	// Addresses is a list of contracts from which events will be fetched.
 	Addresses []*starknet.Felt
 	// Interval specifies how often provider should check for new events.
 	Interval time.Duration
 	// PrefetchPeriod specifies how far back in time provider should prefetch
-	// events. It is used only during the initial start of the provider.
+	// events. It is used only during the initial start of the provider if
+	// there is no checkpoint for some of the addresses.
 	PrefetchPeriod time.Duration
+	// Checkpoint is an optional store used to record the last fully
+	// processed accepted block for every address. If a checkpoint exists for
+	// all addresses, the provider resumes from the oldest one instead of
+	// prefetching blocks.
+	Checkpoint checkpoint.Store
+	// Chain identifies the chain in checkpoint keys, so providers for
+	// different networks can share a checkpoint store.
+	Chain string
 	// Logger is an instance of a logger. Logger is used mostly to report
 	// recoverable errors.
 	Logger log.Logger
@@ -73,6 +100,10 @@ type Config struct {
 // until it reaches the block that is older than the prefetch period. This is
 // done to fetch events that were emitted before the provider was started.
 //
+// If the checkpoint store is configured, the provider records the last
+// accepted block processed for every address. After a restart, accepted
+// blocks are fetched starting from the block after the oldest checkpoint.
+//
 // Finally, it also listens for newly accepted blocks. This is done to make
 // sure that provider does not miss any events from the pending block. This
 // can happen if the Starknet node becomes unavailable, so it cannot fetch
@@ -92,8 +123,16 @@ type EventProvider struct {
 	addresses      []*starknet.Felt
 	interval       time.Duration
 	prefetchPeriod time.Duration
+	checkpoint     checkpoint.Store
+	chain          string
 	log            log.Logger
 
+	// prefetching contains keys of addresses for which the prefetch routine
+	// is still running. Checkpoints for these addresses are not updated until
+	// the prefetch is finished, otherwise a restart during the prefetch would
+	// skip older blocks.
+	prefetching map[string]bool
+
 	// Fields for tracking transactions from a pending block, used in the
 	// processBlock method:
 	pendingParent *starknet.Felt
@@ -122,7 +161,10 @@ func New(cfg Config) (*EventProvider, error) {
 		addresses:      cfg.Addresses,
 		interval:       cfg.Interval,
 		prefetchPeriod: cfg.PrefetchPeriod,
+		checkpoint:     cfg.Checkpoint,
+		chain:          cfg.Chain,
 		log:            cfg.Logger.WithField("tag", LoggerTag),
+		prefetching:    map[string]bool{},
 	}, nil
 }
 
@@ -133,25 +175,59 @@ func (ep *EventProvider) Events() chan *messages.Event {
 
 // Start implements the publisher.EventPublisher interface.
 func (ep *EventProvider) Start(ctx context.Context) error {
-	if !ep.disablePrefetchBlocksRoutine {
+	checkpoints := ep.loadCheckpoints(ctx)
+	if !ep.disablePrefetchBlocksRoutine && ep.prefetchPeriod > 0 && len(checkpoints) < len(ep.addresses) {
+		ep.mu.Lock()
+		for _, address := range ep.addresses {
+			if _, ok := checkpoints[checkpointKey(ep.chain, address)]; !ok {
+				ep.prefetching[checkpointKey(ep.chain, address)] = true
+			}
+		}
+		ep.mu.Unlock()
 		go ep.prefetchBlocksRoutine(ctx)
 	}
 	if !ep.disablePendingBlockRoutine {
 		go ep.handlePendingBlockRoutine(ctx)
 	}
 	if !ep.disableAcceptedBlocksRoutine {
-		go ep.handleAcceptedBlocksRoutine(ctx)
+		go ep.handleAcceptedBlocksRoutine(ctx, checkpoints)
 	}
 	return nil
 }
 
+// loadCheckpoints returns the last fully processed blocks for addresses
+// that have a checkpoint. The returned map is indexed by checkpoint keys.
+func (ep *EventProvider) loadCheckpoints(ctx context.Context) map[string]uint64 {
+	checkpoints := map[string]uint64{}
+	if ep.checkpoint == nil {
+		return checkpoints
+	}
+	for _, address := range ep.addresses {
+		key := checkpointKey(ep.chain, address)
+		block, ok, err := ep.checkpoint.Get(ctx, key)
+		if err != nil {
+			ep.log.
+				WithError(err).
+				WithField("key", key).
+				Error("Unable to load checkpoint")
+			continue
+		}
+		if ok {
+			checkpoints[key] = block
+		}
+	}
+	return checkpoints
+}
+
 // prefetchBlocksRoutine fetches older blocks until it reaches the block that
 // is older than the prefetch period. This is done to fetch events that were
 // emitted before the provider was started.
 func (ep *EventProvider) prefetchBlocksRoutine(ctx context.Context) {
-	if ep.prefetchPeriod == 0 {
-		return
-	}
+	defer func() {
+		ep.mu.Lock()
+		ep.prefetching = map[string]bool{}
+		ep.mu.Unlock()
+	}()
 	latestBlock, ok := ep.getLatestBlock(ctx)
 	if !ok {
 		return // Context wax canceled.
@@ -188,12 +264,27 @@ func (ep *EventProvider) handlePendingBlockRoutine(ctx context.Context) {
 }
 
 // handleAcceptedBlocksRoutine periodically fetches TeleportGUID events from
-// the accepted blocks.
-func (ep *EventProvider) handleAcceptedBlocksRoutine(ctx context.Context) {
+// the accepted blocks. Before that, it fetches blocks between the oldest
+// checkpoint and the latest block.
+func (ep *EventProvider) handleAcceptedBlocksRoutine(ctx context.Context, checkpoints map[string]uint64) {
 	latestBlock, ok := ep.getLatestBlock(ctx)
 	if !ok {
 		return // Context was canceled.
 	}
+	if len(checkpoints) > 0 {
+		from := latestBlock.BlockNumber
+		for _, block := range checkpoints {
+			if block < from {
+				from = block
+			}
+		}
+		ep.log.
+			WithField("checkpoint", from).
+			Info("Resuming from checkpoint")
+		if !ep.processAcceptedBlocks(ctx, from+1, latestBlock.BlockNumber) {
+			return // Context was canceled.
+		}
+	}
 	t := time.NewTicker(ep.interval)
 	defer t.Stop()
 	for {
@@ -208,18 +299,68 @@ func (ep *EventProvider) handleAcceptedBlocksRoutine(ctx context.Context) {
 			if currentBlock.BlockNumber <= latestBlock.BlockNumber {
 				continue // There is no new blocks.
 			}
-			for bn := latestBlock.BlockNumber + 1; bn <= currentBlock.BlockNumber; bn++ {
-				block, ok := ep.getBlockByNumber(ctx, bn)
-				if !ok {
-					return // Context was canceled.
-				}
-				ep.processBlock(block)
+			if !ep.processAcceptedBlocks(ctx, latestBlock.BlockNumber+1, currentBlock.BlockNumber) {
+				return // Context was canceled.
 			}
 			latestBlock = currentBlock
 		}
 	}
 }
 
+// processAcceptedBlocks processes accepted blocks in the given range and
+// updates checkpoints after every block. It returns false if the context was
+// canceled.
+func (ep *EventProvider) processAcceptedBlocks(ctx context.Context, from, to uint64) bool {
+	if rs, ok := ep.sequencer.(RangeSequencer); ok {
+		for bn := from; bn <= to; bn += rangeSize {
+			end := bn + rangeSize - 1
+			if end > to {
+				end = to
+			}
+			blocks, ok := ep.getBlocksByRange(ctx, rs, bn, end)
+			if !ok {
+				return false
+			}
+			for _, block := range blocks {
+				ep.processBlock(block)
+				ep.saveCheckpoints(ctx, block.BlockNumber)
+			}
+		}
+		return true
+	}
+	for bn := from; bn <= to; bn++ {
+		block, ok := ep.getBlockByNumber(ctx, bn)
+		if !ok {
+			return false
+		}
+		ep.processBlock(block)
+		ep.saveCheckpoints(ctx, bn)
+	}
+	return true
+}
+
+// saveCheckpoints records the given block as the last fully processed block
+// for all addresses.
+func (ep *EventProvider) saveCheckpoints(ctx context.Context, block uint64) {
+	if ep.checkpoint == nil {
+		return
+	}
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	for _, address := range ep.addresses {
+		key := checkpointKey(ep.chain, address)
+		if ep.prefetching[key] {
+			continue
+		}
+		if err := ep.checkpoint.Set(ctx, key, block); err != nil {
+			ep.log.
+				WithError(err).
+				WithField("key", key).
+				Error("Unable to save checkpoint")
+		}
+	}
+}
+
 // processBlock finds TeleportGUID events in the given block and converts them
 // into event messages. Converted messages are sent to the eventCh channel.
 func (ep *EventProvider) processBlock(block *starknet.Block) {
@@ -293,6 +434,12 @@ func (ep *EventProvider) isTeleportEvent(evt *starknet.Event) bool {
 	return false
 }
 
+// checkpointKey returns the key under which the checkpoint for the given
+// address on the given chain is stored.
+func checkpointKey(chain string, address *starknet.Felt) string {
+	return TeleportEventType + ":" + chain + ":0x" + address.Text(16)
+}
+
 // getBlockByNumber returns a block with the given number.
 //
 // The method will try to fetch blocks indefinitely in case of an error.
@@ -319,6 +466,32 @@ func (ep *EventProvider) getBlockByNumber(ctx context.Context, num uint64) (bloc
 	return block, ctx.Err() == nil
 }
 
+// getBlocksByRange returns blocks in the given range.
+//
+// The method will try to fetch blocks indefinitely in case of an error.
+// The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ep *EventProvider) getBlocksByRange(ctx context.Context, rs RangeSequencer, from, to uint64) (blocks []*starknet.Block, ok bool) {
+	retry.TryForever(
+		ctx,
+		func() error {
+			var err error
+			blocks, err = rs.GetBlocksByRange(ctx, from, to)
+			if err, ok := err.(starknet.HTTPError); ok && err.StatusCode == http.StatusTooManyRequests {
+				ep.log.WithError(err).Debug("Unable to get blocks by range")
+				return err
+			}
+			if err != nil {
+				ep.log.WithError(err).Error("Unable to get blocks by range")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	return blocks, ctx.Err() == nil
+}
+
 // getLatestBlock returns the latest block.
 //
 // The method will try to fetch blocks indefinitely in case of an error.
@@ -28,6 +28,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/publisher/checkpoint"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/starknet"
 	"github.com/chronicleprotocol/oracle-suite/pkg/starknet/mocks"
@@ -328,6 +329,113 @@ func Test_teleportListener_AcceptedBlocksRoutine(t *testing.T) {
 	waitForEvents(ctx, t, ep, 4)
 }
 
+func Test_teleportListener_ResumeFromCheckpoint(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	address := starknet.HexToFelt("0x197f9e93cfaf7068ca2daf3ec89c2b91d051505c2231a0a0b9f70801a91fb24")
+	cps := checkpoint.NewMemoryStore()
+	require.NoError(t, cps.Set(ctx, checkpointKey("mainnet", address), 191502))
+
+	cli := &mocks.Sequencer{}
+	ep, err := New(Config{
+		Sequencer:      cli,
+		Addresses:      []*starknet.Felt{address},
+		Interval:       time.Millisecond * 100,
+		PrefetchPeriod: time.Second * 100,
+		Checkpoint:     cps,
+		Chain:          "mainnet",
+		Logger:         null.New(),
+	})
+	require.NoError(t, err)
+	ep.disablePendingBlockRoutine = true
+
+	block1 := dummyBlock()
+	block1.BlockNumber = 191504
+
+	// Prefetch routine must not be started because there is a checkpoint
+	// for the address. Blocks after the checkpoint are fetched immediately.
+	cli.On("GetLatestBlock", ctx, mock.Anything, mock.Anything).Return(block1, nil)
+	cli.On("GetBlockByNumber", ctx, uint64(191503)).Return(block1, nil).Once()
+	cli.On("GetBlockByNumber", ctx, uint64(191504)).Return(block1, nil).Once()
+
+	require.NoError(t, ep.Start(ctx))
+
+	waitForEvents(ctx, t, ep, 2)
+	assert.Eventually(t, func() bool {
+		block, ok, err := cps.Get(ctx, checkpointKey("mainnet", address))
+		return err == nil && ok && block == 191504
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_teleportListener_ResumeFromCheckpointByRange(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	address := starknet.HexToFelt("0x197f9e93cfaf7068ca2daf3ec89c2b91d051505c2231a0a0b9f70801a91fb24")
+	cps := checkpoint.NewMemoryStore()
+	require.NoError(t, cps.Set(ctx, checkpointKey("mainnet", address), 191502))
+
+	cli := &mocks.RangeSequencer{}
+	ep, err := New(Config{
+		Sequencer:      cli,
+		Addresses:      []*starknet.Felt{address},
+		Interval:       time.Millisecond * 100,
+		PrefetchPeriod: time.Second * 100,
+		Checkpoint:     cps,
+		Chain:          "mainnet",
+		Logger:         null.New(),
+	})
+	require.NoError(t, err)
+	ep.disablePendingBlockRoutine = true
+
+	block1 := dummyBlock()
+	block1.BlockNumber = 191503
+	block2 := dummyBlock()
+	block2.BlockNumber = 191504
+
+	// Blocks after the checkpoint must be fetched using a single request.
+	cli.On("GetLatestBlock", ctx, mock.Anything, mock.Anything).Return(block2, nil)
+	cli.On("GetBlocksByRange", ctx, uint64(191503), uint64(191504)).Return([]*starknet.Block{block1, block2}, nil).Once()
+
+	require.NoError(t, ep.Start(ctx))
+
+	waitForEvents(ctx, t, ep, 2)
+	assert.Eventually(t, func() bool {
+		block, ok, err := cps.Get(ctx, checkpointKey("mainnet", address))
+		return err == nil && ok && block == 191504
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_teleportListener_checkpointsForChainsWithSameAddress(t *testing.T) {
+	ctx := context.Background()
+
+	// Providers for two chains share one checkpoint store and listen to
+	// the same address:
+	address := starknet.HexToFelt("0x197f9e93cfaf7068ca2daf3ec89c2b91d051505c2231a0a0b9f70801a91fb24")
+	cps := checkpoint.NewMemoryStore()
+	newProvider := func(chain string) *EventProvider {
+		ep, err := New(Config{
+			Sequencer:  &mocks.Sequencer{},
+			Addresses:  []*starknet.Felt{address},
+			Interval:   time.Second,
+			Checkpoint: cps,
+			Chain:      chain,
+		})
+		require.NoError(t, err)
+		return ep
+	}
+	ep1 := newProvider("mainnet")
+	ep2 := newProvider("goerli")
+
+	ep1.saveCheckpoints(ctx, 100)
+	ep2.saveCheckpoints(ctx, 200)
+
+	// Checkpoints must not overwrite each other:
+	assert.Equal(t, map[string]uint64{checkpointKey("mainnet", address): 100}, ep1.loadCheckpoints(ctx))
+	assert.Equal(t, map[string]uint64{checkpointKey("goerli", address): 200}, ep2.loadCheckpoints(ctx))
+}
+
 func waitForEvents(ctx context.Context, t *testing.T, ep *EventProvider, expectedEvents int) {
 	events := 0
 loop:
@@ -0,0 +1,446 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package file
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+var ErrMemoryLimitExceed = errors.New("file: memory limit exceeded")
+
+const gcInterval = time.Minute        // How often expired events are removed.
+const compactMinRecords = 1000        // Minimum number of records in the log before it is compacted.
+const recordHeaderSize = 8            // Size of the record header: payload length and checksum.
+const maxRecordSize = 2 * 1024 * 1024 // Maximum size of a single record, larger than the maximum event size.
+
+// Storage provides storage mechanism for store.EventStore.
+// It stores events in an append-only log file on a local disk.
+//
+// Every added event is appended to the log file and synced to the disk
+// before the Add method returns. All events are also kept in memory, so the
+// Get and List methods do not read from the disk. When the storage is opened,
+// the log file is replayed to restore events. If the log file ends with an
+// incomplete record, for example after a crash during a write, the record is
+// discarded.
+//
+// Because replaced and expired events are not removed from the log file, it
+// is periodically compacted by rewriting only stored events to a new file.
+//
+// Storage implements the supervisor.Service interface, the log file is
+// closed when the context passed to the Start method is canceled.
+type Storage struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	waitCh chan error
+
+	path     string
+	file     *os.File
+	ttl      time.Duration
+	memLimit int64
+
+	index   map[[sha256.Size]byte]map[[sha256.Size]byte]*record // Events by type and index, then by author and ID.
+	usage   map[string]int64                                    // Memory used by authors.
+	count   int                                                 // Number of stored events.
+	records int                                                 // Number of records in the log file.
+	lastGC  time.Time
+}
+
+// Config is the configuration for the Storage.
+type Config struct {
+	// Path specifies the path to the log file. The file is created if it
+	// does not exist.
+	Path string
+	// MemoryLimit specifies a maximum memory limit for a single Oracle.
+	MemoryLimit int64
+	// TTL specifies how long messages should be kept in storage.
+	TTL time.Duration
+}
+
+// record is a single stored event.
+type record struct {
+	author []byte
+	evt    *messages.Event
+	size   int // Size of the marshalled event.
+}
+
+// New returns a new instance of Storage. Events stored in the log file are
+// loaded into memory.
+func New(cfg Config) (*Storage, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("file: path must not be empty")
+	}
+	s := &Storage{
+		path:     cfg.Path,
+		ttl:      cfg.TTL,
+		memLimit: cfg.MemoryLimit,
+		index:    map[[sha256.Size]byte]map[[sha256.Size]byte]*record{},
+		usage:    map[string]int64{},
+		lastGC:   time.Now(),
+		waitCh:   make(chan error),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add implements the store.Storage interface.
+func (s *Storage) Add(_ context.Context, author []byte, evt *messages.Event) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.lastGC) > gcInterval {
+		if err := s.gc(); err != nil {
+			return false, err
+		}
+	}
+	val, err := evt.MarshallBinary()
+	if err != nil {
+		return false, fmt.Errorf("file: failed to marshal event: %w", err)
+	}
+	if s.isExpired(evt) {
+		return false, nil
+	}
+	hi, hu := hashIdx(evt.Type, evt.Index), hashUnique(author, evt.ID)
+	prev, ok := s.index[hi][hu]
+	if ok && !prev.evt.MessageDate.Before(evt.MessageDate) {
+		return false, nil
+	}
+	// Check if the memory limit is exceeded. The memory used by the replaced
+	// event is released, so it is not counted.
+	used := s.usage[string(author)]
+	if ok {
+		used -= int64(prev.size)
+	}
+	if s.memLimit > 0 && int64(len(val)) > s.memLimit-used {
+		return false, ErrMemoryLimitExceed
+	}
+	author = append([]byte{}, author...)
+	if err := s.write(author, val); err != nil {
+		return false, err
+	}
+	s.put(&record{author: author, evt: evt, size: len(val)})
+	return !ok, nil
+}
+
+// Get implements the store.Storage interface.
+func (s *Storage) Get(_ context.Context, typ string, idx []byte) ([]*messages.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var evts []*messages.Event
+	for _, r := range s.index[hashIdx(typ, idx)] {
+		if s.isExpired(r.evt) {
+			continue
+		}
+		evts = append(evts, r.evt)
+	}
+	return evts, nil
+}
+
+// List implements the store.Storage interface.
+func (s *Storage) List(_ context.Context, typ string, from, to time.Time, limit int) ([]*messages.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var evts []*messages.Event
+	for _, idx := range s.index {
+		for _, r := range idx {
+			evt := r.evt
+			if evt.Type != typ || s.isExpired(evt) {
+				continue
+			}
+			if evt.EventDate.Before(from) || (!to.IsZero() && !evt.EventDate.Before(to)) {
+				continue
+			}
+			evts = append(evts, evt)
+		}
+	}
+	sort.SliceStable(evts, func(i, j int) bool {
+		return evts[i].EventDate.Before(evts[j].EventDate)
+	})
+	if limit > 0 && len(evts) > limit {
+		// Messages with the same event date as the last one must not be
+		// split between pages.
+		n := limit
+		for n < len(evts) && evts[n].EventDate.Unix() == evts[limit-1].EventDate.Unix() {
+			n++
+		}
+		evts = evts[:n]
+	}
+	return evts, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (s *Storage) Start(ctx context.Context) error {
+	if s.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	s.ctx = ctx
+	go s.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (s *Storage) Wait() chan error {
+	return s.waitCh
+}
+
+// Close closes the log file.
+func (s *Storage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// contextCancelHandler closes the log file when the context is canceled.
+func (s *Storage) contextCancelHandler() {
+	defer func() { close(s.waitCh) }()
+	<-s.ctx.Done()
+	if err := s.Close(); err != nil {
+		s.waitCh <- err
+	}
+}
+
+// put adds the record to the index, replacing the previous record of the
+// same author and event ID.
+func (s *Storage) put(r *record) {
+	hi, hu := hashIdx(r.evt.Type, r.evt.Index), hashUnique(r.author, r.evt.ID)
+	if _, ok := s.index[hi]; !ok {
+		s.index[hi] = map[[sha256.Size]byte]*record{}
+	}
+	if prev, ok := s.index[hi][hu]; ok {
+		s.usage[string(prev.author)] -= int64(prev.size)
+	} else {
+		s.count++
+	}
+	s.index[hi][hu] = r
+	s.usage[string(r.author)] += int64(r.size)
+}
+
+// gc removes expired events and compacts the log file if it contains too
+// many records that are no longer stored.
+func (s *Storage) gc() error {
+	s.lastGC = time.Now()
+	for hi, idx := range s.index {
+		for hu, r := range idx {
+			if !s.isExpired(r.evt) {
+				continue
+			}
+			delete(idx, hu)
+			s.count--
+			s.usage[string(r.author)] -= int64(r.size)
+			if s.usage[string(r.author)] <= 0 {
+				delete(s.usage, string(r.author))
+			}
+		}
+		if len(idx) == 0 {
+			delete(s.index, hi)
+		}
+	}
+	if s.records > compactMinRecords && s.records > 2*s.count {
+		return s.compact()
+	}
+	return nil
+}
+
+func (s *Storage) isExpired(evt *messages.Event) bool {
+	return time.Since(evt.EventDate) > s.ttl
+}
+
+// load replays the log file and opens it for appending.
+func (s *Storage) load() error {
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("file: unable to open the log file: %w", err)
+	}
+	var offset int64
+	rd := bufio.NewReader(f)
+	for {
+		author, val, n, err := readRecord(rd)
+		if err != nil {
+			// An incomplete or corrupted record may be caused by a crash
+			// during a write. Remaining data is discarded.
+			break
+		}
+		offset += int64(n)
+		s.records++
+		evt := &messages.Event{}
+		if err := evt.UnmarshallBinary(val); err != nil {
+			continue
+		}
+		// Records are appended in the order they were accepted, so a later
+		// record with the same date replaces the previous one. Dates may be
+		// equal because they are stored with a precision of one second.
+		hi, hu := hashIdx(evt.Type, evt.Index), hashUnique(author, evt.ID)
+		if prev, ok := s.index[hi][hu]; ok && prev.evt.MessageDate.After(evt.MessageDate) {
+			continue
+		}
+		s.put(&record{author: author, evt: evt, size: len(val)})
+	}
+	if err := f.Truncate(offset); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("file: unable to truncate the log file: %w", err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("file: unable to seek the log file: %w", err)
+	}
+	s.file = f
+	return s.gc()
+}
+
+// write appends a record to the log file.
+func (s *Storage) write(author []byte, val []byte) error {
+	b := encodeRecord(author, val)
+	if len(b)-recordHeaderSize > maxRecordSize {
+		return errors.New("file: event is too large")
+	}
+	off, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("file: unable to seek the log file: %w", err)
+	}
+	if _, err := s.file.Write(b); err != nil {
+		// Remove a partially written record, otherwise records written
+		// after it would be lost when the log file is loaded.
+		_ = s.file.Truncate(off)
+		_, _ = s.file.Seek(off, io.SeekStart)
+		return fmt.Errorf("file: unable to write to the log file: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("file: unable to sync the log file: %w", err)
+	}
+	s.records++
+	return nil
+}
+
+// compact rewrites the log file so that it contains only stored events.
+// The new file is written next to the current one and then renamed, so
+// the log file is never left in an incomplete state.
+func (s *Storage) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("file: unable to compact the log file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	w := bufio.NewWriter(tmp)
+	for _, idx := range s.index {
+		for _, r := range idx {
+			val, err := r.evt.MarshallBinary()
+			if err != nil {
+				_ = tmp.Close()
+				return fmt.Errorf("file: failed to marshal event: %w", err)
+			}
+			if _, err := w.Write(encodeRecord(r.author, val)); err != nil {
+				_ = tmp.Close()
+				return fmt.Errorf("file: unable to compact the log file: %w", err)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("file: unable to compact the log file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("file: unable to compact the log file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("file: unable to compact the log file: %w", err)
+	}
+	_ = s.file.Close()
+	s.file = tmp
+	s.records = s.count
+	// The rename must be synced to the disk, otherwise the old file may be
+	// restored after a crash, and events added after the compaction would
+	// be lost.
+	if err := syncDir(filepath.Dir(s.path)); err != nil {
+		return fmt.Errorf("file: unable to sync the log directory: %w", err)
+	}
+	return nil
+}
+
+// syncDir syncs the directory entries to the disk.
+func syncDir(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// encodeRecord encodes a record as the payload length, the CRC32 checksum
+// of the payload and the payload itself. The payload contains the length
+// of the author, the author and the marshalled event.
+func encodeRecord(author []byte, val []byte) []byte {
+	payload := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(author)+len(val))
+	payload = payload[:binary.PutUvarint(payload, uint64(len(author)))]
+	payload = append(payload, author...)
+	payload = append(payload, val...)
+	b := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(b[4:8], crc32.ChecksumIEEE(payload))
+	return append(b, payload...)
+}
+
+// readRecord reads a single record. It returns the author, the marshalled
+// event and the number of bytes read.
+func readRecord(r io.Reader) ([]byte, []byte, int, error) {
+	var h [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return nil, nil, 0, err
+	}
+	size := binary.BigEndian.Uint32(h[0:4])
+	if size > maxRecordSize {
+		return nil, nil, 0, errors.New("record too large")
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, 0, err
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(h[4:8]) {
+		return nil, nil, 0, errors.New("invalid checksum")
+	}
+	l, n := binary.Uvarint(payload)
+	if n <= 0 || uint64(len(payload)-n) < l {
+		return nil, nil, 0, errors.New("invalid record")
+	}
+	return payload[n : n+int(l)], payload[n+int(l):], recordHeaderSize + int(size), nil
+}
+
+func hashUnique(author []byte, id []byte) [sha256.Size]byte {
+	return sha256.Sum256(append(append([]byte{}, author...), id...))
+}
+
+func hashIdx(typ string, idx []byte) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte(typ), idx...))
+}
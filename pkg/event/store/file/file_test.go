@@ -0,0 +1,244 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+func newStorage(t *testing.T, path string) *Storage {
+	s, err := New(Config{Path: path, TTL: time.Minute})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func testEvent(id, idx string, date time.Time) *messages.Event {
+	return &messages.Event{
+		Type:        "test",
+		ID:          []byte(id),
+		Index:       []byte(idx),
+		MessageDate: date,
+		EventDate:   date,
+		Data:        map[string][]byte{"test": []byte(id)},
+		Signatures:  map[string]messages.EventSignature{},
+	}
+}
+
+func TestFile_Add(t *testing.T) {
+	s := newStorage(t, filepath.Join(t.TempDir(), "events"))
+	e1 := testEvent("test", "idx", time.Now())
+	e2 := testEvent("test2", "idx", time.Now())
+	e3 := testEvent("test2", "idx2", time.Now())
+
+	isNew, err := s.Add(context.Background(), []byte("author1"), e1)
+	assert.True(t, isNew)
+	assert.NoError(t, err)
+
+	isNew, err = s.Add(context.Background(), []byte("author2"), e2)
+	assert.True(t, isNew)
+	assert.NoError(t, err)
+
+	isNew, err = s.Add(context.Background(), []byte("author3"), e3) // different index
+	assert.True(t, isNew)
+	assert.NoError(t, err)
+
+	es, err := s.Get(context.Background(), "test", []byte("idx"))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []*messages.Event{e1, e2}, es)
+}
+
+func TestFile_Add_replacePreviousEvent(t *testing.T) {
+	s := newStorage(t, filepath.Join(t.TempDir(), "events"))
+	e1 := testEvent("test", "idx", time.Now())
+	e2 := testEvent("test", "idx", time.Now().Add(time.Second))
+
+	isNew, err := s.Add(context.Background(), []byte("author"), e1)
+	assert.True(t, isNew)
+	assert.NoError(t, err)
+
+	// Replace if newer
+	isNew, err = s.Add(context.Background(), []byte("author"), e2)
+	assert.False(t, isNew)
+	assert.NoError(t, err)
+
+	es, err := s.Get(context.Background(), "test", []byte("idx"))
+	assert.NoError(t, err)
+	assert.Equal(t, []*messages.Event{e2}, es)
+
+	// Keep previous if older
+	isNew, err = s.Add(context.Background(), []byte("author"), e1)
+	assert.False(t, isNew)
+	assert.NoError(t, err)
+
+	es, err = s.Get(context.Background(), "test", []byte("idx"))
+	assert.NoError(t, err)
+	assert.Equal(t, []*messages.Event{e2}, es)
+}
+
+func TestFile_List(t *testing.T) {
+	s := newStorage(t, filepath.Join(t.TempDir(), "events"))
+	now := time.Now()
+	e1 := testEvent("test1", "idx1", now.Add(-3*time.Second))
+	e2 := testEvent("test2", "idx2", now.Add(-2*time.Second))
+	e3 := testEvent("test3", "idx3", now.Add(-1*time.Second))
+	for _, e := range []*messages.Event{e1, e2, e3} {
+		_, err := s.Add(context.Background(), []byte("author"), e)
+		require.NoError(t, err)
+	}
+
+	es, err := s.List(context.Background(), "test", now.Add(-3*time.Second), now.Add(-1*time.Second), 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []*messages.Event{e1, e2}, es)
+
+	es, err = s.List(context.Background(), "test", now.Add(-2*time.Second), time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []*messages.Event{e2, e3}, es)
+
+	// Messages are sorted by the event date and limited:
+	es, err = s.List(context.Background(), "test", now.Add(-3*time.Second), time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*messages.Event{e1, e2}, es)
+}
+
+func TestFile_memoryLimit(t *testing.T) {
+	s, err := New(Config{Path: filepath.Join(t.TempDir(), "events"), TTL: time.Minute, MemoryLimit: 60})
+	require.NoError(t, err)
+	defer s.Close()
+
+	isNew, err := s.Add(context.Background(), []byte("author"), testEvent("test", "idx1", time.Now()))
+	assert.True(t, isNew)
+	assert.NoError(t, err)
+
+	isNew, err = s.Add(context.Background(), []byte("author"), testEvent("test", "idx2", time.Now()))
+	assert.False(t, isNew)
+	assert.ErrorIs(t, err, ErrMemoryLimitExceed)
+
+	// Limit is applied per author:
+	isNew, err = s.Add(context.Background(), []byte("author2"), testEvent("test", "idx2", time.Now()))
+	assert.True(t, isNew)
+	assert.NoError(t, err)
+
+	// Memory used by a replaced event is not counted:
+	isNew, err = s.Add(context.Background(), []byte("author"), testEvent("test", "idx1", time.Now().Add(time.Second)))
+	assert.False(t, isNew)
+	assert.NoError(t, err)
+}
+
+func TestFile_Start(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	s, err := New(Config{Path: filepath.Join(t.TempDir(), "events"), TTL: time.Minute})
+	require.NoError(t, err)
+	require.NoError(t, s.Start(ctx))
+	assert.Error(t, s.Start(ctx))
+
+	// The log file must be closed when the context is canceled:
+	cancelFunc()
+	require.NoError(t, <-s.Wait())
+	_, err = s.Add(context.Background(), []byte("author"), testEvent("test", "idx", time.Now()))
+	assert.Error(t, err)
+}
+
+func TestFile_ttl(t *testing.T) {
+	s := newStorage(t, filepath.Join(t.TempDir(), "events"))
+	_, err := s.Add(context.Background(), []byte("author"), testEvent("test1", "idx", time.Now().Add(-30*time.Second)))
+	require.NoError(t, err)
+	_, err = s.Add(context.Background(), []byte("author"), testEvent("test2", "idx", time.Now().Add(-2*time.Minute)))
+	require.NoError(t, err)
+
+	es, err := s.Get(context.Background(), "test", []byte("idx"))
+	assert.NoError(t, err)
+	require.Len(t, es, 1)
+	assert.Equal(t, []byte("test1"), es[0].ID)
+}
+
+func TestFile_reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events")
+	s, err := New(Config{Path: path, TTL: time.Minute})
+	require.NoError(t, err)
+	e1 := testEvent("test", "idx", time.Now())
+	e2 := testEvent("test", "idx", time.Now().Add(time.Second))
+	e3 := testEvent("test2", "idx", time.Now())
+	_, err = s.Add(context.Background(), []byte("author"), e1)
+	require.NoError(t, err)
+	_, err = s.Add(context.Background(), []byte("author"), e2)
+	require.NoError(t, err)
+	_, err = s.Add(context.Background(), []byte("author2"), e3)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	// Simulate a crash during a write:
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 1})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s = newStorage(t, path)
+	es, err := s.Get(context.Background(), "test", []byte("idx"))
+	require.NoError(t, err)
+	assert.Len(t, es, 2)
+	for _, e := range es {
+		if string(e.ID) == "test" {
+			assert.Equal(t, e2.MessageDate.Unix(), e.MessageDate.Unix())
+		}
+	}
+
+	// Events added after reload must be persisted too:
+	_, err = s.Add(context.Background(), []byte("author3"), testEvent("test3", "idx", time.Now()))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	s = newStorage(t, path)
+	es, err = s.Get(context.Background(), "test", []byte("idx"))
+	require.NoError(t, err)
+	assert.Len(t, es, 3)
+}
+
+func TestFile_compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events")
+	s := newStorage(t, path)
+	for i := 0; i < 10; i++ {
+		_, err := s.Add(context.Background(), []byte("author"), testEvent("test", "idx", time.Now().Add(time.Duration(i)*time.Millisecond)))
+		require.NoError(t, err)
+	}
+	before, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, 10, s.records)
+
+	require.NoError(t, s.compact())
+	after, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.records)
+	assert.Less(t, after.Size(), before.Size())
+
+	// The log file must be still writable after compaction:
+	_, err = s.Add(context.Background(), []byte("author"), testEvent("test2", "idx", time.Now()))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	s = newStorage(t, path)
+	es, err := s.Get(context.Background(), "test", []byte("idx"))
+	require.NoError(t, err)
+	assert.Len(t, es, 2)
+}
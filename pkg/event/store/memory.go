@@ -18,6 +18,7 @@ package store
 import (
 	"context"
 	"crypto/sha256"
+	"sort"
 	"sync"
 	"time"
 
@@ -31,6 +32,7 @@ type MemoryStorage struct {
 
 	ttl   time.Duration // Message TTL.
 	index map[[sha256.Size]byte]map[[sha256.Size]byte]*messages.Event
+	dates map[string][]dateEntry // Message keys sorted by the event date, by type.
 
 	// Variables used for message garbage collector.
 	gccount int // Increases every time a message is added.
@@ -43,6 +45,7 @@ func NewMemoryStorage(ttl time.Duration) *MemoryStorage {
 	return &MemoryStorage{
 		ttl:     ttl,
 		index:   map[[sha256.Size]byte]map[[sha256.Size]byte]*messages.Event{},
+		dates:   map[string][]dateEntry{},
 		gcevery: 100,
 	}
 }
@@ -58,7 +61,11 @@ func (m *MemoryStorage) Add(_ context.Context, author []byte, evt *messages.Even
 	}
 	currEvt, ok := m.index[hi][hu]
 	if !ok || (ok && currEvt.MessageDate.Before(evt.MessageDate)) {
+		if ok {
+			m.removeDate(currEvt, hi, hu)
+		}
 		m.index[hi][hu] = evt
+		m.insertDate(evt, hi, hu)
 		m.gc()
 	}
 	return !ok, nil
@@ -79,6 +86,59 @@ func (m *MemoryStorage) Get(_ context.Context, typ string, idx []byte) ([]*messa
 	return nil, nil
 }
 
+// List implements the store.Storage interface.
+func (m *MemoryStorage) List(_ context.Context, typ string, from, to time.Time, limit int) ([]*messages.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	dates := m.dates[typ]
+	var evts []*messages.Event
+	for i := sort.Search(len(dates), func(i int) bool { return !dates[i].date.Before(from) }); i < len(dates); i++ {
+		d := dates[i]
+		if !to.IsZero() && !d.date.Before(to) {
+			break
+		}
+		if limit > 0 && len(evts) >= limit && d.date.Unix() != evts[len(evts)-1].EventDate.Unix() {
+			break
+		}
+		if evt, ok := m.index[d.hi][d.hu]; ok {
+			evts = append(evts, evt)
+		}
+	}
+	return evts, nil
+}
+
+// dateEntry points to a message in the index.
+type dateEntry struct {
+	date   time.Time
+	hi, hu [sha256.Size]byte
+}
+
+// insertDate adds the message key to the list of keys sorted by the
+// event date.
+func (m *MemoryStorage) insertDate(evt *messages.Event, hi, hu [sha256.Size]byte) {
+	dates := m.dates[evt.Type]
+	i := sort.Search(len(dates), func(i int) bool { return dates[i].date.After(evt.EventDate) })
+	dates = append(dates, dateEntry{})
+	copy(dates[i+1:], dates[i:])
+	dates[i] = dateEntry{date: evt.EventDate, hi: hi, hu: hu}
+	m.dates[evt.Type] = dates
+}
+
+// removeDate removes the message key from the list of keys sorted by the
+// event date.
+func (m *MemoryStorage) removeDate(evt *messages.Event, hi, hu [sha256.Size]byte) {
+	dates := m.dates[evt.Type]
+	for i := sort.Search(len(dates), func(i int) bool { return !dates[i].date.Before(evt.EventDate) }); i < len(dates); i++ {
+		if !dates[i].date.Equal(evt.EventDate) {
+			break
+		}
+		if dates[i].hi == hi && dates[i].hu == hu {
+			m.dates[evt.Type] = append(dates[:i], dates[i+1:]...)
+			return
+		}
+	}
+}
+
 // Garbage Collector removes expired messages.
 func (m *MemoryStorage) gc() {
 	m.gccount++
@@ -106,6 +166,16 @@ func (m *MemoryStorage) gc() {
 			}
 		}
 	}
+	// Keys are sorted by the event date, so expired keys are at the
+	// beginning of the list:
+	for typ, dates := range m.dates {
+		n := sort.Search(len(dates), func(i int) bool { return time.Since(dates[i].date) < m.ttl })
+		if n == len(dates) {
+			delete(m.dates, typ)
+		} else if n > 0 {
+			m.dates[typ] = append([]dateEntry(nil), dates[n:]...)
+		}
+	}
 }
 
 func hashUnique(author []byte, id []byte) [sha256.Size]byte {
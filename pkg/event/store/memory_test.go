@@ -117,6 +117,84 @@ func TestMemory_Add_replacePreviousEvent(t *testing.T) {
 	assert.ElementsMatch(t, []*messages.Event{e2}, es)
 }
 
+func TestMemory_List(t *testing.T) {
+	m := NewMemoryStorage(time.Minute)
+	evt := func(typ string, id string, date int64) *messages.Event {
+		return &messages.Event{
+			Type:        typ,
+			ID:          []byte(id),
+			Index:       []byte(id),
+			MessageDate: time.Unix(date, 0),
+			EventDate:   time.Unix(date, 0),
+			Data:        map[string][]byte{},
+			Signatures:  map[string]messages.EventSignature{},
+		}
+	}
+	e1 := evt("test", "test1", 10)
+	e2 := evt("test", "test2", 20)
+	e3 := evt("test", "test3", 30)
+	e4 := evt("test2", "test4", 20)
+	for _, e := range []*messages.Event{e1, e2, e3, e4} {
+		_, err := m.Add(context.Background(), []byte("author"), e)
+		assert.NoError(t, err)
+	}
+
+	es, err := m.List(context.Background(), "test", time.Unix(10, 0), time.Unix(30, 0), 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []*messages.Event{e1, e2}, es)
+
+	es, err = m.List(context.Background(), "test", time.Unix(11, 0), time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []*messages.Event{e2, e3}, es)
+
+	es, err = m.List(context.Background(), "test3", time.Unix(0, 0), time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, es)
+}
+
+func TestMemory_List_limit(t *testing.T) {
+	m := NewMemoryStorage(time.Minute)
+	evt := func(id string, date int64) *messages.Event {
+		return &messages.Event{
+			Type:        "test",
+			ID:          []byte(id),
+			Index:       []byte(id),
+			MessageDate: time.Unix(date, 0),
+			EventDate:   time.Unix(date, 0),
+			Data:        map[string][]byte{},
+			Signatures:  map[string]messages.EventSignature{},
+		}
+	}
+	e1 := evt("test1", 30)
+	e2 := evt("test2", 20)
+	e3 := evt("test3", 10)
+	for _, e := range []*messages.Event{e1, e2, e3} {
+		_, err := m.Add(context.Background(), []byte("author1"), e)
+		assert.NoError(t, err)
+	}
+	_, err := m.Add(context.Background(), []byte("author2"), e2)
+	assert.NoError(t, err)
+
+	// Messages are sorted by the event date:
+	es, err := m.List(context.Background(), "test", time.Unix(0, 0), time.Time{}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*messages.Event{e3}, es)
+
+	// Messages with the same date as the last one must not be split:
+	es, err = m.List(context.Background(), "test", time.Unix(0, 0), time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*messages.Event{e3, e2, e2}, es)
+
+	// A newer message replaces the previous one:
+	e2b := evt("test2", 25)
+	e2b.MessageDate = time.Unix(40, 0)
+	_, err = m.Add(context.Background(), []byte("author1"), e2b)
+	assert.NoError(t, err)
+	es, err = m.List(context.Background(), "test", time.Unix(0, 0), time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*messages.Event{e3, e2, e2b, e1}, es)
+}
+
 func TestMemory_gc(t *testing.T) {
 	m := NewMemoryStorage(time.Minute)
 	_, err := m.Add(context.Background(), []byte("author"), &messages.Event{
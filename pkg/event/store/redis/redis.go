@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -210,7 +211,13 @@ func (r *Storage) Add(ctx context.Context, author []byte, evt *messages.Event) (
 		}
 		return nil
 	}, key)
-	return isNew, err
+	if err != nil {
+		return false, err
+	}
+	if err := r.indexEvt(ctx, key, evt); err != nil {
+		return false, err
+	}
+	return isNew, nil
 }
 
 // Get implements the store.Storage interface.
@@ -235,6 +242,99 @@ func (r *Storage) Get(ctx context.Context, typ string, idx []byte) ([]*messages.
 	return evts, err
 }
 
+// List implements the store.Storage interface.
+func (r *Storage) List(ctx context.Context, typ string, from, to time.Time, limit int) ([]*messages.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys, err := r.listKeys(ctx, typ, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	vals, err := r.redisMGet(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	var evts []*messages.Event
+	for _, val := range vals {
+		evt := &messages.Event{}
+		if err := evt.UnmarshallBinary([]byte(val)); err != nil {
+			continue
+		}
+		// Scores have a precision of one second, so the range must be
+		// checked again.
+		if evt.EventDate.Before(from) || (!to.IsZero() && !evt.EventDate.Before(to)) {
+			continue
+		}
+		evts = append(evts, evt)
+	}
+	sort.SliceStable(evts, func(i, j int) bool {
+		return evts[i].EventDate.Before(evts[j].EventDate)
+	})
+	return evts, nil
+}
+
+// listKeys returns keys of events of the given type with the event date
+// in the given range. If limit is greater than zero, at most limit keys
+// are returned, plus the remaining keys with the same score as the last
+// one, so events with the same date are never split.
+func (r *Storage) listKeys(ctx context.Context, typ string, from, to time.Time, limit int) ([]string, error) {
+	max := "+inf"
+	if !to.IsZero() {
+		max = strconv.FormatInt(to.Unix(), 10)
+	}
+	keysCmd := r.client.ZRangeByScoreWithScores(ctx, typeKey(typ), &redis.ZRangeBy{
+		Min:   strconv.FormatInt(from.Unix(), 10),
+		Max:   max,
+		Count: int64(limit),
+	})
+	if keysCmd.Err() != nil {
+		return nil, cmdError{cmd: keysCmd}
+	}
+	var keys []string
+	seen := map[string]bool{}
+	for _, z := range keysCmd.Val() {
+		if key, ok := z.Member.(string); ok {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	if limit <= 0 || len(keysCmd.Val()) < limit {
+		return keys, nil
+	}
+	last := strconv.FormatFloat(keysCmd.Val()[len(keysCmd.Val())-1].Score, 'f', -1, 64)
+	tailCmd := r.client.ZRangeByScore(ctx, typeKey(typ), &redis.ZRangeBy{Min: last, Max: last})
+	if tailCmd.Err() != nil {
+		return nil, cmdError{cmd: tailCmd}
+	}
+	for _, key := range tailCmd.Val() {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// indexEvt adds the event key to the sorted set of events of the same type,
+// which is used by the List method. The score is the event date, so expired
+// keys can be removed from the set using a score range.
+//
+// The set is updated outside the transaction in the Add method because it
+// belongs to a different slot than the event key.
+func (r *Storage) indexEvt(ctx context.Context, key string, evt *messages.Event) error {
+	tk := typeKey(evt.Type)
+	if cmd := r.client.ZAdd(ctx, tk, &redis.Z{Score: float64(evt.EventDate.Unix()), Member: key}); cmd.Err() != nil {
+		return cmdError{cmd: cmd}
+	}
+	exp := strconv.FormatInt(time.Now().Add(-r.ttl).Unix(), 10)
+	if cmd := r.client.ZRemRangeByScore(ctx, tk, "-inf", "("+exp); cmd.Err() != nil {
+		return cmdError{cmd: cmd}
+	}
+	return nil
+}
+
 // getAvailMem returns the available memory for the given author.
 //
 // Finds all the memory usage keys for given author and sums them up. The exact
@@ -340,11 +440,16 @@ func (r *Storage) redisMGet(ctx context.Context, keys ...string) ([]string, erro
 			}
 			return nil
 		})
-		if err != nil {
+		// Keys may expire between listing and fetching them, missing keys
+		// are skipped, the same as in the single node mode.
+		if err != nil && !errors.Is(err, redis.Nil) {
 			return nil, fmt.Errorf("redis: pipeline get %s: %w", strings.Join(keys, ", "), err)
 		}
 		var res []string
 		for _, cmd := range cmds {
+			if errors.Is(cmd.Err(), redis.Nil) {
+				continue
+			}
 			if err := cmd.Err(); err != nil {
 				return nil, cmdError{cmd: cmd}
 			}
@@ -416,6 +521,10 @@ func wildcardEvtKey(typ string, idx []byte) string {
 	return fmt.Sprintf("evt:%x:*", hashIdx(typ, idx))
 }
 
+func typeKey(typ string) string {
+	return fmt.Sprintf("type:%x", sha256.Sum256([]byte(typ)))
+}
+
 func memUsageKey(author []byte, eventDate time.Time) string {
 	return fmt.Sprintf("mem:%x:%x:{%x}", author, eventDate.Unix()/memUsageTimeQuantum, hashtag(author))
 }
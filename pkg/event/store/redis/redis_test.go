@@ -140,6 +140,51 @@ func TestRedis_Add_replacePreviousEvent(t *testing.T) {
 	assert.ElementsMatch(t, eventsToByteSlices([]*messages.Event{e2}), eventsToByteSlices(es))
 }
 
+func TestRedis_List(t *testing.T) {
+	ok, cfg := getConfig()
+	if !ok {
+		t.Skip()
+		return
+	}
+	typ := strconv.Itoa(rand.Int())
+	author := strconv.Itoa(rand.Int())
+	r, err := New(cfg)
+	require.NoError(t, err)
+	now := time.Now().Truncate(time.Second)
+	evt := func(typ string, id string, date time.Time) *messages.Event {
+		return &messages.Event{
+			Type:        typ,
+			ID:          []byte(id),
+			Index:       []byte(id),
+			MessageDate: date,
+			EventDate:   date,
+			Data:        map[string][]byte{},
+			Signatures:  map[string]messages.EventSignature{},
+		}
+	}
+	e1 := evt(typ, "test1", now.Add(-3*time.Second))
+	e2 := evt(typ, "test2", now.Add(-2*time.Second))
+	e3 := evt(typ, "test3", now.Add(-1*time.Second))
+	e4 := evt(typ+"2", "test4", now.Add(-2*time.Second))
+	for _, e := range []*messages.Event{e1, e2, e3, e4} {
+		_, err := r.Add(context.Background(), []byte(author), e)
+		require.NoError(t, err)
+	}
+
+	es, err := r.List(context.Background(), typ, now.Add(-3*time.Second), now.Add(-1*time.Second), 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, eventsToByteSlices([]*messages.Event{e1, e2}), eventsToByteSlices(es))
+
+	es, err = r.List(context.Background(), typ, now.Add(-2*time.Second), time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, eventsToByteSlices([]*messages.Event{e2, e3}), eventsToByteSlices(es))
+
+	// Messages are sorted by the event date and limited:
+	es, err = r.List(context.Background(), typ, now.Add(-3*time.Second), time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, eventsToByteSlices([]*messages.Event{e1, e2}), eventsToByteSlices(es))
+}
+
 func TestRedis_memoryLimit(t *testing.T) {
 	ok, cfg := getConfig()
 	cfg.MemoryLimit = 60 // 60 is enough for one message
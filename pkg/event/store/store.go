@@ -19,7 +19,11 @@ import (
 	"context"
 	"encoding/hex"
 	"errors"
+	"sync"
+	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/eip712"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
@@ -28,13 +32,30 @@ import (
 
 const LoggerTag = "EVENT_STORE"
 
+// subscriptionBufferSize is the size of the buffer of subscription channels.
+const subscriptionBufferSize = 1024
+
+// attestedEventType is the type of records used to mark events as attested.
+// Events of this type are never accepted from the transport, because it is
+// not possible to add it to the list of supported event types.
+const attestedEventType = "$attested"
+
+// attestedAuthor is the author of records used to mark events as attested.
+var attestedAuthor = []byte("$attested")
+
 // EventStore listens for event messages using the transport and stores
 // them for later use.
 type EventStore struct {
+	mu sync.Mutex
+
 	ctx        context.Context
 	eventTypes []string
 	storage    Storage
 	transport  transport.Transport
+	signer     ethereum.Signer
+	feeds      []ethereum.Address
+	domain     *eip712.Domain
+	subs       map[chan *messages.Event]struct{}
 	log        log.Logger
 	waitCh     chan error
 }
@@ -48,6 +69,17 @@ type Config struct {
 	Storage Storage
 	// Transport is a transport interface used to fetch events from Oracles.
 	Transport transport.Transport
+	// Signer is used to verify event signatures. If nil, signatures are
+	// not verified.
+	Signer ethereum.Signer
+	// Feeds is a list of addresses allowed to sign events. If empty, events
+	// must be signed by the author of the message. Used only if the Signer
+	// is set.
+	Feeds []ethereum.Address
+	// Domain is the EIP-712 domain used to verify "eip712" signatures. If
+	// nil, such signatures are rejected. The Signer must implement the
+	// ethereum.TypedDataSigner interface if the domain is set.
+	Domain *eip712.Domain
 	// Logger is a current logger interface used by the EventStore.
 	// The Logger is required to monitor asynchronous processes.
 	Logger log.Logger
@@ -62,6 +94,14 @@ type Storage interface {
 	// Get returns messages form the store for the given type and index. If the
 	// message does not exist, nil will be returned. The method is thread-safe.
 	Get(ctx context.Context, typ string, idx []byte) ([]*messages.Event, error)
+	// List returns messages form the store for the given type with the event
+	// date in the [from, to) range, sorted by the event date. If to is zero,
+	// the range is not bounded from above. If limit is greater than zero, at
+	// most limit messages are returned, except that messages with the same
+	// event date, in seconds, as the last one are always returned together,
+	// so messages for the same event are not split. The method is
+	// thread-safe.
+	List(ctx context.Context, typ string, from, to time.Time, limit int) ([]*messages.Event, error)
 }
 
 // New returns a new instance of the EventStore struct.
@@ -72,6 +112,11 @@ func New(cfg Config) (*EventStore, error) {
 	if cfg.Transport == nil {
 		return nil, errors.New("transport must not be nil")
 	}
+	if cfg.Domain != nil {
+		if _, ok := cfg.Signer.(ethereum.TypedDataSigner); !ok {
+			return nil, errors.New("signer must support EIP-712 typed data if the domain is set")
+		}
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = null.New()
 	}
@@ -79,6 +124,10 @@ func New(cfg Config) (*EventStore, error) {
 		eventTypes: cfg.EventTypes,
 		storage:    cfg.Storage,
 		transport:  cfg.Transport,
+		signer:     cfg.Signer,
+		feeds:      cfg.Feeds,
+		domain:     cfg.Domain,
+		subs:       map[chan *messages.Event]struct{}{},
 		log:        cfg.Logger.WithField("tag", LoggerTag),
 		waitCh:     make(chan error),
 	}, nil
@@ -108,6 +157,64 @@ func (e *EventStore) Events(ctx context.Context, typ string, idx []byte) ([]*mes
 	return e.storage.Get(ctx, typ, idx)
 }
 
+// List returns events for the given type with the event date in the
+// [from, to) range, sorted by the event date. If to is zero, the range is
+// not bounded from above. See Storage.List for the meaning of limit.
+// The method is thread-safe.
+func (e *EventStore) List(ctx context.Context, typ string, from, to time.Time, limit int) ([]*messages.Event, error) {
+	return e.storage.List(ctx, typ, from, to, limit)
+}
+
+// MarkAttested records that the event identified by the given key has
+// reached the quorum. It returns true if the event was not marked before.
+// Marks are kept in the storage, so they survive restarts, and they expire
+// together with the event.
+func (e *EventStore) MarkAttested(ctx context.Context, evt *messages.Event, key []byte) (bool, error) {
+	return e.storage.Add(ctx, attestedAuthor, &messages.Event{
+		Type:        attestedEventType,
+		ID:          key,
+		Index:       key,
+		EventDate:   evt.EventDate,
+		MessageDate: evt.EventDate,
+		Data:        map[string][]byte{},
+		Signatures:  map[string]messages.EventSignature{},
+	})
+}
+
+// Subscribe returns a channel that receives events that were not stored
+// before, that is new events and events from new authors (signers). Events
+// are not sent if the subscriber is too slow to receive them. The channel
+// is closed when the context is canceled.
+func (e *EventStore) Subscribe(ctx context.Context) <-chan *messages.Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ch := make(chan *messages.Event, subscriptionBufferSize)
+	e.subs[ch] = struct{}{}
+	go func() {
+		<-ctx.Done()
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.subs, ch)
+		close(ch)
+	}()
+	return ch
+}
+
+// notify sends the event to all subscribers.
+func (e *EventStore) notify(evt *messages.Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for ch := range e.subs {
+		select {
+		case ch <- evt:
+		default:
+			e.log.
+				WithField("id", hex.EncodeToString(evt.ID)).
+				Warn("Subscriber is too slow, the event was not sent to it")
+		}
+	}
+}
+
 func (e *EventStore) eventCollectorRoutine() {
 	for {
 		select {
@@ -126,6 +233,19 @@ func (e *EventStore) eventCollectorRoutine() {
 			if !e.isEventSupported(evt) {
 				continue
 			}
+			if e.signer != nil {
+				if err := e.verify(msg.Author, evt); err != nil {
+					e.log.
+						WithError(err).
+						WithFields(log.Fields{
+							"id":   hex.EncodeToString(evt.ID),
+							"type": evt.Type,
+							"from": msg.Author,
+						}).
+						Warn("Event rejected")
+					continue
+				}
+			}
 			isNew, err := e.storage.Add(e.ctx, msg.Author, evt)
 			e.log.
 				WithFields(log.Fields{
@@ -144,11 +264,17 @@ func (e *EventStore) eventCollectorRoutine() {
 				e.log.WithError(err).Error("Unable to store the event")
 				continue
 			}
+			if isNew {
+				e.notify(evt)
+			}
 		}
 	}
 }
 
 func (e *EventStore) isEventSupported(evt *messages.Event) bool {
+	if evt.Type == attestedEventType {
+		return false
+	}
 	for _, typ := range e.eventTypes {
 		if typ == evt.Type {
 			return true
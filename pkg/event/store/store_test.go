@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
@@ -88,3 +89,137 @@ func TestEventStore(t *testing.T) {
 	assert.Equal(t, event.Data, events[0].Data)
 	assert.Equal(t, event.Signatures, events[0].Signatures)
 }
+
+func TestEventStore_MarkAttested(t *testing.T) {
+	ctx := context.Background()
+	tra := local.New([]byte("test"), 1, map[string]transport.Message{messages.EventV1MessageName: (*messages.Event)(nil)})
+	mem := NewMemoryStorage(time.Minute)
+	newStore := func() *EventStore {
+		evs, err := New(Config{
+			EventTypes: []string{"test"},
+			Storage:    mem,
+			Transport:  tra,
+			Logger:     null.New(),
+		})
+		require.NoError(t, err)
+		return evs
+	}
+	evs := newStore()
+	event := &messages.Event{Type: "test", EventDate: time.Now()}
+
+	isNew, err := evs.MarkAttested(ctx, event, []byte("key1"))
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	isNew, err = evs.MarkAttested(ctx, event, []byte("key1"))
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	isNew, err = evs.MarkAttested(ctx, event, []byte("key2"))
+	require.NoError(t, err)
+	assert.True(t, isNew)
+
+	// Marks are kept in the storage, so they are shared with a new instance
+	// of the event store:
+	isNew, err = newStore().MarkAttested(ctx, event, []byte("key1"))
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	// Marks must not be visible as events:
+	events, err := evs.List(ctx, "test", time.Time{}, time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestEventStore_Subscribe(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	tra := local.New([]byte("test"), 3, map[string]transport.Message{messages.EventV1MessageName: (*messages.Event)(nil)})
+
+	evs, err := New(Config{
+		EventTypes: []string{"test"},
+		Storage:    NewMemoryStorage(time.Minute),
+		Transport:  tra,
+		Logger:     null.New(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, tra.Start(ctx))
+	require.NoError(t, evs.Start(ctx))
+	defer func() {
+		cancelFunc()
+		require.NoError(t, <-evs.Wait())
+		require.NoError(t, <-tra.Wait())
+	}()
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	ch := evs.Subscribe(subCtx)
+
+	event := &messages.Event{
+		Type:        "test",
+		ID:          []byte("test"),
+		Index:       []byte("idx"),
+		EventDate:   time.Now(),
+		MessageDate: time.Now(),
+		Data:        map[string][]byte{"test": []byte("test")},
+		Signatures:  map[string]messages.EventSignature{},
+	}
+	replaced := *event
+	replaced.MessageDate = event.MessageDate.Add(time.Second)
+	require.NoError(t, tra.Broadcast(messages.EventV1MessageName, event))
+	require.NoError(t, tra.Broadcast(messages.EventV1MessageName, &replaced))                      // not a new event
+	require.NoError(t, tra.Broadcast(messages.EventV1MessageName, &messages.Event{Type: "other"})) // unsupported type
+
+	select {
+	case evt := <-ch:
+		assert.Equal(t, event.ID, evt.ID)
+	case <-time.After(time.Second):
+		require.Fail(t, "event not received")
+	}
+	select {
+	case evt := <-ch:
+		require.Fail(t, "unexpected event", evt)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Channel must be closed after the context is canceled.
+	subCancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestEventStore_rejectInvalidSignature(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	tra := local.New([]byte("test"), 1, map[string]transport.Message{messages.EventV1MessageName: (*messages.Event)(nil)})
+
+	mem := NewMemoryStorage(time.Minute)
+	evs, err := New(Config{
+		EventTypes: []string{"test"},
+		Storage:    mem,
+		Transport:  tra,
+		Signer:     geth.NewSigner(nil),
+		Logger:     null.New(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, tra.Start(ctx))
+	require.NoError(t, evs.Start(ctx))
+	defer func() {
+		cancelFunc()
+		require.NoError(t, <-evs.Wait())
+		require.NoError(t, <-tra.Wait())
+	}()
+
+	require.NoError(t, tra.Broadcast(messages.EventV1MessageName, &messages.Event{
+		Type:        "test",
+		ID:          []byte("test"),
+		Index:       []byte("idx"),
+		EventDate:   time.Now(),
+		MessageDate: time.Now(),
+		Data:        map[string][]byte{"hash": []byte("hash")},
+		Signatures:  map[string]messages.EventSignature{"ethereum": {Signer: []byte("val"), Signature: []byte("val")}},
+	}))
+
+	time.Sleep(100 * time.Millisecond)
+
+	events, err := evs.Events(context.Background(), "test", []byte("idx"))
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,95 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/eip712"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+// ethereumSignatureKey is the key of the Ethereum signature in the event
+// signatures map. Signatures are created over the "hash" field of the
+// event data.
+const ethereumSignatureKey = "ethereum"
+
+var ErrMissingSignature = errors.New("event has no signatures")
+var ErrMissingHash = errors.New("event has no hash field")
+var ErrUnsupportedSignature = errors.New("unsupported signature type")
+var ErrInvalidSignature = errors.New("invalid signature")
+var ErrUnauthorizedSigner = errors.New("unauthorized signer")
+
+// verify verifies all signatures of the event. The recovered signer address
+// must match the address in the signature and it must be one of the
+// allowed feeds, or the message author if the list of feeds is empty.
+//
+// EIP-712 signatures are supported only if the EIP-712 domain is configured.
+func (e *EventStore) verify(author []byte, evt *messages.Event) error {
+	if len(evt.Signatures) == 0 {
+		return ErrMissingSignature
+	}
+	for typ, s := range evt.Signatures {
+		if typ != ethereumSignatureKey && (typ != eip712.SignatureKey || e.domain == nil) {
+			return fmt.Errorf("%w: %s", ErrUnsupportedSignature, typ)
+		}
+		h, ok := evt.Data["hash"]
+		if !ok {
+			return ErrMissingHash
+		}
+		if len(s.Signature) != ethereum.SignatureLength {
+			return ErrInvalidSignature
+		}
+		addr, err := e.recover(typ, ethereum.SignatureFromBytes(s.Signature), h, evt)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+		}
+		if !bytes.Equal(addr.Bytes(), s.Signer) {
+			return fmt.Errorf("%w: recovered address %s does not match the signer", ErrInvalidSignature, addr)
+		}
+		if !e.isAuthorized(author, *addr) {
+			return fmt.Errorf("%w: %s", ErrUnauthorizedSigner, addr)
+		}
+	}
+	return nil
+}
+
+// recover returns the address that created the signature of the given type.
+func (e *EventStore) recover(typ string, sig ethereum.Signature, hash []byte, evt *messages.Event) (*ethereum.Address, error) {
+	if typ == eip712.SignatureKey {
+		td, err := eip712.TypedData(*e.domain, evt)
+		if err != nil {
+			return nil, err
+		}
+		return e.signer.(ethereum.TypedDataSigner).RecoverTypedData(sig, td)
+	}
+	return e.signer.Recover(sig, hash)
+}
+
+func (e *EventStore) isAuthorized(author []byte, addr ethereum.Address) bool {
+	if len(e.feeds) == 0 {
+		return bytes.Equal(author, addr.Bytes())
+	}
+	for _, f := range e.feeds {
+		if f == addr {
+			return true
+		}
+	}
+	return false
+}
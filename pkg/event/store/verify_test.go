@@ -0,0 +1,131 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
+	"github.com/chronicleprotocol/oracle-suite/pkg/event/eip712"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+func TestEventStore_verify(t *testing.T) {
+	key1, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	key2, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr1 := crypto.PubkeyToAddress(key1.PublicKey)
+	addr2 := crypto.PubkeyToAddress(key2.PublicKey)
+	hash := crypto.Keccak256([]byte("event"))
+
+	sign := func(data []byte) []byte {
+		msg := []byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data))
+		s, err := crypto.Sign(crypto.Keccak256(msg), key1)
+		require.NoError(t, err)
+		s[64] += 27
+		return s
+	}
+	event := func(sigs map[string]messages.EventSignature) *messages.Event {
+		return &messages.Event{Type: "test", Data: map[string][]byte{"hash": hash}, Signatures: sigs}
+	}
+	domain := &eip712.Domain{Name: "Lair", Version: "1", ChainID: 1}
+	otherDomain := &eip712.Domain{Name: "Lair", Version: "1", ChainID: 2}
+	signTyped := func(d *eip712.Domain) []byte {
+		td, err := eip712.TypedData(*d, event(nil))
+		require.NoError(t, err)
+		h, err := geth.TypedDataHash(td)
+		require.NoError(t, err)
+		s, err := crypto.Sign(h, key1)
+		require.NoError(t, err)
+		s[64] += 27
+		return s
+	}
+	valid := map[string]messages.EventSignature{"ethereum": {Signer: addr1.Bytes(), Signature: sign(hash)}}
+	validTyped := map[string]messages.EventSignature{"eip712": {Signer: addr1.Bytes(), Signature: signTyped(domain)}}
+
+	tests := []struct {
+		feeds  []ethereum.Address
+		domain *eip712.Domain
+		author []byte
+		event  *messages.Event
+		err    error
+	}{
+		{feeds: []ethereum.Address{addr1}, event: event(valid)},
+		{feeds: []ethereum.Address{addr2, addr1}, author: addr2.Bytes(), event: event(valid)},
+		{author: addr1.Bytes(), event: event(valid)},
+		{author: addr2.Bytes(), event: event(valid), err: ErrUnauthorizedSigner},
+		{feeds: []ethereum.Address{addr2}, author: addr1.Bytes(), event: event(valid), err: ErrUnauthorizedSigner},
+		{
+			feeds: []ethereum.Address{addr1, addr2},
+			event: event(map[string]messages.EventSignature{"ethereum": {Signer: addr2.Bytes(), Signature: sign(hash)}}),
+			err:   ErrInvalidSignature,
+		},
+		{
+			feeds: []ethereum.Address{addr1},
+			event: event(map[string]messages.EventSignature{"ethereum": {Signer: addr1.Bytes(), Signature: sign([]byte("other"))}}),
+			err:   ErrInvalidSignature,
+		},
+		{
+			feeds: []ethereum.Address{addr1},
+			event: event(map[string]messages.EventSignature{"ethereum": {Signer: addr1.Bytes(), Signature: []byte("short")}}),
+			err:   ErrInvalidSignature,
+		},
+		{
+			feeds: []ethereum.Address{addr1},
+			event: event(map[string]messages.EventSignature{"other": {Signer: addr1.Bytes(), Signature: sign(hash)}}),
+			err:   ErrUnsupportedSignature,
+		},
+		{
+			feeds: []ethereum.Address{addr1},
+			event: &messages.Event{Type: "test", Data: map[string][]byte{}, Signatures: valid},
+			err:   ErrMissingHash,
+		},
+		{feeds: []ethereum.Address{addr1}, event: event(nil), err: ErrMissingSignature},
+		{feeds: []ethereum.Address{addr1}, domain: domain, event: event(validTyped)},
+		{feeds: []ethereum.Address{addr1}, domain: domain, event: event(valid)},
+		{feeds: []ethereum.Address{addr1}, event: event(validTyped), err: ErrUnsupportedSignature},
+		{
+			feeds:  []ethereum.Address{addr1},
+			domain: domain,
+			event:  event(map[string]messages.EventSignature{"eip712": {Signer: addr1.Bytes(), Signature: signTyped(otherDomain)}}),
+			err:    ErrInvalidSignature,
+		},
+		{
+			feeds:  []ethereum.Address{addr1},
+			domain: domain,
+			event:  event(map[string]messages.EventSignature{"eip712": {Signer: addr1.Bytes(), Signature: sign(hash)}}),
+			err:    ErrInvalidSignature,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			evs := &EventStore{signer: geth.NewSigner(nil), feeds: tt.feeds, domain: tt.domain}
+			err := evs.verify(tt.author, tt.event)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -38,12 +38,14 @@ type Ghost struct {
 	ctx    context.Context
 	waitCh chan error
 
-	priceProvider provider.Provider
-	signer        ethereum.Signer
-	transport     transport.Transport
-	interval      time.Duration
-	pairs         []provider.Pair
-	log           log.Logger
+	priceProvider  provider.Provider
+	signer         ethereum.Signer
+	rotationSigner ethereum.Signer
+	rotationUntil  time.Time
+	transport      transport.Transport
+	interval       time.Duration
+	pairs          []provider.Pair
+	log            log.Logger
 }
 
 // Config is the configuration for the Ghost.
@@ -55,6 +57,15 @@ type Config struct {
 	// Signer is an instance of the ethereum.Signer which will be used to
 	// sign prices.
 	Signer ethereum.Signer
+	// RotationSigner is an optional signer with a new feeder key. During
+	// a key rotation window, prices are signed and broadcast with both
+	// keys, so relayers can accept prices before and after the new key is
+	// lifted on the Median contracts.
+	RotationSigner ethereum.Signer
+	// RotationUntil is the end of the key rotation window. After this time,
+	// prices are signed only with the RotationSigner. If zero, both keys are
+	// used until the configuration is changed.
+	RotationUntil time.Time
 	// Transport is an implementation of transport used to send prices to
 	// relayers.
 	Transport transport.Transport
@@ -83,13 +94,15 @@ func New(cfg Config) (*Ghost, error) {
 		return nil, err
 	}
 	g := &Ghost{
-		waitCh:        make(chan error),
-		priceProvider: cfg.PriceProvider,
-		signer:        cfg.Signer,
-		transport:     cfg.Transport,
-		interval:      cfg.Interval,
-		pairs:         pairs,
-		log:           cfg.Logger.WithField("tag", LoggerTag),
+		waitCh:         make(chan error),
+		priceProvider:  cfg.PriceProvider,
+		signer:         cfg.Signer,
+		rotationSigner: cfg.RotationSigner,
+		rotationUntil:  cfg.RotationUntil,
+		transport:      cfg.Transport,
+		interval:       cfg.Interval,
+		pairs:          pairs,
+		log:            cfg.Logger.WithField("tag", LoggerTag),
 	}
 	return g, nil
 }
@@ -116,8 +129,6 @@ func (g *Ghost) Wait() chan error {
 // broadcast sends price for single pair to the network. This method uses
 // current price from the Provider, so it must be updated beforehand.
 func (g *Ghost) broadcast(pair provider.Pair) error {
-	var err error
-
 	tick, err := g.priceProvider.Price(pair)
 	if err != nil {
 		return err
@@ -125,13 +136,21 @@ func (g *Ghost) broadcast(pair provider.Pair) error {
 	if tick.Error != "" {
 		return errors.New(tick.Error)
 	}
+	for _, signer := range g.signers() {
+		if err := g.broadcastWithSigner(pair, tick, signer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+func (g *Ghost) broadcastWithSigner(pair provider.Pair, tick *provider.Price, signer ethereum.Signer) error {
 	// Create price:
 	price := &oracle.Price{Wat: pair.Base + pair.Quote, Age: tick.Time}
 	price.SetFloat64Price(tick.Price)
 
 	// Sign price:
-	err = price.Sign(g.signer)
+	err := price.Sign(signer)
 	if err != nil {
 		return err
 	}
@@ -147,7 +166,18 @@ func (g *Ghost) broadcast(pair provider.Pair) error {
 	if err := g.transport.Broadcast(messages.PriceV1MessageName, msg.AsV1()); err != nil {
 		return err
 	}
-	return err
+	return nil
+}
+
+// signers returns the list of signers that should be used to sign prices.
+func (g *Ghost) signers() []ethereum.Signer {
+	if g.rotationSigner == nil {
+		return []ethereum.Signer{g.signer}
+	}
+	if !g.rotationUntil.IsZero() && time.Now().After(g.rotationUntil) {
+		return []ethereum.Signer{g.rotationSigner}
+	}
+	return []ethereum.Signer{g.signer, g.rotationSigner}
 }
 
 // broadcasterRoutine creates an asynchronous loop which fetches prices from exchanges and then
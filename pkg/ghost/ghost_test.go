@@ -205,6 +205,80 @@ func TestGhost_Broadcast(t *testing.T) {
 	}
 }
 
+func TestGhost_BroadcastWithRotationSigner(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer ctxCancel()
+
+	pro := &priceMocks.Provider{}
+	sig := &ethereumMocks.Signer{}
+	rot := &ethereumMocks.Signer{}
+	tra := local.New([]byte("test"), 0, map[string]transport.Message{
+		messages.PriceV0MessageName: (*messages.Price)(nil),
+		messages.PriceV1MessageName: (*messages.Price)(nil),
+	})
+	_ = tra.Start(ctx)
+	defer func() {
+		<-tra.Wait()
+	}()
+
+	gho, err := New(Config{
+		Pairs:          []string{"AAA/BBB"},
+		PriceProvider:  pro,
+		Signer:         sig,
+		RotationSigner: rot,
+		Transport:      tra,
+		Interval:       time.Second,
+	})
+	require.NoError(t, err)
+
+	pro.On("Price", provider.Pair{Base: "AAA", Quote: "BBB"}).Return(PriceAAABBB, nil).Times(1)
+	sig.On("Signature", PriceAAABBBHash).Return(ethereum.SignatureFromBytes(bytes.Repeat([]byte{0xAA}, 65)), nil)
+	rot.On("Signature", PriceAAABBBHash).Return(ethereum.SignatureFromBytes(bytes.Repeat([]byte{0xBB}, 65)), nil)
+
+	require.NoError(t, gho.Start(ctx))
+	defer func() {
+		<-gho.Wait()
+	}()
+
+	// Wait for two messages, one signed by each key.
+	var prices []*messages.Price
+	for len(prices) < 2 {
+		select {
+		case <-tra.Messages(messages.PriceV0MessageName):
+		case msg := <-tra.Messages(messages.PriceV1MessageName):
+			prices = append(prices, msg.Message.(*messages.Price))
+		}
+	}
+	ctxCancel()
+
+	assertPrice(t, PriceAAABBB, prices[0])
+	assert.Equal(t, "AAABBB", prices[1].Price.Wat)
+	assert.Equal(t, prices[0].Price.Val, prices[1].Price.Val)
+	assert.Equal(t, uint8(0xBB), prices[1].Price.V)
+}
+
+func TestGhost_Signers(t *testing.T) {
+	sig := &ethereumMocks.Signer{}
+	rot := &ethereumMocks.Signer{}
+	tests := []struct {
+		name           string
+		rotationSigner ethereum.Signer
+		rotationUntil  time.Time
+		want           []ethereum.Signer
+	}{
+		{name: "no-rotation", want: []ethereum.Signer{sig}},
+		{name: "rotation", rotationSigner: rot, want: []ethereum.Signer{sig, rot}},
+		{name: "rotation-window", rotationSigner: rot, rotationUntil: time.Now().Add(time.Hour), want: []ethereum.Signer{sig, rot}},
+		{name: "rotation-window-ended", rotationSigner: rot, rotationUntil: time.Now().Add(-time.Hour), want: []ethereum.Signer{rot}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Ghost{signer: sig, rotationSigner: tt.rotationSigner, rotationUntil: tt.rotationUntil}
+			assert.Equal(t, tt.want, g.signers())
+		})
+	}
+}
+
 func TestGhost_InvalidConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,509 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+const LoggerTag = "MONITOR"
+
+// Names of the alerting rules.
+const (
+	RuleStaleAge     = "staleAge"
+	RuleDeviation    = "deviation"
+	RuleBarChanged   = "barChanged"
+	RuleFeedsChanged = "feedsChanged"
+	RuleReadFailed   = "readFailed"
+)
+
+// AlertStatus describes whether the alert condition started or ended.
+type AlertStatus string
+
+const (
+	// AlertFiring is used when the alert condition is met. Alerts for rules
+	// that detect changes are always firing.
+	AlertFiring AlertStatus = "firing"
+	// AlertResolved is used when the alert condition is no longer met.
+	AlertResolved AlertStatus = "resolved"
+)
+
+// Config is the configuration for Monitor.
+type Config struct {
+	// Contracts is the list of monitored Median contracts.
+	Contracts []*Contract
+	// Reference is an optional price provider used to get reference prices
+	// for the deviation rule.
+	Reference provider.Provider
+	// Interval describes how often contracts should be checked.
+	Interval time.Duration
+	// Rules contains the configuration of the alerting rules.
+	Rules Rules
+	// Webhooks is a list of HTTP endpoints notified about alerts.
+	Webhooks []Webhook
+	// Logger is a current logger interface used by the Monitor. Alerts and
+	// the state of monitored contracts are logged, so they may be turned into
+	// metrics by the logger.
+	Logger log.Logger
+}
+
+// Contract is a single monitored Median contract.
+type Contract struct {
+	// Name is the name of the contract used in logs and alerts, e.g. ETHUSD.
+	Name string
+	// Pair is the asset pair of the reference price. If empty, the deviation
+	// rule is not evaluated for the contract.
+	Pair provider.Pair
+	// Median is the instance of the oracle.Median which is the interface for
+	// the contract.
+	Median oracle.Median
+}
+
+// Rules contains the configuration of the alerting rules. Rules with zero
+// values are disabled.
+type Rules struct {
+	// MaxAge is the maximum time since the last update of the contract.
+	MaxAge time.Duration
+	// MaxDeviation is the maximum difference between the contract price and
+	// the reference price, in percent.
+	MaxDeviation float64
+	// BarChanged enables alerts when the quorum of the contract changes.
+	BarChanged bool
+	// FeedsChanged enables alerts when the list of feeds authorized to update
+	// the contract changes.
+	FeedsChanged bool
+	// ReadFailed enables alerts when the state of the contract cannot be
+	// read.
+	ReadFailed bool
+}
+
+// Alert is sent when an alerting rule is triggered.
+type Alert struct {
+	Rule     string      `json:"rule"`
+	Status   AlertStatus `json:"status"`
+	Contract string      `json:"contract"`
+	Address  string      `json:"address"`
+	Message  string      `json:"message"`
+	Time     time.Time   `json:"time"`
+}
+
+// state is the last observed state of a contract.
+type state struct {
+	read   bool // True if the contract state was read at least once.
+	age    time.Time
+	bar    int64
+	feeds  []ethereum.Address
+	active map[string]bool // Rules that are currently firing.
+	fired  map[string]int  // Number of firing alerts sent for every rule.
+}
+
+// reading is the state of a contract read during a single check.
+type reading struct {
+	age   time.Time
+	val   *big.Int
+	bar   int64
+	feeds []ethereum.Address
+}
+
+// alertFunc adds an alert to the list of alerts triggered during a check.
+type alertFunc func(rule string, status AlertStatus, msg string, args ...interface{})
+
+// Monitor periodically reads the state of Median contracts and evaluates
+// alerting rules.
+//
+// On every check, the age, price, quorum and feeds of every contract are
+// read and logged along with the reference price and its deviation. The
+// state of every enabled rule is logged as well. Alerts are logged and sent
+// to webhooks. If the contract cannot be read, the stale age rule is
+// evaluated using the last known age. Alerts for the stale age and deviation
+// rules are sent when the condition starts and when it is resolved, alerts
+// for the other rules are sent once for every change.
+type Monitor struct {
+	ctx    context.Context
+	waitCh chan error
+
+	contracts []*Contract
+	reference provider.Provider
+	interval  time.Duration
+	rules     Rules
+	webhooks  []*webhook
+	states    map[*Contract]*state
+	log       log.Logger
+}
+
+// New returns a new instance of the Monitor struct.
+func New(cfg Config) (*Monitor, error) {
+	if cfg.Interval == 0 {
+		return nil, errors.New("interval is not set")
+	}
+	if len(cfg.Contracts) == 0 {
+		return nil, errors.New("no contracts provided")
+	}
+	for _, c := range cfg.Contracts {
+		if c.Median == nil {
+			return nil, fmt.Errorf("median of the %s contract must not be nil", c.Name)
+		}
+		if !c.Pair.Empty() && cfg.Reference == nil && cfg.Rules.MaxDeviation > 0 {
+			return nil, errors.New("reference price provider must not be nil")
+		}
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	m := &Monitor{
+		waitCh:    make(chan error),
+		contracts: cfg.Contracts,
+		reference: cfg.Reference,
+		interval:  cfg.Interval,
+		rules:     cfg.Rules,
+		states:    map[*Contract]*state{},
+		log:       cfg.Logger.WithField("tag", LoggerTag),
+	}
+	for _, w := range cfg.Webhooks {
+		if w.URL == "" {
+			return nil, errors.New("webhook URL must not be empty")
+		}
+		m.webhooks = append(m.webhooks, newWebhook(w, m.log))
+	}
+	return m, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (m *Monitor) Start(ctx context.Context) error {
+	if m.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	m.log.Info("Starting")
+	m.ctx = ctx
+	for _, w := range m.webhooks {
+		go w.deliveryRoutine(ctx)
+	}
+	go m.monitorRoutine()
+	go m.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (m *Monitor) Wait() chan error {
+	return m.waitCh
+}
+
+func (m *Monitor) monitorRoutine() {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+	for {
+		for _, c := range m.contracts {
+			for _, a := range m.check(m.ctx, c) {
+				m.notify(a)
+			}
+		}
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// check reads the state of the contract, logs it, and evaluates alerting
+// rules. It returns the list of triggered alerts.
+func (m *Monitor) check(ctx context.Context, c *Contract) []Alert {
+	fields := log.Fields{
+		"contract": c.Name,
+		"address":  c.Median.Address().String(),
+	}
+	st, ok := m.states[c]
+	if !ok {
+		st = &state{active: map[string]bool{}, fired: map[string]int{}}
+		m.states[c] = st
+	}
+	defer m.logAlertState(c, st)
+
+	now := time.Now()
+	var alerts []Alert
+	alert := func(rule string, status AlertStatus, msg string, args ...interface{}) {
+		if status == AlertFiring {
+			st.fired[rule]++
+		}
+		alerts = append(alerts, Alert{
+			Rule:     rule,
+			Status:   status,
+			Contract: c.Name,
+			Address:  c.Median.Address().String(),
+			Message:  fmt.Sprintf(msg, args...),
+			Time:     now,
+		})
+	}
+
+	r, err := m.read(ctx, c)
+	if err != nil {
+		m.log.WithError(err).WithFields(fields).Error("Unable to read the contract state")
+		if m.rules.ReadFailed && !st.active[RuleReadFailed] {
+			st.active[RuleReadFailed] = true
+			alert(RuleReadFailed, AlertFiring, "Unable to read the contract state: %s", err)
+		}
+		if st.read {
+			m.checkStaleAge(st, now, alert)
+		}
+		return alerts
+	}
+	if st.active[RuleReadFailed] {
+		st.active[RuleReadFailed] = false
+		alert(RuleReadFailed, AlertResolved, "The contract state was read")
+	}
+	if !st.read {
+		st.read = true
+		st.bar = r.bar
+		st.feeds = r.feeds
+	}
+	st.age = r.age
+
+	price := valToFloat64(r.val)
+	fields["val"] = r.val.String()
+	fields["price"] = price
+	fields["age"] = r.age.UTC().Format(time.RFC3339)
+	fields["ageSeconds"] = now.Sub(r.age).Seconds()
+	fields["bar"] = r.bar
+	fields["feeds"] = len(r.feeds)
+
+	// Stale age:
+	m.checkStaleAge(st, now, alert)
+
+	// Deviation from the reference price:
+	if m.rules.MaxDeviation > 0 && !c.Pair.Empty() {
+		ref, err := m.referencePrice(c.Pair)
+		if err != nil {
+			m.log.WithError(err).WithFields(fields).Warn("Unable to get reference price")
+		} else {
+			deviation := math.Abs(price-ref) / ref * 100
+			fields["reference"] = ref
+			fields["deviation"] = deviation
+			exceeded := deviation > m.rules.MaxDeviation
+			if exceeded != st.active[RuleDeviation] {
+				st.active[RuleDeviation] = exceeded
+				if exceeded {
+					alert(RuleDeviation, AlertFiring, "The contract price %f deviates from the reference price %f by %.2f%%", price, ref, deviation)
+				} else {
+					alert(RuleDeviation, AlertResolved, "The contract price %f is close to the reference price %f", price, ref)
+				}
+			}
+		}
+	}
+
+	// Bar changed:
+	if m.rules.BarChanged && r.bar != st.bar {
+		alert(RuleBarChanged, AlertFiring, "The bar changed from %d to %d", st.bar, r.bar)
+	}
+	st.bar = r.bar
+
+	// Feeds changed:
+	if m.rules.FeedsChanged {
+		if lifted, dropped := diffAddresses(st.feeds, r.feeds); len(lifted) > 0 || len(dropped) > 0 {
+			alert(RuleFeedsChanged, AlertFiring, "The feeds changed, lifted: %s, dropped: %s", joinAddresses(lifted), joinAddresses(dropped))
+		}
+	}
+	st.feeds = r.feeds
+
+	m.log.WithFields(fields).Info("Median state")
+	return alerts
+}
+
+// read reads the state of the contract.
+func (m *Monitor) read(ctx context.Context, c *Contract) (*reading, error) {
+	var (
+		r   reading
+		err error
+	)
+	if r.age, err = c.Median.Age(ctx); err != nil {
+		return nil, fmt.Errorf("unable to read age: %w", err)
+	}
+	if r.val, err = c.Median.Val(ctx); err != nil {
+		return nil, fmt.Errorf("unable to read price: %w", err)
+	}
+	if r.bar, err = c.Median.Bar(ctx); err != nil {
+		return nil, fmt.Errorf("unable to read bar: %w", err)
+	}
+	if r.feeds, err = c.Median.Feeds(ctx); err != nil {
+		return nil, fmt.Errorf("unable to read feeds: %w", err)
+	}
+	sort.Slice(r.feeds, func(i, j int) bool {
+		return strings.Compare(r.feeds[i].Hex(), r.feeds[j].Hex()) < 0
+	})
+	return &r, nil
+}
+
+// checkStaleAge evaluates the stale age rule using the last known age of
+// the contract.
+func (m *Monitor) checkStaleAge(st *state, now time.Time, alert alertFunc) {
+	if m.rules.MaxAge <= 0 {
+		return
+	}
+	stale := now.Sub(st.age) > m.rules.MaxAge
+	if stale == st.active[RuleStaleAge] {
+		return
+	}
+	st.active[RuleStaleAge] = stale
+	if stale {
+		alert(RuleStaleAge, AlertFiring, "The contract was not updated for %s", now.Sub(st.age).Round(time.Second))
+	} else {
+		alert(RuleStaleAge, AlertResolved, "The contract was updated")
+	}
+}
+
+// logAlertState logs the state of every enabled rule of the contract, so it
+// may be turned into metrics by the logger. The active field is 1 while the
+// rule is firing and 0 otherwise, the fired field is the total number of
+// firing alerts sent for the rule since the start.
+func (m *Monitor) logAlertState(c *Contract, st *state) {
+	for _, rule := range m.enabledRules(c) {
+		active := 0
+		if st.active[rule] {
+			active = 1
+		}
+		m.log.
+			WithFields(log.Fields{
+				"contract": c.Name,
+				"address":  c.Median.Address().String(),
+				"rule":     rule,
+				"active":   active,
+				"fired":    st.fired[rule],
+			}).
+			Info("Alert state")
+	}
+}
+
+// enabledRules returns the names of rules evaluated for the contract.
+func (m *Monitor) enabledRules(c *Contract) []string {
+	var rules []string
+	if m.rules.MaxAge > 0 {
+		rules = append(rules, RuleStaleAge)
+	}
+	if m.rules.MaxDeviation > 0 && !c.Pair.Empty() {
+		rules = append(rules, RuleDeviation)
+	}
+	if m.rules.BarChanged {
+		rules = append(rules, RuleBarChanged)
+	}
+	if m.rules.FeedsChanged {
+		rules = append(rules, RuleFeedsChanged)
+	}
+	if m.rules.ReadFailed {
+		rules = append(rules, RuleReadFailed)
+	}
+	return rules
+}
+
+// referencePrice returns the reference price for the given pair.
+func (m *Monitor) referencePrice(pair provider.Pair) (float64, error) {
+	p, err := m.reference.Price(pair)
+	if err != nil {
+		return 0, err
+	}
+	if p.Error != "" {
+		return 0, errors.New(p.Error)
+	}
+	if p.Price <= 0 {
+		return 0, fmt.Errorf("invalid reference price: %f", p.Price)
+	}
+	return p.Price, nil
+}
+
+// notify logs the alert and sends it to webhooks.
+func (m *Monitor) notify(a Alert) {
+	l := m.log.WithFields(log.Fields{
+		"rule":     a.Rule,
+		"status":   string(a.Status),
+		"contract": a.Contract,
+		"address":  a.Address,
+	})
+	if a.Status == AlertFiring {
+		l.Warn("Alert: " + a.Message)
+	} else {
+		l.Info("Alert: " + a.Message)
+	}
+	for _, w := range m.webhooks {
+		select {
+		case w.queue <- a:
+		default:
+			m.log.
+				WithField("url", w.url).
+				Error("Webhook queue is full, the alert was not sent to it")
+		}
+	}
+}
+
+func (m *Monitor) contextCancelHandler() {
+	defer func() { close(m.waitCh) }()
+	defer m.log.Info("Stopped")
+	<-m.ctx.Done()
+}
+
+// valToFloat64 converts the price stored in the contract to float64.
+func valToFloat64(val *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(val), new(big.Float).SetFloat64(oracle.PriceMultiplier)).Float64()
+	return f
+}
+
+// diffAddresses returns addresses that are only in the b list (lifted) and
+// addresses that are only in the a list (dropped).
+func diffAddresses(a, b []ethereum.Address) (lifted, dropped []ethereum.Address) {
+	in := func(l []ethereum.Address, x ethereum.Address) bool {
+		for _, y := range l {
+			if x == y {
+				return true
+			}
+		}
+		return false
+	}
+	for _, x := range b {
+		if !in(a, x) {
+			lifted = append(lifted, x)
+		}
+	}
+	for _, x := range a {
+		if !in(b, x) {
+			dropped = append(dropped, x)
+		}
+	}
+	return lifted, dropped
+}
+
+func joinAddresses(l []ethereum.Address) string {
+	if len(l) == 0 {
+		return "none"
+	}
+	s := make([]string, len(l))
+	for i, a := range l {
+		s[i] = a.String()
+	}
+	return strings.Join(s, ", ")
+}
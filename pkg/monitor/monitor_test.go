@@ -0,0 +1,259 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package monitor
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+)
+
+var (
+	testFeed1 = ethereum.HexToAddress("0x1111111111111111111111111111111111111111")
+	testFeed2 = ethereum.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// testMedian is a fake Median contract with a state that can be modified
+// by tests.
+type testMedian struct {
+	oracle.Median
+
+	age   time.Time
+	val   *big.Int
+	bar   int64
+	feeds []ethereum.Address
+	err   error // If set, reading the price fails.
+}
+
+func (m *testMedian) Address() common.Address {
+	return common.HexToAddress("0x3333333333333333333333333333333333333333")
+}
+
+func (m *testMedian) Age(_ context.Context) (time.Time, error) {
+	return m.age, nil
+}
+
+func (m *testMedian) Val(_ context.Context) (*big.Int, error) {
+	return m.val, m.err
+}
+
+func (m *testMedian) Bar(_ context.Context) (int64, error) {
+	return m.bar, nil
+}
+
+func (m *testMedian) Feeds(_ context.Context) ([]ethereum.Address, error) {
+	return append([]ethereum.Address(nil), m.feeds...), nil
+}
+
+// testProvider is a fake price provider that returns the same price for
+// every pair.
+type testProvider struct {
+	provider.Provider
+
+	price float64
+}
+
+func (p *testProvider) Price(pair provider.Pair) (*provider.Price, error) {
+	return &provider.Price{Pair: pair, Price: p.price}, nil
+}
+
+func newTestMonitor(t *testing.T, med *testMedian, ref *testProvider, webhooks ...Webhook) (*Monitor, *Contract) {
+	c := &Contract{
+		Name:   "ETHUSD",
+		Pair:   provider.Pair{Base: "ETH", Quote: "USD"},
+		Median: med,
+	}
+	m, err := New(Config{
+		Contracts: []*Contract{c},
+		Reference: ref,
+		Interval:  time.Minute,
+		Rules: Rules{
+			MaxAge:       time.Hour,
+			MaxDeviation: 1,
+			BarChanged:   true,
+			FeedsChanged: true,
+			ReadFailed:   true,
+		},
+		Webhooks: webhooks,
+	})
+	require.NoError(t, err)
+	return m, c
+}
+
+func testVal(price int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(price), big.NewInt(oracle.PriceMultiplier))
+}
+
+func rules(alerts []Alert) []string {
+	var r []string
+	for _, a := range alerts {
+		r = append(r, a.Rule+":"+string(a.Status))
+	}
+	return r
+}
+
+func TestMonitor_check(t *testing.T) {
+	ctx := context.Background()
+	med := &testMedian{
+		age:   time.Now(),
+		val:   testVal(100),
+		bar:   13,
+		feeds: []ethereum.Address{testFeed1},
+	}
+	ref := &testProvider{price: 100.5}
+	m, c := newTestMonitor(t, med, ref)
+
+	// Everything is fine:
+	assert.Empty(t, m.check(ctx, c))
+
+	// Stale age and the deviation above the threshold:
+	med.age = time.Now().Add(-2 * time.Hour)
+	ref.price = 102
+	assert.Equal(t, []string{"staleAge:firing", "deviation:firing"}, rules(m.check(ctx, c)))
+
+	// Alerts are not repeated while conditions are met:
+	assert.Empty(t, m.check(ctx, c))
+
+	// Conditions are resolved:
+	med.age = time.Now()
+	med.val = testVal(102)
+	assert.Equal(t, []string{"staleAge:resolved", "deviation:resolved"}, rules(m.check(ctx, c)))
+
+	// Bar and feeds changed:
+	med.bar = 15
+	med.feeds = []ethereum.Address{testFeed2}
+	alerts := m.check(ctx, c)
+	assert.Equal(t, []string{"barChanged:firing", "feedsChanged:firing"}, rules(alerts))
+	assert.Equal(t, "The bar changed from 13 to 15", alerts[0].Message)
+	assert.Contains(t, alerts[1].Message, "lifted: "+testFeed2.String())
+	assert.Contains(t, alerts[1].Message, "dropped: "+testFeed1.String())
+	assert.Equal(t, "ETHUSD", alerts[0].Contract)
+	assert.Equal(t, med.Address().String(), alerts[0].Address)
+
+	// The order of feeds does not matter:
+	med.feeds = []ethereum.Address{testFeed2, testFeed1}
+	assert.Equal(t, []string{"feedsChanged:firing"}, rules(m.check(ctx, c)))
+	med.feeds = []ethereum.Address{testFeed1, testFeed2}
+	assert.Empty(t, m.check(ctx, c))
+}
+
+func TestMonitor_check_readFailed(t *testing.T) {
+	ctx := context.Background()
+	med := &testMedian{
+		age:   time.Now().Add(-50 * time.Minute),
+		val:   testVal(100),
+		bar:   13,
+		feeds: []ethereum.Address{testFeed1},
+	}
+	m, c := newTestMonitor(t, med, &testProvider{price: 100})
+	assert.Empty(t, m.check(ctx, c))
+
+	// The contract cannot be read:
+	med.err = errors.New("rpc error")
+	alerts := m.check(ctx, c)
+	assert.Equal(t, []string{"readFailed:firing"}, rules(alerts))
+	assert.Equal(t, "Unable to read the contract state: unable to read price: rpc error", alerts[0].Message)
+
+	// Alerts are not repeated while the contract cannot be read:
+	assert.Empty(t, m.check(ctx, c))
+
+	// The stale age rule is evaluated using the last known age:
+	m.states[c].age = time.Now().Add(-2 * time.Hour)
+	assert.Equal(t, []string{"staleAge:firing"}, rules(m.check(ctx, c)))
+
+	// The contract can be read again:
+	med.err = nil
+	med.age = time.Now()
+	assert.Equal(t, []string{"readFailed:resolved", "staleAge:resolved"}, rules(m.check(ctx, c)))
+
+	// Alert counters:
+	assert.Equal(t, 1, m.states[c].fired[RuleReadFailed])
+	assert.Equal(t, 1, m.states[c].fired[RuleStaleAge])
+	assert.Equal(t, []string{RuleStaleAge, RuleDeviation, RuleBarChanged, RuleFeedsChanged, RuleReadFailed}, m.enabledRules(c))
+}
+
+func TestMonitor_webhook(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	secret := []byte("secret")
+	calls := 0
+	bodies := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			res.WriteHeader(http.StatusInternalServerError) // First call fails, must be retried.
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(signBody(secret, body)), req.Header.Get(SignatureHeader))
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	med := &testMedian{
+		age: time.Now().Add(-2 * time.Hour),
+		val: testVal(100),
+		bar: 13,
+	}
+	m, _ := newTestMonitor(t, med, &testProvider{price: 100})
+	m.webhooks = []*webhook{newWebhook(Webhook{URL: srv.URL, Secret: secret}, m.log)}
+	m.webhooks[0].retryDelay = 10 * time.Millisecond
+	require.NoError(t, m.Start(ctx))
+
+	select {
+	case body := <-bodies:
+		var a Alert
+		require.NoError(t, json.Unmarshal(body, &a))
+		assert.Equal(t, RuleStaleAge, a.Rule)
+		assert.Equal(t, AlertFiring, a.Status)
+		assert.Equal(t, "ETHUSD", a.Contract)
+	case <-time.After(time.Second):
+		t.Fatal("webhook was not called")
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{
+		Contracts: []*Contract{{Name: "ETHUSD", Pair: provider.Pair{Base: "ETH", Quote: "USD"}, Median: &testMedian{}}},
+		Interval:  time.Minute,
+		Rules:     Rules{MaxDeviation: 1},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reference price provider")
+
+	_, err = New(Config{
+		Contracts: []*Contract{{Name: "ETHUSD", Median: &testMedian{}}},
+		Interval:  time.Minute,
+		Rules:     Rules{MaxDeviation: 1},
+	})
+	require.NoError(t, err)
+}
@@ -16,12 +16,14 @@
 package geth
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"math/big"
 	"sort"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
@@ -29,6 +31,7 @@ import (
 )
 
 var ErrStorageQueryFailed = errors.New("oracle contract storage query failed")
+var ErrNotPokeCalldata = errors.New("calldata is not a poke call")
 
 // TODO: make it configurable
 const gasLimit = 200000
@@ -152,29 +155,14 @@ func (m *Median) Poke(ctx context.Context, prices []*oracle.Price, simulateBefor
 		return prices[i].Val.Cmp(prices[j].Val) < 0
 	})
 
-	var (
-		val []*big.Int
-		age []*big.Int
-		v   []uint8
-		r   [][32]byte
-		s   [][32]byte
-	)
-
-	for _, arg := range prices {
-		val = append(val, arg.Val)
-		age = append(age, big.NewInt(arg.Age.Unix()))
-		v = append(v, arg.V)
-		r = append(r, arg.R)
-		s = append(s, arg.S)
-	}
-
+	args := pokeArgs(prices)
 	if simulateBeforeRun {
-		if _, err := m.read(ctx, "poke", val, age, v, r, s); err != nil {
+		if _, err := m.read(ctx, "poke", args...); err != nil {
 			return nil, err
 		}
 	}
 
-	return m.write(ctx, "poke", val, age, v, r, s)
+	return m.write(ctx, "poke", args...)
 }
 
 // Lift implements the oracle.Median interface.
@@ -210,32 +198,133 @@ func (m *Median) SetBar(ctx context.Context, bar *big.Int, simulateBeforeRun boo
 	return m.write(ctx, "setBar", bar)
 }
 
+// LiftCalldata returns the calldata for the lift method. It may be used to
+// draft transactions that are sent by other means, e.g. by a multisig wallet.
+func LiftCalldata(addresses []common.Address) ([]byte, error) {
+	return medianABI.Pack("lift", addresses)
+}
+
+// DropCalldata returns the calldata for the drop method. It may be used to
+// draft transactions that are sent by other means, e.g. by a multisig wallet.
+func DropCalldata(addresses []common.Address) ([]byte, error) {
+	return medianABI.Pack("drop", addresses)
+}
+
+// PokeCalldata returns the calldata for the poke method. Prices must be
+// sorted by their values, otherwise the transaction will fail.
+func PokeCalldata(prices []*oracle.Price) ([]byte, error) {
+	return medianABI.Pack("poke", pokeArgs(prices)...)
+}
+
+// DecodePokeCalldata decodes the calldata of the poke method into the list
+// of prices. Because the asset name is not a part of the calldata, it must
+// be provided separately. Signatures of the returned prices may be used to
+// recover the addresses of the feeders.
+func DecodePokeCalldata(wat string, calldata []byte) ([]*oracle.Price, error) {
+	method := medianABI.Methods["poke"]
+	if len(calldata) < 4 || !bytes.Equal(calldata[:4], method.ID) {
+		return nil, ErrNotPokeCalldata
+	}
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, err
+	}
+	val := args[0].([]*big.Int)
+	age := args[1].([]*big.Int)
+	v := args[2].([]uint8)
+	r := args[3].([][32]byte)
+	s := args[4].([][32]byte)
+	if len(age) != len(val) || len(v) != len(val) || len(r) != len(val) || len(s) != len(val) {
+		return nil, ErrNotPokeCalldata
+	}
+	prices := make([]*oracle.Price, len(val))
+	for i := range val {
+		prices[i] = &oracle.Price{
+			Wat: wat,
+			Val: val[i],
+			Age: time.Unix(age[i].Int64(), 0),
+			V:   v[i],
+			R:   r[i],
+			S:   s[i],
+		}
+	}
+	return prices, nil
+}
+
+// pokeArgs returns the arguments of the poke method for the given prices.
+func pokeArgs(prices []*oracle.Price) []interface{} {
+	var (
+		val []*big.Int
+		age []*big.Int
+		v   []uint8
+		r   [][32]byte
+		s   [][32]byte
+	)
+
+	for _, arg := range prices {
+		val = append(val, arg.Val)
+		age = append(age, big.NewInt(arg.Age.Unix()))
+		v = append(v, arg.V)
+		r = append(r, arg.R)
+		s = append(s, arg.S)
+	}
+
+	return []interface{}{val, age, v, r, s}
+}
+
 func (m *Median) read(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
-	cd, err := medianABI.Pack(method, args...)
+	return read(ctx, m.ethereum, m.address, medianABI, method, args...)
+}
+
+func (m *Median) write(ctx context.Context, method string, args ...interface{}) (*ethereum.Hash, error) {
+	return write(ctx, m.ethereum, m.address, medianABI, method, args...)
+}
+
+// read calls the given contract method and unpacks its results. Failed
+// calls are retried.
+func read(
+	ctx context.Context,
+	client ethereum.Client,
+	address ethereum.Address,
+	contractABI abi.ABI,
+	method string,
+	args ...interface{},
+) ([]interface{}, error) {
+
+	cd, err := contractABI.Pack(method, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	var data []byte
 	err = retry(maxReadRetries, delayBetweenReadRetries, func() error {
-		data, err = m.ethereum.Call(ctx, ethereum.Call{Address: m.address, Data: cd})
+		data, err = client.Call(ctx, ethereum.Call{Address: address, Data: cd})
 		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return medianABI.Unpack(method, data)
+	return contractABI.Unpack(method, data)
 }
 
-func (m *Median) write(ctx context.Context, method string, args ...interface{}) (*ethereum.Hash, error) {
-	cd, err := medianABI.Pack(method, args...)
+// write sends a transaction that invokes the given contract method.
+func write(
+	ctx context.Context,
+	client ethereum.Client,
+	address ethereum.Address,
+	contractABI abi.ABI,
+	method string,
+	args ...interface{},
+) (*ethereum.Hash, error) {
+
+	cd, err := contractABI.Pack(method, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.ethereum.SendTransaction(ctx, &ethereum.Transaction{
-		Address:  m.address,
+	return client.SendTransaction(ctx, &ethereum.Transaction{
+		Address:  address,
 		GasLimit: new(big.Int).SetUint64(gasLimit),
 		Data:     cd,
 	})
@@ -244,13 +333,12 @@ func (m *Median) write(ctx context.Context, method string, args ...interface{})
 func retry(maxRetries int, delay time.Duration, f func() error) error {
 	for i := 0; ; i++ {
 		err := f()
-		if err != nil {
-			return err
+		if err == nil {
+			return nil
 		}
 		if i >= (maxRetries - 1) {
-			break
+			return err
 		}
 		time.Sleep(delay)
 	}
-	return nil
 }
@@ -19,6 +19,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 	"time"
@@ -26,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
@@ -166,3 +169,68 @@ func TestMedian_Poke(t *testing.T) {
 	assert.Equal(t, uint64(0), tx.Nonce)
 	assert.Equal(t, cd, hex.EncodeToString(tx.Data))
 }
+
+func Test_retry(t *testing.T) {
+	tests := []struct {
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{failures: 0, wantCalls: 1},
+		{failures: 2, wantCalls: 3},
+		{failures: 3, wantCalls: 3, wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			calls := 0
+			err := retry(3, 0, func() error {
+				calls++
+				if calls <= tt.failures {
+					return errors.New("error")
+				}
+				return nil
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLiftDropCalldata(t *testing.T) {
+	addr := common.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	args := "0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000002d800d93b065ce011af83f316cef9f0d005b0aa4"
+
+	lift, err := LiftCalldata([]common.Address{addr})
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(medianABI.Methods["lift"].ID)+args, hex.EncodeToString(lift))
+
+	drop, err := DropCalldata([]common.Address{addr})
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(medianABI.Methods["drop"].ID)+args, hex.EncodeToString(drop))
+}
+
+func TestPokeCalldata(t *testing.T) {
+	prices := []*oracle.Price{
+		{Wat: "AAABBB", Val: big.NewInt(10), Age: time.Unix(1600000000, 0), V: 27, R: [32]byte{0xaa}, S: [32]byte{0xcc}},
+		{Wat: "AAABBB", Val: big.NewInt(20), Age: time.Unix(1600000001, 0), V: 28, R: [32]byte{0xbb}, S: [32]byte{0xdd}},
+	}
+
+	cd, err := PokeCalldata(prices)
+	require.NoError(t, err)
+	assert.Equal(t, medianABI.Methods["poke"].ID, cd[:4])
+
+	decoded, err := DecodePokeCalldata("AAABBB", cd)
+	require.NoError(t, err)
+	assert.Equal(t, prices, decoded)
+
+	lift, err := LiftCalldata([]common.Address{{}})
+	require.NoError(t, err)
+	_, err = DecodePokeCalldata("AAABBB", lift)
+	assert.ErrorIs(t, err, ErrNotPokeCalldata)
+}
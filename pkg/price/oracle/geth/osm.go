@@ -0,0 +1,141 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// Storage slots of the cur and nxt variables in the OSM contract.
+const (
+	osmCurSlot = 3
+	osmNxtSlot = 4
+)
+
+// OSM implements the oracle.OSM interface using go-ethereum packages.
+type OSM struct {
+	ethereum ethereum.Client
+	address  ethereum.Address
+}
+
+// NewOSM creates the new OSM instance.
+func NewOSM(ethereum ethereum.Client, address ethereum.Address) *OSM {
+	return &OSM{
+		ethereum: ethereum,
+		address:  address,
+	}
+}
+
+// Address implements the oracle.OSM interface.
+func (o *OSM) Address() common.Address {
+	return o.address
+}
+
+// Src implements the oracle.OSM interface.
+func (o *OSM) Src(ctx context.Context) (common.Address, error) {
+	r, err := o.read(ctx, "src")
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return r[0].(common.Address), nil
+}
+
+// Peek implements the oracle.OSM interface.
+func (o *OSM) Peek(ctx context.Context) (*big.Int, bool, error) {
+	return o.feed(ctx, osmCurSlot)
+}
+
+// Peep implements the oracle.OSM interface.
+func (o *OSM) Peep(ctx context.Context) (*big.Int, bool, error) {
+	return o.feed(ctx, osmNxtSlot)
+}
+
+// Hop implements the oracle.OSM interface.
+func (o *OSM) Hop(ctx context.Context) (time.Duration, error) {
+	r, err := o.read(ctx, "hop")
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(r[0].(uint16)) * time.Second, nil
+}
+
+// Zzz implements the oracle.OSM interface.
+func (o *OSM) Zzz(ctx context.Context) (time.Time, error) {
+	r, err := o.read(ctx, "zzz")
+	if err != nil {
+		return time.Unix(0, 0), err
+	}
+
+	return time.Unix(int64(r[0].(uint64)), 0), nil
+}
+
+// Pass implements the oracle.OSM interface.
+func (o *OSM) Pass(ctx context.Context) (bool, error) {
+	r, err := o.read(ctx, "pass")
+	if err != nil {
+		return false, err
+	}
+
+	return r[0].(bool), nil
+}
+
+// Poke implements the oracle.OSM interface.
+func (o *OSM) Poke(ctx context.Context, simulateBeforeRun bool) (*ethereum.Hash, error) {
+	if simulateBeforeRun {
+		if _, err := o.read(ctx, "poke"); err != nil {
+			return nil, err
+		}
+	}
+
+	return o.write(ctx, "poke")
+}
+
+// feed reads the Feed struct from the given storage slot. The struct
+// consists of the val and has fields, both of the uint128 type.
+func (o *OSM) feed(ctx context.Context, slot int64) (*big.Int, bool, error) {
+	const (
+		offset = 16
+		length = 16
+	)
+
+	b, err := o.ethereum.Storage(ctx, o.address, common.BigToHash(big.NewInt(slot)))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(b) < (offset + length) {
+		return nil, false, ErrStorageQueryFailed
+	}
+
+	val := new(big.Int).SetBytes(b[offset : offset+length])
+	has := new(big.Int).SetBytes(b[:offset])
+	return val, has.Sign() != 0, nil
+}
+
+func (o *OSM) read(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	return read(ctx, o.ethereum, o.address, osmABI, method, args...)
+}
+
+func (o *OSM) write(ctx context.Context, method string, args ...interface{}) (*ethereum.Hash, error) {
+	return write(ctx, o.ethereum, o.address, osmABI, method, args...)
+}
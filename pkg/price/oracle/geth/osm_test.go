@@ -0,0 +1,132 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+)
+
+func TestOSM_Hop(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// Call Hop function:
+	bts := make([]byte, 32)
+	big.NewInt(3600).FillBytes(bts)
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: osmABI.Methods["hop"].ID}).Return(bts, nil)
+	hop, err := o.Hop(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, hop)
+}
+
+func TestOSM_Zzz(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// Call Zzz function:
+	bts := make([]byte, 32)
+	big.NewInt(1600000000).FillBytes(bts)
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: osmABI.Methods["zzz"].ID}).Return(bts, nil)
+	zzz, err := o.Zzz(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1600000000), zzz.Unix())
+}
+
+func TestOSM_Pass(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// Call Pass function:
+	bts := make([]byte, 32)
+	bts[31] = 1
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: osmABI.Methods["pass"].ID}).Return(bts, nil)
+	pass, err := o.Pass(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.True(t, pass)
+}
+
+func TestOSM_PeekPeep(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// The has field is stored in the upper 16 bytes and val in the lower ones:
+	cur := make([]byte, 32)
+	big.NewInt(42).FillBytes(cur[16:])
+	cur[15] = 1
+	nxt := make([]byte, 32)
+	big.NewInt(43).FillBytes(nxt[16:])
+	c.On("Storage", mock.Anything, a, common.BigToHash(big.NewInt(3))).Return(cur, nil)
+	c.On("Storage", mock.Anything, a, common.BigToHash(big.NewInt(4))).Return(nxt, nil)
+
+	// Call Peek and Peep functions:
+	val, has, err := o.Peek(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), val)
+	assert.True(t, has)
+
+	val, has, err = o.Peep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(43), val)
+	assert.False(t, has)
+}
+
+func TestOSM_Poke(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	o := NewOSM(c, a)
+
+	c.On("Call", mock.Anything, mock.Anything).Return([]byte{}, nil)
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&ethereum.Hash{}, nil)
+
+	// Call Poke function:
+	_, err := o.Poke(context.Background(), true)
+	require.NoError(t, err)
+
+	// Verify generated transaction:
+	call := c.Calls[0].Arguments.Get(1).(ethereum.Call)
+	tx := c.Calls[1].Arguments.Get(1).(*ethereum.Transaction)
+	assert.Equal(t, "18178358", hex.EncodeToString(call.Data))
+	assert.Equal(t, a, tx.Address)
+	assert.Equal(t, big.NewInt(gasLimit), tx.GasLimit)
+	assert.Equal(t, "18178358", hex.EncodeToString(tx.Data))
+}
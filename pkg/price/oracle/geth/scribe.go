@@ -0,0 +1,161 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+)
+
+// scribePokeData and scribeSchnorrData mirror the PokeData and SchnorrData
+// structs of the Scribe contract.
+type scribePokeData struct {
+	Val *big.Int
+	Age uint32
+}
+
+type scribeSchnorrData struct {
+	Signature  [32]byte
+	Commitment common.Address
+	FeedIDs    []byte `abi:"feedIds"`
+}
+
+// Scribe implements the oracle.Scribe interface using go-ethereum packages.
+type Scribe struct {
+	ethereum ethereum.Client
+	address  ethereum.Address
+}
+
+// NewScribe creates the new Scribe instance.
+func NewScribe(ethereum ethereum.Client, address ethereum.Address) *Scribe {
+	return &Scribe{
+		ethereum: ethereum,
+		address:  address,
+	}
+}
+
+// Address implements the oracle.Scribe interface.
+func (s *Scribe) Address() common.Address {
+	return s.address
+}
+
+// Wat implements the oracle.Scribe interface.
+func (s *Scribe) Wat(ctx context.Context) (string, error) {
+	r, err := s.read(ctx, "wat")
+	if err != nil {
+		return "", err
+	}
+
+	b := r[0].([32]byte)
+	return string(b[:]), nil
+}
+
+// Bar implements the oracle.Scribe interface.
+func (s *Scribe) Bar(ctx context.Context) (int64, error) {
+	r, err := s.read(ctx, "bar")
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(r[0].(uint8)), nil
+}
+
+// Read implements the oracle.Scribe interface.
+//
+// The readWithAge method is available only to whitelisted addresses, but
+// Scribe allows calls from the zero address, which is used by eth_call when
+// the sender is not specified.
+func (s *Scribe) Read(ctx context.Context) (*big.Int, time.Time, error) {
+	r, err := s.read(ctx, "readWithAge")
+	if err != nil {
+		return nil, time.Unix(0, 0), err
+	}
+
+	return r[0].(*big.Int), time.Unix(r[1].(*big.Int).Int64(), 0), nil
+}
+
+// Feeds implements the oracle.Scribe interface.
+func (s *Scribe) Feeds(ctx context.Context) ([]ethereum.Address, error) {
+	r, err := s.read(ctx, "feeds")
+	if err != nil {
+		return nil, err
+	}
+
+	return r[0].([]common.Address), nil
+}
+
+// PokeMessage implements the oracle.Scribe interface.
+func (s *Scribe) PokeMessage(ctx context.Context, pokeData oracle.ScribePokeData) ([32]byte, error) {
+	r, err := s.read(ctx, "constructPokeMessage", scribePokeArg(pokeData))
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return r[0].([32]byte), nil
+}
+
+// Poke implements the oracle.Scribe interface.
+func (s *Scribe) Poke(
+	ctx context.Context,
+	pokeData oracle.ScribePokeData,
+	schnorrData oracle.SchnorrData,
+	simulateBeforeRun bool,
+) (*ethereum.Hash, error) {
+
+	args := []interface{}{scribePokeArg(pokeData), scribeSchnorrArg(schnorrData)}
+	if simulateBeforeRun {
+		if _, err := s.read(ctx, "poke", args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.write(ctx, "poke", args...)
+}
+
+// ScribePokeCalldata returns the calldata for the poke method of the Scribe
+// contract.
+func ScribePokeCalldata(pokeData oracle.ScribePokeData, schnorrData oracle.SchnorrData) ([]byte, error) {
+	return scribeABI.Pack("poke", scribePokeArg(pokeData), scribeSchnorrArg(schnorrData))
+}
+
+func scribePokeArg(pokeData oracle.ScribePokeData) scribePokeData {
+	return scribePokeData{
+		Val: pokeData.Val,
+		Age: uint32(pokeData.Age.Unix()),
+	}
+}
+
+func scribeSchnorrArg(schnorrData oracle.SchnorrData) scribeSchnorrData {
+	return scribeSchnorrData{
+		Signature:  schnorrData.Signature,
+		Commitment: schnorrData.Commitment,
+		FeedIDs:    schnorrData.FeedIDs,
+	}
+}
+
+func (s *Scribe) read(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	return read(ctx, s.ethereum, s.address, scribeABI, method, args...)
+}
+
+func (s *Scribe) write(ctx context.Context, method string, args ...interface{}) (*ethereum.Hash, error) {
+	return write(ctx, s.ethereum, s.address, scribeABI, method, args...)
+}
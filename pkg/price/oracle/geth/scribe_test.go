@@ -0,0 +1,124 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+)
+
+func TestScribe_Read(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	s := NewScribe(c, a)
+
+	// Call Read function:
+	bts := make([]byte, 64)
+	big.NewInt(42).FillBytes(bts[:32])
+	big.NewInt(1600000000).FillBytes(bts[32:])
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: scribeABI.Methods["readWithAge"].ID}).Return(bts, nil)
+	val, age, err := s.Read(context.Background())
+
+	// Verify:
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), val)
+	assert.Equal(t, int64(1600000000), age.Unix())
+}
+
+func TestScribe_Bar(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	s := NewScribe(c, a)
+
+	// Call Bar function:
+	bts := make([]byte, 32)
+	big.NewInt(13).FillBytes(bts)
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: scribeABI.Methods["bar"].ID}).Return(bts, nil)
+	bar, err := s.Bar(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.Equal(t, int64(13), bar)
+}
+
+func TestScribe_Feeds(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	s := NewScribe(c, a)
+	feeds := []common.Address{
+		common.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4"),
+		common.HexToAddress("0xe3ced0f62f7eb2856d37bed128d2b195712d2644"),
+	}
+
+	// Call Feeds function:
+	bts, err := scribeABI.Methods["feeds"].Outputs.Pack(feeds)
+	require.NoError(t, err)
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: scribeABI.Methods["feeds"].ID}).Return(bts, nil)
+	res, err := s.Feeds(context.Background())
+
+	// Verify:
+	require.NoError(t, err)
+	assert.Equal(t, feeds, res)
+}
+
+func TestScribe_Poke(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	s := NewScribe(c, a)
+	pokeData := oracle.ScribePokeData{Val: big.NewInt(42), Age: time.Unix(1600000000, 0)}
+	schnorrData := oracle.SchnorrData{
+		Signature:  [32]byte{0xaa},
+		Commitment: common.HexToAddress("0xe3ced0f62f7eb2856d37bed128d2b195712d2644"),
+		FeedIDs:    []byte{1, 2, 3},
+	}
+
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&ethereum.Hash{}, nil)
+
+	// Call Poke function:
+	_, err := s.Poke(context.Background(), pokeData, schnorrData, false)
+	require.NoError(t, err)
+
+	// Verify generated transaction:
+	tx := c.Calls[0].Arguments.Get(1).(*ethereum.Transaction)
+	selector := crypto.Keccak256([]byte("poke((uint128,uint32),(bytes32,address,bytes))"))[:4]
+	assert.Equal(t, a, tx.Address)
+	assert.Equal(t, big.NewInt(gasLimit), tx.GasLimit)
+	assert.Equal(t, selector, tx.Data[:4])
+
+	// The static poke data tuple is encoded in place:
+	assert.Equal(t, common.LeftPadBytes([]byte{42}, 32), tx.Data[4:36])
+	assert.Equal(t, common.LeftPadBytes(big.NewInt(1600000000).Bytes(), 32), tx.Data[36:68])
+
+	cd, err := ScribePokeCalldata(pokeData, schnorrData)
+	require.NoError(t, err)
+	assert.Equal(t, cd, tx.Data)
+}
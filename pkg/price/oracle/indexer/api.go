@@ -0,0 +1,335 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver"
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver/middleware"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+)
+
+const APILoggerTag = "MEDIAN_INDEXER_API"
+
+// defaultTimeout is the default timeout for the HTTP server.
+const defaultTimeout = 3 * time.Second
+
+// defaultLimit is the default number of pokes returned by the /pokes
+// endpoint.
+const defaultLimit = 100
+
+// maxLimit is the maximum number of pokes returned by the /pokes endpoint.
+const maxLimit = 1000
+
+// API provides an HTTP API for the Indexer.
+//
+// It provides two GET endpoints:
+//
+// /pokes - returns the most recent indexed pokes
+// /summary - returns statistics calculated from indexed pokes
+//
+// Both endpoints accept following query parameters:
+//
+// contract - the address of the Median contract, optional
+// sender - the address of the transaction sender, optional
+// feeder - the address of the feeder, optional
+// from - the unix timestamp of the oldest poke, inclusive, optional
+// to - the unix timestamp of the newest poke, exclusive, optional
+//
+// The /pokes endpoint also accepts the limit parameter, which is the maximum
+// number of returned pokes, 100 by default. The /summary endpoint uses all
+// pokes that match the query.
+//
+// Errors are returned as a JSON object with the "error" field.
+type API struct {
+	ctx context.Context
+
+	srv *httpserver.HTTPServer
+	ix  *Indexer
+	log log.Logger
+}
+
+// APIConfig is the configuration for the API.
+type APIConfig struct {
+	// Indexer is the indexer whose pokes are served.
+	Indexer *Indexer
+	// Address specifies the TCP address for the server to listen on in the
+	// form "host:port".
+	Address string
+	// Logger is a current logger used by the API.
+	Logger log.Logger
+}
+
+type jsonPoke struct {
+	Contract    types.Address   `json:"contract"`
+	Wat         string          `json:"wat"`
+	Val         string          `json:"val"`
+	Age         int64           `json:"age"`
+	BlockNumber uint64          `json:"blockNumber"`
+	TxHash      types.Hash      `json:"txHash"`
+	LogIndex    uint64          `json:"logIndex"`
+	Sender      types.Address   `json:"sender"`
+	GasUsed     uint64          `json:"gasUsed"`
+	GasPrice    string          `json:"gasPrice"`
+	Feeders     []types.Address `json:"feeders"`
+}
+
+type jsonStats struct {
+	Pokes   int                                `json:"pokes"`
+	Feeders map[types.Address]int              `json:"feeders"`
+	Senders map[types.Address]*jsonSenderStats `json:"senders"`
+}
+
+type jsonSenderStats struct {
+	Pokes        int    `json:"pokes"`
+	Transactions int    `json:"transactions"`
+	GasUsed      uint64 `json:"gasUsed"`
+	Cost         string `json:"cost"`
+}
+
+type jsonError struct {
+	Error string `json:"error"`
+}
+
+// NewAPI returns a new instance of the API struct.
+func NewAPI(cfg APIConfig) (*API, error) {
+	if cfg.Indexer == nil {
+		return nil, errors.New("indexer must not be nil")
+	}
+	if cfg.Address == "" {
+		return nil, errors.New("address must not be empty")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	api := &API{
+		ix:  cfg.Indexer,
+		log: cfg.Logger.WithField("tag", APILoggerTag),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pokes", api.pokesHandler)
+	mux.HandleFunc("/summary", api.summaryHandler)
+	api.srv = httpserver.New(&http.Server{
+		Addr:              cfg.Address,
+		Handler:           http.TimeoutHandler(mux, defaultTimeout, `{"error":"timeout"}`),
+		IdleTimeout:       defaultTimeout,
+		ReadTimeout:       defaultTimeout,
+		ReadHeaderTimeout: defaultTimeout,
+	})
+	api.srv.Use(&middleware.HealthCheck{
+		Path:  "/health",
+		Check: func(r *http.Request) bool { return true },
+	})
+	api.srv.Use(&middleware.Logger{Log: api.log})
+	return api, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (a *API) Start(ctx context.Context) error {
+	if a.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	a.log.Info("Starting")
+	a.ctx = ctx
+	err := a.srv.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to start the HTTP server: %w", err)
+	}
+	go a.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (a *API) Wait() chan error {
+	return a.srv.Wait()
+}
+
+func (a *API) pokesHandler(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(res, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	q, err := parseQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	q.Limit = defaultLimit
+	if s := req.URL.Query().Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			writeError(res, http.StatusBadRequest, fmt.Sprintf("invalid limit parameter: must be between 1 and %d", maxLimit))
+			return
+		}
+		q.Limit = limit
+	}
+	pokes, ok := a.pokes(res, q)
+	if !ok {
+		return
+	}
+	r := make([]*jsonPoke, 0, len(pokes))
+	for _, p := range pokes {
+		r = append(r, mapPoke(p))
+	}
+	writeJSON(res, http.StatusOK, r)
+}
+
+func (a *API) summaryHandler(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(res, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	q, err := parseQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	pokes, ok := a.pokes(res, q)
+	if !ok {
+		return
+	}
+	writeJSON(res, http.StatusOK, mapStats(Summarize(pokes)))
+}
+
+// pokes fetches pokes from the indexer. If an error occurs, it writes the
+// error response and returns false.
+func (a *API) pokes(res http.ResponseWriter, q Query) ([]Poke, bool) {
+	ctx, ctxCancel := context.WithTimeout(a.ctx, defaultTimeout)
+	defer ctxCancel()
+	pokes, err := a.ix.Pokes(ctx, q)
+	if err != nil {
+		a.log.WithError(err).Error("Storage error")
+		writeError(res, http.StatusInternalServerError, "storage error")
+		return nil, false
+	}
+	return pokes, true
+}
+
+func (a *API) contextCancelHandler() {
+	defer a.log.Info("Stopped")
+	<-a.ctx.Done()
+}
+
+// parseQuery parses query parameters shared by all endpoints.
+func parseQuery(v url.Values) (Query, error) {
+	var q Query
+	var err error
+	if q.Contract, err = parseAddress(v, "contract"); err != nil {
+		return Query{}, err
+	}
+	if q.Sender, err = parseAddress(v, "sender"); err != nil {
+		return Query{}, err
+	}
+	if q.Feeder, err = parseAddress(v, "feeder"); err != nil {
+		return Query{}, err
+	}
+	if s := v.Get("from"); s != "" {
+		from, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid from parameter: %w", err)
+		}
+		q.From = time.Unix(from, 0)
+	}
+	if s := v.Get("to"); s != "" {
+		to, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid to parameter: %w", err)
+		}
+		q.To = time.Unix(to, 0)
+	}
+	return q, nil
+}
+
+// parseAddress parses an optional address parameter. It returns nil if the
+// parameter is not set.
+func parseAddress(v url.Values, name string) (*types.Address, error) {
+	s := v.Get(name)
+	if s == "" {
+		return nil, nil
+	}
+	if !ethereum.IsHexAddress(s) {
+		return nil, fmt.Errorf("invalid %s parameter: must be an address", name)
+	}
+	addr := types.HexToAddress(s)
+	return &addr, nil
+}
+
+func mapPoke(p Poke) *jsonPoke {
+	j := &jsonPoke{
+		Contract:    p.Contract,
+		Wat:         p.Wat,
+		Val:         "0",
+		Age:         p.Age.Unix(),
+		BlockNumber: p.BlockNumber,
+		TxHash:      p.TxHash,
+		LogIndex:    p.LogIndex,
+		Sender:      p.Sender,
+		GasUsed:     p.GasUsed,
+		GasPrice:    "0",
+		Feeders:     p.Feeders,
+	}
+	if p.Val != nil {
+		j.Val = p.Val.String()
+	}
+	if p.GasPrice != nil {
+		j.GasPrice = p.GasPrice.String()
+	}
+	if j.Feeders == nil {
+		j.Feeders = []types.Address{}
+	}
+	return j
+}
+
+func mapStats(s Stats) *jsonStats {
+	j := &jsonStats{
+		Pokes:   s.Pokes,
+		Feeders: s.Feeders,
+		Senders: map[types.Address]*jsonSenderStats{},
+	}
+	for addr, ss := range s.Senders {
+		j.Senders[addr] = &jsonSenderStats{
+			Pokes:        ss.Pokes,
+			Transactions: ss.Transactions,
+			GasUsed:      ss.GasUsed,
+			Cost:         ss.Cost.String(),
+		}
+	}
+	return j
+}
+
+func writeJSON(res http.ResponseWriter, status int, v interface{}) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(status)
+	_ = json.NewEncoder(res).Encode(v)
+}
+
+func writeError(res http.ResponseWriter, status int, msg string) {
+	writeJSON(res, status, jsonError{Error: msg})
+}
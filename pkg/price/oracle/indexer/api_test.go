@@ -0,0 +1,127 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+func TestAPI(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+
+	ix := newTestIndexer(t, newIndexerClient(), 0)
+	require.NoError(t, ix.storage.Add(ctx, []Poke{
+		{
+			Contract:    testMedian1,
+			Wat:         "ETHUSD",
+			Val:         big.NewInt(10),
+			Age:         time.Unix(100, 0),
+			BlockNumber: 1,
+			TxHash:      types.HexToHash("0x01"),
+			Sender:      testSender,
+			GasUsed:     100,
+			GasPrice:    big.NewInt(2),
+			Feeders:     []types.Address{testFeeder1},
+		},
+		{
+			Contract:    testMedian2,
+			Wat:         "BTCUSD",
+			Val:         big.NewInt(20),
+			Age:         time.Unix(200, 0),
+			BlockNumber: 2,
+			TxHash:      types.HexToHash("0x02"),
+			Sender:      testSender,
+			GasUsed:     300,
+			GasPrice:    big.NewInt(2),
+		},
+	}))
+	api, err := NewAPI(APIConfig{Indexer: ix, Address: "127.0.0.1:0"})
+	require.NoError(t, err)
+	require.NoError(t, api.Start(ctx))
+	defer func() {
+		ctxCancel()
+		require.NoError(t, <-api.Wait())
+	}()
+
+	tests := []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{
+			path:   "/pokes?contract=0x1111111111111111111111111111111111111111",
+			status: http.StatusOK,
+			want:   `[{"contract":"0x1111111111111111111111111111111111111111","wat":"ETHUSD","val":"10","age":100,"blockNumber":1,"txHash":"0x0000000000000000000000000000000000000000000000000000000000000001","logIndex":0,"sender":"0x3333333333333333333333333333333333333333","gasUsed":100,"gasPrice":"2","feeders":["0x2d800d93b065ce011af83f316cef9f0d005b0aa4"]}]`,
+		},
+		{
+			path:   "/pokes?from=150&limit=1",
+			status: http.StatusOK,
+			want:   `[{"contract":"0x2222222222222222222222222222222222222222","wat":"BTCUSD","val":"20","age":200,"blockNumber":2,"txHash":"0x0000000000000000000000000000000000000000000000000000000000000002","logIndex":0,"sender":"0x3333333333333333333333333333333333333333","gasUsed":300,"gasPrice":"2","feeders":[]}]`,
+		},
+		{
+			path:   "/pokes?to=100",
+			status: http.StatusOK,
+			want:   `[]`,
+		},
+		{
+			path:   "/summary",
+			status: http.StatusOK,
+			want:   `{"pokes":2,"feeders":{"0x2d800d93b065ce011af83f316cef9f0d005b0aa4":1},"senders":{"0x3333333333333333333333333333333333333333":{"pokes":2,"transactions":2,"gasUsed":400,"cost":"800"}}}`,
+		},
+		{
+			path:   "/summary?feeder=0x2d800d93b065ce011af83f316cef9f0d005b0aa4",
+			status: http.StatusOK,
+			want:   `{"pokes":1,"feeders":{"0x2d800d93b065ce011af83f316cef9f0d005b0aa4":1},"senders":{"0x3333333333333333333333333333333333333333":{"pokes":1,"transactions":1,"gasUsed":100,"cost":"200"}}}`,
+		},
+		{
+			path:   "/pokes?sender=0x33",
+			status: http.StatusBadRequest,
+			want:   `{"error":"invalid sender parameter: must be an address"}`,
+		},
+		{
+			path:   "/pokes?limit=0",
+			status: http.StatusBadRequest,
+			want:   `{"error":"invalid limit parameter: must be between 1 and 1000"}`,
+		},
+		{
+			path:   "/summary?from=abc",
+			status: http.StatusBadRequest,
+			want:   `{"error":"invalid from parameter: strconv.ParseInt: parsing \"abc\": invalid syntax"}`,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			res, err := http.Get(fmt.Sprintf("http://%s%s", api.srv.Addr().String(), tt.path))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, res.StatusCode)
+			assert.JSONEq(t, tt.want, string(body))
+		})
+	}
+}
@@ -0,0 +1,539 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/retry"
+)
+
+const LoggerTag = "MEDIAN_INDEXER"
+
+// retryInterval is the interval between retry attempts in case of an error
+// while communicating with a node.
+const retryInterval = 5 * time.Second
+
+// logMedianPriceTopic0 is Keccak256("LogMedianPrice(uint256,uint256)")
+var logMedianPriceTopic0 = types.HexToHash("0xb78ebc573f1f889ca9e1e0fb62c843c836f3d3a2e1f43ef62940e9b894f4ea4c")
+
+// watCalldata is the calldata of the wat() method of the Median contract.
+var watCalldata = types.HexToBytes("0x4ca29923")
+
+var errTransactionNotFound = errors.New("transaction not found")
+
+// Config contains a configuration options for Indexer.
+type Config struct {
+	// Client is an instance of Ethereum RPC client.
+	Client ethereumv2.Client
+	// Signer is an instance of the ethereum.Signer which will be used to
+	// recover feeder addresses from price signatures.
+	Signer ethereum.Signer
+	// Storage is the storage implementation for indexed pokes.
+	Storage Storage
+	// Contracts is a list of Median contracts to index.
+	Contracts []types.Address
+	// Interval specifies how often indexer should check for new pokes.
+	Interval time.Duration
+	// PrefetchBlocks specifies how many blocks before the latest block
+	// should be indexed during the start of the indexer.
+	PrefetchBlocks uint64
+	// BlockLimit specifies how from many blocks logs can be fetched at once.
+	BlockLimit uint64
+	// BlockConfirmations specifies how many blocks should be confirmed before
+	// fetching logs.
+	BlockConfirmations uint64
+	// Logger is a current logger interface used by the Indexer.
+	Logger log.Logger
+}
+
+// Poke is a single update of a Median contract.
+type Poke struct {
+	// Contract is the address of the Median contract.
+	Contract types.Address
+	// Wat is the asset name of the Median contract.
+	Wat string
+	// Val is the new median price multiplied by oracle.PriceMultiplier.
+	Val *big.Int
+	// Age is the time of the poke reported by the contract.
+	Age time.Time
+	// BlockNumber is the number of the block with the poke.
+	BlockNumber uint64
+	// TxHash is the hash of the poke transaction.
+	TxHash types.Hash
+	// LogIndex is the index of the LogMedianPrice log in the block.
+	LogIndex uint64
+	// Sender is the address of the transaction sender.
+	Sender types.Address
+	// GasUsed is the amount of gas used by the whole transaction.
+	GasUsed uint64
+	// GasPrice is the effective price per gas paid by the sender.
+	GasPrice *big.Int
+	// Feeders is the list of feeders whose signatures were included in the
+	// poke. It is empty if the poke method was not called directly by the
+	// transaction, e.g. if it was called by another contract.
+	Feeders []types.Address
+}
+
+// Cost returns the amount of wei paid for the poke transaction.
+func (p Poke) Cost() *big.Int {
+	if p.GasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(p.GasUsed), p.GasPrice)
+}
+
+// Indexer indexes pokes of Median contracts.
+//
+// It periodically fetches LogMedianPrice logs emitted by the configured
+// contracts. For every log, it fetches the poke transaction and its receipt
+// to find the sender and the gas used, and decodes the transaction calldata
+// to recover the feeders whose signatures were included in the poke.
+// Indexed pokes are added to the storage and can be queried using the Pokes
+// method.
+//
+// During the start, the indexer also indexes the given number of blocks
+// before the latest block.
+//
+// In the event of an error in communication with a node, the indexer will try
+// to repeat requests to the node indefinitely.
+type Indexer struct {
+	ctx    context.Context
+	waitCh chan error
+
+	// Configuration parameters copied from Config:
+	client         ethereumv2.Client
+	signer         ethereum.Signer
+	storage        Storage
+	contracts      []types.Address
+	interval       time.Duration
+	prefetchBlocks uint64
+	blockLimit     uint64
+	blockConfirms  uint64
+	log            log.Logger
+
+	// wats contains cached asset names of contracts.
+	wats map[types.Address]string
+}
+
+// New returns a new instance of the Indexer struct.
+func New(cfg Config) (*Indexer, error) {
+	if cfg.Client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if cfg.Signer == nil {
+		return nil, errors.New("signer must not be nil")
+	}
+	if cfg.Storage == nil {
+		return nil, errors.New("storage must not be nil")
+	}
+	if cfg.Interval == 0 {
+		return nil, errors.New("interval is not set")
+	}
+	if len(cfg.Contracts) == 0 {
+		return nil, errors.New("no contracts provided")
+	}
+	if cfg.BlockLimit <= 0 {
+		return nil, errors.New("block limit must be greater than 0")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	return &Indexer{
+		waitCh:         make(chan error),
+		client:         cfg.Client,
+		signer:         cfg.Signer,
+		storage:        cfg.Storage,
+		contracts:      cfg.Contracts,
+		interval:       cfg.Interval,
+		prefetchBlocks: cfg.PrefetchBlocks,
+		blockLimit:     cfg.BlockLimit,
+		blockConfirms:  cfg.BlockConfirmations,
+		log:            cfg.Logger.WithField("tag", LoggerTag),
+		wats:           map[types.Address]string{},
+	}, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (ix *Indexer) Start(ctx context.Context) error {
+	if ix.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	ix.log.Info("Starting")
+	ix.ctx = ctx
+	go ix.indexerRoutine()
+	go ix.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (ix *Indexer) Wait() chan error {
+	return ix.waitCh
+}
+
+// Pokes returns indexed pokes that match the given query.
+func (ix *Indexer) Pokes(ctx context.Context, q Query) ([]Poke, error) {
+	return ix.storage.Pokes(ctx, q)
+}
+
+// indexerRoutine indexes blocks from the prefetch range and then
+// periodically indexes new blocks.
+func (ix *Indexer) indexerRoutine() {
+	latestBlock, ok := ix.getBlockNumber()
+	if !ok {
+		return // Context was canceled.
+	}
+	head := subUint64(latestBlock, ix.blockConfirms)
+	next, ok := ix.indexBlocks(subUint64(head+1, ix.prefetchBlocks), head)
+	if !ok {
+		return // Context was canceled.
+	}
+	t := time.NewTicker(ix.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ix.ctx.Done():
+			return
+		case <-t.C:
+			latestBlock, ok := ix.getBlockNumber()
+			if !ok {
+				return // Context was canceled.
+			}
+			head := subUint64(latestBlock, ix.blockConfirms)
+			if head < next {
+				continue // There is no new blocks.
+			}
+			if next, ok = ix.indexBlocks(next, head); !ok {
+				return // Context was canceled.
+			}
+		}
+	}
+}
+
+// indexBlocks indexes pokes from the given block range. It returns the
+// number of the next block to index. If pokes cannot be added to the
+// storage, the returned block is the first one that was not stored. The
+// second return value is false if the context was canceled.
+func (ix *Indexer) indexBlocks(from, to uint64) (uint64, bool) {
+	for _, b := range splitBlockRanges(from, to, ix.blockLimit) {
+		ix.log.
+			WithFields(log.Fields{
+				"from": b[0],
+				"to":   b[1],
+			}).
+			Debug("Indexing pokes")
+		logs, ok := ix.filterLogs(b[0], b[1])
+		if !ok {
+			return from, false // Context was canceled.
+		}
+		var pokes []Poke
+		for _, l := range logs {
+			if l.Removed {
+				continue
+			}
+			p, ok := ix.poke(l)
+			if !ok {
+				return from, false // Context was canceled.
+			}
+			if p != nil {
+				pokes = append(pokes, *p)
+			}
+		}
+		if len(pokes) > 0 {
+			if err := ix.storage.Add(ix.ctx, pokes); err != nil {
+				// The range will be indexed again during the next tick.
+				ix.log.WithError(err).Error("Unable to add pokes to the storage")
+				return from, true
+			}
+		}
+		from = b[1] + 1
+	}
+	return from, true
+}
+
+// poke creates a Poke from the LogMedianPrice log. It returns nil if the log
+// is invalid. The second return value is false if the context was canceled.
+func (ix *Indexer) poke(l types.Log) (*Poke, bool) {
+	if len(l.Data) < 64 {
+		ix.log.
+			WithField("txHash", l.TxHash.String()).
+			Warn("Invalid LogMedianPrice log")
+		return nil, true
+	}
+	wat, ok := ix.getWat(l.Address)
+	if !ok {
+		return nil, false
+	}
+	tx, ok := ix.getTransaction(l.TxHash)
+	if !ok {
+		return nil, false
+	}
+	receipt, ok := ix.getReceipt(l.TxHash)
+	if !ok {
+		return nil, false
+	}
+	gasPrice := tx.GasPrice.Big()
+	if receipt.EffectiveGasPrice != nil {
+		gasPrice = receipt.EffectiveGasPrice.Big()
+	}
+	return &Poke{
+		Contract:    l.Address,
+		Wat:         wat,
+		Val:         new(big.Int).SetBytes(l.Data[0:32]),
+		Age:         time.Unix(new(big.Int).SetBytes(l.Data[32:64]).Int64(), 0),
+		BlockNumber: l.BlockNumber.Big().Uint64(),
+		TxHash:      l.TxHash,
+		LogIndex:    l.LogIndex.Big().Uint64(),
+		Sender:      tx.From,
+		GasUsed:     receipt.GasUsed.Big().Uint64(),
+		GasPrice:    gasPrice,
+		Feeders:     ix.feeders(l.Address, wat, tx),
+	}, true
+}
+
+// feeders recovers addresses of feeders from the poke calldata. It returns
+// nil if the transaction is not a direct poke call to the contract.
+func (ix *Indexer) feeders(contract types.Address, wat string, tx *types.Transaction) []types.Address {
+	if tx.To != contract {
+		return nil
+	}
+	prices, err := geth.DecodePokeCalldata(wat, tx.Input)
+	if err != nil {
+		ix.log.
+			WithError(err).
+			WithField("txHash", tx.Hash.String()).
+			Debug("Unable to decode poke calldata")
+		return nil
+	}
+	var feeders []types.Address
+	for _, p := range prices {
+		from, err := p.From(ix.signer)
+		if err != nil {
+			ix.log.
+				WithError(err).
+				WithField("txHash", tx.Hash.String()).
+				Warn("Unable to recover feeder address")
+			continue
+		}
+		feeders = append(feeders, types.Address(*from))
+	}
+	return feeders
+}
+
+func (ix *Indexer) contextCancelHandler() {
+	defer func() { close(ix.waitCh) }()
+	defer ix.log.Info("Stopped")
+	<-ix.ctx.Done()
+}
+
+// getBlockNumber returns the latest block number on the blockchain.
+//
+// The method will try to fetch the block number indefinitely in case of an
+// error. The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ix *Indexer) getBlockNumber() (uint64, bool) {
+	var err error
+	var res uint64
+	retry.TryForever(
+		ix.ctx,
+		func() error {
+			res, err = ix.client.BlockNumber(ix.ctx)
+			if err != nil {
+				ix.log.WithError(err).Error("Unable to get block number")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if ix.ctx.Err() != nil {
+		return 0, false
+	}
+	return res, true
+}
+
+// getWat returns the asset name of the given Median contract. Asset names are
+// cached, because they cannot be changed.
+//
+// The method will try to fetch the asset name indefinitely in case of an
+// error. The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ix *Indexer) getWat(contract types.Address) (string, bool) {
+	if wat, ok := ix.wats[contract]; ok {
+		return wat, true
+	}
+	var err error
+	var res []byte
+	retry.TryForever(
+		ix.ctx,
+		func() error {
+			res, err = ix.client.Call(ix.ctx, types.Call{To: &contract, Data: watCalldata}, types.LatestBlockNumber)
+			if err == nil && len(res) < 32 {
+				err = errors.New("invalid wat response")
+			}
+			if err != nil {
+				ix.log.
+					WithError(err).
+					WithField("address", contract.String()).
+					Error("Unable to get asset name")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if ix.ctx.Err() != nil {
+		return "", false
+	}
+	wat := string(bytes.TrimRight(res[:32], "\x00"))
+	ix.wats[contract] = wat
+	return wat, true
+}
+
+// getTransaction returns the transaction with the given hash.
+//
+// The method will try to fetch the transaction indefinitely in case of an
+// error. The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ix *Indexer) getTransaction(hash types.Hash) (*types.Transaction, bool) {
+	var err error
+	var res *types.Transaction
+	retry.TryForever(
+		ix.ctx,
+		func() error {
+			res, err = ix.client.TransactionByHash(ix.ctx, hash)
+			if err == nil && res == nil {
+				err = errTransactionNotFound
+			}
+			if err != nil {
+				ix.log.
+					WithError(err).
+					WithField("txHash", hash.String()).
+					Error("Unable to get transaction")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if ix.ctx.Err() != nil {
+		return nil, false
+	}
+	return res, true
+}
+
+// getReceipt returns the receipt of the transaction with the given hash.
+//
+// The method will try to fetch the receipt indefinitely in case of an error.
+// The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ix *Indexer) getReceipt(hash types.Hash) (*types.TransactionReceiptType, bool) {
+	var err error
+	var res *types.TransactionReceiptType
+	retry.TryForever(
+		ix.ctx,
+		func() error {
+			res, err = ix.client.TransactionReceipt(ix.ctx, hash)
+			if err == nil && res == nil {
+				err = errTransactionNotFound
+			}
+			if err != nil {
+				ix.log.
+					WithError(err).
+					WithField("txHash", hash.String()).
+					Error("Unable to get transaction receipt")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if ix.ctx.Err() != nil {
+		return nil, false
+	}
+	return res, true
+}
+
+// filterLogs fetches LogMedianPrice logs emitted by the configured contracts
+// from the given block range.
+//
+// The method will try to fetch logs indefinitely in case of an error.
+// The only way to stop this method from trying again is to cancel the
+// context. In that case, the method will return false as a second return
+// value.
+func (ix *Indexer) filterLogs(from, to uint64) ([]types.Log, bool) {
+	var err error
+	var res []types.Log
+	retry.TryForever(
+		ix.ctx,
+		func() error {
+			fromBlockNumber := types.Uint64ToBlockNumber(from)
+			toBlockNumber := types.Uint64ToBlockNumber(to)
+			res, err = ix.client.FilterLogs(ix.ctx, types.FilterLogsQuery{
+				FromBlock: &fromBlockNumber,
+				ToBlock:   &toBlockNumber,
+				Address:   ix.contracts,
+				Topics:    []types.Hashes{{logMedianPriceTopic0}},
+			})
+			if err != nil {
+				ix.log.WithError(err).Error("Unable to filter logs")
+			}
+			return err
+		},
+		retryInterval,
+	)
+	if ix.ctx.Err() != nil {
+		return nil, false
+	}
+	return res, true
+}
+
+// subUint64 subtracts b from a. If the result would be negative, it returns 0.
+func subUint64(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
+
+// splitBlockRanges splits a block range into smaller ranges of at most
+// "limit" blocks. Some RPC providers have a limit on the number of blocks
+// that can be fetched in a single request.
+func splitBlockRanges(from, to, limit uint64) [][2]uint64 {
+	var ranges [][2]uint64
+	for from <= to {
+		end := to
+		if to-from >= limit {
+			end = from + limit - 1
+		}
+		ranges = append(ranges, [2]uint64{from, end})
+		from = end + 1
+	}
+	return ranges
+}
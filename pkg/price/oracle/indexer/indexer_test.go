@@ -0,0 +1,322 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	ethereumGeth "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/geth"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle/geth"
+)
+
+var (
+	testMedian1 = types.HexToAddress("0x1111111111111111111111111111111111111111")
+	testMedian2 = types.HexToAddress("0x2222222222222222222222222222222222222222")
+	testSender  = types.HexToAddress("0x3333333333333333333333333333333333333333")
+	testFeeder1 = types.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	testFeeder2 = types.HexToAddress("0x8eb3daaf5cb4138f5f96711c09c0cfd0288a36e9")
+
+	// testSigners contains signers of test feeders. Keys are loaded lazily,
+	// because decrypting the keystore is slow.
+	testSigners = map[types.Address]ethereum.Signer{}
+)
+
+// indexerClient is a fake Ethereum client that serves logs, transactions
+// and receipts of pokes.
+type indexerClient struct {
+	ethereumv2.Client
+
+	mu          sync.Mutex
+	blockNumber uint64
+	logs        []types.Log
+	txs         map[types.Hash]*types.Transaction
+	receipts    map[types.Hash]*types.TransactionReceiptType
+	queries     []types.FilterLogsQuery
+}
+
+func newIndexerClient() *indexerClient {
+	return &indexerClient{
+		txs:      map[types.Hash]*types.Transaction{},
+		receipts: map[types.Hash]*types.TransactionReceiptType{},
+	}
+}
+
+func (c *indexerClient) BlockNumber(_ context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blockNumber, nil
+}
+
+func (c *indexerClient) Call(_ context.Context, call types.Call, _ types.BlockNumber) ([]byte, error) {
+	wat := make([]byte, 32)
+	switch *call.To {
+	case testMedian1:
+		copy(wat, "ETHUSD")
+	case testMedian2:
+		copy(wat, "BTCUSD")
+	}
+	return wat, nil
+}
+
+func (c *indexerClient) FilterLogs(_ context.Context, q types.FilterLogsQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = append(c.queries, q)
+	from := q.FromBlock.Big().Uint64()
+	to := q.ToBlock.Big().Uint64()
+	var logs []types.Log
+	for _, l := range c.logs {
+		n := l.BlockNumber.Big().Uint64()
+		if n < from || n > to {
+			continue
+		}
+		for _, a := range q.Address {
+			if a == l.Address {
+				logs = append(logs, l)
+			}
+		}
+	}
+	return logs, nil
+}
+
+func (c *indexerClient) TransactionByHash(_ context.Context, hash types.Hash) (*types.Transaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.txs[hash], nil
+}
+
+func (c *indexerClient) TransactionReceipt(_ context.Context, hash types.Hash) (*types.TransactionReceiptType, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.receipts[hash], nil
+}
+
+// addPoke adds a poke transaction that emits LogMedianPrice logs for every
+// given contract. The calldata is a poke call if only one contract is given.
+func (c *indexerClient) addPoke(
+	t *testing.T,
+	txHash types.Hash,
+	block uint64,
+	contracts []types.Address,
+	prices []*oracle.Price,
+) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cd, err := geth.PokeCalldata(prices)
+	require.NoError(t, err)
+	tx := &types.Transaction{
+		Hash:     txHash,
+		From:     testSender,
+		To:       contracts[0],
+		GasPrice: types.Uint64ToNumber(10),
+		Input:    cd,
+	}
+	if len(contracts) > 1 {
+		tx.To = types.HexToAddress("0x4444444444444444444444444444444444444444")
+	}
+	effectiveGasPrice := types.Uint64ToNumber(5)
+	c.txs[txHash] = tx
+	c.receipts[txHash] = &types.TransactionReceiptType{
+		TransactionHash:   txHash,
+		GasUsed:           types.Uint64ToNumber(100000),
+		EffectiveGasPrice: &effectiveGasPrice,
+	}
+	for _, contract := range contracts {
+		data := make([]byte, 64)
+		prices[len(prices)/2].Val.FillBytes(data[0:32])
+		big.NewInt(prices[len(prices)/2].Age.Unix()).FillBytes(data[32:64])
+		c.logs = append(c.logs, types.Log{
+			Address:     contract,
+			Topics:      []types.Hash{logMedianPriceTopic0},
+			Data:        data,
+			BlockNumber: types.Uint64ToNumber(block),
+			TxHash:      txHash,
+			LogIndex:    types.Uint64ToNumber(uint64(len(c.logs))),
+		})
+	}
+}
+
+func (c *indexerClient) setBlockNumber(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blockNumber = n
+}
+
+func signedPrices(t *testing.T, wat string) []*oracle.Price {
+	var prices []*oracle.Price
+	for i, feeder := range []types.Address{testFeeder1, testFeeder2} {
+		if testSigners[feeder] == nil {
+			account, err := ethereumGeth.NewAccount("./testdata/keystore", "test123", ethereum.Address(feeder))
+			require.NoError(t, err)
+			testSigners[feeder] = ethereumGeth.NewSigner(account)
+		}
+		p := &oracle.Price{Wat: wat, Val: big.NewInt(int64(10 + i)), Age: time.Unix(1600000000, 0)}
+		require.NoError(t, p.Sign(testSigners[feeder]))
+		prices = append(prices, p)
+	}
+	return prices
+}
+
+func newTestIndexer(t *testing.T, cli *indexerClient, prefetch uint64) *Indexer {
+	ix, err := New(Config{
+		Client:             cli,
+		Signer:             ethereumGeth.NewSigner(nil),
+		Storage:            NewMemoryStorage(0),
+		Contracts:          []types.Address{testMedian1, testMedian2},
+		Interval:           time.Millisecond * 10,
+		PrefetchBlocks:     prefetch,
+		BlockLimit:         10,
+		BlockConfirmations: 2,
+	})
+	require.NoError(t, err)
+	return ix
+}
+
+func waitForPokes(t *testing.T, ix *Indexer, n int) []Poke {
+	var pokes []Poke
+	require.Eventually(t, func() bool {
+		var err error
+		pokes, err = ix.Pokes(context.Background(), Query{})
+		require.NoError(t, err)
+		return len(pokes) >= n
+	}, time.Second, time.Millisecond*5)
+	return pokes
+}
+
+func TestIndexer(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	cli := newIndexerClient()
+	cli.setBlockNumber(100)
+	cli.addPoke(t, types.HexToHash("0x01"), 50, []types.Address{testMedian1}, signedPrices(t, "ETHUSD"))
+	cli.addPoke(t, types.HexToHash("0x02"), 60, []types.Address{testMedian1, testMedian2}, signedPrices(t, "ETHUSD"))
+
+	ix := newTestIndexer(t, cli, 60)
+	require.NoError(t, ix.Start(ctx))
+
+	pokes := waitForPokes(t, ix, 3)
+	require.Len(t, pokes, 3)
+
+	// Direct poke call:
+	assert.Equal(t, testMedian1, pokes[0].Contract)
+	assert.Equal(t, "ETHUSD", pokes[0].Wat)
+	assert.Equal(t, big.NewInt(11), pokes[0].Val)
+	assert.Equal(t, int64(1600000000), pokes[0].Age.Unix())
+	assert.Equal(t, uint64(50), pokes[0].BlockNumber)
+	assert.Equal(t, types.HexToHash("0x01"), pokes[0].TxHash)
+	assert.Equal(t, testSender, pokes[0].Sender)
+	assert.Equal(t, uint64(100000), pokes[0].GasUsed)
+	assert.Equal(t, big.NewInt(5), pokes[0].GasPrice)
+	assert.Equal(t, big.NewInt(500000), pokes[0].Cost())
+	assert.Equal(t, []types.Address{testFeeder1, testFeeder2}, pokes[0].Feeders)
+
+	// Poke called by another contract:
+	assert.Equal(t, testMedian1, pokes[1].Contract)
+	assert.Equal(t, testMedian2, pokes[2].Contract)
+	assert.Equal(t, "BTCUSD", pokes[2].Wat)
+	assert.Empty(t, pokes[1].Feeders)
+	assert.Empty(t, pokes[2].Feeders)
+
+	// Prefetched blocks are 39 to 98, because of the block confirmations:
+	cli.mu.Lock()
+	assert.Equal(t, uint64(39), cli.queries[0].FromBlock.Big().Uint64())
+	assert.Equal(t, uint64(98), cli.queries[len(cli.queries)-1].ToBlock.Big().Uint64())
+	cli.mu.Unlock()
+
+	// New blocks:
+	cli.addPoke(t, types.HexToHash("0x03"), 101, []types.Address{testMedian2}, signedPrices(t, "BTCUSD"))
+	cli.setBlockNumber(103)
+
+	pokes = waitForPokes(t, ix, 4)
+	require.Len(t, pokes, 4)
+	assert.Equal(t, types.HexToHash("0x03"), pokes[3].TxHash)
+	assert.Equal(t, []types.Address{testFeeder1, testFeeder2}, pokes[3].Feeders)
+
+	// Query:
+	pokes, err := ix.Pokes(ctx, Query{Contract: &testMedian2, Feeder: &testFeeder1})
+	require.NoError(t, err)
+	require.Len(t, pokes, 1)
+	assert.Equal(t, types.HexToHash("0x03"), pokes[0].TxHash)
+}
+
+func Test_splitBlockRanges(t *testing.T) {
+	tests := []struct {
+		from, to, limit uint64
+		want            [][2]uint64
+	}{
+		{from: 1, to: 0, limit: 10, want: nil},
+		{from: 0, to: 0, limit: 10, want: [][2]uint64{{0, 0}}},
+		{from: 0, to: 9, limit: 10, want: [][2]uint64{{0, 9}}},
+		{from: 0, to: 10, limit: 10, want: [][2]uint64{{0, 9}, {10, 10}}},
+		{from: 5, to: 25, limit: 10, want: [][2]uint64{{5, 14}, {15, 24}, {25, 25}}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			assert.Equal(t, tt.want, splitBlockRanges(tt.from, tt.to, tt.limit))
+		})
+	}
+}
+
+// failingStorage is a storage that fails to add pokes a given number of times.
+type failingStorage struct {
+	Storage
+
+	mu    sync.Mutex
+	fails int
+}
+
+func (s *failingStorage) Add(ctx context.Context, pokes []Poke) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("storage error")
+	}
+	return s.Storage.Add(ctx, pokes)
+}
+
+func TestIndexer_StorageError(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	cli := newIndexerClient()
+	cli.setBlockNumber(100)
+	cli.addPoke(t, types.HexToHash("0x01"), 50, []types.Address{testMedian1}, signedPrices(t, "ETHUSD"))
+
+	ix := newTestIndexer(t, cli, 60)
+	ix.storage = &failingStorage{Storage: NewMemoryStorage(0), fails: 2}
+	require.NoError(t, ix.Start(ctx))
+
+	// Blocks must be indexed again after the storage error:
+	pokes := waitForPokes(t, ix, 1)
+	require.Len(t, pokes, 1)
+	assert.Equal(t, types.HexToHash("0x01"), pokes[0].TxHash)
+}
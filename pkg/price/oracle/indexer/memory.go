@@ -0,0 +1,91 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+// pokeID uniquely identifies a poke.
+type pokeID struct {
+	txHash   types.Hash
+	logIndex uint64
+}
+
+// MemoryStorage is an in-memory implementation of the Storage interface.
+type MemoryStorage struct {
+	mu    sync.RWMutex
+	limit int
+	pokes []Poke
+	ids   map[pokeID]struct{}
+}
+
+// NewMemoryStorage creates a new storage instance. The storage keeps only
+// the given number of the most recent pokes. If the limit is 0, all pokes
+// are kept.
+func NewMemoryStorage(limit int) *MemoryStorage {
+	return &MemoryStorage{
+		limit: limit,
+		ids:   map[pokeID]struct{}{},
+	}
+}
+
+// Add implements the Storage interface.
+func (m *MemoryStorage) Add(_ context.Context, pokes []Poke) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range pokes {
+		id := pokeID{txHash: p.TxHash, logIndex: p.LogIndex}
+		if _, ok := m.ids[id]; ok {
+			continue
+		}
+		m.ids[id] = struct{}{}
+		m.pokes = append(m.pokes, p)
+	}
+	sort.SliceStable(m.pokes, func(i, j int) bool {
+		if m.pokes[i].BlockNumber != m.pokes[j].BlockNumber {
+			return m.pokes[i].BlockNumber < m.pokes[j].BlockNumber
+		}
+		return m.pokes[i].LogIndex < m.pokes[j].LogIndex
+	})
+	if m.limit > 0 && len(m.pokes) > m.limit {
+		for _, p := range m.pokes[:len(m.pokes)-m.limit] {
+			delete(m.ids, pokeID{txHash: p.TxHash, logIndex: p.LogIndex})
+		}
+		m.pokes = append([]Poke(nil), m.pokes[len(m.pokes)-m.limit:]...)
+	}
+	return nil
+}
+
+// Pokes implements the Storage interface.
+func (m *MemoryStorage) Pokes(_ context.Context, q Query) ([]Poke, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var pokes []Poke
+	for _, p := range m.pokes {
+		if q.Match(p) {
+			pokes = append(pokes, p)
+		}
+	}
+	if q.Limit > 0 && len(pokes) > q.Limit {
+		pokes = pokes[len(pokes)-q.Limit:]
+	}
+	return pokes, nil
+}
@@ -0,0 +1,99 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+func testPoke(block, logIndex uint64, contract types.Address, feeders ...types.Address) Poke {
+	return Poke{
+		Contract:    contract,
+		Age:         time.Unix(int64(block), 0),
+		BlockNumber: block,
+		TxHash:      types.BytesToHash([]byte{byte(block)}),
+		LogIndex:    logIndex,
+		Sender:      testSender,
+		Feeders:     feeders,
+	}
+}
+
+func TestMemoryStorage_Add(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStorage(3)
+
+	require.NoError(t, m.Add(ctx, []Poke{testPoke(2, 0, testMedian1), testPoke(1, 0, testMedian1)}))
+	require.NoError(t, m.Add(ctx, []Poke{testPoke(2, 0, testMedian1), testPoke(3, 0, testMedian1)}))
+
+	pokes, err := m.Pokes(ctx, Query{})
+	require.NoError(t, err)
+	require.Len(t, pokes, 3)
+	assert.Equal(t, uint64(1), pokes[0].BlockNumber)
+	assert.Equal(t, uint64(2), pokes[1].BlockNumber)
+	assert.Equal(t, uint64(3), pokes[2].BlockNumber)
+
+	// The oldest poke must be removed after the limit is reached:
+	require.NoError(t, m.Add(ctx, []Poke{testPoke(4, 0, testMedian1)}))
+	pokes, err = m.Pokes(ctx, Query{})
+	require.NoError(t, err)
+	require.Len(t, pokes, 3)
+	assert.Equal(t, uint64(2), pokes[0].BlockNumber)
+	assert.Equal(t, uint64(4), pokes[2].BlockNumber)
+}
+
+func TestMemoryStorage_Pokes(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStorage(0)
+	require.NoError(t, m.Add(ctx, []Poke{
+		testPoke(1, 0, testMedian1, testFeeder1),
+		testPoke(2, 0, testMedian2, testFeeder1, testFeeder2),
+		testPoke(3, 0, testMedian1, testFeeder2),
+		testPoke(4, 0, testMedian2),
+	}))
+
+	tests := []struct {
+		query  Query
+		blocks []uint64
+	}{
+		{query: Query{}, blocks: []uint64{1, 2, 3, 4}},
+		{query: Query{Contract: &testMedian1}, blocks: []uint64{1, 3}},
+		{query: Query{Feeder: &testFeeder1}, blocks: []uint64{1, 2}},
+		{query: Query{Sender: &testMedian1}, blocks: nil},
+		{query: Query{Sender: &testSender}, blocks: []uint64{1, 2, 3, 4}},
+		{query: Query{From: time.Unix(2, 0), To: time.Unix(4, 0)}, blocks: []uint64{2, 3}},
+		{query: Query{Limit: 2}, blocks: []uint64{3, 4}},
+		{query: Query{Contract: &testMedian2, Feeder: &testFeeder2, Limit: 1}, blocks: []uint64{2}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			pokes, err := m.Pokes(ctx, tt.query)
+			require.NoError(t, err)
+			var blocks []uint64
+			for _, p := range pokes {
+				blocks = append(blocks, p.BlockNumber)
+			}
+			assert.Equal(t, tt.blocks, blocks)
+		})
+	}
+}
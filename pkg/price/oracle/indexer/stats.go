@@ -0,0 +1,77 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"math/big"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+// Stats contains statistics calculated from a list of pokes.
+type Stats struct {
+	// Pokes is the number of pokes.
+	Pokes int
+	// Feeders maps feeder addresses to the number of pokes in which their
+	// signatures were included.
+	Feeders map[types.Address]int
+	// Senders maps addresses of transaction senders to their statistics.
+	Senders map[types.Address]*SenderStats
+}
+
+// SenderStats contains statistics of a single transaction sender.
+type SenderStats struct {
+	// Pokes is the number of pokes sent.
+	Pokes int
+	// Transactions is the number of transactions sent. It may be lower than
+	// the number of pokes if multiple contracts were poked in a single
+	// transaction.
+	Transactions int
+	// GasUsed is the total amount of gas used by transactions.
+	GasUsed uint64
+	// Cost is the total amount of wei paid for transactions.
+	Cost *big.Int
+}
+
+// Summarize calculates statistics for the given list of pokes. Gas used by
+// a transaction that pokes multiple contracts is counted only once.
+func Summarize(pokes []Poke) Stats {
+	s := Stats{
+		Pokes:   len(pokes),
+		Feeders: map[types.Address]int{},
+		Senders: map[types.Address]*SenderStats{},
+	}
+	txs := map[types.Hash]struct{}{}
+	for _, p := range pokes {
+		for _, f := range p.Feeders {
+			s.Feeders[f]++
+		}
+		ss, ok := s.Senders[p.Sender]
+		if !ok {
+			ss = &SenderStats{Cost: new(big.Int)}
+			s.Senders[p.Sender] = ss
+		}
+		ss.Pokes++
+		if _, ok := txs[p.TxHash]; ok {
+			continue
+		}
+		txs[p.TxHash] = struct{}{}
+		ss.Transactions++
+		ss.GasUsed += p.GasUsed
+		ss.Cost.Add(ss.Cost, p.Cost())
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+func TestSummarize(t *testing.T) {
+	p1 := testPoke(1, 0, testMedian1, testFeeder1, testFeeder2)
+	p2 := testPoke(1, 1, testMedian2, testFeeder1)
+	p3 := testPoke(2, 0, testMedian1, testFeeder2)
+	p3.Sender = testFeeder1
+	for _, p := range []*Poke{&p1, &p2, &p3} {
+		p.GasUsed = 100
+		p.GasPrice = big.NewInt(2)
+	}
+
+	s := Summarize([]Poke{p1, p2, p3})
+
+	assert.Equal(t, 3, s.Pokes)
+	assert.Equal(t, map[types.Address]int{testFeeder1: 2, testFeeder2: 2}, s.Feeders)
+
+	// The first two pokes were sent in the same transaction:
+	assert.Equal(t, 2, s.Senders[testSender].Pokes)
+	assert.Equal(t, 1, s.Senders[testSender].Transactions)
+	assert.Equal(t, uint64(100), s.Senders[testSender].GasUsed)
+	assert.Equal(t, big.NewInt(200), s.Senders[testSender].Cost)
+	assert.Equal(t, 1, s.Senders[testFeeder1].Pokes)
+	assert.Equal(t, big.NewInt(200), s.Senders[testFeeder1].Cost)
+}
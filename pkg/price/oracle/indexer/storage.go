@@ -0,0 +1,81 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package indexer
+
+import (
+	"context"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+)
+
+// Storage provides an interface to the poke storage.
+type Storage interface {
+	// Add adds pokes to the storage. Pokes that are already in the storage
+	// are ignored. The method is thread-safe.
+	Add(ctx context.Context, pokes []Poke) error
+	// Pokes returns pokes that match the given query, ordered from the oldest
+	// to the newest. The method is thread-safe.
+	Pokes(ctx context.Context, q Query) ([]Poke, error)
+}
+
+// Query specifies which pokes should be returned from the storage. Empty
+// fields are not used to filter pokes.
+type Query struct {
+	// Contract is the address of the Median contract.
+	Contract *types.Address
+	// Sender is the address of the poke transaction sender.
+	Sender *types.Address
+	// Feeder is the address of the feeder whose signature was included in
+	// the poke.
+	Feeder *types.Address
+	// From is the time of the oldest poke to return, inclusive.
+	From time.Time
+	// To is the time of the newest poke to return, exclusive.
+	To time.Time
+	// Limit is the maximum number of the most recent pokes to return.
+	Limit int
+}
+
+// Match returns true if the poke matches the query. The Limit field is not
+// taken into account.
+func (q Query) Match(p Poke) bool {
+	if q.Contract != nil && *q.Contract != p.Contract {
+		return false
+	}
+	if q.Sender != nil && *q.Sender != p.Sender {
+		return false
+	}
+	if q.Feeder != nil && !containsAddress(p.Feeders, *q.Feeder) {
+		return false
+	}
+	if !q.From.IsZero() && p.Age.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !p.Age.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+func containsAddress(addresses []types.Address, address types.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
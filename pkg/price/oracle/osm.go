@@ -0,0 +1,66 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// OSM is an interface for the Oracle Security Module contract:
+// https://github.com/makerdao/osm/
+//
+// The OSM delays prices read from its source (usually a Median contract)
+// by one hop. The next price (nxt) becomes the current one (cur) on the
+// poke call that happens after the hop has passed.
+//
+// Contract documentation:
+// https://docs.makerdao.com/smart-contract-modules/oracle-module/oracle-security-module-osm-detailed-documentation
+type OSM interface {
+	// Address returns OSM contract address.
+	Address() common.Address
+	// Src returns the address of the source contract from which prices are
+	// read during the poke.
+	Src(ctx context.Context) (common.Address, error)
+	// Peek returns the current price and its validity flag from the
+	// contract's storage. The storage is used instead of the peek method
+	// because that method is available only to whitelisted addresses.
+	Peek(ctx context.Context) (*big.Int, bool, error)
+	// Peep returns the next price and its validity flag from the contract's
+	// storage. The storage is used instead of the peep method because that
+	// method is available only to whitelisted addresses.
+	Peep(ctx context.Context) (*big.Int, bool, error)
+	// Hop returns the value from contract's hop method. The hop is the
+	// minimum time between two pokes.
+	Hop(ctx context.Context) (time.Duration, error)
+	// Zzz returns the value from contract's zzz method. The zzz is the time
+	// of the last poke rounded down to the nearest hop.
+	Zzz(ctx context.Context) (time.Time, error)
+	// Pass returns the value from contract's pass method. It returns true if
+	// enough time has passed since the last poke to poke the contract again.
+	Pass(ctx context.Context) (bool, error)
+	// Poke sends transaction to the smart contract which invokes contract's
+	// poke method, which moves the next price to the current one and reads
+	// the new next price from the source. If simulateBeforeRun is set to
+	// true, then transaction will be simulated on the EVM before actual
+	// transaction will be send.
+	Poke(ctx context.Context, simulateBeforeRun bool) (*ethereum.Hash, error)
+}
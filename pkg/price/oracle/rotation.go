@@ -0,0 +1,60 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"fmt"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// FeedRotation describes the Median transactions required to replace
+// a feeder key.
+type FeedRotation struct {
+	// Lift is true if the new key has to be lifted.
+	Lift bool
+	// Drop is true if the old key has to be dropped.
+	Drop bool
+	// DropFirst is true if the old key has to be dropped before the new key
+	// is lifted. This is the case when both keys use the same bloom slot, so
+	// there can be no window during which both keys are lifted.
+	DropFirst bool
+}
+
+// PlanFeedRotation returns the transactions required to replace the old
+// feeder key with the new one, based on the current list of feeds.
+//
+// The Median contract allows only one feeder for each bloom slot, which is
+// the first byte of the feeder address. An error is returned if the slot of
+// the new key is used by a different feeder.
+func PlanFeedRotation(feeds []ethereum.Address, oldAddr, newAddr ethereum.Address) (FeedRotation, error) {
+	var r FeedRotation
+	var oldLifted, newLifted bool
+	for _, f := range feeds {
+		switch {
+		case f == oldAddr:
+			oldLifted = true
+		case f == newAddr:
+			newLifted = true
+		case f[0] == newAddr[0]:
+			return r, fmt.Errorf("the slot of the new key %s is used by %s", newAddr, f)
+		}
+	}
+	r.Lift = !newLifted
+	r.Drop = oldLifted
+	r.DropFirst = r.Lift && r.Drop && oldAddr[0] == newAddr[0]
+	return r, nil
+}
@@ -0,0 +1,77 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+func TestPlanFeedRotation(t *testing.T) {
+	oldAddr := ethereum.HexToAddress("0x1100000000000000000000000000000000000001")
+	newAddr := ethereum.HexToAddress("0x2200000000000000000000000000000000000002")
+	sameSlot := ethereum.HexToAddress("0x1100000000000000000000000000000000000002")
+	other := ethereum.HexToAddress("0x3300000000000000000000000000000000000003")
+	conflict := ethereum.HexToAddress("0x2200000000000000000000000000000000000003")
+
+	tests := []struct {
+		feeds   []ethereum.Address
+		newAddr ethereum.Address
+		want    FeedRotation
+		wantErr bool
+	}{
+		{
+			feeds:   []ethereum.Address{oldAddr, other},
+			newAddr: newAddr,
+			want:    FeedRotation{Lift: true, Drop: true},
+		},
+		{
+			feeds:   []ethereum.Address{oldAddr, newAddr, other},
+			newAddr: newAddr,
+			want:    FeedRotation{Lift: false, Drop: true},
+		},
+		{
+			feeds:   []ethereum.Address{newAddr, other},
+			newAddr: newAddr,
+			want:    FeedRotation{Lift: false, Drop: false},
+		},
+		{
+			feeds:   []ethereum.Address{oldAddr, other},
+			newAddr: sameSlot,
+			want:    FeedRotation{Lift: true, Drop: true, DropFirst: true},
+		},
+		{
+			feeds:   []ethereum.Address{oldAddr, conflict},
+			newAddr: newAddr,
+			wantErr: true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := PlanFeedRotation(tt.feeds, oldAddr, tt.newAddr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
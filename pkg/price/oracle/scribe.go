@@ -0,0 +1,73 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// Scribe is an interface for the Schnorr signature based oracle contract:
+// https://github.com/chronicleprotocol/scribe/
+//
+// Unlike the Median contract, Scribe does not verify a signature of every
+// feed separately. Instead, it verifies a single aggregated Schnorr signature
+// created by at least bar feeds.
+type Scribe interface {
+	// Address returns scribe contract address.
+	Address() common.Address
+	// Wat returns asset name.
+	Wat(ctx context.Context) (string, error)
+	// Bar returns the value from contract's bar method. The bar method returns
+	// the minimum number of feeds that must participate in the signature.
+	Bar(ctx context.Context) (int64, error)
+	// Read returns current asset price and the time of the last update from
+	// the contract's readWithAge method.
+	Read(ctx context.Context) (*big.Int, time.Time, error)
+	// Feeds returns a list of all Ethereum addresses that are authorized to
+	// participate in the signature.
+	Feeds(ctx context.Context) ([]ethereum.Address, error)
+	// PokeMessage returns the message that has to be signed by feeds for the
+	// given poke data, as returned by contract's constructPokeMessage method.
+	PokeMessage(ctx context.Context, pokeData ScribePokeData) ([32]byte, error)
+	// Poke sends transaction to the smart contract which invokes contract's
+	// poke method, which updates asset price. If simulateBeforeRun is set to
+	// true, then transaction will be simulated on the EVM before actual
+	// transaction will be send.
+	Poke(ctx context.Context, pokeData ScribePokeData, schnorrData SchnorrData, simulateBeforeRun bool) (*ethereum.Hash, error)
+}
+
+// ScribePokeData is the price data sent to the Scribe contract.
+type ScribePokeData struct {
+	Val *big.Int
+	Age time.Time
+}
+
+// SchnorrData is the aggregated Schnorr signature of the poke message.
+type SchnorrData struct {
+	// Signature is the aggregated signature.
+	Signature [32]byte
+	// Commitment is the address of the aggregated public nonce.
+	Commitment common.Address
+	// FeedIDs is the list of IDs of the feeds that participated in
+	// the signature, one byte per feed.
+	FeedIDs []byte
+}
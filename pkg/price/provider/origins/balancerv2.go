@@ -16,13 +16,10 @@
 package origins
 
 import (
-	"context"
 	_ "embed"
 	"fmt"
 	"math/big"
-	"sort"
 	"strings"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 
@@ -49,20 +46,20 @@ const prefixRef = "Ref:"
 var balancerV2PoolABI string
 
 type BalancerV2 struct {
-	ethClient         ethereum.Client
+	mc                *Multicall
 	ContractAddresses ContractAddresses
 	abi               abi.ABI
 	variable          byte
 	blocks            []int64
 }
 
-func NewBalancerV2(ethClient ethereum.Client, addrs ContractAddresses, blocks []int64) (*BalancerV2, error) {
+func NewBalancerV2(mc *Multicall, addrs ContractAddresses, blocks []int64) (*BalancerV2, error) {
 	a, err := abi.JSON(strings.NewReader(balancerV2PoolABI))
 	if err != nil {
 		return nil, err
 	}
 	return &BalancerV2{
-		ethClient:         ethClient,
+		mc:                mc,
 		ContractAddresses: addrs,
 		abi:               a,
 		variable:          0, // PAIR_PRICE
@@ -71,127 +68,57 @@ func NewBalancerV2(ethClient ethereum.Client, addrs ContractAddresses, blocks []
 }
 
 func (s BalancerV2) PullPrices(pairs []Pair) []FetchResult {
-	sort.Slice(pairs, func(i, j int) bool {
-		return pairs[i].String() < pairs[j].String()
-	})
-	var frs []FetchResult
-	var cds = make([][]ethereum.Call, len(pairs))
-	// Prepare list of calls.
-	for n, pair := range pairs {
-		contract, indirect, err := s.ContractAddresses.AddressByPair(pair)
-		if err != nil {
-			return fetchResultListWithErrors(pairs, err)
-		}
-		if indirect {
-			return fetchResultListWithErrors(
-				pairs,
-				fmt.Errorf("cannot use indirect pair to retrieve price: %s", pair.String()),
-			)
-		}
-		callData, err := s.abi.Pack("getLatest", s.variable)
-		if err != nil {
-			return fetchResultListWithErrors(
-				pairs,
-				fmt.Errorf("failed to pack contract args for getLatest (pair %s): %w", pair.String(), err),
-			)
-		}
-		cds[n] = append(cds[n], ethereum.Call{
-			Address: contract,
-			Data:    callData,
-		})
-		if err != nil {
-			return fetchResultListWithErrors(pairs, err)
-		}
-		token, inverted, ok := s.ContractAddresses.ByPair(Pair{Base: prefixRef + pair.Base, Quote: pair.Quote})
-		if ok {
-			if inverted {
-				return fetchResultListWithErrors(
-					pairs,
-					fmt.Errorf("cannot use inverted pair to retrieve price: %s", pair.String()),
-				)
-			}
-			callData, err := s.abi.Pack("getPriceRateCache", ethereum.HexToAddress(token)) //nolint:staticcheck
-			if err != nil {
-				return fetchResultListWithErrors(
-					pairs,
-					fmt.Errorf(
-						"failed to pack contract args for getPriceRateCache (pair %s): %w",
-						pair.String(),
-						err,
-					),
-				)
-			}
-			cds[n] = append(cds[n], ethereum.Call{
-				Address: contract,
-				Data:    callData,
-			})
-		}
+	return pullMulticallPrices(s.mc, pairs, s.blocks, s.calls, s.price)
+}
+
+func (s BalancerV2) multicall() *Multicall {
+	return s.mc
+}
+
+func (s BalancerV2) calls(pair Pair) ([]ethereum.Call, error) {
+	contract, indirect, err := s.ContractAddresses.AddressByPair(pair)
+	if err != nil {
+		return nil, err
 	}
-	// Execute calls.
-	resps, err := nestedMultiCall(s.ethClient, cds, s.blocks)
+	if indirect {
+		return nil, fmt.Errorf("cannot use indirect pair to retrieve price: %s", pair.String())
+	}
+	callData, err := s.abi.Pack("getLatest", s.variable)
 	if err != nil {
-		return fetchResultListWithErrors(pairs, err)
+		return nil, fmt.Errorf("failed to pack contract args for getLatest (pair %s): %w", pair.String(), err)
 	}
-	// Calculate prices.
-	for n, pair := range pairs {
-		priceFloat := reduceEtherAverageFloat(resps[n][0])
-		// If there are two calls, the second one is the reference price for the pair.
-		// We need to multiply the pair price by the reference price to get the final price.
-		if len(resps[n]) > 1 {
-			priceFloat = new(big.Float).Mul(reduceEtherAverageFloat(resps[n][1]), priceFloat)
+	calls := []ethereum.Call{{Address: contract, Data: callData}}
+	token, inverted, ok := s.ContractAddresses.ByPair(Pair{Base: prefixRef + pair.Base, Quote: pair.Quote})
+	if ok {
+		if inverted {
+			return nil, fmt.Errorf("cannot use inverted pair to retrieve price: %s", pair.String())
+		}
+		callData, err := s.abi.Pack("getPriceRateCache", ethereum.HexToAddress(token)) //nolint:staticcheck
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to pack contract args for getPriceRateCache (pair %s): %w",
+				pair.String(),
+				err,
+			)
 		}
-		price, _ := priceFloat.Float64()
-		frs = append(frs, FetchResult{
-			Price: Price{
-				Pair:      pair,
-				Price:     price,
-				Timestamp: time.Now(),
-			},
-		})
+		calls = append(calls, ethereum.Call{Address: contract, Data: callData})
 	}
-	return frs
+	return calls, nil
 }
 
-// nestedMultiCall performs a list of calls in a single multicall for each
-// block delta defined in the blocks argument. The returned slice have the
-// same structure as the calls slice. In the last array dimension, the
-// returned slice contains the results of the multicall for each block delta.
-func nestedMultiCall(ethClient ethereum.Client, calls [][]ethereum.Call, blocks []int64) ([][][][]byte, error) {
-	block, err := ethClient.BlockNumber(context.Background())
+func (s BalancerV2) price(_ Pair, res [][]MulticallResult) (*big.Float, error) {
+	price, err := averageMulticallResults(res[0])
 	if err != nil {
 		return nil, err
 	}
-	rs := make([][][][]byte, len(calls))
-	for i, call := range calls {
-		rs[i] = make([][][]byte, len(call))
-	}
-	// Perform multicall for each block delta.
-	for _, blockDelta := range blocks {
-		// Perform multicall.
-		cs := make([]ethereum.Call, 0, len(calls))
-		for _, call := range calls {
-			cs = append(cs, call...)
-		}
-		mrs, err := ethClient.MultiCall(
-			ethereum.WithBlockNumber(context.Background(), big.NewInt(block.Int64()-blockDelta)),
-			cs,
-		)
+	// If there are two calls, the second one is the reference price for the pair.
+	// We need to multiply the pair price by the reference price to get the final price.
+	if len(res) > 1 {
+		ref, err := averageMulticallResults(res[1])
 		if err != nil {
 			return nil, err
 		}
-		if len(mrs) != len(cs) {
-			return nil, fmt.Errorf(
-				"unexpected number of multicall results, expected %d, got %d", len(cs), len(mrs),
-			)
-		}
-		// Recreate the original structure of the calls slice.
-		n := 0
-		for i, call := range calls {
-			for j := range call {
-				rs[i][j] = append(rs[i][j], mrs[n])
-				n++
-			}
-		}
+		price = new(big.Float).Mul(ref, price)
 	}
-	return rs, nil
+	return price, nil
 }
@@ -19,19 +19,13 @@ import (
 	"math/big"
 	"testing"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/stretchr/testify/mock"
-
-	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
-	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
-
 	"github.com/stretchr/testify/suite"
 )
 
 type BalancerV2Suite struct {
 	suite.Suite
 	addresses ContractAddresses
-	client    *ethereumMocks.Client
+	client    *multicallClient
 	origin    *BaseExchangeHandler
 }
 
@@ -48,8 +42,8 @@ func (suite *BalancerV2Suite) TearDownSuite() {
 }
 
 func (suite *BalancerV2Suite) SetupTest() {
-	suite.client = &ethereumMocks.Client{}
-	o, err := NewBalancerV2(suite.client, suite.addresses, []int64{0, 10, 20})
+	suite.client = newMulticallClient(100)
+	o, err := NewBalancerV2(NewMulticall(suite.client, Multicall3Address), suite.addresses, []int64{0, 10, 20})
 	suite.NoError(err)
 	suite.origin = NewBaseExchangeHandler(o, nil)
 }
@@ -68,43 +62,11 @@ func TestBalancerV2Suite(t *testing.T) {
 }
 
 func (suite *BalancerV2Suite) TestSuccessResponse() {
-	resp := [][]byte{
-		common.BigToHash(big.NewInt(0.94 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.98 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.99 * 1e18)).Bytes(),
-	}
-
-	suite.client.On(
-		"BlockNumber",
-		mock.Anything,
-	).Return(big.NewInt(100), nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0x32296969Ef14EB0c6d29669C550D4a0449130230"),
-			Data:    ethereum.HexToBytes("0xb10be7390000000000000000000000000000000000000000000000000000000000000000"),
-		}},
-	).Return([][]byte{resp[0]}, nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0x32296969Ef14EB0c6d29669C550D4a0449130230"),
-			Data:    ethereum.HexToBytes("0xb10be7390000000000000000000000000000000000000000000000000000000000000000"),
-		}},
-	).Return([][]byte{resp[1]}, nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0x32296969Ef14EB0c6d29669C550D4a0449130230"),
-			Data:    ethereum.HexToBytes("0xb10be7390000000000000000000000000000000000000000000000000000000000000000"),
-		}},
-	).Return([][]byte{resp[2]}, nil).Once()
+	suite.client.respondBlocks(
+		"0x32296969Ef14EB0c6d29669C550D4a0449130230",
+		"0xb10be7390000000000000000000000000000000000000000000000000000000000000000",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
 
 	pair := Pair{Base: "STETH", Quote: "WETH"}
 
@@ -112,64 +74,23 @@ func (suite *BalancerV2Suite) TestSuccessResponse() {
 	suite.Require().NoError(results1[0].Error)
 	suite.Equal(0.97, results1[0].Price.Price)
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
+	suite.Equal([]int64{100, 90, 80}, suite.client.blocks)
 
 	results2 := suite.origin.Fetch([]Pair{pair.Inverse()})
 	suite.Require().Error(results2[0].Error)
 }
 
 func (suite *BalancerV2Suite) TestSuccessResponseWithRef() {
-	resp := [][]byte{
-		common.BigToHash(big.NewInt(0.94 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.98 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.99 * 1e18)).Bytes(),
-	}
-
-	resp2 := [][]byte{
-		common.BigToHash(big.NewInt(0.2 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.6 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.7 * 1e18)).Bytes(),
-	}
-
-	suite.client.On(
-		"BlockNumber",
-		mock.Anything,
-	).Return(big.NewInt(100), nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0x1E19CF2D73a72Ef1332C882F20534B6519Be0276"),
-			Data:    ethereum.HexToBytes("0xb10be7390000000000000000000000000000000000000000000000000000000000000000"),
-		}, {
-			Address: ethereum.HexToAddress("0x1E19CF2D73a72Ef1332C882F20534B6519Be0276"),
-			Data:    ethereum.HexToBytes("0xb867ee5a000000000000000000000000ae78736cd615f374d3085123a210448e74fc6393"),
-		}},
-	).Return([][]byte{resp[0], resp2[0]}, nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0x1E19CF2D73a72Ef1332C882F20534B6519Be0276"),
-			Data:    ethereum.HexToBytes("0xb10be7390000000000000000000000000000000000000000000000000000000000000000"),
-		}, {
-			Address: ethereum.HexToAddress("0x1E19CF2D73a72Ef1332C882F20534B6519Be0276"),
-			Data:    ethereum.HexToBytes("0xb867ee5a000000000000000000000000ae78736cd615f374d3085123a210448e74fc6393"),
-		}},
-	).Return([][]byte{resp[1], resp2[1]}, nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0x1E19CF2D73a72Ef1332C882F20534B6519Be0276"),
-			Data:    ethereum.HexToBytes("0xb10be7390000000000000000000000000000000000000000000000000000000000000000"),
-		}, {
-			Address: ethereum.HexToAddress("0x1E19CF2D73a72Ef1332C882F20534B6519Be0276"),
-			Data:    ethereum.HexToBytes("0xb867ee5a000000000000000000000000ae78736cd615f374d3085123a210448e74fc6393"),
-		}},
-	).Return([][]byte{resp[2], resp2[2]}, nil).Once()
+	suite.client.respondBlocks(
+		"0x1E19CF2D73a72Ef1332C882F20534B6519Be0276",
+		"0xb10be7390000000000000000000000000000000000000000000000000000000000000000",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
+	suite.client.respondBlocks(
+		"0x1E19CF2D73a72Ef1332C882F20534B6519Be0276",
+		"0xb867ee5a000000000000000000000000ae78736cd615f374d3085123a210448e74fc6393",
+		big.NewInt(0.2*1e18), big.NewInt(0.6*1e18), big.NewInt(0.7*1e18),
+	)
 
 	pair := Pair{Base: "RETH", Quote: "WETH"}
 
@@ -177,11 +98,28 @@ func (suite *BalancerV2Suite) TestSuccessResponseWithRef() {
 	suite.Require().NoError(results1[0].Error)
 	suite.Equal(0.485, results1[0].Price.Price)
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
+	suite.Equal([]int64{100, 90, 80}, suite.client.blocks)
+	suite.Equal(6, suite.client.calls)
 
 	results2 := suite.origin.Fetch([]Pair{pair.Inverse()})
 	suite.Require().Error(results2[0].Error)
 }
 
+func (suite *BalancerV2Suite) TestSuccessResponse_MultiplePairs() {
+	suite.client.respondBlocks(
+		"0x32296969Ef14EB0c6d29669C550D4a0449130230",
+		"0xb10be7390000000000000000000000000000000000000000000000000000000000000000",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
+
+	// The YFI pair is inverted, so it cannot be used, but it must not affect
+	// the STETH pair.
+	frs := suite.origin.Fetch([]Pair{{Base: "YFI", Quote: "WETH"}, {Base: "STETH", Quote: "WETH"}})
+	suite.Require().Error(frs[0].Error)
+	suite.Require().NoError(frs[1].Error)
+	suite.Equal(0.97, frs[1].Price.Price)
+}
+
 func (suite *BalancerV2Suite) TestFailOnWrongPair() {
 	pair := Pair{Base: "x", Quote: "y"}
 	cr := suite.origin.Fetch([]Pair{pair})
@@ -16,16 +16,11 @@
 package origins
 
 import (
-	"context"
 	_ "embed"
-	"fmt"
 	"math/big"
-	"sort"
 	"strings"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 
 	pkgEthereum "github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
@@ -35,20 +30,20 @@ import (
 var curvePoolABI string
 
 type CurveFinance struct {
-	ethClient                 pkgEthereum.Client
+	mc                        *Multicall
 	addrs                     ContractAddresses
 	abi                       abi.ABI
 	baseIndex, quoteIndex, dx *big.Int
 	blocks                    []int64
 }
 
-func NewCurveFinance(cli pkgEthereum.Client, addrs ContractAddresses, blocks []int64) (*CurveFinance, error) {
+func NewCurveFinance(mc *Multicall, addrs ContractAddresses, blocks []int64) (*CurveFinance, error) {
 	a, err := abi.JSON(strings.NewReader(curvePoolABI))
 	if err != nil {
 		return nil, err
 	}
 	return &CurveFinance{
-		ethClient:  cli,
+		mc:         mc,
 		addrs:      addrs,
 		abi:        a,
 		baseIndex:  big.NewInt(0),
@@ -58,74 +53,31 @@ func NewCurveFinance(cli pkgEthereum.Client, addrs ContractAddresses, blocks []i
 	}, nil
 }
 
-func (s CurveFinance) pairsToContractAddress(pair Pair) (common.Address, bool, error) {
-	contract, inverted, ok := s.addrs.ByPair(pair)
-	if !ok {
-		return common.Address{},
-			inverted,
-			fmt.Errorf("failed to get contract address for pair: %s", pair.String())
-	}
-	return common.HexToAddress(contract), inverted, nil
+func (s CurveFinance) PullPrices(pairs []Pair) []FetchResult {
+	return pullMulticallPrices(s.mc, pairs, s.blocks, s.calls, s.price)
 }
 
-func (s CurveFinance) PullPrices(pairs []Pair) []FetchResult {
-	sort.Slice(pairs, func(i, j int) bool {
-		return pairs[i].String() < pairs[j].String()
-	})
-	var (
-		frs []FetchResult
-		cds []pkgEthereum.Call
-	)
-	for _, pair := range pairs {
-		contract, inverted, err := s.pairsToContractAddress(pair)
-		if err != nil {
-			return fetchResultListWithErrors(pairs, err)
-		}
-		var callData []byte
-		if !inverted {
-			callData, err = s.abi.Pack("get_dy", s.baseIndex, s.quoteIndex, s.dx)
-		} else {
-			callData, err = s.abi.Pack("get_dy", s.quoteIndex, s.baseIndex, s.dx)
-		}
-		if err != nil {
-			return fetchResultListWithErrors(pairs, err)
-		}
-		cds = append(cds, pkgEthereum.Call{Address: contract, Data: callData})
-	}
-	blockNumber, err := s.ethClient.BlockNumber(context.Background())
+func (s CurveFinance) multicall() *Multicall {
+	return s.mc
+}
+
+func (s CurveFinance) calls(pair Pair) ([]pkgEthereum.Call, error) {
+	contract, inverted, err := s.addrs.AddressByPair(pair)
 	if err != nil {
-		return fetchResultListWithErrors(pairs, err)
+		return nil, err
 	}
-	resps := make([][][]byte, len(cds))
-	for _, blockDelta := range s.blocks {
-		ctx := pkgEthereum.WithBlockNumber(context.Background(), big.NewInt(blockNumber.Int64()-blockDelta))
-		multiCallResps, err := s.ethClient.MultiCall(ctx, cds)
-		if err != nil {
-			return fetchResultListWithErrors(pairs, err)
-		}
-		if len(multiCallResps) != len(resps) {
-			return fetchResultListWithErrors(
-				pairs,
-				fmt.Errorf(
-					"multi call response length mismatch, expected %d, got %d",
-					len(resps),
-					len(multiCallResps),
-				),
-			)
-		}
-		for i, r := range multiCallResps {
-			resps[i] = append(resps[i], r)
-		}
+	var callData []byte
+	if !inverted {
+		callData, err = s.abi.Pack("get_dy", s.baseIndex, s.quoteIndex, s.dx)
+	} else {
+		callData, err = s.abi.Pack("get_dy", s.quoteIndex, s.baseIndex, s.dx)
 	}
-	for i, pair := range pairs {
-		price, _ := reduceEtherAverageFloat(resps[i]).Float64()
-		frs = append(frs, FetchResult{
-			Price: Price{
-				Pair:      pair,
-				Price:     price,
-				Timestamp: time.Now(),
-			},
-		})
+	if err != nil {
+		return nil, err
 	}
-	return frs
+	return []pkgEthereum.Call{{Address: contract, Data: callData}}, nil
+}
+
+func (s CurveFinance) price(_ Pair, res [][]MulticallResult) (*big.Float, error) {
+	return averageMulticallResults(res[0])
 }
@@ -19,19 +19,13 @@ import (
 	"math/big"
 	"testing"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/stretchr/testify/mock"
-
-	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
-	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
-
 	"github.com/stretchr/testify/suite"
 )
 
 type CurveSuite struct {
 	suite.Suite
 	addresses ContractAddresses
-	client    *ethereumMocks.Client
+	client    *multicallClient
 	origin    *BaseExchangeHandler
 }
 
@@ -45,8 +39,8 @@ func (suite *CurveSuite) TearDownSuite() {
 }
 
 func (suite *CurveSuite) SetupTest() {
-	suite.client = &ethereumMocks.Client{}
-	o, err := NewCurveFinance(suite.client, suite.addresses, []int64{0, 10, 20})
+	suite.client = newMulticallClient(100)
+	o, err := NewCurveFinance(NewMulticall(suite.client, Multicall3Address), suite.addresses, []int64{0, 10, 20})
 	suite.NoError(err)
 	suite.origin = NewBaseExchangeHandler(o, nil)
 }
@@ -65,97 +59,35 @@ func TestCurveSuite(t *testing.T) {
 }
 
 func (suite *CurveSuite) TestSuccessResponse() {
-	resp := [][]byte{
-		common.BigToHash(big.NewInt(0.94 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.98 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.99 * 1e18)).Bytes(),
-	}
-
-	suite.client.On(
-		"BlockNumber",
-		mock.Anything,
-	).Return(big.NewInt(100), nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0xDC24316b9AE028F1497c275EB9192a3Ea0f67022"),
-			Data:    ethereum.HexToBytes("0x5e0d443f000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000de0b6b3a7640000"),
-		}},
-	).Return([][]byte{resp[0]}, nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0xDC24316b9AE028F1497c275EB9192a3Ea0f67022"),
-			Data:    ethereum.HexToBytes("0x5e0d443f000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000de0b6b3a7640000"),
-		}},
-	).Return([][]byte{resp[1]}, nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0xDC24316b9AE028F1497c275EB9192a3Ea0f67022"),
-			Data:    ethereum.HexToBytes("0x5e0d443f000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000de0b6b3a7640000"),
-		}},
-	).Return([][]byte{resp[2]}, nil).Once()
+	suite.client.respondBlocks(
+		"0xDC24316b9AE028F1497c275EB9192a3Ea0f67022",
+		"0x5e0d443f000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000de0b6b3a7640000",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
 
 	pair := Pair{Base: "STETH", Quote: "ETH"}
+
 	results1 := suite.origin.Fetch([]Pair{pair})
 	suite.Require().NoError(results1[0].Error)
 	suite.Equal(0.97, results1[0].Price.Price)
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
+	suite.Equal([]int64{100, 90, 80}, suite.client.blocks)
 }
 
 func (suite *CurveSuite) TestSuccessResponse_Inverse() {
-	resp := [][]byte{
-		common.BigToHash(big.NewInt(0.94 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.98 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.99 * 1e18)).Bytes(),
-	}
-
-	suite.client.On(
-		"BlockNumber",
-		mock.Anything,
-	).Return(big.NewInt(100), nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0xDC24316b9AE028F1497c275EB9192a3Ea0f67022"),
-			Data:    ethereum.HexToBytes("0x5e0d443f000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000de0b6b3a7640000"),
-		}},
-	).Return([][]byte{resp[0]}, nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0xDC24316b9AE028F1497c275EB9192a3Ea0f67022"),
-			Data:    ethereum.HexToBytes("0x5e0d443f000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000de0b6b3a7640000"),
-		}},
-	).Return([][]byte{resp[1]}, nil).Once()
-
-	suite.client.On(
-		"MultiCall",
-		mock.Anything,
-		[]ethereum.Call{{
-			Address: ethereum.HexToAddress("0xDC24316b9AE028F1497c275EB9192a3Ea0f67022"),
-			Data:    ethereum.HexToBytes("0x5e0d443f000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000de0b6b3a7640000"),
-		}},
-	).Return([][]byte{resp[2]}, nil).Once()
-
-	pair := Pair{Base: "STETH", Quote: "ETH"}
+	suite.client.respondBlocks(
+		"0xDC24316b9AE028F1497c275EB9192a3Ea0f67022",
+		"0x5e0d443f000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000de0b6b3a7640000",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
 
-	results2 := suite.origin.Fetch([]Pair{pair.Inverse()})
+	pair := Pair{Base: "ETH", Quote: "STETH"}
 
-	suite.Require().NoError(results2[0].Error)
-	suite.Equal(0.97, results2[0].Price.Price)
-	suite.Greater(results2[0].Price.Timestamp.Unix(), int64(0))
+	results1 := suite.origin.Fetch([]Pair{pair})
+	suite.Require().NoError(results1[0].Error)
+	suite.Equal(0.97, results1[0].Price.Price)
+	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
+	suite.Equal([]int64{100, 90, 80}, suite.client.blocks)
 }
 
 func (suite *CurveSuite) TestFailOnWrongPair() {
@@ -163,3 +95,13 @@ func (suite *CurveSuite) TestFailOnWrongPair() {
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.Require().EqualError(cr[0].Error, "failed to get contract address for pair: x/y")
 }
+
+func (suite *CurveSuite) TestFailOnFailedCall() {
+	// The response for the oldest block is missing.
+	suite.client.respond(100, "0xDC24316b9AE028F1497c275EB9192a3Ea0f67022", "0x5e0d443f000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000de0b6b3a7640000", make([]byte, 32))
+	suite.client.respond(90, "0xDC24316b9AE028F1497c275EB9192a3Ea0f67022", "0x5e0d443f000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000de0b6b3a7640000", make([]byte, 32))
+
+	pair := Pair{Base: "STETH", Quote: "ETH"}
+	cr := suite.origin.Fetch([]Pair{pair})
+	suite.Require().ErrorIs(cr[0].Error, ErrMulticallCallFailed)
+}
@@ -0,0 +1,362 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+//go:embed multicall3_abi.json
+var multicall3ABI string
+
+// Multicall3Address is the address of the Multicall3 contract. The contract
+// is deployed at the same address on most EVM compatible chains.
+//
+// https://github.com/mds1/multicall
+var Multicall3Address = ethereum.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+var ErrMulticallCallFailed = errors.New("multicall sub-call failed")
+
+// MulticallResult is the result of a single call executed by Multicall.
+type MulticallResult struct {
+	Data []byte
+	Err  error
+}
+
+// Multicall executes contract calls of on-chain origins using the Multicall3
+// aggregate3 method.
+//
+// Calls of all origins fetched within the same Set.Fetch invocation, which
+// is one feeder cycle, are collected and executed together. As a result,
+// a single eth_call is made for every block from which prices are averaged,
+// regardless of the number of origins and pairs. Every call is allowed to
+// fail on its own, a failed call does not affect other calls.
+//
+// If the aggregate3 call fails as a whole, for example because the
+// Multicall3 contract is not deployed on the chain, calls are executed
+// directly, using a separate eth_call for each of them.
+type Multicall struct {
+	mu      sync.Mutex
+	cli     ethereum.Client
+	address ethereum.Address
+	abi     abi.ABI
+
+	// active is the number of origins that are expected to add their calls
+	// before the pending calls are executed.
+	active  int
+	pending []*multicallRequest
+}
+
+type multicallRequest struct {
+	calls  []ethereum.Call
+	blocks []int64
+	res    [][]MulticallResult
+	err    error
+	done   chan struct{}
+}
+
+// multicallHandler is implemented by origins that use Multicall.
+type multicallHandler interface {
+	multicall() *Multicall
+}
+
+// NewMulticall returns a new Multicall instance that uses the Multicall3
+// contract deployed at the given address.
+func NewMulticall(cli ethereum.Client, address ethereum.Address) *Multicall {
+	a, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		panic(err)
+	}
+	return &Multicall{
+		cli:     cli,
+		address: address,
+		abi:     a,
+	}
+}
+
+// Call executes given calls on every block from the blocks list. Blocks are
+// distances from the latest block. The call is blocked until all origins
+// participating in the current cycle add their calls.
+//
+// The returned slice contains results for every call and every block, in
+// the same order as in the calls and blocks slices. An error is returned
+// only if calls could not be executed at all.
+func (m *Multicall) Call(calls []ethereum.Call, blocks []int64) ([][]MulticallResult, error) {
+	req := &multicallRequest{
+		calls:  calls,
+		blocks: blocks,
+		done:   make(chan struct{}),
+	}
+	m.mu.Lock()
+	m.pending = append(m.pending, req)
+	batch := m.takeBatch()
+	m.mu.Unlock()
+	if batch != nil {
+		m.execute(batch)
+	}
+	<-req.done
+	return req.res, req.err
+}
+
+// begin announces that n origins will add their calls.
+func (m *Multicall) begin(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active += n
+}
+
+// leave is called by an origin that will not add any more calls.
+func (m *Multicall) leave() {
+	m.mu.Lock()
+	m.active--
+	batch := m.takeBatch()
+	m.mu.Unlock()
+	if batch != nil {
+		m.execute(batch)
+	}
+}
+
+// takeBatch returns pending requests if all active origins added their
+// calls. Otherwise, it returns nil. It must be called with the mutex locked.
+func (m *Multicall) takeBatch() []*multicallRequest {
+	if len(m.pending) == 0 || len(m.pending) < m.active {
+		return nil
+	}
+	batch := m.pending
+	m.pending = nil
+	return batch
+}
+
+// execute executes requests from the batch, using one aggregate3 call for
+// each block.
+func (m *Multicall) execute(batch []*multicallRequest) {
+	defer func() {
+		for _, req := range batch {
+			close(req.done)
+		}
+	}()
+
+	// callRef refers to the result of the call of the request on a block.
+	type callRef struct {
+		req   *multicallRequest
+		call  int
+		block int
+	}
+
+	// Group calls by blocks. Identical calls on the same block are executed
+	// only once.
+	var deltas []int64
+	refs := map[int64][][]callRef{}
+	index := map[int64]map[string]int{}
+	for _, req := range batch {
+		req.res = make([][]MulticallResult, len(req.calls))
+		for i, call := range req.calls {
+			req.res[i] = make([]MulticallResult, len(req.blocks))
+			for j, delta := range req.blocks {
+				if _, ok := index[delta]; !ok {
+					deltas = append(deltas, delta)
+					index[delta] = map[string]int{}
+				}
+				key := string(call.Address.Bytes()) + string(call.Data)
+				n, ok := index[delta][key]
+				if !ok {
+					n = len(refs[delta])
+					index[delta][key] = n
+					refs[delta] = append(refs[delta], nil)
+				}
+				refs[delta][n] = append(refs[delta][n], callRef{req: req, call: i, block: j})
+			}
+		}
+	}
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer ctxCancel()
+	block, err := m.cli.BlockNumber(ctx)
+	if err != nil {
+		for _, req := range batch {
+			req.err = fmt.Errorf("failed to get block number: %w", err)
+		}
+		return
+	}
+	for _, delta := range deltas {
+		calls := make([]ethereum.Call, len(refs[delta]))
+		for n, rs := range refs[delta] {
+			calls[n] = rs[0].req.calls[rs[0].call]
+		}
+		blockCtx := ethereum.WithBlockNumber(ctx, new(big.Int).Sub(block, big.NewInt(delta)))
+		res, err := m.aggregate(blockCtx, calls)
+		if err != nil {
+			res = m.callDirect(blockCtx, calls)
+		}
+		for n, rs := range refs[delta] {
+			for _, r := range rs {
+				r.req.res[r.call][r.block] = res[n]
+			}
+		}
+	}
+}
+
+// aggregate executes calls using a single aggregate3 call.
+func (m *Multicall) aggregate(ctx context.Context, calls []ethereum.Call) ([]MulticallResult, error) {
+	type call3 struct {
+		Target       ethereum.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+	type result struct {
+		Success    bool
+		ReturnData []byte
+	}
+	args := make([]call3, len(calls))
+	for i, c := range calls {
+		args[i] = call3{Target: c.Address, AllowFailure: true, CallData: c.Data}
+	}
+	data, err := m.abi.Pack("aggregate3", args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.cli.Call(ctx, ethereum.Call{Address: m.address, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	var rs []result
+	if err := m.abi.UnpackIntoInterface(&rs, "aggregate3", resp); err != nil {
+		return nil, err
+	}
+	if len(rs) != len(calls) {
+		return nil, fmt.Errorf("multicall response length mismatch, expected %d, got %d", len(calls), len(rs))
+	}
+	res := make([]MulticallResult, len(rs))
+	for i, r := range rs {
+		if !r.Success {
+			res[i] = MulticallResult{Err: fmt.Errorf("%w: call to %s failed", ErrMulticallCallFailed, calls[i].Address)}
+			continue
+		}
+		res[i] = MulticallResult{Data: r.ReturnData}
+	}
+	return res, nil
+}
+
+// multicallOf returns the Multicall used by the handler, or nil if the
+// handler does not use Multicall.
+func multicallOf(h Handler) *Multicall {
+	if m, ok := h.(multicallHandler); ok {
+		return m.multicall()
+	}
+	return nil
+}
+
+// pullMulticallPrices fetches prices for pairs using Multicall. The calls
+// function returns the list of calls required to calculate the price of the
+// pair. The price function calculates the price from the results of these
+// calls, results are indexed by call and then by block. An error returned by
+// any of these functions is returned only for the affected pair.
+func pullMulticallPrices(
+	mc *Multicall,
+	pairs []Pair,
+	blocks []int64,
+	calls func(pair Pair) ([]ethereum.Call, error),
+	price func(pair Pair, res [][]MulticallResult) (*big.Float, error),
+) []FetchResult {
+
+	frs := make([]FetchResult, len(pairs))
+	offsets := make([]int, len(pairs)+1)
+	var cs []ethereum.Call
+	for i, pair := range pairs {
+		offsets[i] = len(cs)
+		pcs, err := calls(pair)
+		if err != nil {
+			frs[i] = fetchResultWithError(pair, err)
+		} else {
+			cs = append(cs, pcs...)
+		}
+	}
+	offsets[len(pairs)] = len(cs)
+	if len(cs) == 0 {
+		return frs
+	}
+	res, err := mc.Call(cs, blocks)
+	for i, pair := range pairs {
+		if frs[i].Error != nil {
+			continue
+		}
+		if err != nil {
+			frs[i] = fetchResultWithError(pair, err)
+			continue
+		}
+		p, err := price(pair, res[offsets[i]:offsets[i+1]])
+		if err != nil {
+			frs[i] = fetchResultWithError(pair, err)
+			continue
+		}
+		f, _ := p.Float64()
+		frs[i] = fetchResult(Price{
+			Pair:      pair,
+			Price:     f,
+			Timestamp: time.Now(),
+		})
+	}
+	return frs
+}
+
+// averageMulticallResults returns the average of the results as Ether
+// values. It fails if any of the results contains an error.
+func averageMulticallResults(res []MulticallResult) (*big.Float, error) {
+	bs := make([][]byte, len(res))
+	for i, r := range res {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		if len(r.Data) < 32 {
+			return nil, fmt.Errorf("%w: unexpected response length %d", ErrInvalidPrice, len(r.Data))
+		}
+		bs[i] = r.Data
+	}
+	return reduceEtherAverageFloat(bs), nil
+}
+
+// callDirect executes calls separately, without using the Multicall3
+// contract.
+func (m *Multicall) callDirect(ctx context.Context, calls []ethereum.Call) []MulticallResult {
+	res := make([]MulticallResult, len(calls))
+	wg := sync.WaitGroup{}
+	for i, c := range calls {
+		i, c := i, c
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := m.cli.Call(ctx, c)
+			if err != nil {
+				res[i] = MulticallResult{Err: fmt.Errorf("%w: call to %s failed: %v", ErrMulticallCallFailed, c.Address, err)}
+				return
+			}
+			res[i] = MulticallResult{Data: data}
+		}()
+	}
+	wg.Wait()
+	return res
+}
@@ -0,0 +1,240 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package origins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+)
+
+// multicallClient is an ethereum.Client that executes aggregate3 calls using
+// predefined responses.
+type multicallClient struct {
+	// Client is embedded to satisfy the methods that are not used by the
+	// Multicall. Calling them panics.
+	ethereum.Client
+
+	mu        sync.Mutex
+	abi       abi.ABI
+	block     int64
+	responses map[string][]byte
+	blocks    []int64 // Blocks on which aggregate3 was called.
+	calls     int     // Total number of sub-calls.
+	direct    int     // Number of calls executed without aggregate3.
+	blockErr  error
+	mcErr     error // Error returned by aggregate3 calls.
+}
+
+func newMulticallClient(block int64) *multicallClient {
+	return &multicallClient{
+		abi:       NewMulticall(nil, Multicall3Address).abi,
+		block:     block,
+		responses: map[string][]byte{},
+	}
+}
+
+// respond sets the response for the call on the given block. Calls without
+// a response fail.
+func (c *multicallClient) respond(block int64, address, data string, resp []byte) {
+	c.responses[multicallClientKey(block, ethereum.HexToAddress(address), ethereum.HexToBytes(data))] = resp
+}
+
+// respondBlocks sets responses for the call on the last blocks, starting
+// from the latest one.
+func (c *multicallClient) respondBlocks(address, data string, resps ...*big.Int) {
+	for i, r := range resps {
+		c.respond(c.block-int64(i)*10, address, data, common.BigToHash(r).Bytes())
+	}
+}
+
+func (c *multicallClient) BlockNumber(_ context.Context) (*big.Int, error) {
+	if c.blockErr != nil {
+		return nil, c.blockErr
+	}
+	return big.NewInt(c.block), nil
+}
+
+func (c *multicallClient) Call(ctx context.Context, call ethereum.Call) ([]byte, error) {
+	type call3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+	type result struct {
+		Success    bool
+		ReturnData []byte
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block := ethereum.BlockNumberFromContext(ctx).Int64()
+	method := c.abi.Methods["aggregate3"]
+	if call.Address != Multicall3Address {
+		c.direct++
+		resp, ok := c.responses[multicallClientKey(block, call.Address, call.Data)]
+		if !ok {
+			return nil, errors.New("execution reverted")
+		}
+		return resp, nil
+	}
+	if c.mcErr != nil {
+		return nil, c.mcErr
+	}
+	if string(call.Data[:4]) != string(method.ID) {
+		return nil, errors.New("unexpected call")
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	var calls []call3
+	if err := method.Inputs.Copy(&calls, args); err != nil {
+		return nil, err
+	}
+	c.blocks = append(c.blocks, block)
+	c.calls += len(calls)
+	results := make([]result, len(calls))
+	for i, cl := range calls {
+		resp, ok := c.responses[multicallClientKey(block, cl.Target, cl.CallData)]
+		results[i] = result{Success: ok, ReturnData: resp}
+	}
+	return method.Outputs.Pack(results)
+}
+
+func multicallClientKey(block int64, address ethereum.Address, data []byte) string {
+	return fmt.Sprintf("%d:%s:%x", block, address.String(), data)
+}
+
+func TestMulticall_Call(t *testing.T) {
+	cli := newMulticallClient(100)
+	mc := NewMulticall(cli, Multicall3Address)
+	cli.respond(100, "0x1", "0xaa", []byte{1})
+	cli.respond(90, "0x1", "0xaa", []byte{2})
+	cli.respond(100, "0x2", "0xbb", []byte{3})
+
+	res, err := mc.Call([]ethereum.Call{
+		{Address: ethereum.HexToAddress("0x1"), Data: ethereum.HexToBytes("0xaa")},
+		{Address: ethereum.HexToAddress("0x2"), Data: ethereum.HexToBytes("0xbb")},
+		{Address: ethereum.HexToAddress("0x1"), Data: ethereum.HexToBytes("0xaa")},
+	}, []int64{0, 10})
+	require.NoError(t, err)
+
+	// One aggregate3 call per block, identical calls are executed once:
+	assert.Equal(t, []int64{100, 90}, cli.blocks)
+	assert.Equal(t, 4, cli.calls)
+
+	assert.Equal(t, []byte{1}, res[0][0].Data)
+	assert.Equal(t, []byte{2}, res[0][1].Data)
+	assert.Equal(t, []byte{3}, res[1][0].Data)
+	assert.ErrorIs(t, res[1][1].Err, ErrMulticallCallFailed)
+	assert.Equal(t, res[0], res[2])
+}
+
+func TestMulticall_DirectFallback(t *testing.T) {
+	cli := newMulticallClient(100)
+	cli.mcErr = errors.New("execution reverted")
+	mc := NewMulticall(cli, Multicall3Address)
+	cli.respond(100, "0x1", "0xaa", []byte{1})
+
+	res, err := mc.Call([]ethereum.Call{
+		{Address: ethereum.HexToAddress("0x1"), Data: ethereum.HexToBytes("0xaa")},
+		{Address: ethereum.HexToAddress("0x2"), Data: ethereum.HexToBytes("0xbb")},
+	}, []int64{0})
+	require.NoError(t, err)
+
+	// If aggregate3 fails, calls are executed directly:
+	assert.Equal(t, 2, cli.direct)
+	assert.Equal(t, []byte{1}, res[0][0].Data)
+	assert.ErrorIs(t, res[1][0].Err, ErrMulticallCallFailed)
+}
+
+func TestMulticall_BlockNumberError(t *testing.T) {
+	cli := newMulticallClient(100)
+	cli.blockErr = errors.New("block error")
+	mc := NewMulticall(cli, Multicall3Address)
+
+	_, err := mc.Call([]ethereum.Call{{Address: ethereum.HexToAddress("0x1")}}, []int64{0})
+	assert.Error(t, err)
+}
+
+func TestSet_Fetch_Multicall(t *testing.T) {
+	cli := newMulticallClient(100)
+	mc := NewMulticall(cli, Multicall3Address)
+	curve, err := NewCurveFinance(mc, ContractAddresses{
+		"ETH/STETH": "0xDC24316b9AE028F1497c275EB9192a3Ea0f67022",
+	}, []int64{0, 10, 20})
+	require.NoError(t, err)
+	wsteth, err := NewWrappedStakedETH(mc, ContractAddresses{
+		"WSTETH/STETH": "0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0",
+	}, []int64{0, 10, 20})
+	require.NoError(t, err)
+	rocketpool, err := NewRocketPool(mc, ContractAddresses{
+		"RETH/ETH": "0xae78736Cd615f374D3085123A210448E74Fc6393",
+	}, []int64{0, 10, 20})
+	require.NoError(t, err)
+	set := NewSet(map[string]Handler{
+		"curve":      NewBaseExchangeHandler(*curve, nil),
+		"wsteth":     NewBaseExchangeHandler(*wsteth, nil),
+		"rocketpool": NewBaseExchangeHandler(*rocketpool, nil),
+	})
+
+	cli.respondBlocks(
+		"0xDC24316b9AE028F1497c275EB9192a3Ea0f67022",
+		"0x5e0d443f000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000de0b6b3a7640000",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
+	cli.respondBlocks(
+		"0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0",
+		"0x035faf82",
+		big.NewInt(1.1*1e18), big.NewInt(1.1*1e18), big.NewInt(1.1*1e18),
+	)
+	// The response for the RETH/ETH pair on the oldest block is missing, so
+	// only this pair should fail.
+	cli.respond(100, "0xae78736Cd615f374D3085123A210448E74Fc6393", "0xe6aa216c", common.BigToHash(big.NewInt(1e18)).Bytes())
+	cli.respond(90, "0xae78736Cd615f374D3085123A210448E74Fc6393", "0xe6aa216c", common.BigToHash(big.NewInt(1e18)).Bytes())
+
+	frs := set.Fetch(map[string][]Pair{
+		"curve":      {{Base: "STETH", Quote: "ETH"}},
+		"wsteth":     {{Base: "WSTETH", Quote: "STETH"}, {Base: "FOO", Quote: "BAR"}},
+		"rocketpool": {{Base: "RETH", Quote: "ETH"}},
+	})
+
+	// All calls must be executed using one aggregate3 call per block:
+	assert.ElementsMatch(t, []int64{100, 90, 80}, cli.blocks)
+	assert.Equal(t, 9, cli.calls)
+
+	require.Len(t, frs["curve"], 1)
+	require.NoError(t, frs["curve"][0].Error)
+	assert.Equal(t, 0.97, frs["curve"][0].Price.Price)
+
+	require.Len(t, frs["wsteth"], 2)
+	require.NoError(t, frs["wsteth"][0].Error)
+	assert.InDelta(t, 1.1, frs["wsteth"][0].Price.Price, 1e-9)
+	assert.EqualError(t, frs["wsteth"][1].Error, "failed to get contract address for pair: FOO/BAR")
+
+	require.Len(t, frs["rocketpool"], 1)
+	assert.ErrorIs(t, frs["rocketpool"][0].Error, ErrMulticallCallFailed)
+}
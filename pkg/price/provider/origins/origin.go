@@ -51,6 +51,14 @@ func NewBaseExchangeHandler(handler ExchangeHandler, aliases SymbolAliases) *Bas
 	}
 }
 
+// multicall implements the multicallHandler interface.
+func (h BaseExchangeHandler) multicall() *Multicall {
+	if m, ok := h.ExchangeHandler.(multicallHandler); ok {
+		return m.multicall()
+	}
+	return nil
+}
+
 func (h BaseExchangeHandler) Fetch(pairs []Pair) []FetchResult {
 	if h.aliases == nil {
 		return h.PullPrices(pairs)
@@ -219,18 +227,32 @@ func (e *Set) Handlers() map[string]Handler {
 }
 
 // Fetch makes handler fetch using handlers from the Set structure.
+//
+// On-chain origins that share a Multicall instance are announced to it
+// before fetching, so their calls are collected and executed together.
 func (e *Set) Fetch(originPairs map[string][]Pair) map[string][]FetchResult {
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
 	wg.Add(len(originPairs))
 
+	participants := map[*Multicall]int{}
+	for origin := range originPairs {
+		if mc := multicallOf(e.list[origin]); mc != nil {
+			participants[mc]++
+		}
+	}
+	for mc, n := range participants {
+		mc.begin(n)
+	}
+
 	frs := map[string][]FetchResult{}
 	for origin, pairs := range originPairs {
 		origin, pairs := origin, pairs
 		handler, ok := e.list[origin]
 
 		go func() {
+			defer wg.Done()
 			if !ok {
 				mu.Lock()
 				frs[origin] = fetchResultListWithErrors(
@@ -238,14 +260,15 @@ func (e *Set) Fetch(originPairs map[string][]Pair) map[string][]FetchResult {
 					fmt.Errorf("%w (%s)", ErrUnknownOrigin, origin),
 				)
 				mu.Unlock()
-			} else {
-				resp := handler.Fetch(pairs)
-				mu.Lock()
-				frs[origin] = append(frs[origin], resp...)
-				mu.Unlock()
+				return
 			}
-
-			wg.Done()
+			if mc := multicallOf(handler); mc != nil {
+				defer mc.leave()
+			}
+			resp := handler.Fetch(pairs)
+			mu.Lock()
+			frs[origin] = append(frs[origin], resp...)
+			mu.Unlock()
 		}()
 	}
 
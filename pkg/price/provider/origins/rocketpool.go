@@ -16,12 +16,10 @@
 package origins
 
 import (
-	"context"
 	_ "embed"
 	"fmt"
 	"math/big"
 	"strings"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 
@@ -29,7 +27,7 @@ import (
 )
 
 type RocketPool struct {
-	ethClient  ethereum.Client
+	mc         *Multicall
 	addrs      ContractAddresses
 	abi        abi.ABI
 	circuitABI abi.ABI
@@ -39,29 +37,32 @@ type RocketPool struct {
 //go:embed rocketpool_abi.json
 var rocketPoolABI string
 
-func NewRocketPool(cli ethereum.Client, addrs ContractAddresses, blocks []int64) (*RocketPool, error) {
+func NewRocketPool(mc *Multicall, addrs ContractAddresses, blocks []int64) (*RocketPool, error) {
 	a, err := abi.JSON(strings.NewReader(rocketPoolABI))
 	if err != nil {
 		return nil, err
 	}
 	return &RocketPool{
-		ethClient: cli,
-		addrs:     addrs,
-		abi:       a,
-		blocks:    blocks,
+		mc:     mc,
+		addrs:  addrs,
+		abi:    a,
+		blocks: blocks,
 	}, nil
 }
 
 func (s RocketPool) PullPrices(pairs []Pair) []FetchResult {
-	return callSinglePairOrigin(&s, pairs)
+	return pullMulticallPrices(s.mc, pairs, s.blocks, s.calls, s.price)
 }
 
-func (s RocketPool) callOne(pair Pair) (*Price, error) {
+func (s RocketPool) multicall() *Multicall {
+	return s.mc
+}
+
+func (s RocketPool) calls(pair Pair) ([]ethereum.Call, error) {
 	contract, inverted, err := s.addrs.AddressByPair(pair)
 	if err != nil {
 		return nil, err
 	}
-
 	var callData []byte
 	if !inverted {
 		callData, err = s.abi.Pack("getExchangeRate")
@@ -71,16 +72,9 @@ func (s RocketPool) callOne(pair Pair) (*Price, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get contract args for pair: %s: %w", pair.String(), err)
 	}
+	return []ethereum.Call{{Address: contract, Data: callData}}, nil
+}
 
-	resp, err := s.ethClient.CallBlocks(context.Background(), ethereum.Call{Address: contract, Data: callData}, s.blocks)
-	if err != nil {
-		return nil, err
-	}
-	price, _ := reduceEtherAverageFloat(resp).Float64()
-
-	return &Price{
-		Pair:      pair,
-		Price:     price,
-		Timestamp: time.Now(),
-	}, nil
+func (s RocketPool) price(_ Pair, res [][]MulticallResult) (*big.Float, error) {
+	return averageMulticallResults(res[0])
 }
@@ -19,19 +19,13 @@ import (
 	"math/big"
 	"testing"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/stretchr/testify/mock"
-
-	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
-	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
-
 	"github.com/stretchr/testify/suite"
 )
 
 type RocketPoolSuite struct {
 	suite.Suite
 	addresses ContractAddresses
-	client    *ethereumMocks.Client
+	client    *multicallClient
 	origin    *BaseExchangeHandler
 }
 
@@ -46,8 +40,8 @@ func (suite *RocketPoolSuite) TearDownSuite() {
 }
 
 func (suite *RocketPoolSuite) SetupTest() {
-	suite.client = &ethereumMocks.Client{}
-	o, err := NewRocketPool(suite.client, suite.addresses, []int64{0, 10, 20})
+	suite.client = newMulticallClient(100)
+	o, err := NewRocketPool(NewMulticall(suite.client, Multicall3Address), suite.addresses, []int64{0, 10, 20})
 	suite.NoError(err)
 	suite.origin = NewBaseExchangeHandler(o, nil)
 }
@@ -66,20 +60,11 @@ func TestRocketPoolSuite(t *testing.T) {
 }
 
 func (suite *RocketPoolSuite) TestSuccessResponse() {
-	resp := [][]byte{
-		common.BigToHash(big.NewInt(0.94 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.98 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.99 * 1e18)).Bytes(),
-	}
-	suite.client.On(
-		"CallBlocks",
-		mock.Anything,
-		ethereum.Call{
-			Address: ethereum.HexToAddress("0xae78736Cd615f374D3085123A210448E74Fc6393"),
-			Data:    ethereum.HexToBytes("0xe6aa216c"),
-		},
-		[]int64{0, 10, 20},
-	).Return(resp, nil).Once()
+	suite.client.respondBlocks(
+		"0xae78736Cd615f374D3085123A210448E74Fc6393",
+		"0xe6aa216c",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
 
 	pair := Pair{Base: "RETH", Quote: "ETH"}
 
@@ -87,25 +72,15 @@ func (suite *RocketPoolSuite) TestSuccessResponse() {
 	suite.Require().NoError(results1[0].Error)
 	suite.Equal(0.97, results1[0].Price.Price)
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
-
-	suite.client.AssertNumberOfCalls(suite.T(), "CallBlocks", 1)
+	suite.Equal([]int64{100, 90, 80}, suite.client.blocks)
 }
 
 func (suite *RocketPoolSuite) TestSuccessResponse_Inverted() {
-	resp := [][]byte{
-		common.BigToHash(big.NewInt(0.94 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.98 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.99 * 1e18)).Bytes(),
-	}
-	suite.client.On(
-		"CallBlocks",
-		mock.Anything,
-		ethereum.Call{
-			Address: ethereum.HexToAddress("0xae78736Cd615f374D3085123A210448E74Fc6393"),
-			Data:    ethereum.HexToBytes("0x4346f03e0000000000000000000000000000000000000000000000000de0b6b3a7640000"),
-		},
-		[]int64{0, 10, 20},
-	).Return(resp, nil).Once()
+	suite.client.respondBlocks(
+		"0xae78736Cd615f374D3085123A210448E74Fc6393",
+		"0x4346f03e0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
 
 	pair := Pair{Base: "ETH", Quote: "RETH"}
 
@@ -113,8 +88,7 @@ func (suite *RocketPoolSuite) TestSuccessResponse_Inverted() {
 	suite.Require().NoError(results1[0].Error)
 	suite.Equal(0.97, results1[0].Price.Price)
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
-
-	suite.client.AssertNumberOfCalls(suite.T(), "CallBlocks", 1)
+	suite.Equal([]int64{100, 90, 80}, suite.client.blocks)
 }
 
 func (suite *RocketPoolSuite) TestFailOnWrongPair() {
@@ -16,11 +16,10 @@
 package origins
 
 import (
-	"context"
 	_ "embed"
 	"fmt"
+	"math/big"
 	"strings"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 
@@ -33,35 +32,38 @@ var wrappedStakedETHABI string
 const ether uint64 = 1e18
 
 type WrappedStakedETH struct {
-	ethClient ethereum.Client
-	addrs     ContractAddresses
-	abi       abi.ABI
-	blocks    []int64
+	mc     *Multicall
+	addrs  ContractAddresses
+	abi    abi.ABI
+	blocks []int64
 }
 
-func NewWrappedStakedETH(cli ethereum.Client, addrs ContractAddresses, blocks []int64) (*WrappedStakedETH, error) {
+func NewWrappedStakedETH(mc *Multicall, addrs ContractAddresses, blocks []int64) (*WrappedStakedETH, error) {
 	a, err := abi.JSON(strings.NewReader(wrappedStakedETHABI))
 	if err != nil {
 		return nil, err
 	}
 	return &WrappedStakedETH{
-		ethClient: cli,
-		addrs:     addrs,
-		abi:       a,
-		blocks:    blocks,
+		mc:     mc,
+		addrs:  addrs,
+		abi:    a,
+		blocks: blocks,
 	}, nil
 }
 
 func (s WrappedStakedETH) PullPrices(pairs []Pair) []FetchResult {
-	return callSinglePairOrigin(&s, pairs)
+	return pullMulticallPrices(s.mc, pairs, s.blocks, s.calls, s.price)
 }
 
-func (s WrappedStakedETH) callOne(pair Pair) (*Price, error) {
+func (s WrappedStakedETH) multicall() *Multicall {
+	return s.mc
+}
+
+func (s WrappedStakedETH) calls(pair Pair) ([]ethereum.Call, error) {
 	contract, inverted, err := s.addrs.AddressByPair(pair)
 	if err != nil {
 		return nil, err
 	}
-
 	var callData []byte
 	if !inverted {
 		callData, err = s.abi.Pack("stEthPerToken")
@@ -71,16 +73,9 @@ func (s WrappedStakedETH) callOne(pair Pair) (*Price, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get contract args for pair: %s", pair.String())
 	}
+	return []ethereum.Call{{Address: contract, Data: callData}}, nil
+}
 
-	resp, err := s.ethClient.CallBlocks(context.Background(), ethereum.Call{Address: contract, Data: callData}, s.blocks)
-	if err != nil {
-		return nil, err
-	}
-
-	price, _ := reduceEtherAverageFloat(resp).Float64()
-	return &Price{
-		Pair:      pair,
-		Price:     price,
-		Timestamp: time.Now(),
-	}, nil
+func (s WrappedStakedETH) price(_ Pair, res [][]MulticallResult) (*big.Float, error) {
+	return averageMulticallResults(res[0])
 }
@@ -19,19 +19,13 @@ import (
 	"math/big"
 	"testing"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/stretchr/testify/mock"
-
-	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
-	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
-
 	"github.com/stretchr/testify/suite"
 )
 
 type WrappedStakedETHSuite struct {
 	suite.Suite
 	addresses ContractAddresses
-	client    *ethereumMocks.Client
+	client    *multicallClient
 	origin    *BaseExchangeHandler
 }
 
@@ -45,15 +39,15 @@ func (suite *WrappedStakedETHSuite) TearDownSuite() {
 }
 
 func (suite *WrappedStakedETHSuite) SetupTest() {
-	suite.client = &ethereumMocks.Client{}
-	o, err := NewWrappedStakedETH(suite.client, suite.addresses, []int64{0, 10, 20})
+	suite.client = newMulticallClient(100)
+	o, err := NewWrappedStakedETH(NewMulticall(suite.client, Multicall3Address), suite.addresses, []int64{0, 10, 20})
 	suite.NoError(err)
 	suite.origin = NewBaseExchangeHandler(o, nil)
 }
 
 func (suite *WrappedStakedETHSuite) TearDownTest() {
-	suite.client = nil
 	suite.origin = nil
+	suite.client = nil
 }
 
 func (suite *WrappedStakedETHSuite) Origin() Handler {
@@ -65,20 +59,11 @@ func TestWrappedStakedETHSuite(t *testing.T) {
 }
 
 func (suite *WrappedStakedETHSuite) TestSuccessResponse() {
-	resp := [][]byte{
-		common.BigToHash(big.NewInt(0.94 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.98 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.99 * 1e18)).Bytes(),
-	}
-	suite.client.On(
-		"CallBlocks",
-		mock.Anything,
-		ethereum.Call{
-			Address: ethereum.HexToAddress("0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0"),
-			Data:    ethereum.HexToBytes("0x035faf82"),
-		},
-		[]int64{0, 10, 20},
-	).Return(resp, nil).Once()
+	suite.client.respondBlocks(
+		"0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0",
+		"0x035faf82",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
 
 	pair := Pair{Base: "WSTETH", Quote: "STETH"}
 
@@ -86,35 +71,23 @@ func (suite *WrappedStakedETHSuite) TestSuccessResponse() {
 	suite.Require().NoError(results1[0].Error)
 	suite.Equal(0.97, results1[0].Price.Price)
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
-
-	suite.client.AssertNumberOfCalls(suite.T(), "CallBlocks", 1)
+	suite.Equal([]int64{100, 90, 80}, suite.client.blocks)
 }
 
 func (suite *WrappedStakedETHSuite) TestSuccessResponse_Inverted() {
-	resp := [][]byte{
-		common.BigToHash(big.NewInt(0.94 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.98 * 1e18)).Bytes(),
-		common.BigToHash(big.NewInt(0.99 * 1e18)).Bytes(),
-	}
-	suite.client.On(
-		"CallBlocks",
-		mock.Anything,
-		ethereum.Call{
-			Address: ethereum.HexToAddress("0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0"),
-			Data:    ethereum.HexToBytes("0x9576a0c8"),
-		},
-		[]int64{0, 10, 20},
-	).Return(resp, nil).Once()
+	suite.client.respondBlocks(
+		"0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0",
+		"0x9576a0c8",
+		big.NewInt(0.94*1e18), big.NewInt(0.98*1e18), big.NewInt(0.99*1e18),
+	)
 
 	pair := Pair{Base: "STETH", Quote: "WSTETH"}
 
 	results1 := suite.origin.Fetch([]Pair{pair})
-
 	suite.Require().NoError(results1[0].Error)
 	suite.Equal(0.97, results1[0].Price.Price)
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
-
-	suite.client.AssertNumberOfCalls(suite.T(), "CallBlocks", 1)
+	suite.Equal([]int64{100, 90, 80}, suite.client.blocks)
 }
 
 func (suite *WrappedStakedETHSuite) TestFailOnWrongPair() {
@@ -122,3 +95,13 @@ func (suite *WrappedStakedETHSuite) TestFailOnWrongPair() {
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.Require().EqualError(cr[0].Error, "failed to get contract address for pair: x/y")
 }
+
+func (suite *WrappedStakedETHSuite) TestFailOnFailedCall() {
+	// The response for the oldest block is missing.
+	suite.client.respond(100, "0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0", "0x035faf82", make([]byte, 32))
+	suite.client.respond(90, "0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0", "0x035faf82", make([]byte, 32))
+
+	pair := Pair{Base: "WSTETH", Quote: "STETH"}
+	cr := suite.origin.Fetch([]Pair{pair})
+	suite.Require().ErrorIs(cr[0].Error, ErrMulticallCallFailed)
+}
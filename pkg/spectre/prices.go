@@ -22,6 +22,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
@@ -123,3 +124,35 @@ func (p *prices) clearOlderThan(t time.Time) {
 	}
 	p.prices = prices
 }
+
+// uniqueFeeders removes prices sent by the same logical feeder, only one
+// price from each feeder is kept. Prices for which the lifted function
+// returns true are preferred over others, then the most recent price is
+// chosen. The feeder function returns the logical feeder address for
+// a price, prices for which it returns an error are removed.
+func (p *prices) uniqueFeeders(
+	feeder func(*messages.Price) (ethereum.Address, error),
+	lifted func(*messages.Price) bool,
+) {
+
+	var prices []*messages.Price
+	idx := map[ethereum.Address]int{}
+	for _, price := range p.prices {
+		addr, err := feeder(price)
+		if err != nil {
+			continue
+		}
+		if i, ok := idx[addr]; ok {
+			newLifted, oldLifted := lifted(price), lifted(prices[i])
+			if newLifted && !oldLifted {
+				prices[i] = price
+			} else if newLifted == oldLifted && price.Price.Age.After(prices[i].Price.Age) {
+				prices[i] = price
+			}
+			continue
+		}
+		idx[addr] = len(prices)
+		prices = append(prices, price)
+	}
+	p.prices = prices
+}
@@ -16,6 +16,7 @@
 package spectre
 
 import (
+	"errors"
 	"math"
 	"math/big"
 	"strconv"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/store/testutil"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
@@ -167,3 +169,50 @@ func TestPrices_clearOlderThan(t *testing.T) {
 	assert.Contains(t, ps.oraclePrices(), testutil.PriceAAABBB3.Price)
 	assert.Contains(t, ps.oraclePrices(), testutil.PriceAAABBB4.Price)
 }
+
+func TestPrices_uniqueFeeders(t *testing.T) {
+	ps := newPricesList([]*messages.Price{
+		testutil.PriceAAABBB1,
+		testutil.PriceAAABBB2,
+		testutil.PriceAAABBB3,
+		testutil.PriceAAABBB4,
+	})
+
+	// The first two prices and the last two prices are sent by the same
+	// feeders. The third price is invalid.
+	feeders := map[*messages.Price]ethereum.Address{
+		testutil.PriceAAABBB1: testutil.Address1,
+		testutil.PriceAAABBB2: testutil.Address1,
+		testutil.PriceAAABBB4: testutil.Address2,
+	}
+	ps.uniqueFeeders(func(p *messages.Price) (ethereum.Address, error) {
+		if addr, ok := feeders[p]; ok {
+			return addr, nil
+		}
+		return ethereum.Address{}, errors.New("invalid signature")
+	}, func(*messages.Price) bool {
+		return true
+	})
+
+	assert.Len(t, ps.messages(), 2)
+	assert.Contains(t, ps.messages(), testutil.PriceAAABBB2)
+	assert.Contains(t, ps.messages(), testutil.PriceAAABBB4)
+}
+
+func TestPrices_uniqueFeeders_PreferLifted(t *testing.T) {
+	ps := newPricesList([]*messages.Price{
+		testutil.PriceAAABBB1,
+		testutil.PriceAAABBB2,
+	})
+
+	// Both prices are sent by the same feeder, but only the older one is
+	// signed by a lifted key:
+	ps.uniqueFeeders(func(*messages.Price) (ethereum.Address, error) {
+		return testutil.Address1, nil
+	}, func(p *messages.Price) bool {
+		return p == testutil.PriceAAABBB1
+	})
+
+	assert.Len(t, ps.messages(), 1)
+	assert.Contains(t, ps.messages(), testutil.PriceAAABBB1)
+}
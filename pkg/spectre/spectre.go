@@ -23,14 +23,21 @@ import (
 	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
 const LoggerTag = "SPECTRE"
 
+// errOSMPokePending is returned by pokeOSM if the previous poke transaction
+// is still pending.
+var errOSMPokePending = errors.New("previous OSM poke transaction is still pending")
+
 type errNotEnoughPricesForQuorum struct {
 	AssetPair string
 }
@@ -66,8 +73,15 @@ type Spectre struct {
 	signer     ethereum.Signer
 	priceStore *store.PriceStore
 	interval   time.Duration
+	aliases    map[ethereum.Address]ethereum.Address
 	log        log.Logger
 	pairs      map[string]*Pair
+	osms       []*OSM
+	client     ethereumv2.Client
+
+	// osmTxs contains hashes of the last poke transactions sent to OSMs.
+	// A transaction is removed once it is mined or dropped.
+	osmTxs map[*OSM]ethereum.Hash
 }
 
 // Config is the configuration for Spectre.
@@ -79,6 +93,18 @@ type Config struct {
 	Interval time.Duration
 	// Pairs is the list supported pairs by Spectre with their configuration.
 	Pairs []*Pair
+	// FeederAliases maps feeder addresses to logical feeder addresses. It is
+	// used during key rotations to make sure that prices signed by the old
+	// and the new key of the same feeder are not both used to achieve
+	// a quorum.
+	FeederAliases map[ethereum.Address]ethereum.Address
+	// OSMs is the list of OSM contracts that are poked by Spectre whenever
+	// enough time has passed since their last update.
+	OSMs []*OSM
+	// Client is used to check whether OSM poke transactions were mined or
+	// dropped. Until then, the OSM is not poked again. It is required if
+	// OSMs are set.
+	Client ethereumv2.Client
 	// Logger is a current logger interface used by the Spectre. The Logger is
 	// required to monitor asynchronous processes.
 	Logger log.Logger
@@ -101,6 +127,14 @@ type Pair struct {
 	Median oracle.Median
 }
 
+type OSM struct {
+	// Name is the name of the OSM used in logs, e.g. ETHUSD.
+	Name string
+	// OSM is the instance of the oracle.OSM which is the interface for
+	// the OSM contract.
+	OSM oracle.OSM
+}
+
 func NewSpectre(cfg Config) (*Spectre, error) {
 	if cfg.Signer == nil {
 		return nil, errors.New("signer must not be nil")
@@ -108,6 +142,9 @@ func NewSpectre(cfg Config) (*Spectre, error) {
 	if cfg.PriceStore == nil {
 		return nil, errors.New("price store must not be nil")
 	}
+	if len(cfg.OSMs) > 0 && cfg.Client == nil {
+		return nil, errors.New("client must not be nil if OSMs are set")
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = null.New()
 	}
@@ -116,7 +153,11 @@ func NewSpectre(cfg Config) (*Spectre, error) {
 		signer:     cfg.Signer,
 		priceStore: cfg.PriceStore,
 		interval:   cfg.Interval,
+		aliases:    cfg.FeederAliases,
 		pairs:      make(map[string]*Pair),
+		osms:       cfg.OSMs,
+		client:     cfg.Client,
+		osmTxs:     make(map[*OSM]ethereum.Hash),
 		log:        cfg.Logger.WithField("tag", LoggerTag),
 	}
 	for _, p := range cfg.Pairs {
@@ -182,6 +223,17 @@ func (s *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
 	pricesList.clearOlderThan(time.Now().Add(-1 * pair.PriceExpiration))
 	pricesList.clearOlderThan(oracleTime)
 
+	// Use only one price from each feeder during key rotations. Prices
+	// signed by keys that are not lifted yet would revert the poke, so
+	// prices signed by lifted keys are preferred:
+	if len(s.aliases) > 0 {
+		feeds, err := pair.Median.Feeds(s.ctx)
+		if err != nil {
+			return nil, err
+		}
+		pricesList.uniqueFeeders(s.feeder, s.lifted(feeds))
+	}
+
 	// Use only a minimum prices required to achieve a quorum:
 	pricesList.truncate(oracleQuorum)
 
@@ -225,6 +277,89 @@ func (s *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
 	return nil, nil
 }
 
+// pokeOSM pokes the given OSM contract if its pass method returns true.
+// It'll return transaction hash or nil if the OSM cannot be poked yet.
+// If the previous poke transaction is neither mined nor dropped, the OSM is
+// not poked and the hash of that transaction is returned along with
+// errOSMPokePending.
+func (s *Spectre) pokeOSM(osm *OSM) (*ethereum.Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if hash, ok := s.osmTxs[osm]; ok {
+		pending, err := s.isPending(hash)
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			return &hash, errOSMPokePending
+		}
+		delete(s.osmTxs, osm)
+	}
+
+	pass, err := osm.OSM.Pass(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !pass {
+		return nil, nil
+	}
+
+	tx, err := osm.OSM.Poke(s.ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if tx != nil {
+		s.osmTxs[osm] = *tx
+	}
+	return tx, nil
+}
+
+// isPending returns true if the transaction with the given hash is known to
+// the node but not yet mined.
+func (s *Spectre) isPending(hash ethereum.Hash) (bool, error) {
+	receipt, err := s.client.TransactionReceipt(s.ctx, types.Hash(hash))
+	if err != nil {
+		return false, err
+	}
+	if receipt != nil {
+		return false, nil
+	}
+	tx, err := s.client.TransactionByHash(s.ctx, types.Hash(hash))
+	if err != nil {
+		return false, err
+	}
+	return tx != nil, nil
+}
+
+// feeder returns the logical feeder address for the given price.
+func (s *Spectre) feeder(price *messages.Price) (ethereum.Address, error) {
+	from, err := price.Price.From(s.signer)
+	if err != nil {
+		return ethereum.Address{}, err
+	}
+	if addr, ok := s.aliases[*from]; ok {
+		return addr, nil
+	}
+	return *from, nil
+}
+
+// lifted returns a function that checks if the given price is signed by
+// one of the given feeds.
+func (s *Spectre) lifted(feeds []ethereum.Address) func(*messages.Price) bool {
+	set := make(map[ethereum.Address]bool, len(feeds))
+	for _, f := range feeds {
+		set[f] = true
+	}
+	return func(price *messages.Price) bool {
+		from, err := price.Price.From(s.signer)
+		if err != nil {
+			return false
+		}
+		return set[*from]
+	}
+}
+
 // relayerLoop creates a asynchronous loop which tries to send an update
 // to an Oracle contract at a specified interval.
 func (s *Spectre) relayerLoop() {
@@ -263,6 +398,31 @@ func (s *Spectre) relayerLoop() {
 							Info("Oracle updated")
 					}
 				}
+				for _, osm := range s.osms {
+					tx, err := s.pokeOSM(osm)
+					if errors.Is(err, errOSMPokePending) {
+						s.log.
+							WithFields(log.Fields{"osm": osm.Name, "tx": tx.String()}).
+							Info("OSM poke transaction is still pending")
+						continue
+					}
+					if err != nil {
+						s.log.
+							WithFields(log.Fields{"osm": osm.Name}).
+							WithError(err).
+							Warn("Unable to poke OSM")
+					}
+					if err == nil && tx == nil {
+						s.log.
+							WithFields(log.Fields{"osm": osm.Name}).
+							Info("OSM cannot be poked yet")
+					}
+					if tx != nil {
+						s.log.
+							WithFields(log.Fields{"osm": osm.Name, "tx": tx.String()}).
+							Info("OSM poked")
+					}
+				}
 			}
 		}
 	}()
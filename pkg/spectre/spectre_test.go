@@ -0,0 +1,259 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereumv2/types"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/oracle"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/store/testutil"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+// testOSM is a fake OSM contract that records pokes.
+type testOSM struct {
+	oracle.OSM
+
+	pass    bool
+	passErr error
+	pokes   int
+}
+
+func (o *testOSM) Pass(_ context.Context) (bool, error) {
+	return o.pass, o.passErr
+}
+
+func (o *testOSM) Poke(_ context.Context, _ bool) (*ethereum.Hash, error) {
+	o.pokes++
+	return &ethereum.Hash{0x01}, nil
+}
+
+// testMedian is a fake Median contract that records poked prices.
+type testMedian struct {
+	oracle.Median
+
+	feeds  []ethereum.Address
+	prices []*oracle.Price
+}
+
+func (m *testMedian) Bar(_ context.Context) (int64, error) {
+	return 1, nil
+}
+
+func (m *testMedian) Age(_ context.Context) (time.Time, error) {
+	return time.Unix(0, 0), nil
+}
+
+func (m *testMedian) Val(_ context.Context) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (m *testMedian) Feeds(_ context.Context) ([]ethereum.Address, error) {
+	return m.feeds, nil
+}
+
+func (m *testMedian) Poke(_ context.Context, prices []*oracle.Price, _ bool) (*ethereum.Hash, error) {
+	m.prices = prices
+	return &ethereum.Hash{0x01}, nil
+}
+
+// testTxClient is a fake Ethereum client that reports the status of
+// transactions.
+type testTxClient struct {
+	ethereumv2.Client
+
+	mined   bool
+	dropped bool
+}
+
+func (c *testTxClient) TransactionReceipt(_ context.Context, _ types.Hash) (*types.TransactionReceiptType, error) {
+	if c.mined {
+		return &types.TransactionReceiptType{}, nil
+	}
+	return nil, nil
+}
+
+func (c *testTxClient) TransactionByHash(_ context.Context, hash types.Hash) (*types.Transaction, error) {
+	if c.mined || c.dropped {
+		return nil, nil
+	}
+	return &types.Transaction{Hash: hash}, nil
+}
+
+func TestSpectre_pokeOSM(t *testing.T) {
+	tests := []struct {
+		osm       *testOSM
+		wantTx    bool
+		wantErr   bool
+		wantPokes int
+	}{
+		{
+			osm:       &testOSM{pass: true},
+			wantTx:    true,
+			wantPokes: 1,
+		},
+		{
+			osm:       &testOSM{pass: false},
+			wantPokes: 0,
+		},
+		{
+			osm:       &testOSM{pass: true, passErr: errors.New("err")},
+			wantErr:   true,
+			wantPokes: 0,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			osm := &OSM{Name: "AAABBB", OSM: tt.osm}
+			s, err := NewSpectre(Config{
+				Signer:     &ethereumMocks.Signer{},
+				PriceStore: &store.PriceStore{},
+				OSMs:       []*OSM{osm},
+				Client:     &testTxClient{},
+			})
+			require.NoError(t, err)
+			s.ctx = context.Background()
+
+			tx, err := s.pokeOSM(osm)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantTx, tx != nil)
+			assert.Equal(t, tt.wantPokes, tt.osm.pokes)
+		})
+	}
+}
+
+func TestSpectre_pokeOSM_PendingTransaction(t *testing.T) {
+	cli := &testTxClient{}
+	osm := &OSM{Name: "AAABBB", OSM: &testOSM{pass: true}}
+	s, err := NewSpectre(Config{
+		Signer:     &ethereumMocks.Signer{},
+		PriceStore: &store.PriceStore{},
+		OSMs:       []*OSM{osm},
+		Client:     cli,
+	})
+	require.NoError(t, err)
+	s.ctx = context.Background()
+
+	tx, err := s.pokeOSM(osm)
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+
+	// The OSM must not be poked again while the transaction is pending:
+	pending, err := s.pokeOSM(osm)
+	assert.ErrorIs(t, err, errOSMPokePending)
+	assert.Equal(t, tx, pending)
+	assert.Equal(t, 1, osm.OSM.(*testOSM).pokes)
+
+	// The OSM can be poked again after the transaction is dropped:
+	cli.dropped = true
+	_, err = s.pokeOSM(osm)
+	require.NoError(t, err)
+	assert.Equal(t, 2, osm.OSM.(*testOSM).pokes)
+
+	// And after it is mined:
+	cli.dropped = false
+	cli.mined = true
+	_, err = s.pokeOSM(osm)
+	require.NoError(t, err)
+	assert.Equal(t, 3, osm.OSM.(*testOSM).pokes)
+}
+
+func TestNewSpectre_OSMWithoutClient(t *testing.T) {
+	_, err := NewSpectre(Config{
+		Signer:     &ethereumMocks.Signer{},
+		PriceStore: &store.PriceStore{},
+		OSMs:       []*OSM{{Name: "AAABBB", OSM: &testOSM{}}},
+	})
+	require.Error(t, err)
+}
+
+func TestSpectre_relay_FeederAliases(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	// The same tick is signed by the lifted old key and by the new key that
+	// is not lifted yet:
+	oldKey, newKey := testutil.Address1, testutil.Address2
+	oldPrice := &messages.Price{Price: &oracle.Price{Wat: "AAABBB", Val: big.NewInt(10), Age: time.Now(), V: 1}}
+	newPrice := &messages.Price{Price: &oracle.Price{Wat: "AAABBB", Val: big.NewInt(10), Age: oldPrice.Price.Age, V: 2}}
+
+	sig := &ethereumMocks.Signer{}
+	sig.On("Recover", oldPrice.Price.Signature(), mock.Anything).Return(&oldKey, nil)
+	sig.On("Recover", newPrice.Price.Signature(), mock.Anything).Return(&newKey, nil)
+
+	tra := local.New([]byte("test"), 0, map[string]transport.Message{messages.PriceV0MessageName: (*messages.Price)(nil)})
+	require.NoError(t, tra.Start(ctx))
+	ps, err := store.New(store.Config{
+		Storage:   store.NewMemoryStorage(),
+		Signer:    sig,
+		Transport: tra,
+		Pairs:     []string{"AAABBB"},
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, ps.Start(ctx))
+
+	assert.NoError(t, tra.Broadcast(messages.PriceV0MessageName, oldPrice))
+	assert.NoError(t, tra.Broadcast(messages.PriceV0MessageName, newPrice))
+
+	// PriceStore fetches prices asynchronously, so we wait up to 1 second:
+	for i := 0; i < 10; i++ {
+		time.Sleep(100 * time.Millisecond)
+		prices, err := ps.GetByAssetPair(ctx, "AAABBB")
+		require.NoError(t, err)
+		if len(prices) == 2 {
+			break
+		}
+	}
+
+	med := &testMedian{feeds: []ethereum.Address{oldKey}}
+	s, err := NewSpectre(Config{
+		Signer:        sig,
+		PriceStore:    ps,
+		Pairs:         []*Pair{{AssetPair: "AAABBB", PriceExpiration: time.Minute, Median: med}},
+		FeederAliases: map[ethereum.Address]ethereum.Address{newKey: oldKey},
+		Logger:        null.New(),
+	})
+	require.NoError(t, err)
+	s.ctx = ctx
+
+	tx, err := s.relay("AAABBB")
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	require.Len(t, med.prices, 1)
+	assert.Equal(t, oldPrice.Price.Signature(), med.prices[0].Signature())
+}
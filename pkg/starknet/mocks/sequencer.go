@@ -55,3 +55,14 @@ func (c *Sequencer) Calls() []mock.Call {
 	defer c.mu.Unlock()
 	return c.Mock.Calls
 }
+
+type RangeSequencer struct {
+	Sequencer
+}
+
+func (c *RangeSequencer) GetBlocksByRange(ctx context.Context, from, to uint64) ([]*starknet.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	args := c.Called(ctx, from, to)
+	return args.Get(0).([]*starknet.Block), args.Error(1)
+}
@@ -0,0 +1,224 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package starknet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const defaultChunkSize = 100
+const defaultMaxBlockRange = 1000
+
+// BlockID identifies a block in JSON-RPC requests. It is either a block
+// number or one of the "latest" and "pending" tags.
+type BlockID struct {
+	Number uint64
+	Tag    string
+}
+
+// LatestBlock is the ID of the latest accepted block.
+var LatestBlock = BlockID{Tag: "latest"}
+
+// PendingBlock is the ID of the pending block.
+var PendingBlock = BlockID{Tag: "pending"}
+
+// BlockNumber returns the ID of the block with the given number.
+func BlockNumber(n uint64) BlockID {
+	return BlockID{Number: n}
+}
+
+func (b BlockID) MarshalJSON() ([]byte, error) {
+	if b.Tag != "" {
+		return json.Marshal(b.Tag)
+	}
+	return json.Marshal(struct {
+		BlockNumber uint64 `json:"block_number"`
+	}{b.Number})
+}
+
+// EventFilter is a filter used to fetch events using the starknet_getEvents
+// method.
+type EventFilter struct {
+	FromBlock BlockID
+	ToBlock   BlockID
+	// Address is the address of the contract that emitted events. If nil,
+	// events from all contracts are returned.
+	Address *Felt
+	// Keys is a list of accepted keys for every key position. An empty list
+	// at a given position matches any key.
+	Keys [][]*Felt
+}
+
+// EmittedEvent is an event returned by the starknet_getEvents method.
+type EmittedEvent struct {
+	Event
+	BlockHash       *Felt  `json:"block_hash"`
+	BlockNumber     uint64 `json:"block_number"`
+	TransactionHash *Felt  `json:"transaction_hash"`
+}
+
+// RPCBlock is a block returned by the starknet_getBlockWithTxHashes method.
+// Pending blocks do not have the block hash and the block number.
+type RPCBlock struct {
+	Status       string  `json:"status"`
+	BlockHash    *Felt   `json:"block_hash"`
+	ParentHash   *Felt   `json:"parent_hash"`
+	BlockNumber  uint64  `json:"block_number"`
+	Timestamp    int64   `json:"timestamp"`
+	Transactions []*Felt `json:"transactions"`
+}
+
+// RPCConfig is the configuration for the RPC client.
+type RPCConfig struct {
+	// Endpoint is the JSON-RPC endpoint of the Starknet node.
+	Endpoint string
+	// HTTPClient is the HTTP client used to communicate with the node.
+	HTTPClient http.Client
+	// ChunkSize is the number of events fetched in a single
+	// starknet_getEvents request. If zero, 100 is used.
+	ChunkSize int
+	// MaxBlockRange is the maximum number of blocks queried in a single
+	// starknet_getEvents request. Larger ranges are split into multiple
+	// requests. If zero, 1000 is used.
+	MaxBlockRange uint64
+}
+
+// RPC is a client for the Starknet JSON-RPC API.
+type RPC struct {
+	client        *rpc.Client
+	chunkSize     int
+	maxBlockRange uint64
+}
+
+// NewRPC returns a new RPC instance.
+func NewRPC(cfg RPCConfig) (*RPC, error) {
+	httpClient := cfg.HTTPClient
+	client, err := rpc.DialHTTPWithClient(cfg.Endpoint, &httpClient)
+	if err != nil {
+		return nil, Error{Err: err}
+	}
+	if cfg.ChunkSize == 0 {
+		cfg.ChunkSize = defaultChunkSize
+	}
+	if cfg.MaxBlockRange == 0 {
+		cfg.MaxBlockRange = defaultMaxBlockRange
+	}
+	return &RPC{client: client, chunkSize: cfg.ChunkSize, maxBlockRange: cfg.MaxBlockRange}, nil
+}
+
+// BlockNumber returns the number of the latest accepted block.
+func (r *RPC) BlockNumber(ctx context.Context) (uint64, error) {
+	var n uint64
+	if err := r.call(ctx, &n, "starknet_blockNumber"); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// GetBlockWithTxHashes returns the block with the given ID.
+func (r *RPC) GetBlockWithTxHashes(ctx context.Context, id BlockID) (*RPCBlock, error) {
+	var block *RPCBlock
+	if err := r.call(ctx, &block, "starknet_getBlockWithTxHashes", id); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, Error{Err: errors.New("block not found")}
+	}
+	return block, nil
+}
+
+// GetEvents returns all events matching the filter. Results are fetched in
+// chunks using continuation tokens. If both the FromBlock and ToBlock are
+// block numbers, the range is split into smaller ranges of at most
+// MaxBlockRange blocks.
+func (r *RPC) GetEvents(ctx context.Context, filter EventFilter) ([]*EmittedEvent, error) {
+	if filter.FromBlock.Tag != "" || filter.ToBlock.Tag != "" {
+		return r.getEvents(ctx, filter)
+	}
+	var events []*EmittedEvent
+	for from := filter.FromBlock.Number; from <= filter.ToBlock.Number; from += r.maxBlockRange {
+		to := from + r.maxBlockRange - 1
+		if to > filter.ToBlock.Number {
+			to = filter.ToBlock.Number
+		}
+		f := filter
+		f.FromBlock = BlockNumber(from)
+		f.ToBlock = BlockNumber(to)
+		evts, err := r.getEvents(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, evts...)
+	}
+	return events, nil
+}
+
+// getEvents returns all events matching the filter using continuation
+// tokens.
+func (r *RPC) getEvents(ctx context.Context, filter EventFilter) ([]*EmittedEvent, error) {
+	type request struct {
+		FromBlock         BlockID   `json:"from_block"`
+		ToBlock           BlockID   `json:"to_block"`
+		Address           *Felt     `json:"address,omitempty"`
+		Keys              [][]*Felt `json:"keys,omitempty"`
+		ChunkSize         int       `json:"chunk_size"`
+		ContinuationToken string    `json:"continuation_token,omitempty"`
+	}
+	type response struct {
+		Events            []*EmittedEvent `json:"events"`
+		ContinuationToken string          `json:"continuation_token"`
+	}
+	req := request{
+		FromBlock: filter.FromBlock,
+		ToBlock:   filter.ToBlock,
+		Address:   filter.Address,
+		Keys:      filter.Keys,
+		ChunkSize: r.chunkSize,
+	}
+	var events []*EmittedEvent
+	for {
+		var res response
+		if err := r.call(ctx, &res, "starknet_getEvents", req); err != nil {
+			return nil, err
+		}
+		events = append(events, res.Events...)
+		if res.ContinuationToken == "" {
+			return events, nil
+		}
+		if res.ContinuationToken == req.ContinuationToken {
+			return nil, Error{Err: errors.New("node returned the same continuation token")}
+		}
+		req.ContinuationToken = res.ContinuationToken
+	}
+}
+
+func (r *RPC) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	err := r.client.CallContext(ctx, result, method, args...)
+	if err != nil {
+		var httpErr rpc.HTTPError
+		if errors.As(err, &httpErr) {
+			return HTTPError{StatusCode: httpErr.StatusCode}
+		}
+		return Error{Err: fmt.Errorf("%s: %w", method, err)}
+	}
+	return nil
+}
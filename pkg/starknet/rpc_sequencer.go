@@ -0,0 +1,199 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package starknet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// RPCSequencer provides the same methods as the Sequencer, but it uses
+// the Starknet JSON-RPC API, so it can be used with any standard Starknet
+// node.
+//
+// Blocks are built from the block header and the events fetched using the
+// starknet_getEvents method. Only events emitted by the given addresses and
+// matching the given keys are included in transaction receipts. Other
+// receipt fields and transactions are not set.
+//
+// Events are fetched using a single paginated query for a block or a range
+// of blocks. If there is more than one address, the query is not filtered
+// by the address, because the starknet_getEvents method accepts only one,
+// and events of other contracts are discarded. In that case, event keys
+// should be used to limit the number of returned events.
+type RPCSequencer struct {
+	rpc       *RPC
+	addresses []*Felt
+	keys      [][]*Felt
+}
+
+// NewRPCSequencer returns a new RPCSequencer instance. If the keys list
+// is empty, events with any keys are returned.
+func NewRPCSequencer(rpc *RPC, addresses []*Felt, keys []*Felt) *RPCSequencer {
+	s := &RPCSequencer{rpc: rpc, addresses: addresses}
+	if len(keys) > 0 {
+		s.keys = [][]*Felt{keys}
+	}
+	return s
+}
+
+func (s *RPCSequencer) GetPendingBlock(ctx context.Context) (*Block, error) {
+	return s.getBlock(ctx, PendingBlock)
+}
+
+func (s *RPCSequencer) GetLatestBlock(ctx context.Context) (*Block, error) {
+	return s.getBlock(ctx, LatestBlock)
+}
+
+func (s *RPCSequencer) GetBlockByNumber(ctx context.Context, blockNumber uint64) (*Block, error) {
+	return s.getBlock(ctx, BlockNumber(blockNumber))
+}
+
+// GetBlocksByRange returns blocks in the given range, inclusive. Events for
+// all blocks are fetched using a single paginated query.
+func (s *RPCSequencer) GetBlocksByRange(ctx context.Context, from, to uint64) ([]*Block, error) {
+	if from > to {
+		return nil, nil
+	}
+	headers := make([]*RPCBlock, 0, to-from+1)
+	for bn := from; bn <= to; bn++ {
+		header, err := s.rpc.GetBlockWithTxHashes(ctx, BlockNumber(bn))
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, header)
+	}
+	events, err := s.getEvents(ctx, BlockNumber(from), BlockNumber(to))
+	if err != nil {
+		return nil, err
+	}
+	byBlock := map[uint64][]*EmittedEvent{}
+	for _, evt := range events {
+		byBlock[evt.BlockNumber] = append(byBlock[evt.BlockNumber], evt)
+	}
+	blocks := make([]*Block, 0, len(headers))
+	for _, header := range headers {
+		block, err := newBlock(header, byBlock[header.BlockNumber], false)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func (s *RPCSequencer) getBlock(ctx context.Context, id BlockID) (*Block, error) {
+	header, err := s.rpc.GetBlockWithTxHashes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Events for the latest block are fetched using the block number,
+	// otherwise a new block could be accepted between both requests.
+	if id == LatestBlock {
+		id = BlockNumber(header.BlockNumber)
+	}
+	events, err := s.getEvents(ctx, id, id)
+	if err != nil {
+		return nil, err
+	}
+	return newBlock(header, events, id == PendingBlock)
+}
+
+// getEvents returns events emitted by the addresses in the given block range.
+// Events are returned in the order they were emitted.
+func (s *RPCSequencer) getEvents(ctx context.Context, from, to BlockID) ([]*EmittedEvent, error) {
+	filter := EventFilter{
+		FromBlock: from,
+		ToBlock:   to,
+		Keys:      s.keys,
+	}
+	if len(s.addresses) == 1 {
+		filter.Address = s.addresses[0]
+	}
+	events, err := s.rpc.GetEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var res []*EmittedEvent
+	for _, evt := range events {
+		if s.isAddressSupported(evt.FromAddress) {
+			res = append(res, evt)
+		}
+	}
+	return res, nil
+}
+
+func (s *RPCSequencer) isAddressSupported(address *Felt) bool {
+	if address == nil {
+		return false
+	}
+	for _, a := range s.addresses {
+		if a.Cmp(address.Int) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// newBlock builds a block from the block header and events emitted in that
+// block. Receipts are sorted by the transaction index and events in every
+// receipt are kept in the order they were emitted.
+func newBlock(header *RPCBlock, events []*EmittedEvent, pending bool) (*Block, error) {
+	block := &Block{
+		BlockHash:       header.BlockHash,
+		ParentBlockHash: header.ParentHash,
+		BlockNumber:     header.BlockNumber,
+		Status:          header.Status,
+		Timestamp:       header.Timestamp,
+	}
+	// Pending blocks returned by some nodes do not have the status field:
+	if pending {
+		block.Status = "PENDING"
+	}
+	txIndex := map[string]int{}
+	for i, txHash := range header.Transactions {
+		txIndex[txHash.Text(16)] = i
+	}
+	receipts := map[string]*TransactionReceipt{}
+	for _, evt := range events {
+		// The block could be replaced between fetching the header and
+		// events:
+		if !pending && evt.BlockHash != nil && header.BlockHash != nil && evt.BlockHash.Cmp(header.BlockHash.Int) != 0 {
+			return nil, fmt.Errorf("block %d changed while fetching events", header.BlockNumber)
+		}
+		key := evt.TransactionHash.Text(16)
+		tx, ok := receipts[key]
+		if !ok {
+			// Transactions that are not in the block header, which may
+			// happen if the pending block changed between requests, are
+			// added at the end.
+			idx, ok := txIndex[key]
+			if !ok {
+				idx = len(header.Transactions) + len(receipts)
+			}
+			tx = &TransactionReceipt{TransactionIndex: idx, TransactionHash: evt.TransactionHash}
+			receipts[key] = tx
+			block.TransactionReceipts = append(block.TransactionReceipts, tx)
+		}
+		e := evt.Event
+		tx.Events = append(tx.Events, &e)
+	}
+	sort.SliceStable(block.TransactionReceipts, func(i, j int) bool {
+		return block.TransactionReceipts[i].TransactionIndex < block.TransactionReceipts[j].TransactionIndex
+	})
+	return block, nil
+}
@@ -0,0 +1,218 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package starknet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testAddress = HexToFelt("0x197f9e93cfaf7068ca2daf3ec89c2b91d051505c2231a0a0b9f70801a91fb24")
+	testKey     = HexToFelt("0x2f988de39be0ebaa4ef3701988d8affa01403c00f22537d314abcb111ae9c86")
+)
+
+// rpcFixture is a recorded JSON-RPC request and its response.
+type rpcFixture struct {
+	Method string          `json:"method"`
+	Params interface{}     `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// newRPCFixtureServer returns a JSON-RPC server that responds with recorded
+// responses from the testdata/rpc.json file. Requests without a matching
+// fixture fail the test.
+func newRPCFixtureServer(t *testing.T) *httptest.Server {
+	b, err := os.ReadFile("./testdata/rpc.json")
+	require.NoError(t, err)
+	var fixtures []rpcFixture
+	require.NoError(t, json.Unmarshal(b, &fixtures))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params interface{}     `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Params == nil {
+			req.Params = []interface{}{}
+		}
+		for _, f := range fixtures {
+			if f.Method != req.Method || !reflect.DeepEqual(f.Params, req.Params) {
+				continue
+			}
+			res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			if f.Error != nil {
+				res["error"] = f.Error
+			} else {
+				res["result"] = f.Result
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(res)
+			return
+		}
+		p, _ := json.Marshal(req.Params)
+		t.Errorf("unexpected request: %s %s", req.Method, p)
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}))
+}
+
+func newTestRPC(t *testing.T, url string) *RPC {
+	rpc, err := NewRPC(RPCConfig{Endpoint: url, ChunkSize: 1, MaxBlockRange: 100})
+	require.NoError(t, err)
+	return rpc
+}
+
+func TestRPC_BlockNumber(t *testing.T) {
+	srv := newRPCFixtureServer(t)
+	defer srv.Close()
+
+	n, err := newTestRPC(t, srv.URL).BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(191504), n)
+}
+
+func TestRPC_GetBlockWithTxHashes(t *testing.T) {
+	srv := newRPCFixtureServer(t)
+	defer srv.Close()
+
+	block, err := newTestRPC(t, srv.URL).GetBlockWithTxHashes(context.Background(), BlockNumber(191504))
+	require.NoError(t, err)
+	assert.Equal(t, "ACCEPTED_ON_L1", block.Status)
+	assert.Equal(t, uint64(191504), block.BlockNumber)
+	assert.Equal(t, int64(1652698140), block.Timestamp)
+	assert.Equal(t, "26af2e23367fd4f46198bf469d5dbbe33b29919710b1fa08b65599f79672ecb", block.ParentHash.Text(16))
+	assert.Len(t, block.Transactions, 2)
+
+	// Node error:
+	_, err = newTestRPC(t, srv.URL).GetBlockWithTxHashes(context.Background(), BlockNumber(999999999))
+	assert.Error(t, err)
+}
+
+func TestRPC_GetEvents(t *testing.T) {
+	srv := newRPCFixtureServer(t)
+	defer srv.Close()
+
+	// Continuation tokens:
+	events, err := newTestRPC(t, srv.URL).GetEvents(context.Background(), EventFilter{
+		FromBlock: BlockNumber(191504),
+		ToBlock:   BlockNumber(191504),
+		Address:   testAddress,
+		Keys:      [][]*Felt{{testKey}},
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "57a333bfccf30465cf287460c9c4bb7b21645213bc9cca7fbe99e1b9167d202", events[0].TransactionHash.Text(16))
+	assert.Equal(t, "24a45d2690614692451862c5b0249567854c6731a0a9e9aef236c643ce9abaf", events[1].TransactionHash.Text(16))
+	assert.Equal(t, uint64(191504), events[0].BlockNumber)
+	assert.Equal(t, testAddress.Text(16), events[0].FromAddress.Text(16))
+	assert.Len(t, events[0].Data, 7)
+
+	// Block range pagination:
+	events, err = newTestRPC(t, srv.URL).GetEvents(context.Background(), EventFilter{
+		FromBlock: BlockNumber(100),
+		ToBlock:   BlockNumber(250),
+		Address:   testAddress,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, uint64(150), events[0].BlockNumber)
+	assert.Equal(t, uint64(210), events[1].BlockNumber)
+}
+
+func TestRPC_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	_, err := newTestRPC(t, srv.URL).BlockNumber(context.Background())
+	assert.Equal(t, HTTPError{StatusCode: http.StatusTooManyRequests}, err)
+}
+
+func TestRPCSequencer(t *testing.T) {
+	srv := newRPCFixtureServer(t)
+	defer srv.Close()
+
+	seq := NewRPCSequencer(newTestRPC(t, srv.URL), []*Felt{testAddress}, []*Felt{testKey})
+
+	for _, get := range []func() (*Block, error){
+		func() (*Block, error) { return seq.GetBlockByNumber(context.Background(), 191504) },
+		func() (*Block, error) { return seq.GetLatestBlock(context.Background()) },
+	} {
+		block, err := get()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(191504), block.BlockNumber)
+		assert.Equal(t, "ACCEPTED_ON_L1", block.Status)
+		assert.Equal(t, int64(1652698140), block.Timestamp)
+
+		// Receipts must be in the same order as transactions in the block:
+		require.Len(t, block.TransactionReceipts, 2)
+		assert.Equal(t, "24a45d2690614692451862c5b0249567854c6731a0a9e9aef236c643ce9abaf", block.TransactionReceipts[0].TransactionHash.Text(16))
+		assert.Equal(t, 0, block.TransactionReceipts[0].TransactionIndex)
+		assert.Equal(t, "57a333bfccf30465cf287460c9c4bb7b21645213bc9cca7fbe99e1b9167d202", block.TransactionReceipts[1].TransactionHash.Text(16))
+		assert.Equal(t, 1, block.TransactionReceipts[1].TransactionIndex)
+		require.Len(t, block.TransactionReceipts[1].Events, 1)
+		assert.Equal(t, testAddress.Text(16), block.TransactionReceipts[1].Events[0].FromAddress.Text(16))
+	}
+
+	block, err := seq.GetPendingBlock(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "PENDING", block.Status)
+	assert.Equal(t, "74ff65a69e077e69663539f8a277d3c81965f7eb9a61d039b437e66290f38ea", block.ParentBlockHash.Text(16))
+	require.Len(t, block.TransactionReceipts, 1)
+	require.Len(t, block.TransactionReceipts[0].Events, 1)
+}
+
+func TestRPCSequencer_GetBlocksByRange(t *testing.T) {
+	srv := newRPCFixtureServer(t)
+	defer srv.Close()
+
+	// With more than one address, events are not filtered by the address
+	// in the request, and events of other contracts must be discarded.
+	seq := NewRPCSequencer(newTestRPC(t, srv.URL), []*Felt{testAddress, HexToFelt("0x123")}, []*Felt{testKey})
+
+	blocks, err := seq.GetBlocksByRange(context.Background(), 300, 301)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+
+	assert.Equal(t, uint64(300), blocks[0].BlockNumber)
+	require.Len(t, blocks[0].TransactionReceipts, 2)
+	assert.Equal(t, "a", blocks[0].TransactionReceipts[0].TransactionHash.Text(16))
+	assert.Equal(t, 0, blocks[0].TransactionReceipts[0].TransactionIndex)
+	require.Len(t, blocks[0].TransactionReceipts[0].Events, 2)
+	assert.Equal(t, "3", blocks[0].TransactionReceipts[0].Events[0].Data[0].Text(16))
+	assert.Equal(t, "4", blocks[0].TransactionReceipts[0].Events[1].Data[0].Text(16))
+	assert.Equal(t, "b", blocks[0].TransactionReceipts[1].TransactionHash.Text(16))
+	assert.Equal(t, 1, blocks[0].TransactionReceipts[1].TransactionIndex)
+
+	assert.Equal(t, uint64(301), blocks[1].BlockNumber)
+	require.Len(t, blocks[1].TransactionReceipts, 1)
+	require.Len(t, blocks[1].TransactionReceipts[0].Events, 1)
+	assert.Equal(t, "5", blocks[1].TransactionReceipts[0].Events[0].Data[0].Text(16))
+}
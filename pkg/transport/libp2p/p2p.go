@@ -112,6 +112,11 @@ type Config struct {
 	// FeedersAddrs is a list of price feeders. Only feeders can create new
 	// messages in the network.
 	FeedersAddrs []ethereum.Address
+	// FeederAliases maps feeder addresses to logical feeder addresses. During
+	// key rotations, a feeder may send prices signed with a different key
+	// than the one used to sign libp2p messages. Such prices are accepted
+	// only if both keys belong to the same logical feeder.
+	FeederAliases map[ethereum.Address]ethereum.Address
 	// Discovery indicates whenever peer discovery should be enabled.
 	// If discovery is disabled, then DirectPeersAddrs must be used
 	// to connect to the network. Always enabled in bootstrap mode.
@@ -212,7 +217,7 @@ func New(cfg Config) (*P2P, error) {
 			messageValidator(cfg.Topics, logger), // must be registered before any other validator
 			feederValidator(cfg.FeedersAddrs, logger),
 			eventValidator(logger),
-			priceValidator(cfg.Signer, logger),
+			priceValidator(cfg.Signer, cfg.FeederAliases, logger),
 		)
 		if cfg.MessagePrivKey != nil {
 			opts = append(opts, internal.MessagePrivKey(cfg.MessagePrivKey))
@@ -111,7 +111,8 @@ func eventValidator(logger log.Logger) internal.Options {
 
 // priceValidator adds a validator for price messages. The validator checks if
 // the price message is valid, and if the price is not older than 5 min.
-func priceValidator(signer ethereum.Signer, logger log.Logger) internal.Options {
+// The aliases map is used to accept prices signed with a rotated feeder key.
+func priceValidator(signer ethereum.Signer, aliases map[ethereum.Address]ethereum.Address, logger log.Logger) internal.Options {
 	return func(n *internal.Node) error {
 		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
 			priceMsg, ok := psMsg.ValidatorData.(*messages.Price)
@@ -134,7 +135,7 @@ func priceValidator(signer ethereum.Signer, logger log.Logger) internal.Options
 				return pubsub.ValidationReject
 			}
 			// The libp2p message should be created by the same person who signs the price message:
-			if ethkey.AddressToPeerID(*priceFrom) != psMsg.GetFrom() {
+			if !sameFeeder(aliases, *priceFrom, ethkey.PeerIDToAddress(psMsg.GetFrom())) {
 				logger.
 					WithField("peerID", psMsg.GetFrom().String()).
 					WithField("from", priceFrom.String()).
@@ -163,3 +164,17 @@ func priceValidator(signer ethereum.Signer, logger log.Logger) internal.Options
 		return nil
 	}
 }
+
+// sameFeeder returns true if both addresses belong to the same logical feeder.
+func sameFeeder(aliases map[ethereum.Address]ethereum.Address, a, b ethereum.Address) bool {
+	if a == b {
+		return true
+	}
+	if alias, ok := aliases[a]; ok {
+		a = alias
+	}
+	if alias, ok := aliases[b]; ok {
+		b = alias
+	}
+	return a == b
+}
@@ -6,8 +6,29 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
+// Redacted is used in place of values of sensitive fields.
+const Redacted = "[REDACTED]"
+
+// sensitiveNames is a list of struct field names and map keys whose values
+// are redacted. Names are compared case-insensitively, ignoring "_" and "-".
+var sensitiveNames = map[string]bool{
+	"accesstoken":  true,
+	"apikey":       true,
+	"apitoken":     true,
+	"authtoken":    true,
+	"bearertoken":  true,
+	"passphrase":   true,
+	"password":     true,
+	"privatekey":   true,
+	"privkey":      true,
+	"privkeyseed":  true,
+	"refreshtoken": true,
+	"secret":       true,
+}
+
 // Dump converts an arbitrary value to a simple scalar value.
 //
 // The purpose of this function is to provide an alternative to
@@ -17,6 +38,8 @@ import (
 // String, int and floats are returned as is.
 // Byte slices are converted to hex format.
 // Complex data structures are represented as JSON.
+// Values of struct fields and map keys with sensitive names, such as
+// "password" or "apiKey", are redacted.
 //
 // It does not support recursive data.
 func Dump(v interface{}) interface{} {
@@ -43,7 +66,7 @@ func Dump(v interface{}) interface{} {
 		case reflect.Struct:
 			m := map[string]interface{}{}
 			for n := 0; n < rv.NumField(); n++ {
-				m[rt.Field(n).Name] = Dump(rv.Field(n).Interface())
+				m[rt.Field(n).Name] = dumpField(rt.Field(n).Name, rv.Field(n).Interface())
 			}
 			return toJSON(m)
 		case reflect.Slice, reflect.Array:
@@ -55,7 +78,8 @@ func Dump(v interface{}) interface{} {
 		case reflect.Map:
 			m := map[string]interface{}{}
 			for _, k := range rv.MapKeys() {
-				m[fmt.Sprint(Dump(k))] = Dump(rv.MapIndex(k).Interface())
+				name := fmt.Sprint(Dump(k.Interface()))
+				m[name] = dumpField(name, rv.MapIndex(k).Interface())
 			}
 			return toJSON(m)
 		case reflect.Ptr, reflect.Interface:
@@ -66,6 +90,13 @@ func Dump(v interface{}) interface{} {
 	}
 }
 
+func dumpField(name string, v interface{}) interface{} {
+	if sensitiveNames[strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))] {
+		return Redacted
+	}
+	return Dump(v)
+}
+
 func toJSON(v interface{}) json.RawMessage {
 	b, err := json.Marshal(v)
 	if err != nil {
@@ -24,6 +24,11 @@ func TestSerialize(t *testing.T) {
 		{arg: struct{ A int }{A: 1}, want: json.RawMessage(`{"A":1}`)},
 		{arg: &struct{ A int }{A: 1}, want: json.RawMessage(`{"A":1}`)},
 		{arg: errors.New("foo"), want: "foo"},
+		{arg: map[int]string{1: "foo"}, want: json.RawMessage(`{"1":"foo"}`)},
+		{arg: map[string]string{"api_key": "foo"}, want: json.RawMessage(`{"api_key":"[REDACTED]"}`)},
+		{arg: struct{ Password string }{Password: "foo"}, want: json.RawMessage(`{"Password":"[REDACTED]"}`)},
+		{arg: map[string]string{"accessToken": "foo"}, want: json.RawMessage(`{"accessToken":"[REDACTED]"}`)},
+		{arg: map[string]string{"token": "0x6b175474e89094c44da98b954eedeac495271d0f"}, want: json.RawMessage(`{"token":"0x6b175474e89094c44da98b954eedeac495271d0f"}`)},
 	}
 	for n, tt := range tests {
 		t.Run(fmt.Sprintf("case-%d", n), func(t *testing.T) {